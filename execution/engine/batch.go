@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+)
+
+var (
+	ErrSubscriptionNotAllowedInBatch = errors.New("subscriptions are not allowed in batched requests")
+)
+
+// BatchTooLargeError is returned when a batch contains more operations than allowed by BatchConfiguration.MaxBatchSize
+type BatchTooLargeError struct {
+	Size         int
+	MaxBatchSize int
+}
+
+func (e *BatchTooLargeError) Error() string {
+	return fmt.Sprintf("the batch contains %d operations, the maximum is %d", e.Size, e.MaxBatchSize)
+}
+
+var internalErrorResponse = []byte(`{"errors":[{"message":"Internal Error"}]}`)
+
+// ExecuteBatch executes the operations of a batched request concurrently and writes their results as JSON array
+// in the order of the operations. The number of concurrently resolved operations is limited by the resolver.
+//
+// Errors are isolated per operation: an operation which could not be executed does not fail the batch,
+// instead its errors are written at its position. Errors caused by the operation, e.g. validation errors,
+// are returned to the client. All other errors are replaced by an internal error and returned in errs at the index
+// of the operation, so they could be logged. The returned err is only set if the batch as a whole could not be executed.
+//
+// The operations of a batch are independent, mutations of one batch are not executed in a guaranteed order.
+func (e *ExecutionEngine) ExecuteBatch(ctx context.Context, operations []*graphql.Request, writer io.Writer, options ...ExecutionOptions) (errs []error, err error) {
+	if len(operations) == 0 {
+		return nil, graphql.ErrEmptyBatch
+	}
+
+	maxBatchSize := e.config.batchConfiguration.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+	if len(operations) > maxBatchSize {
+		return nil, &BatchTooLargeError{Size: len(operations), MaxBatchSize: maxBatchSize}
+	}
+
+	if e.config.batchConfiguration.DeduplicateFetches {
+		deduplicator := resolve.NewFetchDeduplicator()
+		options = append(options[:len(options):len(options)], func(ctx *internalExecutionContext) {
+			ctx.resolveContext.SetFetchDeduplicator(deduplicator)
+		})
+	}
+
+	results := make([]*bytes.Buffer, len(operations))
+	errs = make([]error, len(operations))
+
+	wg := &sync.WaitGroup{}
+	for i := range operations {
+		results[i] = bytes.NewBuffer(make([]byte, 0, 1024))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = e.executeBatchOperation(ctx, operations[i], results[i], options...)
+		}(i)
+	}
+	wg.Wait()
+
+	hasErrors := false
+	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	buf.WriteByte('[')
+	for i := range results {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if errs[i] == nil {
+			buf.Write(results[i].Bytes())
+			continue
+		}
+		if graphqlerrors.IsRequestError(errs[i]) {
+			if err = writeOperationErrors(buf, errs[i]); err != nil {
+				return nil, err
+			}
+			errs[i] = nil
+			continue
+		}
+		hasErrors = true
+		buf.Write(internalErrorResponse)
+	}
+	buf.WriteByte(']')
+
+	if _, err = writer.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if !hasErrors {
+		return nil, nil
+	}
+	return errs, nil
+}
+
+func (e *ExecutionEngine) executeBatchOperation(ctx context.Context, operation *graphql.Request, result *bytes.Buffer, options ...ExecutionOptions) error {
+	if err := e.preparePersistedOperation(ctx, operation); err != nil {
+		return err
+	}
+
+	operationType, err := operation.OperationType()
+	if err != nil {
+		return err
+	}
+	if operationType == graphql.OperationTypeSubscription {
+		return graphqlerrors.RequestErrorsFromError(ErrSubscriptionNotAllowedInBatch)
+	}
+
+	resultWriter := graphql.NewEngineResultWriterFromBuffer(result)
+	if err = e.Execute(ctx, operation, &resultWriter, options...); err != nil {
+		result.Reset()
+		return err
+	}
+	return nil
+}
+
+func writeOperationErrors(buf *bytes.Buffer, err error) error {
+	response, err := json.Marshal(struct {
+		Errors graphqlerrors.RequestErrors `json:"errors"`
+	}{
+		Errors: graphqlerrors.RequestErrorsFromError(err),
+	})
+	if err != nil {
+		return err
+	}
+	buf.Write(response)
+	return nil
+}
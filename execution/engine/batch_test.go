@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+)
+
+func TestExecutionEngine_ExecuteBatch(t *testing.T) {
+	requestCount := atomic.NewInt32(0)
+	subgraph := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Inc()
+		_, _ = fmt.Fprint(w, `{"data":{"hello":"world"}}`)
+	}))
+	defer subgraph.Close()
+
+	newEngine := func(t *testing.T, batchConfiguration BatchConfiguration) *ExecutionEngine {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		schema, err := graphql.NewSchemaFromString(`
+			type Query { hello: String }
+			type Subscription { helloChanged: String }
+		`)
+		require.NoError(t, err)
+
+		engineConf, err := NewProxyEngineConfigFactory(ctx, schema, ProxyUpstreamConfig{
+			URL:    subgraph.URL,
+			Method: http.MethodPost,
+		}).EngineConfiguration()
+		require.NoError(t, err)
+		engineConf.SetBatchConfiguration(batchConfiguration)
+
+		engine, err := NewExecutionEngine(ctx, abstractlogger.NoopLogger, engineConf)
+		require.NoError(t, err)
+		return engine
+	}
+
+	operations := func(queries ...string) []*graphql.Request {
+		requests := make([]*graphql.Request, 0, len(queries))
+		for _, query := range queries {
+			requests = append(requests, &graphql.Request{Query: query})
+		}
+		return requests
+	}
+
+	t.Run("results are written in the order of the operations", func(t *testing.T) {
+		engine := newEngine(t, BatchConfiguration{})
+
+		buf := &bytes.Buffer{}
+		errs, err := engine.ExecuteBatch(context.Background(), operations(
+			`{ hello }`,
+			`{ unknown }`,
+			`subscription { helloChanged }`,
+			`{ hello `,
+			`query Hello { hello }`,
+		), buf)
+		require.NoError(t, err)
+		assert.Nil(t, errs)
+		assert.Equal(t, `[`+
+			`{"data":{"hello":"world"}},`+
+			`{"errors":[{"message":"field: unknown not defined on type: Query","path":["query","unknown"]}]},`+
+			`{"errors":[{"message":"subscriptions are not allowed in batched requests"}]},`+
+			`{"errors":[{"message":"unexpected token - got: EOF want one of: [RBRACE IDENT SPREAD]","locations":[{"line":0,"column":0}]}]},`+
+			`{"data":{"hello":"world"}}`+
+			`]`, buf.String())
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		engine := newEngine(t, BatchConfiguration{})
+
+		_, err := engine.ExecuteBatch(context.Background(), nil, &bytes.Buffer{})
+		assert.Equal(t, graphql.ErrEmptyBatch, err)
+	})
+
+	t.Run("batch too large", func(t *testing.T) {
+		engine := newEngine(t, BatchConfiguration{MaxBatchSize: 2})
+
+		buf := &bytes.Buffer{}
+		_, err := engine.ExecuteBatch(context.Background(), operations(`{ hello }`, `{ hello }`, `{ hello }`), buf)
+		assert.Equal(t, &BatchTooLargeError{Size: 3, MaxBatchSize: 2}, err)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("fetches are not deduplicated by default", func(t *testing.T) {
+		engine := newEngine(t, BatchConfiguration{})
+		requestCount.Store(0)
+
+		buf := &bytes.Buffer{}
+		_, err := engine.ExecuteBatch(context.Background(), operations(`{ hello }`, `{ hello }`, `{ hello }`), buf)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"data":{"hello":"world"}},{"data":{"hello":"world"}},{"data":{"hello":"world"}}]`, buf.String())
+		assert.Equal(t, int32(3), requestCount.Load())
+	})
+
+	t.Run("identical fetches are deduplicated", func(t *testing.T) {
+		engine := newEngine(t, BatchConfiguration{DeduplicateFetches: true})
+		requestCount.Store(0)
+
+		buf := &bytes.Buffer{}
+		_, err := engine.ExecuteBatch(context.Background(), operations(`{ hello }`, `query A { hello }`, `{ hello }`), buf)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"data":{"hello":"world"}},{"data":{"hello":"world"}},{"data":{"hello":"world"}}]`, buf.String())
+		assert.Equal(t, int32(1), requestCount.Load())
+	})
+}
@@ -10,16 +10,29 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 const (
 	DefaultFlushIntervalInMilliseconds = 1000
+	DefaultMaxBatchSize                = 10
 )
 
 type Configuration struct {
 	schema                   *graphql.Schema
 	plannerConfig            plan.Configuration
 	websocketBeforeStartHook WebsocketBeforeStartHook
+	persistedOperations      *graphql.PersistedOperations
+	batchConfiguration       BatchConfiguration
+}
+
+// BatchConfiguration configures the execution of batched requests, see ExecutionEngine.ExecuteBatch
+type BatchConfiguration struct {
+	// MaxBatchSize is the maximum number of operations of a batch, defaults to DefaultMaxBatchSize
+	MaxBatchSize int
+	// DeduplicateFetches shares the responses of identical fetches between the operations of a batch,
+	// mutations and fetches with uploaded files are never deduplicated
+	DeduplicateFetches bool
 }
 
 func NewConfiguration(schema *graphql.Schema) Configuration {
@@ -41,6 +54,11 @@ func (e *Configuration) SetCustomResolveMap(customResolveMap map[string]resolve.
 	e.plannerConfig.CustomResolveMap = customResolveMap
 }
 
+// SetScalarCodecs - sets the codecs of custom scalars, they validate literals of operations and serialize the values in the response
+func (e *Configuration) SetScalarCodecs(registry *scalars.Registry) {
+	e.plannerConfig.ScalarCodecs = registry
+}
+
 func (e *Configuration) AddDataSource(dataSource plan.DataSource) {
 	e.plannerConfig.DataSources = append(e.plannerConfig.DataSources, dataSource)
 }
@@ -70,6 +88,16 @@ func (e *Configuration) SetWebsocketBeforeStartHook(hook WebsocketBeforeStartHoo
 	e.websocketBeforeStartHook = hook
 }
 
+// SetPersistedOperations - enables persisted operations, requests could then reference an operation by its sha256 hash
+func (e *Configuration) SetPersistedOperations(persistedOperations *graphql.PersistedOperations) {
+	e.persistedOperations = persistedOperations
+}
+
+// SetBatchConfiguration - configures the maximum size of batched requests and whether their fetches are deduplicated
+func (e *Configuration) SetBatchConfiguration(config BatchConfiguration) {
+	e.batchConfiguration = config
+}
+
 type dataSourceGeneratorOptions struct {
 	streamingClient           *http.Client
 	subscriptionType          SubscriptionType
@@ -149,7 +177,7 @@ func (d *graphqlDataSourceGenerator) generateSubscriptionClient(httpClient *http
 		graphqlSubscriptionClient = definedOptions.subscriptionClientFactory.NewSubscriptionClient(
 			httpClient,
 			definedOptions.streamingClient,
-			nil,
+			d.engineCtx,
 			graphql_datasource.WithWSSubProtocol(graphql_datasource.ProtocolGraphQLTWS),
 		)
 	default:
@@ -157,7 +185,7 @@ func (d *graphqlDataSourceGenerator) generateSubscriptionClient(httpClient *http
 		graphqlSubscriptionClient = definedOptions.subscriptionClientFactory.NewSubscriptionClient(
 			httpClient,
 			definedOptions.streamingClient,
-			nil,
+			d.engineCtx,
 			graphql_datasource.WithWSSubProtocol(graphql_datasource.ProtocolGraphQLWS),
 		)
 	}
@@ -1,29 +1,39 @@
 package engine
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/buger/jsonparser"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/jensneuse/abstractlogger"
+	"github.com/tidwall/sjson"
 
 	"github.com/wundergraph/graphql-go-tools/execution/graphql"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/introspection_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/postprocess"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/variablesvalidation"
 )
 
 type internalExecutionContext struct {
 	resolveContext *resolve.Context
 	postProcessor  *postprocess.Processor
+	costCalculator *graphql.CostCalculator
 }
 
 func newInternalExecutionContext() *internalExecutionContext {
@@ -53,6 +63,7 @@ func (e *internalExecutionContext) setVariables(variables []byte) {
 
 func (e *internalExecutionContext) reset() {
 	e.resolveContext.Free()
+	e.costCalculator = nil
 }
 
 type ExecutionEngine struct {
@@ -104,6 +115,22 @@ func WithRequestTraceOptions(options resolve.TraceOptions) ExecutionOptions {
 	}
 }
 
+// WithFiles passes the uploaded files of the request to the data sources, which forward them to the origins
+func WithFiles(files []httpclient.File) ExecutionOptions {
+	return func(ctx *internalExecutionContext) {
+		ctx.resolveContext.Files = files
+	}
+}
+
+// WithCostAnalysis rejects the operation if its estimated cost exceeds the maximum cost of the calculator.
+// The estimated and the actual cost are reported in the extensions of the response, e.g. {"extensions":{"cost":{"estimated":12,"actual":5}}}.
+// The cost of subscriptions is only estimated.
+func WithCostAnalysis(calculator *graphql.CostCalculator) ExecutionOptions {
+	return func(ctx *internalExecutionContext) {
+		ctx.costCalculator = calculator
+	}
+}
+
 func NewExecutionEngine(ctx context.Context, logger abstractlogger.Logger, engineConfig Configuration) (*ExecutionEngine, error) {
 	executionPlanCache, err := lru.New(1024)
 	if err != nil {
@@ -145,6 +172,10 @@ func NewExecutionEngine(ctx context.Context, logger abstractlogger.Logger, engin
 }
 
 func (e *ExecutionEngine) Execute(ctx context.Context, operation *graphql.Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptions) error {
+	if err := e.preparePersistedOperation(ctx, operation); err != nil {
+		return err
+	}
+
 	if !operation.IsNormalized() {
 		result, err := operation.Normalize(e.config.schema)
 		if err != nil {
@@ -156,13 +187,16 @@ func (e *ExecutionEngine) Execute(ctx context.Context, operation *graphql.Reques
 		}
 	}
 
-	result, err := operation.ValidateForSchema(e.config.schema)
+	result, err := operation.ValidateForSchema(e.config.schema, e.validationOptions()...)
 	if err != nil {
 		return err
 	}
 	if !result.Valid {
 		return result.Errors
 	}
+	if err = e.coerceVariables(operation); err != nil {
+		return err
+	}
 
 	execContext := e.getExecutionCtx()
 	defer e.putExecutionCtx(execContext)
@@ -173,6 +207,18 @@ func (e *ExecutionEngine) Execute(ctx context.Context, operation *graphql.Reques
 		options[i](execContext)
 	}
 
+	var estimatedCost int
+	if execContext.costCalculator != nil {
+		costResult, err := execContext.costCalculator.Calculate(operation.Document(), e.config.schema.Document())
+		if err != nil {
+			return err
+		}
+		if costResult.Errors != nil && costResult.Errors.Count() > 0 {
+			return costResult.Errors
+		}
+		estimatedCost = costResult.Cost
+	}
+
 	if execContext.resolveContext.TracingOptions.Enable {
 		traceCtx := resolve.SetTraceStart(execContext.resolveContext.Context(), execContext.resolveContext.TracingOptions.EnablePredictableDebugTimings)
 		execContext.setContext(traceCtx)
@@ -203,6 +249,10 @@ func (e *ExecutionEngine) Execute(ctx context.Context, operation *graphql.Reques
 
 	switch p := cachedPlan.(type) {
 	case *plan.SynchronousResponsePlan:
+		if execContext.costCalculator != nil {
+			err = e.resolveWithCost(execContext, operation, p.Response, estimatedCost, writer)
+			break
+		}
 		err = e.resolver.ResolveGraphQLResponse(execContext.resolveContext, p.Response, nil, writer)
 	case *plan.SubscriptionResponsePlan:
 		err = e.resolver.ResolveGraphQLSubscription(execContext.resolveContext, p.Response, writer)
@@ -213,6 +263,77 @@ func (e *ExecutionEngine) Execute(ctx context.Context, operation *graphql.Reques
 	return err
 }
 
+// resolveWithCost resolves the response into a buffer, so the actual cost is calculated from its data
+// and added to the extensions before the response is written
+func (e *ExecutionEngine) resolveWithCost(ctx *internalExecutionContext, operation *graphql.Request, response *resolve.GraphQLResponse, estimatedCost int, writer io.Writer) error {
+	buf := &bytes.Buffer{}
+	if err := e.resolver.ResolveGraphQLResponse(ctx.resolveContext, response, nil, buf); err != nil {
+		return err
+	}
+
+	result := buf.Bytes()
+	data, _, _, err := jsonparser.Get(result, "data")
+	if err != nil && !errors.Is(err, jsonparser.KeyPathNotFoundError) {
+		return err
+	}
+
+	actualCost, err := ctx.costCalculator.ActualCost(operation.Document(), e.config.schema.Document(), data)
+	if err != nil {
+		return err
+	}
+
+	cost := []byte(fmt.Sprintf(`{"estimated":%d,"actual":%d}`, estimatedCost, actualCost))
+	result, err = sjson.SetRawBytes(bytes.TrimRight(result, " \n"), "extensions.cost", cost)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(result)
+	return err
+}
+
+// validationOptions configures the validation of operations with the scalar codecs of the engine
+func (e *ExecutionEngine) validationOptions() []astvalidation.Option {
+	return []astvalidation.Option{astvalidation.WithScalarCodecs(e.config.plannerConfig.ScalarCodecs)}
+}
+
+// coerceVariables validates the variables of the operation and coerces the values of custom scalars
+// with the scalar codecs of the engine, the coerced variables replace the variables of the operation.
+// Literals are coerced as well, as the normalization extracted them into variables.
+func (e *ExecutionEngine) coerceVariables(operation *graphql.Request) error {
+	if e.config.plannerConfig.ScalarCodecs == nil {
+		return nil
+	}
+	variables := operation.Variables
+	if len(variables) == 0 {
+		variables = []byte("{}")
+	}
+	validator := variablesvalidation.NewVariablesValidator(variablesvalidation.WithScalarCodecs(e.config.plannerConfig.ScalarCodecs))
+	coerced, err := validator.ValidateAndCoerce(operation.Document(), e.config.schema.Document(), variables)
+	if err != nil {
+		return graphqlerrors.RequestErrorsFromError(err)
+	}
+	operation.Variables = coerced
+	return nil
+}
+
+// preparePersistedOperation resolves the operation of a persisted query,
+// prepared persisted operations are already normalized and validated
+func (e *ExecutionEngine) preparePersistedOperation(ctx context.Context, operation *graphql.Request) error {
+	if e.config.persistedOperations != nil {
+		return e.config.persistedOperations.Prepare(ctx, operation, e.config.schema, e.validationOptions()...)
+	}
+
+	_, isPersistedQuery, err := operation.PersistedQuery()
+	if err != nil {
+		return graphqlerrors.RequestErrorsFromError(err)
+	}
+	if isPersistedQuery && operation.Query == "" {
+		return graphqlerrors.RequestErrorsFromError(graphql.ErrPersistedQueryNotSupported)
+	}
+	return nil
+}
+
 func (e *ExecutionEngine) getCachedPlan(ctx *internalExecutionContext, operation, definition *ast.Document, operationName string, report *operationreport.Report) plan.Plan {
 
 	hash := pool.Hash64.Get()
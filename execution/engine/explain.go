@@ -0,0 +1,407 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/postprocess"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const (
+	defaultExplainListSize = 10
+
+	explainListItemPathElement = "@"
+)
+
+// FetchKind names used in the QueryPlanExplanation
+const (
+	ExplainedFetchKindSingle           = "single"
+	ExplainedFetchKindEntity           = "entity"
+	ExplainedFetchKindBatchEntity      = "batchEntity"
+	ExplainedFetchKindParallelListItem = "parallelListItem"
+	ExplainedFetchKindParallel         = "parallel"
+	ExplainedFetchKindSerial           = "serial"
+)
+
+// QueryPlanExplanation describes how an operation would be executed, without executing it
+type QueryPlanExplanation struct {
+	// OperationType is the type of the explained operation, e.g. query, mutation, subscription
+	OperationType string `json:"operationType"`
+	// Fetches is the tree of fetches, starting with the fetches of the root fields
+	Fetches []*ExplainedFetch `json:"fetches"`
+	// CriticalPathDepth is the number of sequential round-trips required to load the whole response
+	CriticalPathDepth int `json:"criticalPathDepth"`
+	// Lists contains the estimated fan-out of each list in the response
+	Lists []ExplainedList `json:"lists"`
+	// Fields contains the datasource which owns each field of the operation
+	Fields []ExplainedField `json:"fields"`
+}
+
+type ExplainedFetch struct {
+	// Kind is one of the ExplainedFetchKind constants
+	Kind string `json:"kind"`
+	// Path is the response path at which the fetch is loaded, e.g. "query.topProducts.@"
+	Path string `json:"path"`
+	// DataSourceID is the id of the datasource which is called by the fetch
+	DataSourceID string                    `json:"dataSourceId,omitempty"`
+	RootFields   []resolve.GraphCoordinate `json:"rootFields,omitempty"`
+	// MergedPaths are the response paths of entity fetches which are loaded together with this fetch
+	MergedPaths []string `json:"mergedPaths,omitempty"`
+	// EstimatedEntities is the estimated number of entities loaded by an entity fetch
+	EstimatedEntities int `json:"estimatedEntities,omitempty"`
+	// EstimatedRequests is the estimated number of requests to the datasource, or to all datasources of a parallel or serial fetch
+	EstimatedRequests int `json:"estimatedRequests"`
+	// Fetches are the nested fetches of a parallel or serial fetch
+	Fetches []*ExplainedFetch `json:"fetches,omitempty"`
+	// DependentFetches are loaded after this fetch, as they depend on its data
+	DependentFetches []*ExplainedFetch `json:"dependentFetches,omitempty"`
+}
+
+type ExplainedList struct {
+	// Path is the response path of the list, e.g. "query.topProducts"
+	Path string `json:"path"`
+	// EstimatedItems is the estimated number of items of the list, including the items of all parent lists
+	EstimatedItems int `json:"estimatedItems"`
+	// EntityFetches is the number of entity fetches which are loaded for each item of the list
+	EntityFetches int `json:"entityFetches"`
+	// EstimatedEntities is the estimated number of entities which are loaded for the items of the list
+	EstimatedEntities int `json:"estimatedEntities"`
+}
+
+type ExplainedField struct {
+	// Path is the path of the field in the operation, e.g. "query.me.reviews"
+	Path         string `json:"path"`
+	TypeName     string `json:"typeName"`
+	FieldName    string `json:"fieldName"`
+	DataSourceID string `json:"dataSourceId"`
+	IsRootNode   bool   `json:"isRootNode"`
+	// SelectionReasons explains why the datasource was selected for the field
+	SelectionReasons []string `json:"selectionReasons"`
+}
+
+type explainConfig struct {
+	listSize int
+}
+
+type ExplainOptions func(config *explainConfig)
+
+// WithExplainListSize sets the number of items assumed for each list when estimating the entity fan-out
+func WithExplainListSize(size int) ExplainOptions {
+	return func(config *explainConfig) {
+		if size > 0 {
+			config.listSize = size
+		}
+	}
+}
+
+// Explain plans the operation without executing it and returns the resulting query plan
+// The plan is computed with a dedicated planner, so neither the plan cache nor any datasource is involved
+func (e *ExecutionEngine) Explain(operation *graphql.Request, options ...ExplainOptions) (*QueryPlanExplanation, error) {
+	config := explainConfig{
+		listSize: defaultExplainListSize,
+	}
+	for i := range options {
+		options[i](&config)
+	}
+
+	// planning modifies the operation document, so we plan a copy of the request
+	request := graphql.Request{
+		OperationName: operation.OperationName,
+		Variables:     operation.Variables,
+		Query:         operation.Query,
+		Extensions:    operation.Extensions,
+	}
+
+	if err := e.preparePersistedOperation(context.Background(), &request); err != nil {
+		return nil, err
+	}
+
+	if !request.IsNormalized() {
+		normalizationResult, err := request.Normalize(e.config.schema)
+		if err != nil {
+			return nil, err
+		}
+		if !normalizationResult.Successful {
+			return nil, normalizationResult.Errors
+		}
+	}
+
+	validationResult, err := request.ValidateForSchema(e.config.schema, e.validationOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	if !validationResult.Valid {
+		return nil, validationResult.Errors
+	}
+
+	plannerConfig := e.config.plannerConfig
+	plannerConfig.IncludeInfo = true
+	plannerConfig.IncludeSelectionReasons = true
+	plannerConfig.Debug = plan.DebugConfiguration{}
+
+	planner, err := plan.NewPlanner(plannerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var report operationreport.Report
+	planResult := planner.Plan(request.Document(), e.config.schema.Document(), request.OperationName, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	planResult = postprocess.DefaultProcessor().Process(planResult)
+
+	var response *resolve.GraphQLResponse
+	switch p := planResult.(type) {
+	case *plan.SynchronousResponsePlan:
+		response = p.Response
+	case *plan.SubscriptionResponsePlan:
+		response = p.Response.Response
+	default:
+		return nil, errors.New("explain of operation is not possible")
+	}
+
+	operationType, err := request.OperationType()
+	if err != nil {
+		return nil, err
+	}
+
+	explainer := &queryPlanExplainer{
+		listSize: config.listSize,
+		objects:  make(map[string]explainedObject),
+	}
+
+	explanation := &QueryPlanExplanation{
+		OperationType: ast.OperationType(operationType).Name(),
+		Fields:        explainFields(planner.NodeSuggestions(), plannerConfig.DataSources),
+	}
+
+	rootPath := []string{explanation.OperationType}
+	explainer.collectObjects(response.Data, nil, rootPath, 1)
+	explanation.Fetches = explainer.explainNode(response.Data, rootPath, nil, 1)
+	explainer.addMergedEntityFetchesToLists(explanation.Fetches)
+	explanation.CriticalPathDepth = criticalPathDepth(explanation.Fetches)
+	explanation.Lists = explainer.lists
+
+	return explanation, nil
+}
+
+type queryPlanExplainer struct {
+	listSize int
+	// objects contains the estimated number of items and the response path for each data path of an object
+	objects map[string]explainedObject
+	lists   []ExplainedList
+}
+
+type explainedObject struct {
+	multiplier   int
+	responsePath string
+}
+
+// collectObjects records all objects by their data path,
+// it is used to explain merged entity fetches which are referenced by data path
+func (q *queryPlanExplainer) collectObjects(node resolve.Node, dataPath, responsePath []string, multiplier int) {
+	switch n := node.(type) {
+	case *resolve.Object:
+		dataPath = append(dataPath[:len(dataPath):len(dataPath)], n.Path...)
+		responsePath = append(responsePath[:len(responsePath):len(responsePath)], n.Path...)
+		q.objects[strings.Join(dataPath, ".")] = explainedObject{
+			multiplier:   multiplier,
+			responsePath: strings.Join(responsePath, "."),
+		}
+		for i := range n.Fields {
+			q.collectObjects(n.Fields[i].Value, dataPath, responsePath, multiplier)
+		}
+	case *resolve.Array:
+		dataPath = append(dataPath[:len(dataPath):len(dataPath)], n.Path...)
+		responsePath = append(responsePath[:len(responsePath):len(responsePath)], n.Path...)
+		q.collectObjects(n.Item, dataPath, append(responsePath, explainListItemPathElement), multiplier*q.listSize)
+	}
+}
+
+// explainNode returns the fetches of the node, the fetches of nested nodes are added as dependent fetches
+func (q *queryPlanExplainer) explainNode(node resolve.Node, responsePath []string, fetches []*ExplainedFetch, multiplier int) []*ExplainedFetch {
+	switch n := node.(type) {
+	case *resolve.Object:
+		// the full slice expression prevents siblings from sharing the backing array of the path
+		responsePath = append(responsePath[:len(responsePath):len(responsePath)], n.Path...)
+
+		var nested []*ExplainedFetch
+		for i := range n.Fields {
+			nested = q.explainNode(n.Fields[i].Value, responsePath, nested, multiplier)
+		}
+
+		if n.Fetch == nil {
+			return append(fetches, nested...)
+		}
+
+		fetch := q.explainFetch(n.Fetch, strings.Join(responsePath, "."), multiplier)
+		fetch.DependentFetches = nested
+		return append(fetches, fetch)
+	case *resolve.Array:
+		listPath := append(responsePath[:len(responsePath):len(responsePath)], n.Path...)
+		listIndex := len(q.lists)
+		q.lists = append(q.lists, ExplainedList{
+			Path:           strings.Join(listPath, "."),
+			EstimatedItems: multiplier * q.listSize,
+		})
+
+		itemPath := append(listPath[:len(listPath):len(listPath)], explainListItemPathElement)
+		itemFetches := q.explainNode(n.Item, itemPath, nil, multiplier*q.listSize)
+		for _, fetch := range itemFetches {
+			if isFetchOnListItem(fetch.Path, strings.Join(itemPath, ".")) {
+				q.lists[listIndex].EntityFetches += countEntityFetches(fetch)
+			}
+		}
+		q.lists[listIndex].EstimatedEntities = q.lists[listIndex].EstimatedItems * q.lists[listIndex].EntityFetches
+
+		return append(fetches, itemFetches...)
+	}
+	return fetches
+}
+
+// addMergedEntityFetchesToLists accounts merged entity fetches to the lists they load entities for
+func (q *queryPlanExplainer) addMergedEntityFetchesToLists(fetches []*ExplainedFetch) {
+	for _, fetch := range fetches {
+		for _, mergedPath := range fetch.MergedPaths {
+			for i := range q.lists {
+				if q.lists[i].Path+"."+explainListItemPathElement != mergedPath {
+					continue
+				}
+				q.lists[i].EntityFetches++
+				q.lists[i].EstimatedEntities = q.lists[i].EstimatedItems * q.lists[i].EntityFetches
+			}
+		}
+		q.addMergedEntityFetchesToLists(fetch.Fetches)
+		q.addMergedEntityFetchesToLists(fetch.DependentFetches)
+	}
+}
+
+func (q *queryPlanExplainer) explainFetch(fetch resolve.Fetch, path string, multiplier int) *ExplainedFetch {
+	switch f := fetch.(type) {
+	case *resolve.SingleFetch:
+		return q.explained(ExplainedFetchKindSingle, path, f.Info, 1, 0)
+	case *resolve.EntityFetch:
+		return q.explained(ExplainedFetchKindEntity, path, f.Info, 1, 1)
+	case *resolve.BatchEntityFetch:
+		entities := multiplier
+		mergedPaths := make([]string, 0, len(f.MergedItemPaths))
+		for _, mergedItemPath := range f.MergedItemPaths {
+			object, ok := q.objects[strings.Join(mergedItemPath, ".")]
+			if !ok {
+				continue
+			}
+			mergedPaths = append(mergedPaths, object.responsePath)
+			entities += object.multiplier
+		}
+		explained := q.explained(ExplainedFetchKindBatchEntity, path, f.Info, 1, entities)
+		if len(mergedPaths) > 0 {
+			explained.MergedPaths = mergedPaths
+		}
+		return explained
+	case *resolve.ParallelListItemFetch:
+		// each item of the list is loaded with a separate request
+		return q.explained(ExplainedFetchKindParallelListItem, path, f.Fetch.Info, multiplier, multiplier)
+	case *resolve.ParallelFetch:
+		explained := &ExplainedFetch{Kind: ExplainedFetchKindParallel, Path: path}
+		for i := range f.Fetches {
+			explained.Fetches = append(explained.Fetches, q.explainFetch(f.Fetches[i], path, multiplier))
+			explained.EstimatedRequests += explained.Fetches[i].EstimatedRequests
+		}
+		return explained
+	case *resolve.SerialFetch:
+		explained := &ExplainedFetch{Kind: ExplainedFetchKindSerial, Path: path}
+		for i := range f.Fetches {
+			explained.Fetches = append(explained.Fetches, q.explainFetch(f.Fetches[i], path, multiplier))
+			explained.EstimatedRequests += explained.Fetches[i].EstimatedRequests
+		}
+		return explained
+	}
+	return &ExplainedFetch{Path: path}
+}
+
+func (q *queryPlanExplainer) explained(kind, path string, info *resolve.FetchInfo, requests, entities int) *ExplainedFetch {
+	explained := &ExplainedFetch{
+		Kind:              kind,
+		Path:              path,
+		EstimatedRequests: requests,
+		EstimatedEntities: entities,
+	}
+	if info != nil {
+		explained.DataSourceID = info.DataSourceID
+		explained.RootFields = info.RootFields
+	}
+	return explained
+}
+
+// isFetchOnListItem returns true when the fetch is loaded for the items of the list and not for the items of a nested list
+func isFetchOnListItem(fetchPath, itemPath string) bool {
+	if !strings.HasPrefix(fetchPath, itemPath) {
+		return false
+	}
+	return !strings.Contains(fetchPath[len(itemPath):], "."+explainListItemPathElement)
+}
+
+// countEntityFetches returns the number of entity fetches of the fetch itself, dependent fetches are not counted
+func countEntityFetches(fetch *ExplainedFetch) (count int) {
+	switch fetch.Kind {
+	case ExplainedFetchKindEntity, ExplainedFetchKindBatchEntity, ExplainedFetchKindParallelListItem:
+		return 1
+	case ExplainedFetchKindParallel, ExplainedFetchKindSerial:
+		for i := range fetch.Fetches {
+			count += countEntityFetches(fetch.Fetches[i])
+		}
+	}
+	return count
+}
+
+// criticalPathDepth returns the longest chain of sequential round-trips of the fetches
+func criticalPathDepth(fetches []*ExplainedFetch) (depth int) {
+	for _, fetch := range fetches {
+		depth = max(depth, ownDepth(fetch)+criticalPathDepth(fetch.DependentFetches))
+	}
+	return depth
+}
+
+func ownDepth(fetch *ExplainedFetch) (depth int) {
+	switch fetch.Kind {
+	case ExplainedFetchKindParallel:
+		for i := range fetch.Fetches {
+			depth = max(depth, ownDepth(fetch.Fetches[i]))
+		}
+		return depth
+	case ExplainedFetchKindSerial:
+		for i := range fetch.Fetches {
+			depth += ownDepth(fetch.Fetches[i])
+		}
+		return depth
+	}
+	return 1
+}
+
+func explainFields(suggestions []plan.NodeSuggestion, dataSources []plan.DataSource) []ExplainedField {
+	dataSourceIDs := make(map[plan.DSHash]string, len(dataSources))
+	for i := range dataSources {
+		dataSourceIDs[dataSources[i].Hash()] = dataSources[i].Id()
+	}
+
+	fields := make([]ExplainedField, 0, len(suggestions))
+	for i := range suggestions {
+		fields = append(fields, ExplainedField{
+			Path:             suggestions[i].Path,
+			TypeName:         suggestions[i].TypeName,
+			FieldName:        suggestions[i].FieldName,
+			DataSourceID:     dataSourceIDs[suggestions[i].DataSourceHash],
+			IsRootNode:       suggestions[i].IsRootNode,
+			SelectionReasons: suggestions[i].SelectionReasons,
+		})
+	}
+	return fields
+}
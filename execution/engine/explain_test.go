@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/execution/federationtesting"
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestExecutionEngine_Explain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// subgraph urls are not reachable, explain should not send any request
+	subgraphs := make([]SubgraphConfiguration, 0, 3)
+	for _, upstream := range []federationtesting.Upstream{federationtesting.UpstreamAccounts, federationtesting.UpstreamProducts, federationtesting.UpstreamReviews} {
+		sdl, err := federationtesting.LoadTestingSubgraphSDL(upstream)
+		require.NoError(t, err)
+		subgraphs = append(subgraphs, SubgraphConfiguration{
+			Name: string(upstream),
+			URL:  "http://" + string(upstream) + ".service",
+			SDL:  string(sdl),
+		})
+	}
+
+	config, err := NewFederationEngineConfigFactory(ctx, subgraphs, WithFederationSubscriptionClientFactory(&MockSubscriptionClientFactory{})).BuildEngineConfiguration()
+	require.NoError(t, err)
+
+	engine, err := NewExecutionEngine(ctx, abstractlogger.NoopLogger, config)
+	require.NoError(t, err)
+
+	const (
+		productsID = "1"
+		reviewsID  = "2"
+	)
+
+	t.Run("federated query", func(t *testing.T) {
+		operation := &graphql.Request{
+			Query: `{ first: topProducts(first: 1) { name reviews { body } } all: topProducts { reviews { body } } me { reviews { product { name } } } }`,
+		}
+
+		explanation, err := engine.Explain(operation, WithExplainListSize(5))
+		require.NoError(t, err)
+		assert.False(t, operation.IsNormalized())
+
+		assert.Equal(t, "query", explanation.OperationType)
+		assert.Equal(t, 2, explanation.CriticalPathDepth)
+		assert.Equal(t, []*ExplainedFetch{
+			{
+				Kind:              ExplainedFetchKindParallel,
+				Path:              "query",
+				EstimatedRequests: 2,
+				Fetches: []*ExplainedFetch{
+					{
+						Kind:              ExplainedFetchKindSingle,
+						Path:              "query",
+						DataSourceID:      productsID,
+						RootFields:        []resolve.GraphCoordinate{{TypeName: "Query", FieldName: "topProducts"}},
+						EstimatedRequests: 1,
+					},
+					{
+						Kind:              ExplainedFetchKindSingle,
+						Path:              "query",
+						DataSourceID:      reviewsID,
+						RootFields:        []resolve.GraphCoordinate{{TypeName: "Query", FieldName: "me"}},
+						EstimatedRequests: 1,
+					},
+				},
+				DependentFetches: []*ExplainedFetch{
+					{
+						Kind:              ExplainedFetchKindBatchEntity,
+						Path:              "query.first.@",
+						DataSourceID:      reviewsID,
+						RootFields:        []resolve.GraphCoordinate{{TypeName: "Product", FieldName: "reviews"}},
+						MergedPaths:       []string{"query.all.@"},
+						EstimatedEntities: 10,
+						EstimatedRequests: 1,
+					},
+					{
+						Kind:              ExplainedFetchKindBatchEntity,
+						Path:              "query.me.reviews.@.product",
+						DataSourceID:      productsID,
+						RootFields:        []resolve.GraphCoordinate{{TypeName: "Product", FieldName: "name"}},
+						EstimatedEntities: 5,
+						EstimatedRequests: 1,
+					},
+				},
+			},
+		}, explanation.Fetches)
+
+		assert.Equal(t, []ExplainedList{
+			{Path: "query.first", EstimatedItems: 5, EntityFetches: 1, EstimatedEntities: 5},
+			{Path: "query.first.@.reviews", EstimatedItems: 25},
+			{Path: "query.all", EstimatedItems: 5, EntityFetches: 1, EstimatedEntities: 5},
+			{Path: "query.all.@.reviews", EstimatedItems: 25},
+			{Path: "query.me.reviews", EstimatedItems: 5, EntityFetches: 1, EstimatedEntities: 5},
+		}, explanation.Lists)
+
+		assert.Contains(t, explanation.Fields, ExplainedField{
+			Path:             "query.first.reviews",
+			TypeName:         "Product",
+			FieldName:        "reviews",
+			DataSourceID:     reviewsID,
+			IsRootNode:       true,
+			SelectionReasons: []string{plan.ReasonStage1Unique},
+		})
+		assert.Contains(t, explanation.Fields, ExplainedField{
+			Path:             "query.first.name",
+			TypeName:         "Product",
+			FieldName:        "name",
+			DataSourceID:     productsID,
+			IsRootNode:       true,
+			SelectionReasons: []string{plan.ReasonStage1SameSourceLeafChild},
+		})
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		_, err := engine.Explain(&graphql.Request{
+			Query: `{ topProducts { unknown } }`,
+		})
+		assert.Error(t, err)
+	})
+}
@@ -85,6 +85,11 @@ func TestFederationIntegrationTest(t *testing.T) {
 		assert.Equal(t, `{"data":{"topProducts":[{"name":"Trilby","reviews":[{"body":"A highly effective form of birth control.","author":{"username":"Me"}}]},{"name":"Fedora","reviews":[{"body":"Fedoras are one of the most fashionable hats around and can look great with a variety of outfits.","author":{"username":"Me"}}]}]}}`, string(resp))
 	})
 
+	t.Run("entity fetches of sibling root fields are merged", func(t *testing.T) {
+		resp := gqlClient.Query(ctx, setup.GatewayServer.URL, testQueryPath("queries/multiple_root_fields_same_entities.query"), nil, t)
+		assert.Equal(t, `{"data":{"first":[{"name":"Trilby","reviews":[{"body":"A highly effective form of birth control."}]},{"name":"Fedora","reviews":[{"body":"Fedoras are one of the most fashionable hats around and can look great with a variety of outfits."}]}],"all":[{"name":"Trilby","reviews":[{"body":"A highly effective form of birth control."}]},{"name":"Fedora","reviews":[{"body":"Fedoras are one of the most fashionable hats around and can look great with a variety of outfits."}]}]}}`, string(resp))
+	})
+
 	t.Run("mutation operation with variables", func(t *testing.T) {
 		resp := gqlClient.Query(ctx, setup.GatewayServer.URL, testQueryPath("mutations/mutation_with_variables.query"), queryVariables{
 			"authorID": "3210",
@@ -0,0 +1,369 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const (
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+var ErrEngineClosed = errors.New("the execution engine is closed")
+
+// ConfigurationBuilder builds the configuration of an engine generation.
+// Stateful data sources, e.g. subscription clients, must be created with the given context,
+// it is cancelled once the generation is retired.
+type ConfigurationBuilder func(ctx context.Context) (Configuration, error)
+
+type reloadableEngineOptions struct {
+	drainTimeout             time.Duration
+	subscriptionDrainTimeout time.Duration
+}
+
+type ReloadableEngineOption func(options *reloadableEngineOptions)
+
+// WithDrainTimeout sets the maximum duration to wait for the in-flight requests of a retired generation,
+// requests which take longer are cancelled together with the generation. Defaults to DefaultDrainTimeout.
+func WithDrainTimeout(timeout time.Duration) ReloadableEngineOption {
+	return func(options *reloadableEngineOptions) {
+		options.drainTimeout = timeout
+	}
+}
+
+// WithSubscriptionDrainTimeout sets the duration active subscriptions keep receiving updates from a retired generation.
+// Afterward they are completed, so clients resubscribe and are served by the current generation. Defaults to 0.
+func WithSubscriptionDrainTimeout(timeout time.Duration) ReloadableEngineOption {
+	return func(options *reloadableEngineOptions) {
+		options.subscriptionDrainTimeout = timeout
+	}
+}
+
+// engineGeneration is an execution engine built from one configuration
+type engineGeneration struct {
+	engine *ExecutionEngine
+	schema *graphql.Schema
+	cancel context.CancelFunc
+
+	// mu guards the counters, so no operation is added once the generation is retired
+	mu            sync.Mutex
+	requests      int
+	subscriptions int
+	// retired is closed once the generation is retired,
+	// requestsDrained and subscriptionsDrained are closed once the retired generation has no operations of the kind left
+	retired              chan struct{}
+	requestsDrained      chan struct{}
+	subscriptionsDrained chan struct{}
+}
+
+// ReloadableExecutionEngine executes operations on the current generation of an ExecutionEngine,
+// the configuration could be swapped atomically with Reload without dropping requests.
+//
+// Each generation has its own plan cache, so plans of a previous configuration are never used again.
+// A retired generation is drained in the background: in-flight requests finish on the retired generation,
+// active subscriptions are completed after the subscription drain timeout and finally
+// the context of the generation is cancelled, which shuts down its resolver and stateful data sources.
+type ReloadableExecutionEngine struct {
+	ctx     context.Context
+	logger  abstractlogger.Logger
+	options reloadableEngineOptions
+
+	mu       sync.RWMutex
+	current  *engineGeneration
+	closed   bool
+	retiring sync.WaitGroup
+}
+
+func NewReloadableExecutionEngine(ctx context.Context, logger abstractlogger.Logger, build ConfigurationBuilder, options ...ReloadableEngineOption) (*ReloadableExecutionEngine, error) {
+	r := &ReloadableExecutionEngine{
+		ctx:    ctx,
+		logger: logger,
+		options: reloadableEngineOptions{
+			drainTimeout: DefaultDrainTimeout,
+		},
+	}
+	for i := range options {
+		options[i](&r.options)
+	}
+
+	generation, err := r.newGeneration(build)
+	if err != nil {
+		return nil, err
+	}
+	r.current = generation
+
+	return r, nil
+}
+
+// Reload builds a new generation from the configuration and swaps it with the current generation.
+// If the configuration could not be built, the current generation stays active.
+// Reload returns once the new generation serves requests, the previous generation is retired in the background.
+func (r *ReloadableExecutionEngine) Reload(build ConfigurationBuilder) error {
+	generation, err := r.newGeneration(build)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		generation.cancel()
+		return ErrEngineClosed
+	}
+	previous := r.current
+	r.current = generation
+	r.retiring.Add(1)
+	r.mu.Unlock()
+
+	go r.retire(previous)
+	return nil
+}
+
+// Close retires the current generation and waits until all generations are drained or the context is done.
+func (r *ReloadableExecutionEngine) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		r.retiring.Add(1)
+		go r.retire(r.current)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.retiring.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Schema returns the schema of the current generation
+func (r *ReloadableExecutionEngine) Schema() *graphql.Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current.schema
+}
+
+// Execute executes the operation on the current generation.
+//
+// Subscriptions are not moved to the next generation on Reload. They keep receiving updates from the retired generation
+// for the subscription drain timeout, afterward they are completed and Execute returns without an error.
+// Clients have to resubscribe to be served by the current generation.
+func (r *ReloadableExecutionEngine) Execute(ctx context.Context, operation *graphql.Request, writer resolve.SubscriptionResponseWriter, options ...ExecutionOptions) error {
+	generation, isSubscription, err := r.acquire(ctx, operation)
+	if err != nil {
+		return err
+	}
+
+	if !isSubscription {
+		defer generation.releaseRequest()
+		return generation.engine.Execute(ctx, operation, writer, options...)
+	}
+
+	defer generation.releaseSubscription()
+	err = generation.engine.Execute(ctx, operation, writer, options...)
+	if errors.Is(err, resolve.ErrResolverClosed) && generation.isRetired() {
+		return nil
+	}
+	return err
+}
+
+// ExecuteBatch executes the operations of a batch on the current generation, see ExecutionEngine.ExecuteBatch
+func (r *ReloadableExecutionEngine) ExecuteBatch(ctx context.Context, operations []*graphql.Request, writer io.Writer, options ...ExecutionOptions) (errs []error, err error) {
+	generation, err := r.acquireForRequest()
+	if err != nil {
+		return nil, err
+	}
+	defer generation.releaseRequest()
+
+	return generation.engine.ExecuteBatch(ctx, operations, writer, options...)
+}
+
+// Explain explains the operation with the current generation, see ExecutionEngine.Explain
+func (r *ReloadableExecutionEngine) Explain(operation *graphql.Request, options ...ExplainOptions) (*QueryPlanExplanation, error) {
+	generation, err := r.acquireForRequest()
+	if err != nil {
+		return nil, err
+	}
+	defer generation.releaseRequest()
+
+	return generation.engine.Explain(operation, options...)
+}
+
+func (r *ReloadableExecutionEngine) newGeneration(build ConfigurationBuilder) (*engineGeneration, error) {
+	ctx, cancel := context.WithCancel(r.ctx)
+
+	config, err := build(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	executionEngine, err := NewExecutionEngine(ctx, r.logger, config)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &engineGeneration{
+		engine:               executionEngine,
+		schema:               config.Schema(),
+		cancel:               cancel,
+		retired:              make(chan struct{}),
+		requestsDrained:      make(chan struct{}),
+		subscriptionsDrained: make(chan struct{}),
+	}, nil
+}
+
+// acquire registers the operation with the current generation,
+// subscriptions are tracked separately as they are not drained like requests
+func (r *ReloadableExecutionEngine) acquire(ctx context.Context, operation *graphql.Request) (generation *engineGeneration, isSubscription bool, err error) {
+	generation, err = r.acquireForRequest()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = generation.engine.preparePersistedOperation(ctx, operation); err != nil {
+		generation.releaseRequest()
+		return nil, false, err
+	}
+
+	// invalid operations are executed as requests, so the engine reports the errors
+	operationType, err := operation.OperationType()
+	if err != nil || operationType != graphql.OperationTypeSubscription {
+		return generation, false, nil
+	}
+
+	// a subscription must not be added to a retired generation, as its drain might be over already,
+	// so it is moved to the current generation
+	for !generation.promoteToSubscription() {
+		generation.releaseRequest()
+		if generation, err = r.acquireForRequest(); err != nil {
+			return nil, false, err
+		}
+	}
+	return generation, true, nil
+}
+
+func (r *ReloadableExecutionEngine) acquireForRequest() (*engineGeneration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed || !r.current.acquireRequest() {
+		return nil, ErrEngineClosed
+	}
+	return r.current, nil
+}
+
+// retire drains the generation and cancels its context afterward.
+// No operation is added to the generation anymore, as it was replaced under the lock before.
+func (r *ReloadableExecutionEngine) retire(generation *engineGeneration) {
+	defer r.retiring.Done()
+
+	generation.retire()
+
+	if !waitTimeout(generation.requestsDrained, r.options.drainTimeout) {
+		r.logger.Error("ReloadableExecutionEngine.retire: in-flight requests did not finish within the drain timeout")
+	}
+	if r.options.subscriptionDrainTimeout > 0 {
+		waitTimeout(generation.subscriptionsDrained, r.options.subscriptionDrainTimeout)
+	}
+
+	generation.cancel()
+}
+
+// acquireRequest registers a request, it fails once the generation is retired
+func (g *engineGeneration) acquireRequest() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.isRetired() {
+		return false
+	}
+	g.requests++
+	return true
+}
+
+func (g *engineGeneration) releaseRequest() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.requests--
+	if g.requests == 0 && g.isRetired() {
+		close(g.requestsDrained)
+	}
+}
+
+// promoteToSubscription turns a registered request into a subscription, it fails once the generation is retired
+func (g *engineGeneration) promoteToSubscription() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.isRetired() {
+		return false
+	}
+	g.subscriptions++
+	// the generation isn't retired, so there is no drain to report
+	g.requests--
+	return true
+}
+
+func (g *engineGeneration) releaseSubscription() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.subscriptions--
+	if g.subscriptions == 0 && g.isRetired() {
+		close(g.subscriptionsDrained)
+	}
+}
+
+// retire marks the generation as retired, afterward the drained channels are closed once the counters reach zero
+func (g *engineGeneration) retire() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	close(g.retired)
+	if g.requests == 0 {
+		close(g.requestsDrained)
+	}
+	if g.subscriptions == 0 {
+		close(g.subscriptionsDrained)
+	}
+}
+
+func (g *engineGeneration) isRetired() bool {
+	select {
+	case <-g.retired:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitTimeout waits until done is closed and returns false if the timeout elapsed before
+func waitTimeout(done <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,304 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+)
+
+type reloadTestSubscriptionWriter struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	flushed   chan struct{}
+	completed bool
+}
+
+func (w *reloadTestSubscriptionWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *reloadTestSubscriptionWriter) Flush() error {
+	select {
+	case w.flushed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (w *reloadTestSubscriptionWriter) Complete() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.completed = true
+}
+
+func TestReloadableExecutionEngine(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// newSubgraph returns a subgraph which responds with hello, until release is closed
+	// subscriptions receive a single event and are kept open until the connection is closed
+	newSubgraph := func(t *testing.T, hello string) (url string, received <-chan struct{}, release chan struct{}) {
+		receivedCh := make(chan struct{}, 1)
+		release = make(chan struct{})
+		subgraph := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") == "text/event-stream" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = fmt.Fprint(w, "event: next\ndata: {\"data\":{\"helloChanged\":\"changed\"}}\n\n")
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+				return
+			}
+			select {
+			case receivedCh <- struct{}{}:
+			default:
+			}
+			<-release
+			_, _ = fmt.Fprintf(w, `{"data":{"hello":%q}}`, hello)
+		}))
+		t.Cleanup(subgraph.Close)
+		return subgraph.URL, receivedCh, release
+	}
+
+	// builder builds a proxy configuration and records the contexts of the generations
+	type builder struct {
+		url  string
+		sdl  string
+		ctxs []context.Context
+		mu   sync.Mutex
+	}
+	build := func(b *builder) ConfigurationBuilder {
+		return func(ctx context.Context) (Configuration, error) {
+			b.mu.Lock()
+			b.ctxs = append(b.ctxs, ctx)
+			b.mu.Unlock()
+
+			schema, err := graphql.NewSchemaFromString(b.sdl)
+			if err != nil {
+				return Configuration{}, err
+			}
+			return NewProxyEngineConfigFactory(ctx, schema, ProxyUpstreamConfig{
+				URL:              b.url,
+				Method:           http.MethodPost,
+				SubscriptionType: SubscriptionTypeSSE,
+			}).EngineConfiguration()
+		}
+	}
+	const sdl = `type Query { hello: String } type Subscription { helloChanged: String }`
+
+	execute := func(t *testing.T, engine *ReloadableExecutionEngine, query string) string {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		resultWriter := graphql.NewEngineResultWriterFromBuffer(buf)
+		require.NoError(t, engine.Execute(context.Background(), &graphql.Request{Query: query}, &resultWriter))
+		return buf.String()
+	}
+
+	t.Run("reload swaps the configuration", func(t *testing.T) {
+		firstURL, _, firstRelease := newSubgraph(t, "first")
+		close(firstRelease)
+		secondURL, _, secondRelease := newSubgraph(t, "second")
+		close(secondRelease)
+
+		engine, err := NewReloadableExecutionEngine(ctx, abstractlogger.NoopLogger, build(&builder{url: firstURL, sdl: sdl}))
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hello":"first"}}`, execute(t, engine, `{ hello }`))
+
+		require.NoError(t, engine.Reload(build(&builder{url: secondURL, sdl: `type Query { hello: String world: String }`})))
+		assert.Equal(t, `{"data":{"hello":"second"}}`, execute(t, engine, `{ hello }`))
+		assert.Equal(t, `{"data":{"world":null}}`, execute(t, engine, `{ world }`))
+
+		require.NoError(t, engine.Close(context.Background()))
+	})
+
+	t.Run("failed reload keeps the current generation", func(t *testing.T) {
+		url, _, release := newSubgraph(t, "current")
+		close(release)
+
+		engine, err := NewReloadableExecutionEngine(ctx, abstractlogger.NoopLogger, build(&builder{url: url, sdl: sdl}))
+		require.NoError(t, err)
+
+		buildErr := errors.New("invalid configuration")
+		err = engine.Reload(func(ctx context.Context) (Configuration, error) {
+			return Configuration{}, buildErr
+		})
+		assert.ErrorIs(t, err, buildErr)
+		assert.Equal(t, `{"data":{"hello":"current"}}`, execute(t, engine, `{ hello }`))
+	})
+
+	t.Run("in-flight requests finish on the retired generation", func(t *testing.T) {
+		firstURL, firstReceived, firstRelease := newSubgraph(t, "first")
+		secondURL, _, secondRelease := newSubgraph(t, "second")
+		close(secondRelease)
+
+		first := &builder{url: firstURL, sdl: sdl}
+		engine, err := NewReloadableExecutionEngine(ctx, abstractlogger.NoopLogger, build(first))
+		require.NoError(t, err)
+
+		inFlight := make(chan string)
+		go func() {
+			inFlight <- execute(t, engine, `{ hello }`)
+		}()
+		<-firstReceived
+
+		require.NoError(t, engine.Reload(build(&builder{url: secondURL, sdl: sdl})))
+		assert.Equal(t, `{"data":{"hello":"second"}}`, execute(t, engine, `{ hello }`))
+		assert.NoError(t, first.ctxs[0].Err(), "the retired generation must not be cancelled before it is drained")
+
+		close(firstRelease)
+		assert.Equal(t, `{"data":{"hello":"first"}}`, <-inFlight)
+
+		select {
+		case <-first.ctxs[0].Done():
+		case <-time.After(time.Second):
+			t.Fatal("the retired generation was not cancelled")
+		}
+	})
+
+	t.Run("in-flight requests are cancelled after the drain timeout", func(t *testing.T) {
+		firstURL, firstReceived, firstRelease := newSubgraph(t, "first")
+		defer close(firstRelease)
+		secondURL, _, secondRelease := newSubgraph(t, "second")
+		close(secondRelease)
+
+		first := &builder{url: firstURL, sdl: sdl}
+		engine, err := NewReloadableExecutionEngine(ctx, abstractlogger.NoopLogger, build(first), WithDrainTimeout(10*time.Millisecond))
+		require.NoError(t, err)
+
+		go func() {
+			buf := &bytes.Buffer{}
+			resultWriter := graphql.NewEngineResultWriterFromBuffer(buf)
+			_ = engine.Execute(context.Background(), &graphql.Request{Query: `{ hello }`}, &resultWriter)
+		}()
+		<-firstReceived
+
+		require.NoError(t, engine.Reload(build(&builder{url: secondURL, sdl: sdl})))
+
+		select {
+		case <-first.ctxs[0].Done():
+		case <-time.After(time.Second):
+			t.Fatal("the retired generation was not cancelled after the drain timeout")
+		}
+	})
+
+	t.Run("subscriptions are completed when the generation is retired", func(t *testing.T) {
+		url, _, release := newSubgraph(t, "first")
+		close(release)
+
+		engine, err := NewReloadableExecutionEngine(ctx, abstractlogger.NoopLogger, build(&builder{url: url, sdl: sdl}))
+		require.NoError(t, err)
+
+		writer := &reloadTestSubscriptionWriter{flushed: make(chan struct{}, 1)}
+		subscriptionErr := make(chan error)
+		go func() {
+			subscriptionErr <- engine.Execute(context.Background(), &graphql.Request{Query: `subscription { helloChanged }`}, writer)
+		}()
+
+		select {
+		case <-writer.flushed:
+		case <-time.After(time.Second):
+			t.Fatal("the subscription did not receive an update")
+		}
+
+		require.NoError(t, engine.Reload(build(&builder{url: url, sdl: sdl})))
+
+		select {
+		case err := <-subscriptionErr:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("the subscription was not completed")
+		}
+
+		writer.mu.Lock()
+		defer writer.mu.Unlock()
+		assert.True(t, writer.completed)
+		assert.Equal(t, `{"data":{"helloChanged":"changed"}}`, writer.buf.String())
+	})
+
+	t.Run("closed engine", func(t *testing.T) {
+		url, _, release := newSubgraph(t, "first")
+		close(release)
+
+		b := &builder{url: url, sdl: sdl}
+		engine, err := NewReloadableExecutionEngine(ctx, abstractlogger.NoopLogger, build(b))
+		require.NoError(t, err)
+		require.NoError(t, engine.Close(context.Background()))
+
+		assert.Error(t, b.ctxs[0].Err())
+
+		resultWriter := graphql.NewEngineResultWriter()
+		assert.Equal(t, ErrEngineClosed, engine.Execute(context.Background(), &graphql.Request{Query: `{ hello }`}, &resultWriter))
+		assert.Equal(t, ErrEngineClosed, engine.Reload(build(b)))
+	})
+}
+
+func TestEngineGeneration(t *testing.T) {
+	newGeneration := func() *engineGeneration {
+		return &engineGeneration{
+			retired:              make(chan struct{}),
+			requestsDrained:      make(chan struct{}),
+			subscriptionsDrained: make(chan struct{}),
+		}
+	}
+	isClosed := func(ch chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+
+	t.Run("drained once the operations are released", func(t *testing.T) {
+		generation := newGeneration()
+		require.True(t, generation.acquireRequest())
+		require.True(t, generation.acquireRequest())
+		require.True(t, generation.promoteToSubscription())
+
+		generation.retire()
+		assert.False(t, isClosed(generation.requestsDrained))
+		assert.False(t, isClosed(generation.subscriptionsDrained))
+
+		generation.releaseRequest()
+		assert.True(t, isClosed(generation.requestsDrained))
+		assert.False(t, isClosed(generation.subscriptionsDrained))
+
+		generation.releaseSubscription()
+		assert.True(t, isClosed(generation.subscriptionsDrained))
+	})
+
+	t.Run("retired generation rejects operations", func(t *testing.T) {
+		generation := newGeneration()
+		require.True(t, generation.acquireRequest())
+		generation.retire()
+
+		assert.False(t, generation.acquireRequest())
+		assert.False(t, generation.promoteToSubscription())
+		assert.True(t, isClosed(generation.subscriptionsDrained))
+
+		generation.releaseRequest()
+		assert.True(t, isClosed(generation.requestsDrained))
+	})
+
+	t.Run("empty generation is drained on retire", func(t *testing.T) {
+		generation := newGeneration()
+		generation.retire()
+		assert.True(t, isClosed(generation.requestsDrained))
+		assert.True(t, isClosed(generation.subscriptionsDrained))
+		assert.True(t, waitTimeout(generation.requestsDrained, time.Millisecond))
+		assert.False(t, waitTimeout(make(chan struct{}), time.Millisecond))
+	})
+}
@@ -24,9 +24,11 @@ func (d defaultComplexityCalculator) Calculate(operation, definition *ast.Docume
 }
 
 type ComplexityResult struct {
-	NodeCount    int
-	Complexity   int
-	Depth        int
+	NodeCount  int
+	Complexity int
+	Depth      int
+	// Cost is the estimated cost of the operation, it is only calculated by the CostCalculator
+	Cost         int
 	PerRootField []FieldComplexityResult
 	Errors       graphqlerrors.Errors
 }
@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_complexity"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_cost"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+type CostCalculatorConfig struct {
+	// MaxCost is the maximum estimated cost of an operation, operations with a higher cost are rejected.
+	// A MaxCost of 0 disables the limit.
+	MaxCost int
+	// DefaultListSize is the size of list fields without the @listSize directive, defaults to operation_cost.DefaultListSize
+	DefaultListSize int
+}
+
+// CostCalculator is a ComplexityCalculator which additionally calculates the cost of an operation
+// with the @cost and @listSize directives of the schema, see operation_cost.DirectiveDefinitions.
+// If the estimated cost exceeds the maximum cost, an error is added to the ComplexityResult.
+type CostCalculator struct {
+	maxCost    int
+	calculator *operation_cost.OperationCostCalculator
+}
+
+func NewCostCalculator(config CostCalculatorConfig) *CostCalculator {
+	return &CostCalculator{
+		maxCost: config.MaxCost,
+		calculator: operation_cost.NewOperationCostCalculator(operation_cost.Config{
+			DefaultListSize: config.DefaultListSize,
+		}),
+	}
+}
+
+func (c *CostCalculator) Calculate(operation, definition *ast.Document) (ComplexityResult, error) {
+	report := operationreport.Report{}
+	globalComplexityResult, fieldsComplexityResult := operation_complexity.CalculateOperationComplexity(operation, definition, &report)
+
+	cost := c.calculator.EstimatedCost(operation, definition, "", &report)
+	if c.maxCost > 0 && cost > c.maxCost {
+		report.AddExternalError(operationreport.ExternalError{
+			Message: fmt.Sprintf("the estimated cost %d of the operation exceeds the maximum cost %d", cost, c.maxCost),
+		})
+	}
+
+	result, err := complexityResult(globalComplexityResult, fieldsComplexityResult, report)
+	result.Cost = cost
+	return result, err
+}
+
+// ActualCost calculates the cost of an executed operation from the data of its response
+func (c *CostCalculator) ActualCost(operation, definition *ast.Document, data []byte) (int, error) {
+	report := operationreport.Report{}
+	cost := c.calculator.ActualCost(operation, definition, "", data, &report)
+	if report.HasErrors() {
+		return 0, report
+	}
+	return cost, nil
+}
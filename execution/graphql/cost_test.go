@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_cost"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestCostCalculator(t *testing.T) {
+	schema, err := NewSchemaFromString(operation_cost.DirectiveDefinitions + `
+		type Query {
+			users(first: Int): [User!]! @listSize(slicingArguments: ["first"])
+		}
+		type User @cost(weight: "2") {
+			name: String
+			friends(first: Int): [User!]! @listSize(slicingArguments: ["first"])
+		}
+	`)
+	require.NoError(t, err)
+
+	t.Run("should calculate the estimated cost with variables", func(t *testing.T) {
+		request := Request{
+			Query:     `query Users($first: Int) { users(first: $first) { name friends(first: 2) { name } } }`,
+			Variables: []byte(`{"first":3}`),
+		}
+		result, err := request.CalculateComplexity(NewCostCalculator(CostCalculatorConfig{}), schema)
+		require.NoError(t, err)
+		assert.Nil(t, result.Errors)
+		assert.Equal(t, 18, result.Cost)
+		assert.Equal(t, 2, result.Complexity)
+	})
+
+	t.Run("should add an error if the estimated cost exceeds the maximum cost", func(t *testing.T) {
+		request := Request{
+			Query: `{ users(first: 10) { friends(first: 10) { name } } }`,
+		}
+		result, err := request.CalculateComplexity(NewCostCalculator(CostCalculatorConfig{MaxCost: 100}), schema)
+		require.NoError(t, err)
+		assert.Equal(t, 220, result.Cost)
+		assert.Equal(t, graphqlerrors.RequestErrors{
+			{Message: "the estimated cost 220 of the operation exceeds the maximum cost 100", Locations: []operationreport.Location{}},
+		}, result.Errors)
+	})
+
+	t.Run("should calculate the actual cost from the data", func(t *testing.T) {
+		request := Request{
+			Query: `{ users(first: 10) { name friends(first: 10) { name } } }`,
+		}
+		require.Empty(t, request.parseQueryOnce().ExternalErrors)
+
+		cost, err := NewCostCalculator(CostCalculatorConfig{}).ActualCost(request.Document(), schema.Document(),
+			[]byte(`{"users":[{"name":"a","friends":[{"name":"b"}]},{"name":"b","friends":[]}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, 6, cost)
+	})
+}
@@ -0,0 +1,490 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/tidwall/sjson"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+)
+
+const (
+	persistedQueryVersion = 1
+
+	defaultPersistedOperationsCacheSize = 1024
+	persistedOperationFileExtension     = ".graphql"
+)
+
+var (
+	// ErrPersistedQueryNotFound is returned when a persisted query is sent without a query and the hash is unknown.
+	// The message is defined by the automatic persisted queries protocol, clients retry with the full query.
+	ErrPersistedQueryNotFound = errors.New("PersistedQueryNotFound")
+	// ErrPersistedQueryNotSupported is returned when a persisted query is sent to an engine without a PersistedOperationStore
+	ErrPersistedQueryNotSupported          = errors.New("PersistedQueryNotSupported")
+	ErrPersistedQueryVersionNotSupported   = errors.New("persisted query version is not supported")
+	ErrPersistedQueryInvalidHash           = errors.New("persisted query sha256Hash must be a lowercase hex encoded sha256 hash")
+	ErrPersistedQueryHashMismatch          = errors.New("provided sha256Hash does not match query")
+	ErrOperationNotPersisted               = errors.New("operation is not persisted")
+	ErrInvalidExtensions                   = errors.New("extensions must be a JSON object")
+	ErrPersistedOperationStoreNotAvailable = errors.New("persisted operation store is not configured")
+)
+
+// PersistedQuery is the persistedQuery entry of the request extensions
+type PersistedQuery struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+type requestExtensions struct {
+	PersistedQuery *PersistedQuery `json:"persistedQuery"`
+}
+
+// PersistedQuery returns the persisted query extension of the request, ok is false if the request has none
+func (r *Request) PersistedQuery() (persistedQuery PersistedQuery, ok bool, err error) {
+	if len(r.Extensions) == 0 || string(r.Extensions) == "null" {
+		return PersistedQuery{}, false, nil
+	}
+
+	var extensions requestExtensions
+	if err = json.Unmarshal(r.Extensions, &extensions); err != nil {
+		return PersistedQuery{}, false, ErrInvalidExtensions
+	}
+	if extensions.PersistedQuery == nil {
+		return PersistedQuery{}, false, nil
+	}
+
+	return *extensions.PersistedQuery, true, nil
+}
+
+// Sha256Hash returns the hex encoded sha256 hash of an operation, as used to identify persisted operations
+func Sha256Hash(operation string) string {
+	sum := sha256.Sum256([]byte(operation))
+	return hex.EncodeToString(sum[:])
+}
+
+func isValidSha256Hash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for i := 0; i < len(hash); i++ {
+		c := hash[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// PersistedOperationStore stores operations by the hex encoded sha256 hash of their content
+type PersistedOperationStore interface {
+	// Get returns the operation for a hash, found is false if the hash is unknown
+	Get(ctx context.Context, sha256Hash string) (operation string, found bool, err error)
+	// Set stores an operation, it is used to register automatic persisted queries
+	Set(ctx context.Context, sha256Hash string, operation string) error
+}
+
+// InMemoryPersistedOperationStore keeps persisted operations in memory
+type InMemoryPersistedOperationStore struct {
+	mu         sync.RWMutex
+	operations map[string]string
+}
+
+// NewInMemoryPersistedOperationStore creates a store which contains the given operations
+func NewInMemoryPersistedOperationStore(operations ...string) *InMemoryPersistedOperationStore {
+	store := &InMemoryPersistedOperationStore{
+		operations: make(map[string]string, len(operations)),
+	}
+	for _, operation := range operations {
+		store.operations[Sha256Hash(operation)] = operation
+	}
+	return store
+}
+
+func (s *InMemoryPersistedOperationStore) Get(_ context.Context, sha256Hash string) (operation string, found bool, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	operation, found = s.operations[sha256Hash]
+	return operation, found, nil
+}
+
+func (s *InMemoryPersistedOperationStore) Set(_ context.Context, sha256Hash string, operation string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations[sha256Hash] = operation
+	return nil
+}
+
+// FilePersistedOperationStore reads persisted operations from a directory,
+// each operation is stored in a file named <sha256Hash>.graphql
+type FilePersistedOperationStore struct {
+	dir string
+}
+
+func NewFilePersistedOperationStore(dir string) *FilePersistedOperationStore {
+	return &FilePersistedOperationStore{
+		dir: dir,
+	}
+}
+
+func (s *FilePersistedOperationStore) Get(_ context.Context, sha256Hash string) (operation string, found bool, err error) {
+	// the hash is part of the file path, so it must not contain anything else than hex characters
+	if !isValidSha256Hash(sha256Hash) {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(s.path(sha256Hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(content), true, nil
+}
+
+// Set writes the operation to a temporary file first and renames it afterward,
+// so concurrent readers never see a partially written operation
+func (s *FilePersistedOperationStore) Set(_ context.Context, sha256Hash string, operation string) error {
+	if !isValidSha256Hash(sha256Hash) {
+		return ErrPersistedQueryInvalidHash
+	}
+
+	file, err := os.CreateTemp(s.dir, sha256Hash+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.WriteString(operation); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(sha256Hash))
+}
+
+func (s *FilePersistedOperationStore) path(sha256Hash string) string {
+	return filepath.Join(s.dir, sha256Hash+persistedOperationFileExtension)
+}
+
+type PersistedOperationsConfig struct {
+	Store PersistedOperationStore
+	// EnableAPQ allows clients to register operations by sending the query together with its hash,
+	// see automatic persisted queries (APQ)
+	EnableAPQ bool
+	// AllowListOnly rejects every operation which is not in the store.
+	// Operations sent as full query are allowed when their hash is in the store.
+	// APQ registration is disabled in this mode.
+	AllowListOnly bool
+	// CacheSize is the number of prepared operations which are cached, defaults to 1024
+	CacheSize int
+}
+
+// PersistedOperations resolves persisted operations of requests and caches the normalized
+// and validated operations per hash, so persisted operations skip normalization and validation.
+type PersistedOperations struct {
+	config PersistedOperationsConfig
+	cache  *lru.Cache
+}
+
+func NewPersistedOperations(config PersistedOperationsConfig) (*PersistedOperations, error) {
+	if config.Store == nil {
+		return nil, ErrPersistedOperationStoreNotAvailable
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaultPersistedOperationsCacheSize
+	}
+
+	cache, err := lru.New(config.CacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PersistedOperations{
+		config: config,
+		cache:  cache,
+	}, nil
+}
+
+// Resolve sets the query of a request which only contains a persisted query hash,
+// registers automatic persisted queries and enforces the allow list.
+// Requests which are not persisted are left untouched.
+func (p *PersistedOperations) Resolve(ctx context.Context, request *Request) error {
+	if request.persistedOperationHash != "" {
+		return nil
+	}
+
+	persistedQuery, ok, err := request.PersistedQuery()
+	if err != nil {
+		return graphqlerrors.RequestErrorsFromError(err)
+	}
+
+	if !ok {
+		return p.resolveQuery(ctx, request, false)
+	}
+
+	if persistedQuery.Version != persistedQueryVersion {
+		return graphqlerrors.RequestErrorsFromError(ErrPersistedQueryVersionNotSupported)
+	}
+	if !isValidSha256Hash(persistedQuery.Sha256Hash) {
+		return graphqlerrors.RequestErrorsFromError(ErrPersistedQueryInvalidHash)
+	}
+
+	if request.Query != "" {
+		if Sha256Hash(request.Query) != persistedQuery.Sha256Hash {
+			return graphqlerrors.RequestErrorsFromError(ErrPersistedQueryHashMismatch)
+		}
+		return p.resolveQuery(ctx, request, true)
+	}
+
+	query, found, err := p.config.Store.Get(ctx, persistedQuery.Sha256Hash)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return graphqlerrors.RequestErrorsFromError(ErrPersistedQueryNotFound)
+	}
+
+	// the request might have been parsed before with an empty query
+	request.document.Reset()
+	request.isParsed = false
+	request.isNormalized = false
+	request.validForSchema = nil
+
+	request.Query = query
+	request.persistedOperationHash = persistedQuery.Sha256Hash
+	return nil
+}
+
+// resolveQuery handles requests which contain the full query,
+// hasPersistedQuery is true if the request contains the persisted query extension as well
+func (p *PersistedOperations) resolveQuery(ctx context.Context, request *Request, hasPersistedQuery bool) error {
+	if !hasPersistedQuery && !p.config.AllowListOnly {
+		return nil
+	}
+	if request.Query == "" {
+		return graphqlerrors.RequestErrorsFromError(ErrOperationNotPersisted)
+	}
+
+	hash := Sha256Hash(request.Query)
+	_, found, err := p.config.Store.Get(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case found:
+	case p.config.AllowListOnly:
+		return graphqlerrors.RequestErrorsFromError(ErrOperationNotPersisted)
+	case !p.config.EnableAPQ:
+		return nil
+	default:
+		if err = p.config.Store.Set(ctx, hash, request.Query); err != nil {
+			return err
+		}
+	}
+
+	request.persistedOperationHash = hash
+	return nil
+}
+
+type preparedOperationKey struct {
+	sha256Hash    string
+	operationName string
+	schemaHash    uint64
+}
+
+// preparedOperation is a normalized and validated persisted operation.
+// The printed operation is cached instead of the document, because the document is modified during planning.
+type preparedOperation struct {
+	query string
+	// variables contains the extracted literals and the default values of the variables
+	variables []byte
+	// variableNames are the names of the variables defined by the operation,
+	// only these are taken from the variables of a request
+	variableNames []string
+}
+
+// Prepare resolves the persisted operation of a request and sets its normalized and validated document.
+// The prepared operation is cached per hash, operation name and schema.
+// Requests which are not persisted are left untouched and have to be normalized and validated as usual.
+// The options are passed to the validation of the operation, see Request.ValidateForSchema.
+func (p *PersistedOperations) Prepare(ctx context.Context, request *Request, schema *Schema, options ...astvalidation.Option) error {
+	if schema == nil {
+		return ErrNilSchema
+	}
+
+	if err := p.Resolve(ctx, request); err != nil {
+		return err
+	}
+	if request.persistedOperationHash == "" || request.isNormalized {
+		return nil
+	}
+
+	key := preparedOperationKey{
+		sha256Hash:    request.persistedOperationHash,
+		operationName: request.OperationName,
+		schemaHash:    schema.Hash(),
+	}
+
+	var prepared *preparedOperation
+	if cached, ok := p.cache.Get(key); ok {
+		prepared = cached.(*preparedOperation)
+	} else {
+		var err error
+		if prepared, err = prepareOperation(request, schema, options...); err != nil {
+			return err
+		}
+		p.cache.Add(key, prepared)
+	}
+
+	return request.applyPreparedOperation(prepared, schema)
+}
+
+func prepareOperation(request *Request, schema *Schema, options ...astvalidation.Option) (*preparedOperation, error) {
+	// the operation is normalized without the variables of the request,
+	// so all default values end up in the variables of the prepared operation
+	operation := Request{
+		OperationName: request.OperationName,
+		Query:         request.Query,
+	}
+
+	report := operation.parseQueryOnce()
+	if report.HasErrors() {
+		result, err := NormalizationResultFromReport(report)
+		if err != nil {
+			return nil, err
+		}
+		return nil, result.Errors
+	}
+
+	variableNames := operation.variableNames()
+
+	normalizationResult, err := operation.Normalize(schema)
+	if err != nil {
+		return nil, err
+	}
+	if !normalizationResult.Successful {
+		return nil, normalizationResult.Errors
+	}
+
+	validationResult, err := operation.ValidateForSchema(schema, options...)
+	if err != nil {
+		return nil, err
+	}
+	if !validationResult.Valid {
+		return nil, validationResult.Errors
+	}
+
+	query, err := astprinter.PrintString(&operation.document, &schema.document)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preparedOperation{
+		query:         query,
+		variables:     operation.Variables,
+		variableNames: variableNames,
+	}, nil
+}
+
+// variableNames returns the names of the variables defined by the operation of the request
+func (r *Request) variableNames() []string {
+	var names []string
+	for _, rootNode := range r.document.RootNodes {
+		if rootNode.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		if r.OperationName != "" && r.document.OperationDefinitionNameString(rootNode.Ref) != r.OperationName {
+			continue
+		}
+
+		operationDefinition := r.document.OperationDefinitions[rootNode.Ref]
+		if !operationDefinition.HasVariableDefinitions {
+			return names
+		}
+		for _, ref := range operationDefinition.VariableDefinitions.Refs {
+			names = append(names, r.document.VariableDefinitionNameString(ref))
+		}
+		return names
+	}
+	return names
+}
+
+func (r *Request) applyPreparedOperation(prepared *preparedOperation, schema *Schema) error {
+	variables, err := prepared.mergeVariables(r.Variables)
+	if err != nil {
+		return err
+	}
+
+	document, report := astparser.ParseGraphqlDocumentString(prepared.query)
+	if report.HasErrors() {
+		return report
+	}
+
+	document.Input.Variables = variables
+	astnormalization.NewVariablesValuesNormalizer().NormalizeOperation(&document, &schema.document, &report)
+	if report.HasErrors() {
+		result, err := NormalizationResultFromReport(report)
+		if err != nil {
+			return err
+		}
+		return result.Errors
+	}
+
+	r.document = document
+	r.isParsed = true
+	r.isNormalized = true
+	r.Variables = r.document.Input.Variables
+	r.validForSchema = map[uint64]ValidationResult{
+		schema.Hash(): {Valid: true},
+	}
+	return nil
+}
+
+// mergeVariables sets the values of the operation variables provided by the request
+// on top of the extracted literals and default values of the prepared operation
+func (p *preparedOperation) mergeVariables(requestVariables []byte) ([]byte, error) {
+	variables := make([]byte, len(p.variables))
+	copy(variables, p.variables)
+
+	if len(requestVariables) == 0 || string(requestVariables) == "null" {
+		return variables, nil
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(requestVariables, &values); err != nil {
+		return nil, graphqlerrors.RequestErrorsFromError(fmt.Errorf("variables must be a JSON object: %w", err))
+	}
+
+	var err error
+	for _, name := range p.variableNames {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if variables, err = sjson.SetRawBytes(variables, name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,340 @@
+package graphql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+)
+
+func persistedOperationsSchema(t *testing.T) *Schema {
+	schema, err := NewSchemaFromString(`
+		schema {
+			query: Query
+		}
+
+		type Character {
+			id: Int
+			name: String
+		}
+
+		input Filter {
+			name: String
+			limit: Int = 5
+		}
+
+		type Query {
+			characters(ids: [Int], first: Int, filter: Filter): [Character]
+		}`)
+	require.NoError(t, err)
+	return schema
+}
+
+func persistedQueryExtensions(query string) []byte {
+	return []byte(`{"persistedQuery":{"version":1,"sha256Hash":"` + Sha256Hash(query) + `"}}`)
+}
+
+func TestRequest_PersistedQuery(t *testing.T) {
+	t.Run("without extensions", func(t *testing.T) {
+		_, ok, err := (&Request{Query: "{ hello }"}).PersistedQuery()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("with persisted query", func(t *testing.T) {
+		persistedQuery, ok, err := (&Request{Extensions: []byte(`{"persistedQuery":{"version":1,"sha256Hash":"abc"}}`)}).PersistedQuery()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, PersistedQuery{Version: 1, Sha256Hash: "abc"}, persistedQuery)
+	})
+
+	t.Run("with other extensions", func(t *testing.T) {
+		_, ok, err := (&Request{Extensions: []byte(`{"tracing":true}`)}).PersistedQuery()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("with invalid extensions", func(t *testing.T) {
+		_, _, err := (&Request{Extensions: []byte(`[]`)}).PersistedQuery()
+		assert.Equal(t, ErrInvalidExtensions, err)
+	})
+}
+
+func TestFilePersistedOperationStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	query := "{ characters { name } }"
+	hash := Sha256Hash(query)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, hash+".graphql"), []byte(query), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.graphql"), []byte("secret"), 0o644))
+
+	store := NewFilePersistedOperationStore(dir)
+
+	t.Run("get", func(t *testing.T) {
+		operation, found, err := store.Get(ctx, hash)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, query, operation)
+	})
+
+	t.Run("get unknown hash", func(t *testing.T) {
+		_, found, err := store.Get(ctx, Sha256Hash("{ unknown }"))
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("get rejects hashes which are no sha256 hashes", func(t *testing.T) {
+		_, found, err := store.Get(ctx, "secret")
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		_, found, err = store.Get(ctx, "../"+hash)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		other := "{ characters { id } }"
+		require.NoError(t, store.Set(ctx, Sha256Hash(other), other))
+
+		operation, found, err := NewFilePersistedOperationStore(dir).Get(ctx, Sha256Hash(other))
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, other, operation)
+
+		assert.Equal(t, ErrPersistedQueryInvalidHash, store.Set(ctx, "../other", other))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+	})
+}
+
+func TestPersistedOperations_Resolve(t *testing.T) {
+	ctx := context.Background()
+	query := "{ characters { name } }"
+	otherQuery := "{ characters { id } }"
+
+	newPersistedOperations := func(t *testing.T, config PersistedOperationsConfig) *PersistedOperations {
+		if config.Store == nil {
+			config.Store = NewInMemoryPersistedOperationStore(query)
+		}
+		persistedOperations, err := NewPersistedOperations(config)
+		require.NoError(t, err)
+		return persistedOperations
+	}
+
+	t.Run("requires a store", func(t *testing.T) {
+		_, err := NewPersistedOperations(PersistedOperationsConfig{})
+		assert.Equal(t, ErrPersistedOperationStoreNotAvailable, err)
+	})
+
+	t.Run("resolves a persisted query by hash", func(t *testing.T) {
+		request := Request{Extensions: persistedQueryExtensions(query)}
+		require.NoError(t, newPersistedOperations(t, PersistedOperationsConfig{}).Resolve(ctx, &request))
+		assert.Equal(t, query, request.Query)
+	})
+
+	t.Run("resolves a persisted query after the empty query was parsed", func(t *testing.T) {
+		request := Request{Extensions: persistedQueryExtensions(query)}
+		operationType, err := request.OperationType()
+		require.NoError(t, err)
+		assert.Equal(t, OperationTypeUnknown, operationType)
+
+		require.NoError(t, newPersistedOperations(t, PersistedOperationsConfig{}).Resolve(ctx, &request))
+
+		operationType, err = request.OperationType()
+		require.NoError(t, err)
+		assert.Equal(t, OperationTypeQuery, operationType)
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		request := Request{Extensions: persistedQueryExtensions(otherQuery)}
+		err := newPersistedOperations(t, PersistedOperationsConfig{EnableAPQ: true}).Resolve(ctx, &request)
+		assert.Equal(t, graphqlerrors.RequestErrors{{Message: "PersistedQueryNotFound"}}, err)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		request := Request{Extensions: []byte(`{"persistedQuery":{"version":2,"sha256Hash":"` + Sha256Hash(query) + `"}}`)}
+		err := newPersistedOperations(t, PersistedOperationsConfig{}).Resolve(ctx, &request)
+		assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrPersistedQueryVersionNotSupported), err)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		request := Request{Extensions: []byte(`{"persistedQuery":{"version":1,"sha256Hash":"../secret"}}`)}
+		err := newPersistedOperations(t, PersistedOperationsConfig{}).Resolve(ctx, &request)
+		assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrPersistedQueryInvalidHash), err)
+	})
+
+	t.Run("hash does not match the query", func(t *testing.T) {
+		request := Request{Query: otherQuery, Extensions: persistedQueryExtensions(query)}
+		err := newPersistedOperations(t, PersistedOperationsConfig{EnableAPQ: true}).Resolve(ctx, &request)
+		assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrPersistedQueryHashMismatch), err)
+	})
+
+	t.Run("registers automatic persisted queries", func(t *testing.T) {
+		persistedOperations := newPersistedOperations(t, PersistedOperationsConfig{EnableAPQ: true})
+
+		request := Request{Query: otherQuery, Extensions: persistedQueryExtensions(otherQuery)}
+		require.NoError(t, persistedOperations.Resolve(ctx, &request))
+
+		request = Request{Extensions: persistedQueryExtensions(otherQuery)}
+		require.NoError(t, persistedOperations.Resolve(ctx, &request))
+		assert.Equal(t, otherQuery, request.Query)
+	})
+
+	t.Run("does not register queries without APQ", func(t *testing.T) {
+		persistedOperations := newPersistedOperations(t, PersistedOperationsConfig{})
+
+		request := Request{Query: otherQuery, Extensions: persistedQueryExtensions(otherQuery)}
+		require.NoError(t, persistedOperations.Resolve(ctx, &request))
+		assert.Empty(t, request.persistedOperationHash)
+
+		request = Request{Extensions: persistedQueryExtensions(otherQuery)}
+		assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrPersistedQueryNotFound), persistedOperations.Resolve(ctx, &request))
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		persistedOperations := newPersistedOperations(t, PersistedOperationsConfig{AllowListOnly: true, EnableAPQ: true})
+
+		t.Run("allows persisted operations sent as query", func(t *testing.T) {
+			request := Request{Query: query}
+			require.NoError(t, persistedOperations.Resolve(ctx, &request))
+			assert.Equal(t, Sha256Hash(query), request.persistedOperationHash)
+		})
+
+		t.Run("rejects operations which are not persisted", func(t *testing.T) {
+			request := Request{Query: otherQuery}
+			assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrOperationNotPersisted), persistedOperations.Resolve(ctx, &request))
+		})
+
+		t.Run("rejects registration of automatic persisted queries", func(t *testing.T) {
+			request := Request{Query: otherQuery, Extensions: persistedQueryExtensions(otherQuery)}
+			assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrOperationNotPersisted), persistedOperations.Resolve(ctx, &request))
+
+			request = Request{Extensions: persistedQueryExtensions(otherQuery)}
+			assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrPersistedQueryNotFound), persistedOperations.Resolve(ctx, &request))
+		})
+
+		t.Run("rejects empty requests", func(t *testing.T) {
+			assert.Equal(t, graphqlerrors.RequestErrorsFromError(ErrOperationNotPersisted), persistedOperations.Resolve(ctx, &Request{}))
+		})
+	})
+}
+
+func TestPersistedOperations_Prepare(t *testing.T) {
+	ctx := context.Background()
+	schema := persistedOperationsSchema(t)
+	query := `query Characters($ids: [Int], $first: Int = 3, $filter: Filter) {
+		characters(ids: $ids, first: $first, filter: $filter) { name }
+		byId: characters(ids: 7) { id }
+	}`
+
+	persistedOperations, err := NewPersistedOperations(PersistedOperationsConfig{
+		Store: NewInMemoryPersistedOperationStore(query),
+	})
+	require.NoError(t, err)
+
+	prepare := func(t *testing.T, variables string) *Request {
+		t.Helper()
+		request := &Request{
+			OperationName: "Characters",
+			Variables:     []byte(variables),
+			Extensions:    persistedQueryExtensions(query),
+		}
+		require.NoError(t, persistedOperations.Prepare(ctx, request, schema))
+		assert.True(t, request.IsNormalized())
+		return request
+	}
+
+	expectedOperation := `query Characters($ids: [Int], $first: Int, $filter: Filter, $a: [Int]){
+    characters(ids: $ids, first: $first, filter: $filter){
+        name
+    }
+    byId: characters(ids: $a){
+        id
+    }
+}`
+
+	t.Run("prepares the operation like the regular normalization", func(t *testing.T) {
+		variables := `{"ids":1,"filter":{"name":"Luke"}}`
+		request := prepare(t, variables)
+
+		regular := Request{OperationName: "Characters", Variables: []byte(variables), Query: query}
+		normalizationResult, err := regular.Normalize(schema)
+		require.NoError(t, err)
+		require.True(t, normalizationResult.Successful)
+
+		printed, err := astprinter.PrintStringIndent(request.Document(), nil, "  ")
+		require.NoError(t, err)
+		assert.Equal(t, expectedOperation, printed)
+
+		regularPrinted, err := astprinter.PrintStringIndent(regular.Document(), nil, "  ")
+		require.NoError(t, err)
+		assert.Equal(t, regularPrinted, printed)
+
+		assert.JSONEq(t, string(regular.Variables), string(request.Variables))
+		assert.JSONEq(t, `{"a":[7],"first":3,"ids":[1],"filter":{"name":"Luke","limit":5}}`, string(request.Variables))
+
+		validationResult, err := request.ValidateForSchema(schema)
+		require.NoError(t, err)
+		assert.True(t, validationResult.Valid)
+	})
+
+	t.Run("uses the cached operation with the variables of the request", func(t *testing.T) {
+		request := prepare(t, `{"first":1}`)
+		assert.Equal(t, 1, persistedOperations.cache.Len())
+		assert.JSONEq(t, `{"a":[7],"first":1}`, string(request.Variables))
+
+		printed, err := astprinter.PrintStringIndent(request.Document(), nil, "  ")
+		require.NoError(t, err)
+		assert.Equal(t, expectedOperation, printed)
+	})
+
+	t.Run("variables of the request could not override extracted literals", func(t *testing.T) {
+		request := prepare(t, `{"a":[1,2,3],"unknown":true}`)
+		assert.JSONEq(t, `{"a":[7],"first":3}`, string(request.Variables))
+	})
+
+	t.Run("explicit null overrides the default value", func(t *testing.T) {
+		request := prepare(t, `{"first":null}`)
+		assert.JSONEq(t, `{"a":[7],"first":null}`, string(request.Variables))
+	})
+
+	t.Run("invalid variables", func(t *testing.T) {
+		request := &Request{
+			OperationName: "Characters",
+			Variables:     []byte(`[]`),
+			Extensions:    persistedQueryExtensions(query),
+		}
+		err := persistedOperations.Prepare(ctx, request, schema)
+		assert.IsType(t, graphqlerrors.RequestErrors{}, err)
+	})
+
+	t.Run("invalid operations are not cached", func(t *testing.T) {
+		invalidQuery := "{ unknown }"
+		persistedOperations, err := NewPersistedOperations(PersistedOperationsConfig{
+			Store: NewInMemoryPersistedOperationStore(invalidQuery),
+		})
+		require.NoError(t, err)
+
+		request := &Request{Extensions: persistedQueryExtensions(invalidQuery)}
+		err = persistedOperations.Prepare(ctx, request, schema)
+		require.IsType(t, graphqlerrors.RequestErrors{}, err)
+		assert.Equal(t, "field: unknown not defined on type: Query", err.(graphqlerrors.RequestErrors)[0].Message)
+		assert.Equal(t, 0, persistedOperations.cache.Len())
+	})
+
+	t.Run("requests which are not persisted are left untouched", func(t *testing.T) {
+		request := &Request{Query: "{ characters { name } }"}
+		require.NoError(t, persistedOperations.Prepare(ctx, request, schema))
+		assert.False(t, request.IsNormalized())
+	})
+}
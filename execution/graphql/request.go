@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
@@ -27,8 +28,13 @@ const (
 	OperationTypeSubscription OperationType = OperationType(ast.OperationTypeSubscription)
 )
 
+// ParseLimits restricts the size of queries to protect against maliciously large documents, see astparser.Limits.
+// A limit of 0 disables the check.
+type ParseLimits = astparser.Limits
+
 var (
 	ErrEmptyRequest = errors.New("the provided request is empty")
+	ErrEmptyBatch   = errors.New("the batch does not contain any operation")
 	ErrNilSchema    = errors.New("the provided schema is nil")
 )
 
@@ -36,11 +42,16 @@ type Request struct {
 	OperationName string          `json:"operationName"`
 	Variables     json.RawMessage `json:"variables,omitempty"`
 	Query         string          `json:"query"`
+	Extensions    json.RawMessage `json:"extensions,omitempty"`
 
 	document     ast.Document
 	isParsed     bool
 	isNormalized bool
 	request      resolve.Request
+	parseLimits  ParseLimits
+
+	// persistedOperationHash is set once the request was resolved against a PersistedOperationStore
+	persistedOperationHash string
 
 	validForSchema map[uint64]ValidationResult
 }
@@ -63,6 +74,53 @@ func UnmarshalHttpRequest(r *http.Request, request *Request) error {
 	return UnmarshalRequest(r.Body, request)
 }
 
+// UnmarshalBatchRequest unmarshals either a single request or a batch of requests sent as JSON array.
+// isBatch reports whether the requests were sent as JSON array, so that the results can be returned in the same shape.
+func UnmarshalBatchRequest(reader io.Reader) (requests []*Request, isBatch bool, err error) {
+	requestBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	requestBytes = bytes.TrimSpace(requestBytes)
+	if len(requestBytes) == 0 {
+		return nil, false, ErrEmptyRequest
+	}
+
+	if requestBytes[0] != '[' {
+		request := &Request{}
+		if err = json.Unmarshal(requestBytes, request); err != nil {
+			return nil, false, err
+		}
+		return []*Request{request}, false, nil
+	}
+
+	if err = json.Unmarshal(requestBytes, &requests); err != nil {
+		return nil, true, err
+	}
+	if len(requests) == 0 {
+		return nil, true, ErrEmptyBatch
+	}
+	for i := range requests {
+		if requests[i] == nil {
+			return nil, true, ErrEmptyRequest
+		}
+	}
+
+	return requests, true, nil
+}
+
+func UnmarshalHttpBatchRequest(r *http.Request) (requests []*Request, isBatch bool, err error) {
+	requests, isBatch, err = UnmarshalBatchRequest(r.Body)
+	if err != nil {
+		return nil, isBatch, err
+	}
+	for i := range requests {
+		requests[i].request.Header = r.Header
+	}
+	return requests, isBatch, nil
+}
+
 func (r *Request) SetHeader(header http.Header) {
 	r.request.Header = header
 }
@@ -81,9 +139,19 @@ func (r *Request) CalculateComplexity(complexityCalculator ComplexityCalculator,
 		)
 	}
 
+	if !r.isNormalized {
+		r.document.Input.Variables = r.Variables
+	}
+
 	return complexityCalculator.Calculate(&r.document, &schema.document)
 }
 
+// SetParseLimits sets the limits the query is parsed with, queries exceeding the limits are rejected with an error.
+// The limits must be set before the request is parsed, e.g. right after it was unmarshalled.
+func (r *Request) SetParseLimits(limits ParseLimits) {
+	r.parseLimits = limits
+}
+
 func (r *Request) Document() *ast.Document {
 	return &r.document
 }
@@ -110,7 +178,7 @@ func (r *Request) parseQueryOnce() (report operationreport.Report) {
 		return report
 	}
 
-	r.document, report = astparser.ParseGraphqlDocumentString(r.Query)
+	r.document, report = astparser.ParseGraphqlDocumentStringWithLimits(r.Query, r.parseLimits)
 	if !report.HasErrors() {
 		// If the given query has problems, and we failed to parse it,
 		// we shouldn't mark it as parsed. It can be misleading for
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
 )
@@ -35,6 +36,42 @@ func TestUnmarshalRequest(t *testing.T) {
 	})
 }
 
+func TestUnmarshalBatchRequest(t *testing.T) {
+	t.Run("should return error when request is empty", func(t *testing.T) {
+		_, _, err := UnmarshalBatchRequest(strings.NewReader("  "))
+		assert.Equal(t, ErrEmptyRequest, err)
+	})
+
+	t.Run("should return error when batch is empty", func(t *testing.T) {
+		_, isBatch, err := UnmarshalBatchRequest(strings.NewReader("[]"))
+		assert.True(t, isBatch)
+		assert.Equal(t, ErrEmptyBatch, err)
+	})
+
+	t.Run("should return error when batch contains null", func(t *testing.T) {
+		_, _, err := UnmarshalBatchRequest(strings.NewReader(`[{"query":"{ hello }"},null]`))
+		assert.Equal(t, ErrEmptyRequest, err)
+	})
+
+	t.Run("should unmarshal single request", func(t *testing.T) {
+		requests, isBatch, err := UnmarshalBatchRequest(strings.NewReader(`{"operationName": "Hello", "query": "query Hello { hello }"}`))
+		require.NoError(t, err)
+		assert.False(t, isBatch)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "Hello", requests[0].OperationName)
+	})
+
+	t.Run("should unmarshal batch", func(t *testing.T) {
+		requests, isBatch, err := UnmarshalBatchRequest(strings.NewReader(` [{"query": "{ hello }"}, {"query": "query Hi($a: Int) { hi(a: $a) }", "variables": {"a": 1}}]`))
+		require.NoError(t, err)
+		assert.True(t, isBatch)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "{ hello }", requests[0].Query)
+		assert.Equal(t, "query Hi($a: Int) { hi(a: $a) }", requests[1].Query)
+		assert.Equal(t, `{"a": 1}`, string(requests[1].Variables))
+	})
+}
+
 func TestRequest_Print(t *testing.T) {
 	query := "query Hello { hello }"
 	request := Request{
@@ -149,6 +186,15 @@ func TestRequest_CalculateComplexity(t *testing.T) {
 	})
 }
 
+func TestRequest_SetParseLimits(t *testing.T) {
+	request := Request{Query: `{ hero { friends { friends { name } } } }`}
+	request.SetParseLimits(ParseLimits{MaxDepth: 3})
+
+	_, err := request.OperationType()
+	assert.EqualError(t, err, "external: the document exceeds the maximum depth of 3, locations: [{Line:1 Column:28}], path: []")
+	assert.False(t, request.isParsed)
+}
+
 func TestRequest_IsIntrospectionQuery(t *testing.T) {
 	run := func(queryPayload string, expectedIsIntrospection bool) func(t *testing.T) {
 		return func(t *testing.T) {
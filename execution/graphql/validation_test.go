@@ -8,8 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
 )
 
@@ -95,6 +97,17 @@ func TestRequest_ValidateForSchema(t *testing.T) {
 		assert.True(t, result.Valid)
 		assert.Nil(t, result.Errors)
 	})
+
+	t.Run("should validate literals of custom scalars with the scalar codecs", func(t *testing.T) {
+		schema, err := NewSchemaFromString("scalar DateTime schema { query: Query } type Query { events(after: DateTime): String }")
+		require.NoError(t, err)
+
+		request := Request{Query: `{ events(after: "yesterday") }`}
+		result, err := request.ValidateForSchema(schema, astvalidation.WithScalarCodecs(scalars.NewDefaultRegistry()))
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, `Expected value of type "DateTime", found "yesterday"; Expected an RFC 3339 date-time string.`, result.Errors.(graphqlerrors.RequestErrors)[0].Message)
+	})
 }
 
 func TestRequest_ValidateRestrictedFields(t *testing.T) {
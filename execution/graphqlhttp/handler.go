@@ -0,0 +1,317 @@
+// Package graphqlhttp implements a GraphQL-over-HTTP handler for the execution engine.
+//
+// The handler follows the GraphQL-over-HTTP specification:
+//   - queries could be sent via GET with url encoded parameters or via POST with a JSON body
+//   - mutations are only allowed via POST
+//   - the response media type is negotiated between application/graphql-response+json and application/json
+//   - the status code depends on the negotiated media type and on the kind of error
+//   - persisted queries could be sent without a query, when the handler is configured with persisted operations
+//   - files could be uploaded via multipart requests, when the handler is configured with uploads
+//   - multiple operations could be sent as JSON array via POST, when the handler is configured with batching
+//
+// See https://graphql.github.io/graphql-over-http/draft/
+package graphqlhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/execution/engine"
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+)
+
+const (
+	defaultMaxRequestBodySize int64 = 8 << 20
+)
+
+// Executor executes a GraphQL request, it is implemented by engine.ExecutionEngine
+type Executor interface {
+	Execute(ctx context.Context, operation *graphql.Request, writer resolve.SubscriptionResponseWriter, options ...engine.ExecutionOptions) error
+}
+
+// BatchExecutor executes batched GraphQL requests, it is implemented by engine.ExecutionEngine
+type BatchExecutor interface {
+	ExecuteBatch(ctx context.Context, operations []*graphql.Request, writer io.Writer, options ...engine.ExecutionOptions) (errs []error, err error)
+}
+
+type Handler struct {
+	executor            Executor
+	logger              abstractlogger.Logger
+	maxRequestBodySize  int64
+	executionOptions    func(r *http.Request) []engine.ExecutionOptions
+	persistedOperations *graphql.PersistedOperations
+	uploadConfig        *UploadConfig
+	batchExecutor       BatchExecutor
+	parseLimits         graphql.ParseLimits
+}
+
+type Option func(h *Handler)
+
+// WithLogger sets the logger which is used to log errors, which could not be returned to the client
+func WithLogger(logger abstractlogger.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithMaxRequestBodySize limits the size of POST request bodies, larger requests are rejected with 413
+func WithMaxRequestBodySize(size int64) Option {
+	return func(h *Handler) {
+		h.maxRequestBodySize = size
+	}
+}
+
+// WithExecutionOptions sets a function which returns the execution options for a request,
+// e.g. to forward headers or to enable tracing
+func WithExecutionOptions(fn func(r *http.Request) []engine.ExecutionOptions) Option {
+	return func(h *Handler) {
+		h.executionOptions = fn
+	}
+}
+
+// WithPersistedOperations resolves persisted queries before the operation type is determined,
+// so persisted mutations are rejected for GET requests as well.
+// The same PersistedOperations should be configured for the executor.
+func WithPersistedOperations(persistedOperations *graphql.PersistedOperations) Option {
+	return func(h *Handler) {
+		h.persistedOperations = persistedOperations
+	}
+}
+
+// WithUploads enables file uploads via multipart requests, see UploadConfig.
+// Multipart requests are simple requests in terms of CORS, so they are not protected by a preflight request.
+// Make sure to protect the handler against CSRF, e.g. by requiring a custom header.
+func WithUploads(config UploadConfig) Option {
+	return func(h *Handler) {
+		config.setDefaults()
+		h.uploadConfig = &config
+	}
+}
+
+// WithBatching enables batched requests, which send multiple operations as JSON array via POST.
+// The operations are executed by the batch executor, their results are returned as JSON array in the same order.
+// A batch is always answered with 200, the errors of each operation are part of its result.
+func WithBatching(batchExecutor BatchExecutor) Option {
+	return func(h *Handler) {
+		h.batchExecutor = batchExecutor
+	}
+}
+
+// WithParseLimits sets the limits the queries of requests are parsed with,
+// parsing of queries exceeding the limits is aborted and the request is rejected
+func WithParseLimits(limits graphql.ParseLimits) Option {
+	return func(h *Handler) {
+		h.parseLimits = limits
+	}
+}
+
+func NewHandler(executor Executor, options ...Option) *Handler {
+	h := &Handler{
+		executor:           executor,
+		logger:             abstractlogger.NoopLogger,
+		maxRequestBodySize: defaultMaxRequestBodySize,
+	}
+	for i := range options {
+		options[i](h)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mediaType, ok := negotiateMediaType(r.Header.Values(headerAccept))
+	if !ok {
+		w.WriteHeader(http.StatusNotAcceptable)
+		return
+	}
+
+	var (
+		request graphql.Request
+		files   *uploads
+		err     error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		err = parseGetRequest(r, &request)
+	case http.MethodPost:
+		if h.uploadConfig != nil && isMultipartRequest(r) {
+			files, err = parseMultipartRequest(r, &request, h.maxRequestBodySize, *h.uploadConfig)
+			break
+		}
+		var body []byte
+		if body, err = readPostRequestBody(r, h.maxRequestBodySize); err != nil {
+			break
+		}
+		if isBatchRequestBody(body) {
+			h.serveBatch(w, r, mediaType, body)
+			return
+		}
+		err = parseRequestBody(body, &request)
+	default:
+		w.Header().Set(headerAllow, allowedMethods)
+		h.writeErrors(w, mediaType, http.StatusMethodNotAllowed, errMethodNotAllowed)
+		return
+	}
+
+	if err != nil {
+		h.writeParseError(w, mediaType, err)
+		return
+	}
+
+	if files != nil {
+		defer func() {
+			if err := files.Close(); err != nil {
+				h.logger.Error("graphqlhttp.Handler.ServeHTTP", abstractlogger.Error(err))
+			}
+		}()
+	}
+
+	request.SetHeader(r.Header)
+	request.SetParseLimits(h.parseLimits)
+
+	if h.persistedOperations != nil {
+		if err = h.persistedOperations.Resolve(r.Context(), &request); err != nil {
+			h.writeExecutionError(w, mediaType, err)
+			return
+		}
+	}
+
+	operationType, err := request.OperationType()
+	if err != nil {
+		h.writeRequestErrors(w, mediaType, err)
+		return
+	}
+
+	switch operationType {
+	case graphql.OperationTypeMutation:
+		if r.Method == http.MethodGet {
+			w.Header().Set(headerAllow, http.MethodPost)
+			h.writeErrors(w, mediaType, http.StatusMethodNotAllowed, errMutationOverGet)
+			return
+		}
+	case graphql.OperationTypeSubscription:
+		h.writeRequestErrors(w, mediaType, errSubscriptionNotSupported)
+		return
+	}
+
+	var executionOptions []engine.ExecutionOptions
+	if h.executionOptions != nil {
+		executionOptions = h.executionOptions(r)
+	}
+	if files != nil && len(files.files) > 0 {
+		executionOptions = append(executionOptions, engine.WithFiles(files.files))
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	resultWriter := graphql.NewEngineResultWriterFromBuffer(buf)
+	if err = h.executor.Execute(r.Context(), &request, &resultWriter, executionOptions...); err != nil {
+		h.writeExecutionError(w, mediaType, err)
+		return
+	}
+
+	h.writeResponse(w, mediaType, http.StatusOK, buf.Bytes())
+}
+
+func (h *Handler) serveBatch(w http.ResponseWriter, r *http.Request, mediaType string, body []byte) {
+	if h.batchExecutor == nil {
+		h.writeErrors(w, mediaType, errBatchingNotSupported.status, errBatchingNotSupported)
+		return
+	}
+
+	requests, err := parseBatchRequestBody(body)
+	if err != nil {
+		h.writeParseError(w, mediaType, err)
+		return
+	}
+	for i := range requests {
+		requests[i].SetHeader(r.Header)
+		requests[i].SetParseLimits(h.parseLimits)
+	}
+
+	var executionOptions []engine.ExecutionOptions
+	if h.executionOptions != nil {
+		executionOptions = h.executionOptions(r)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	errs, err := h.batchExecutor.ExecuteBatch(r.Context(), requests, buf, executionOptions...)
+	if err != nil {
+		var batchTooLargeErr *engine.BatchTooLargeError
+		if errors.As(err, &batchTooLargeErr) {
+			h.writeErrors(w, mediaType, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		h.writeExecutionError(w, mediaType, err)
+		return
+	}
+	for i := range errs {
+		if errs[i] != nil {
+			h.logger.Error("graphqlhttp.Handler.serveBatch", abstractlogger.Int("operation", i), abstractlogger.Error(errs[i]))
+		}
+	}
+
+	h.writeResponse(w, mediaType, http.StatusOK, buf.Bytes())
+}
+
+// writeParseError writes errors of malformed requests, all other errors are logged and hidden
+func (h *Handler) writeParseError(w http.ResponseWriter, mediaType string, err error) {
+	var requestErr *requestError
+	if errors.As(err, &requestErr) {
+		h.writeErrors(w, mediaType, requestErr.status, requestErr)
+		return
+	}
+	h.logger.Error("graphqlhttp.Handler.ServeHTTP", abstractlogger.Error(err))
+	h.writeErrors(w, mediaType, http.StatusInternalServerError, errInternalServerError)
+}
+
+// writeExecutionError writes request errors to the client, all other errors are logged and hidden
+func (h *Handler) writeExecutionError(w http.ResponseWriter, mediaType string, err error) {
+	if graphqlerrors.IsRequestError(err) {
+		h.writeRequestErrors(w, mediaType, err)
+		return
+	}
+	h.logger.Error("graphqlhttp.Handler.ServeHTTP", abstractlogger.Error(err))
+	h.writeErrors(w, mediaType, http.StatusInternalServerError, errInternalServerError)
+}
+
+// writeRequestErrors writes errors of a well-formed request, which could not be executed,
+// e.g. because of a parse or validation error
+func (h *Handler) writeRequestErrors(w http.ResponseWriter, mediaType string, err error) {
+	status := http.StatusOK
+	if mediaType == mediaTypeGraphQLResponseJSON {
+		status = http.StatusBadRequest
+	}
+	h.writeErrors(w, mediaType, status, err)
+}
+
+// errorResponse is the response of a request which was not executed,
+// the data entry must not be present in this case
+type errorResponse struct {
+	Errors graphqlerrors.RequestErrors `json:"errors"`
+}
+
+func (h *Handler) writeErrors(w http.ResponseWriter, mediaType string, status int, err error) {
+	body, marshalErr := json.Marshal(errorResponse{Errors: graphqlerrors.RequestErrorsFromError(err)})
+	if marshalErr != nil {
+		h.logger.Error("graphqlhttp.Handler.writeErrors", abstractlogger.Error(marshalErr))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	h.writeResponse(w, mediaType, status, body)
+}
+
+func (h *Handler) writeResponse(w http.ResponseWriter, mediaType string, status int, body []byte) {
+	w.Header().Set(headerContentType, mediaType+"; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		h.logger.Error("graphqlhttp.Handler.writeResponse", abstractlogger.Error(err))
+	}
+}
@@ -0,0 +1,370 @@
+package graphqlhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/execution/engine"
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func newTestEngine(t *testing.T, configure ...func(conf *engine.Configuration)) *engine.ExecutionEngine {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	schema, err := graphql.NewSchemaFromString(`
+		type Query { hello: String }
+		type Mutation { setHello: String }
+		type Subscription { helloChanged: String }
+	`)
+	require.NoError(t, err)
+
+	dataSource := func(id, typeName, fieldName, data string) plan.DataSource {
+		ds, err := plan.NewDataSourceConfiguration[staticdatasource.Configuration](
+			id,
+			&staticdatasource.Factory[staticdatasource.Configuration]{},
+			&plan.DataSourceMetadata{
+				RootNodes: []plan.TypeField{
+					{TypeName: typeName, FieldNames: []string{fieldName}},
+				},
+			},
+			staticdatasource.Configuration{
+				Data: data,
+			},
+		)
+		require.NoError(t, err)
+		return ds
+	}
+
+	engineConf := engine.NewConfiguration(schema)
+	engineConf.SetDataSources([]plan.DataSource{
+		dataSource("query", "Query", "hello", `{"hello":"world"}`),
+		dataSource("mutation", "Mutation", "setHello", `{"setHello":"updated"}`),
+	})
+	for i := range configure {
+		configure[i](&engineConf)
+	}
+
+	executionEngine, err := engine.NewExecutionEngine(ctx, abstractlogger.NoopLogger, engineConf)
+	require.NoError(t, err)
+	return executionEngine
+}
+
+type failingExecutor struct{}
+
+func (failingExecutor) Execute(_ context.Context, _ *graphql.Request, _ resolve.SubscriptionResponseWriter, _ ...engine.ExecutionOptions) error {
+	return errors.New("upstream connection refused")
+}
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(newTestEngine(t))
+
+	type response struct {
+		status      int
+		contentType string
+		allow       string
+		body        string
+	}
+
+	serve := func(t *testing.T, handler http.Handler, r *http.Request) response {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		body, err := io.ReadAll(rec.Result().Body)
+		require.NoError(t, err)
+		return response{
+			status:      rec.Code,
+			contentType: rec.Header().Get(headerContentType),
+			allow:       rec.Header().Get(headerAllow),
+			body:        string(body),
+		}
+	}
+
+	get := func(params url.Values, accept string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil)
+		if accept != "" {
+			r.Header.Set(headerAccept, accept)
+		}
+		return r
+	}
+
+	post := func(body, contentType, accept string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		if contentType != "" {
+			r.Header.Set(headerContentType, contentType)
+		}
+		if accept != "" {
+			r.Header.Set(headerAccept, accept)
+		}
+		return r
+	}
+
+	const (
+		graphQLResponseJSON = "application/graphql-response+json; charset=utf-8"
+		legacyJSON          = "application/json; charset=utf-8"
+	)
+
+	t.Run("query via GET", func(t *testing.T) {
+		resp := serve(t, handler, get(url.Values{"query": {"{ hello }"}}, mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"hello":"world"}}`}, resp)
+	})
+
+	t.Run("query via GET with variables and operation name", func(t *testing.T) {
+		resp := serve(t, handler, get(url.Values{
+			"query":         {"query A { a: hello } query B($skip: Boolean!) { hello @skip(if: $skip) }"},
+			"operationName": {"B"},
+			"variables":     {`{"skip":false}`},
+		}, mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"hello":"world"}}`}, resp)
+	})
+
+	t.Run("query via POST", func(t *testing.T) {
+		resp := serve(t, handler, post(`{"query":"{ hello }"}`, "application/json; charset=utf-8", mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"hello":"world"}}`}, resp)
+	})
+
+	t.Run("mutation via POST", func(t *testing.T) {
+		resp := serve(t, handler, post(`{"query":"mutation { setHello }"}`, mediaTypeJSON, ""))
+		assert.Equal(t, response{status: http.StatusOK, contentType: legacyJSON, body: `{"data":{"setHello":"updated"}}`}, resp)
+	})
+
+	t.Run("mutation via GET is rejected", func(t *testing.T) {
+		resp := serve(t, handler, get(url.Values{"query": {"mutation { setHello }"}}, mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, response{
+			status:      http.StatusMethodNotAllowed,
+			contentType: graphQLResponseJSON,
+			allow:       http.MethodPost,
+			body:        `{"errors":[{"message":"mutations could only be executed via POST requests"}]}`,
+		}, resp)
+	})
+
+	t.Run("subscription is rejected", func(t *testing.T) {
+		resp := serve(t, handler, post(`{"query":"subscription { helloChanged }"}`, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, response{
+			status:      http.StatusBadRequest,
+			contentType: graphQLResponseJSON,
+			body:        `{"errors":[{"message":"subscriptions are not supported over this transport"}]}`,
+		}, resp)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		resp := serve(t, handler, httptest.NewRequest(http.MethodPut, "/graphql", nil))
+		assert.Equal(t, response{
+			status:      http.StatusMethodNotAllowed,
+			contentType: legacyJSON,
+			allow:       allowedMethods,
+			body:        `{"errors":[{"message":"only GET and POST requests are supported"}]}`,
+		}, resp)
+	})
+
+	t.Run("malformed requests", func(t *testing.T) {
+		t.Run("missing query", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"operationName": {"A"}}, ""))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: legacyJSON, body: `{"errors":[{"message":"the request is missing the query parameter"}]}`}, resp)
+		})
+
+		t.Run("invalid variables", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"query": {"{ hello }"}, "variables": {"[1]"}}, ""))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: legacyJSON, body: `{"errors":[{"message":"the variables parameter must be a JSON object"}]}`}, resp)
+		})
+
+		t.Run("invalid body", func(t *testing.T) {
+			resp := serve(t, handler, post(`{"query":1}`, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, http.StatusBadRequest, resp.status)
+			assert.Contains(t, resp.body, `the request body must be a JSON object`)
+		})
+
+		t.Run("unsupported content type", func(t *testing.T) {
+			resp := serve(t, handler, post(`query { hello }`, "application/graphql", ""))
+			assert.Equal(t, response{status: http.StatusUnsupportedMediaType, contentType: legacyJSON, body: `{"errors":[{"message":"the request body must be of media type application/json"}]}`}, resp)
+		})
+
+		t.Run("body too large", func(t *testing.T) {
+			handler := NewHandler(newTestEngine(t), WithMaxRequestBodySize(8))
+			resp := serve(t, handler, post(`{"query":"{ hello }"}`, mediaTypeJSON, ""))
+			assert.Equal(t, http.StatusRequestEntityTooLarge, resp.status)
+		})
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		query := `{"query":"{ unknown }"}`
+		expectedBody := `{"errors":[{"message":"field: unknown not defined on type: Query","path":["query","unknown"]}]}`
+
+		t.Run("application/graphql-response+json", func(t *testing.T) {
+			resp := serve(t, handler, post(query, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: graphQLResponseJSON, body: expectedBody}, resp)
+		})
+
+		t.Run("application/json", func(t *testing.T) {
+			resp := serve(t, handler, post(query, mediaTypeJSON, mediaTypeJSON))
+			assert.Equal(t, response{status: http.StatusOK, contentType: legacyJSON, body: expectedBody}, resp)
+		})
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		resp := serve(t, handler, get(url.Values{"query": {"{ hello"}}, mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, http.StatusBadRequest, resp.status)
+		assert.Contains(t, resp.body, `"errors":[{"message":"unexpected token`)
+	})
+
+	t.Run("parse limits", func(t *testing.T) {
+		handler := NewHandler(newTestEngine(t), WithParseLimits(graphql.ParseLimits{MaxAliases: 1}))
+
+		t.Run("query within the limits", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"query": {"{ a: hello }"}}, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"a":"world"}}`}, resp)
+		})
+
+		t.Run("query exceeding the limits", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"query": {"{ a: hello b: hello }"}}, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{
+				status:      http.StatusBadRequest,
+				contentType: graphQLResponseJSON,
+				body:        `{"errors":[{"message":"the document exceeds the maximum number of aliases of 1","locations":[{"line":1,"column":12}]}]}`,
+			}, resp)
+		})
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		handler := NewHandler(failingExecutor{})
+		resp := serve(t, handler, get(url.Values{"query": {"{ hello }"}}, mediaTypeGraphQLResponseJSON))
+		assert.Equal(t, response{status: http.StatusInternalServerError, contentType: graphQLResponseJSON, body: `{"errors":[{"message":"internal server error"}]}`}, resp)
+	})
+
+	t.Run("persisted queries", func(t *testing.T) {
+		const (
+			query    = "{ hello }"
+			mutation = "mutation { setHello }"
+		)
+
+		persistedOperations, err := graphql.NewPersistedOperations(graphql.PersistedOperationsConfig{
+			Store:     graphql.NewInMemoryPersistedOperationStore(mutation),
+			EnableAPQ: true,
+		})
+		require.NoError(t, err)
+
+		handler := NewHandler(newTestEngine(t, func(conf *engine.Configuration) {
+			conf.SetPersistedOperations(persistedOperations)
+		}), WithPersistedOperations(persistedOperations))
+
+		extensions := func(query string) string {
+			return `{"persistedQuery":{"version":1,"sha256Hash":"` + graphql.Sha256Hash(query) + `"}}`
+		}
+
+		t.Run("unknown query via GET", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"extensions": {extensions(query)}}, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: graphQLResponseJSON, body: `{"errors":[{"message":"PersistedQueryNotFound"}]}`}, resp)
+		})
+
+		t.Run("register query via POST", func(t *testing.T) {
+			resp := serve(t, handler, post(`{"query":"{ hello }","extensions":`+extensions(query)+`}`, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"hello":"world"}}`}, resp)
+		})
+
+		t.Run("registered query via GET", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"extensions": {extensions(query)}}, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"hello":"world"}}`}, resp)
+		})
+
+		t.Run("persisted mutation via POST", func(t *testing.T) {
+			resp := serve(t, handler, post(`{"extensions":`+extensions(mutation)+`}`, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusOK, contentType: graphQLResponseJSON, body: `{"data":{"setHello":"updated"}}`}, resp)
+		})
+
+		t.Run("persisted mutation via GET is rejected", func(t *testing.T) {
+			resp := serve(t, handler, get(url.Values{"extensions": {extensions(mutation)}}, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.status)
+		})
+
+		t.Run("not supported without persisted operations", func(t *testing.T) {
+			resp := serve(t, NewHandler(newTestEngine(t)), get(url.Values{"extensions": {extensions(query)}}, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: graphQLResponseJSON, body: `{"errors":[{"message":"PersistedQueryNotSupported"}]}`}, resp)
+		})
+	})
+
+	t.Run("batched requests", func(t *testing.T) {
+		executionEngine := newTestEngine(t, func(conf *engine.Configuration) {
+			conf.SetBatchConfiguration(engine.BatchConfiguration{MaxBatchSize: 3})
+		})
+		handler := NewHandler(executionEngine, WithBatching(executionEngine))
+
+		t.Run("results are returned in order", func(t *testing.T) {
+			resp := serve(t, handler, post(` [{"query":"{ hello }"},{"query":"mutation { setHello }"},{"query":"{ unknown }"}]`, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{
+				status:      http.StatusOK,
+				contentType: graphQLResponseJSON,
+				body:        `[{"data":{"hello":"world"}},{"data":{"setHello":"updated"}},{"errors":[{"message":"field: unknown not defined on type: Query","path":["query","unknown"]}]}]`,
+			}, resp)
+		})
+
+		t.Run("subscriptions are rejected per operation", func(t *testing.T) {
+			resp := serve(t, handler, post(`[{"query":"subscription { helloChanged }"},{"query":"{ hello }"}]`, mediaTypeJSON, mediaTypeGraphQLResponseJSON))
+			assert.Equal(t, response{
+				status:      http.StatusOK,
+				contentType: graphQLResponseJSON,
+				body:        `[{"errors":[{"message":"subscriptions are not allowed in batched requests"}]},{"data":{"hello":"world"}}]`,
+			}, resp)
+		})
+
+		t.Run("batch too large", func(t *testing.T) {
+			resp := serve(t, handler, post(`[{"query":"{ hello }"},{"query":"{ hello }"},{"query":"{ hello }"},{"query":"{ hello }"}]`, mediaTypeJSON, ""))
+			assert.Equal(t, response{status: http.StatusRequestEntityTooLarge, contentType: legacyJSON, body: `{"errors":[{"message":"the batch contains 4 operations, the maximum is 3"}]}`}, resp)
+		})
+
+		t.Run("empty batch", func(t *testing.T) {
+			resp := serve(t, handler, post(`[]`, mediaTypeJSON, ""))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: legacyJSON, body: `{"errors":[{"message":"the batch does not contain any operation"}]}`}, resp)
+		})
+
+		t.Run("malformed operation", func(t *testing.T) {
+			resp := serve(t, handler, post(`[{"query":"{ hello }"},{"operationName":"A"}]`, mediaTypeJSON, ""))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: legacyJSON, body: `{"errors":[{"message":"invalid operation at index 1: the request is missing the query parameter"}]}`}, resp)
+		})
+
+		t.Run("batching is disabled by default", func(t *testing.T) {
+			resp := serve(t, NewHandler(executionEngine), post(`[{"query":"{ hello }"}]`, mediaTypeJSON, ""))
+			assert.Equal(t, response{status: http.StatusBadRequest, contentType: legacyJSON, body: `{"errors":[{"message":"batched requests are not supported"}]}`}, resp)
+		})
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		resp := serve(t, handler, get(url.Values{"query": {"{ hello }"}}, "text/html"))
+		assert.Equal(t, http.StatusNotAcceptable, resp.status)
+	})
+}
+
+func TestNegotiateMediaType(t *testing.T) {
+	for _, tc := range []struct {
+		accept   []string
+		expected string
+		ok       bool
+	}{
+		{accept: nil, expected: mediaTypeJSON, ok: true},
+		{accept: []string{"application/json"}, expected: mediaTypeJSON, ok: true},
+		{accept: []string{"application/graphql-response+json"}, expected: mediaTypeGraphQLResponseJSON, ok: true},
+		{accept: []string{"application/graphql-response+json, application/json"}, expected: mediaTypeGraphQLResponseJSON, ok: true},
+		{accept: []string{"application/graphql-response+json;q=0.9, application/json"}, expected: mediaTypeJSON, ok: true},
+		{accept: []string{"application/json;q=0.5", "*/*;q=0.8"}, expected: mediaTypeGraphQLResponseJSON, ok: true},
+		{accept: []string{"application/graphql-response+json;q=0"}, ok: false},
+		{accept: []string{"text/html"}, ok: false},
+	} {
+		mediaType, ok := negotiateMediaType(tc.accept)
+		assert.Equal(t, tc.ok, ok, "%v", tc.accept)
+		assert.Equal(t, tc.expected, mediaType, "%v", tc.accept)
+	}
+}
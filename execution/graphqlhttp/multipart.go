@@ -0,0 +1,277 @@
+package graphqlhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tidwall/sjson"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
+)
+
+const (
+	mediaTypeMultipartFormData = "multipart/form-data"
+
+	multipartFieldOperations = "operations"
+	multipartFieldMap        = "map"
+
+	defaultMaxFileSize       int64 = 32 << 20
+	defaultMaxFiles                = 10
+	defaultMaxMemoryFileSize int64 = 1 << 20
+
+	fileVariablePathPrefix = "variables."
+)
+
+var (
+	errFileTooLarge = &requestError{status: http.StatusRequestEntityTooLarge, message: "the uploaded file is too large"}
+	errTooManyFiles = &requestError{status: http.StatusRequestEntityTooLarge, message: "the request contains too many files"}
+)
+
+// UploadConfig configures file uploads according to the GraphQL multipart request spec
+// See https://github.com/jaydenseric/graphql-multipart-request-spec
+type UploadConfig struct {
+	// MaxFileSize is the maximum size of a single file, defaults to 32MB
+	MaxFileSize int64
+	// MaxFiles is the maximum number of files of a request, defaults to 10
+	MaxFiles int
+	// MaxMemoryFileSize is the size up to which files are kept in memory,
+	// larger files are written to temporary files, defaults to 1MB
+	MaxMemoryFileSize int64
+	// TempDir is the directory of the temporary files, defaults to os.TempDir
+	TempDir string
+}
+
+func (c *UploadConfig) setDefaults() {
+	if c.MaxFileSize <= 0 {
+		c.MaxFileSize = defaultMaxFileSize
+	}
+	if c.MaxFiles <= 0 {
+		c.MaxFiles = defaultMaxFiles
+	}
+	if c.MaxMemoryFileSize <= 0 {
+		c.MaxMemoryFileSize = defaultMaxMemoryFileSize
+	}
+}
+
+// fileContent is the content of an uploaded file,
+// it is either kept in memory or stored in a temporary file
+type fileContent struct {
+	data []byte
+	path string
+}
+
+// uploadedFile is an uploaded file mapped to one variable,
+// a file mapped to multiple variables shares its content
+type uploadedFile struct {
+	name         string
+	variablePath string
+	content      *fileContent
+}
+
+func (f *uploadedFile) Name() string {
+	return f.name
+}
+
+func (f *uploadedFile) VariablePath() string {
+	return f.variablePath
+}
+
+func (f *uploadedFile) Open() (io.ReadCloser, error) {
+	if f.content.path != "" {
+		return os.Open(f.content.path)
+	}
+	return io.NopCloser(bytes.NewReader(f.content.data)), nil
+}
+
+// uploads are the files of a multipart request
+type uploads struct {
+	files    []httpclient.File
+	contents []*fileContent
+}
+
+// Close removes the temporary files of the uploads
+func (u *uploads) Close() error {
+	var errs []error
+	for _, content := range u.contents {
+		if content.path == "" {
+			continue
+		}
+		if err := os.Remove(content.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isMultipartRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(headerContentType))
+	return err == nil && mediaType == mediaTypeMultipartFormData
+}
+
+// parseMultipartRequest parses a request of the GraphQL multipart request spec
+// The parts have to be sent in order: operations, map and the files.
+// The files are streamed into memory or temporary files, the returned uploads have to be closed to remove them.
+func parseMultipartRequest(r *http.Request, request *graphql.Request, maxBodySize int64, config UploadConfig) (*uploads, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, badRequest("unable to read the multipart request: %s", err)
+	}
+
+	operations, err := readMultipartField(reader, multipartFieldOperations, maxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	if err = parseRequestBody(operations, request); err != nil {
+		return nil, err
+	}
+
+	fileMapBytes, err := readMultipartField(reader, multipartFieldMap, maxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	var fileMap map[string][]string
+	if err = json.Unmarshal(fileMapBytes, &fileMap); err != nil {
+		return nil, badRequest("the map field must be a JSON object of file paths: %s", err)
+	}
+	if len(fileMap) > config.MaxFiles {
+		return nil, errTooManyFiles
+	}
+
+	for key, paths := range fileMap {
+		if len(paths) == 0 {
+			return nil, badRequest("the file %s is not mapped to a variable", key)
+		}
+		for _, path := range paths {
+			if !strings.HasPrefix(path, fileVariablePathPrefix) {
+				return nil, badRequest("the file %s must be mapped to a variable, got path: %s", key, path)
+			}
+			variables, err := sjson.SetRawBytes(request.Variables, strings.TrimPrefix(path, fileVariablePathPrefix), []byte("null"))
+			if err != nil {
+				return nil, badRequest("invalid file path %s: %s", path, err)
+			}
+			request.Variables = variables
+		}
+	}
+
+	result := &uploads{}
+	if err = result.readFiles(reader, fileMap, config); err != nil {
+		_ = result.Close()
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func readMultipartField(reader *multipart.Reader, fieldName string, maxSize int64) ([]byte, error) {
+	part, err := reader.NextPart()
+	if err != nil {
+		return nil, badRequest("the multipart request is missing the %s field", fieldName)
+	}
+	defer part.Close()
+
+	if part.FormName() != fieldName {
+		return nil, badRequest("expected the %s field, got: %s", fieldName, part.FormName())
+	}
+
+	data, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+	if err != nil {
+		return nil, badRequest("unable to read the %s field: %s", fieldName, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, errRequestBodyTooLarge
+	}
+	return data, nil
+}
+
+func (u *uploads) readFiles(reader *multipart.Reader, fileMap map[string][]string, config UploadConfig) error {
+	received := make(map[string]struct{}, len(fileMap))
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return badRequest("unable to read the multipart request: %s", err)
+		}
+
+		key := part.FormName()
+		paths, ok := fileMap[key]
+		if !ok {
+			_ = part.Close()
+			return badRequest("the file %s is not part of the map", key)
+		}
+		if _, ok = received[key]; ok {
+			_ = part.Close()
+			return badRequest("the file %s is sent multiple times", key)
+		}
+		received[key] = struct{}{}
+
+		content, err := u.readFile(part, config)
+		_ = part.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			u.files = append(u.files, &uploadedFile{
+				name:         part.FileName(),
+				variablePath: path,
+				content:      content,
+			})
+		}
+	}
+
+	if len(received) != len(fileMap) {
+		for key := range fileMap {
+			if _, ok := received[key]; !ok {
+				return badRequest("the file %s is missing", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// readFile keeps the file in memory up to MaxMemoryFileSize, larger files are written to a temporary file
+func (u *uploads) readFile(part io.Reader, config UploadConfig) (*fileContent, error) {
+	limited := io.LimitReader(part, config.MaxFileSize+1)
+
+	data, err := io.ReadAll(io.LimitReader(limited, config.MaxMemoryFileSize+1))
+	if err != nil {
+		return nil, badRequest("unable to read the file: %s", err)
+	}
+	if int64(len(data)) <= config.MaxMemoryFileSize {
+		content := &fileContent{data: data}
+		u.contents = append(u.contents, content)
+		return content, nil
+	}
+
+	file, err := os.CreateTemp(config.TempDir, "graphql-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	content := &fileContent{path: file.Name()}
+	u.contents = append(u.contents, content)
+
+	written, err := io.Copy(file, io.MultiReader(bytes.NewReader(data), limited))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if written > config.MaxFileSize {
+		return nil, errFileTooLarge
+	}
+
+	return content, nil
+}
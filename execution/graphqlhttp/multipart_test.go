@@ -0,0 +1,223 @@
+package graphqlhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/execution/engine"
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+)
+
+// newUploadSubgraph returns a subgraph which responds with the file names and contents of multipart requests
+func newUploadSubgraph(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	subgraph := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			_, _ = fmt.Fprint(w, `{"data":{"upload":null,"uploadMany":null}}`)
+			return
+		}
+
+		var uploaded []string
+		for fieldName, headers := range r.MultipartForm.File {
+			file, err := headers[0].Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			uploaded = append(uploaded, fieldName+"="+headers[0].Filename+":"+string(content))
+		}
+		sort.Strings(uploaded)
+
+		data, err := json.Marshal(strings.Join(uploaded, ","))
+		require.NoError(t, err)
+		_, _ = fmt.Fprintf(w, `{"data":{"upload":%[1]s,"uploadMany":%[1]s}}`, data)
+	}))
+	t.Cleanup(subgraph.Close)
+	return subgraph
+}
+
+func newUploadEngine(t *testing.T, subgraphURL string) *engine.ExecutionEngine {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	schema, err := graphql.NewSchemaFromString(`
+		scalar Upload
+		type Query { hello: String }
+		type Mutation {
+			upload(file: Upload!): String
+			uploadMany(files: [Upload!]!): String
+		}
+	`)
+	require.NoError(t, err)
+
+	engineConf, err := engine.NewProxyEngineConfigFactory(ctx, schema, engine.ProxyUpstreamConfig{
+		URL:    subgraphURL,
+		Method: http.MethodPost,
+	}).EngineConfiguration()
+	require.NoError(t, err)
+
+	executionEngine, err := engine.NewExecutionEngine(ctx, abstractlogger.NoopLogger, engineConf)
+	require.NoError(t, err)
+	return executionEngine
+}
+
+type multipartFile struct {
+	fieldName string
+	fileName  string
+	content   string
+}
+
+func newMultipartRequest(t *testing.T, operations, fileMap string, files ...multipartFile) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	require.NoError(t, form.WriteField(multipartFieldOperations, operations))
+	require.NoError(t, form.WriteField(multipartFieldMap, fileMap))
+	for _, file := range files {
+		part, err := form.CreateFormFile(file.fieldName, file.fileName)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, form.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	r.Header.Set(headerContentType, form.FormDataContentType())
+	r.Header.Set(headerAccept, mediaTypeGraphQLResponseJSON)
+	return r
+}
+
+func TestHandler_Uploads(t *testing.T) {
+	subgraph := newUploadSubgraph(t)
+	tempDir := t.TempDir()
+
+	handler := NewHandler(newUploadEngine(t, subgraph.URL), WithUploads(UploadConfig{
+		MaxFileSize:       16,
+		MaxFiles:          2,
+		MaxMemoryFileSize: 4,
+		TempDir:           tempDir,
+	}))
+
+	serve := func(t *testing.T, handler http.Handler, r *http.Request) (int, string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code, rec.Body.String()
+	}
+
+	assertTempDirIsEmpty := func(t *testing.T) {
+		t.Helper()
+		entries, err := os.ReadDir(tempDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	}
+
+	const (
+		uploadOperation     = `{"query":"mutation ($file: Upload!) { upload(file: $file) }","variables":{"file":null}}`
+		uploadManyOperation = `{"query":"mutation ($files: [Upload!]!) { uploadMany(files: $files) }","variables":{"files":[null,null]}}`
+	)
+
+	t.Run("single file", func(t *testing.T) {
+		status, body := serve(t, handler, newMultipartRequest(t, uploadOperation, `{"0":["variables.file"]}`,
+			multipartFile{fieldName: "0", fileName: "a.txt", content: "abc"},
+		))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `{"data":{"upload":"0=a.txt:abc"}}`, body)
+		assertTempDirIsEmpty(t)
+	})
+
+	t.Run("multiple files stored in temporary files", func(t *testing.T) {
+		status, body := serve(t, handler, newMultipartRequest(t, uploadManyOperation, `{"0":["variables.files.0"],"1":["variables.files.1"]}`,
+			multipartFile{fieldName: "0", fileName: "a.txt", content: "larger than memory"[:16]},
+			multipartFile{fieldName: "1", fileName: "b.txt", content: "b"},
+		))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `{"data":{"uploadMany":"0=a.txt:larger than memo,1=b.txt:b"}}`, body)
+		assertTempDirIsEmpty(t)
+	})
+
+	t.Run("variables are set for mapped files", func(t *testing.T) {
+		status, body := serve(t, handler, newMultipartRequest(t, `{"query":"mutation ($file: Upload!) { upload(file: $file) }"}`, `{"0":["variables.file"]}`,
+			multipartFile{fieldName: "0", fileName: "a.txt", content: "abc"},
+		))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `{"data":{"upload":"0=a.txt:abc"}}`, body)
+	})
+
+	t.Run("file too large", func(t *testing.T) {
+		status, body := serve(t, handler, newMultipartRequest(t, uploadOperation, `{"0":["variables.file"]}`,
+			multipartFile{fieldName: "0", fileName: "a.txt", content: strings.Repeat("a", 17)},
+		))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.Equal(t, `{"errors":[{"message":"the uploaded file is too large"}]}`, body)
+		assertTempDirIsEmpty(t)
+	})
+
+	t.Run("too many files", func(t *testing.T) {
+		status, _ := serve(t, handler, newMultipartRequest(t, uploadManyOperation, `{"0":["variables.files.0"],"1":["variables.files.1"],"2":["variables.files.2"]}`))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	})
+
+	t.Run("malformed requests", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			request  *http.Request
+			expected string
+		}{
+			{
+				name:     "missing file",
+				request:  newMultipartRequest(t, uploadOperation, `{"0":["variables.file"]}`),
+				expected: `{"errors":[{"message":"the file 0 is missing"}]}`,
+			},
+			{
+				name: "file which is not part of the map",
+				request: newMultipartRequest(t, uploadOperation, `{"0":["variables.file"]}`,
+					multipartFile{fieldName: "0", fileName: "a.txt", content: "a"},
+					multipartFile{fieldName: "1", fileName: "b.txt", content: "b"},
+				),
+				expected: `{"errors":[{"message":"the file 1 is not part of the map"}]}`,
+			},
+			{
+				name:     "file which is not mapped to a variable",
+				request:  newMultipartRequest(t, uploadOperation, `{"0":["query"]}`),
+				expected: `{"errors":[{"message":"the file 0 must be mapped to a variable, got path: query"}]}`,
+			},
+			{
+				name:     "invalid map",
+				request:  newMultipartRequest(t, uploadOperation, `[]`),
+				expected: `{"errors":[{"message":"the map field must be a JSON object of file paths: json: cannot unmarshal array into Go value of type map[string][]string"}]}`,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				status, body := serve(t, handler, tc.request)
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, tc.expected, body)
+				assertTempDirIsEmpty(t)
+			})
+		}
+	})
+
+	t.Run("uploads are disabled by default", func(t *testing.T) {
+		status, _ := serve(t, NewHandler(newUploadEngine(t, subgraph.URL)), newMultipartRequest(t, uploadOperation, `{"0":["variables.file"]}`,
+			multipartFile{fieldName: "0", fileName: "a.txt", content: "abc"},
+		))
+		assert.Equal(t, http.StatusUnsupportedMediaType, status)
+	})
+}
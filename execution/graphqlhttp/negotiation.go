@@ -0,0 +1,74 @@
+package graphqlhttp
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerAccept      = "Accept"
+	headerAllow       = "Allow"
+	headerContentType = "Content-Type"
+
+	allowedMethods = "GET, POST"
+
+	mediaTypeJSON                = "application/json"
+	mediaTypeGraphQLResponseJSON = "application/graphql-response+json"
+)
+
+// negotiateMediaType returns the response media type for the values of the Accept header
+// application/graphql-response+json is preferred over application/json when both have the same quality,
+// requests without an Accept header are treated as legacy clients and receive application/json
+func negotiateMediaType(acceptValues []string) (mediaType string, ok bool) {
+	if len(acceptValues) == 0 {
+		return mediaTypeJSON, true
+	}
+
+	var (
+		graphQLResponseQuality float64 = -1
+		jsonQuality            float64 = -1
+	)
+
+	for _, value := range acceptValues {
+		for _, accepted := range strings.Split(value, ",") {
+			accepted = strings.TrimSpace(accepted)
+			if accepted == "" {
+				continue
+			}
+
+			acceptedType, params, err := mime.ParseMediaType(accepted)
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+			if quality <= 0 {
+				continue
+			}
+
+			switch acceptedType {
+			case mediaTypeGraphQLResponseJSON:
+				graphQLResponseQuality = max(graphQLResponseQuality, quality)
+			case mediaTypeJSON:
+				jsonQuality = max(jsonQuality, quality)
+			case "application/*", "*/*":
+				graphQLResponseQuality = max(graphQLResponseQuality, quality)
+			}
+		}
+	}
+
+	switch {
+	case graphQLResponseQuality < 0 && jsonQuality < 0:
+		return "", false
+	case graphQLResponseQuality >= jsonQuality:
+		return mediaTypeGraphQLResponseJSON, true
+	default:
+		return mediaTypeJSON, true
+	}
+}
@@ -0,0 +1,182 @@
+package graphqlhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/wundergraph/graphql-go-tools/execution/graphql"
+)
+
+const (
+	queryParamQuery         = "query"
+	queryParamOperationName = "operationName"
+	queryParamVariables     = "variables"
+	queryParamExtensions    = "extensions"
+)
+
+var (
+	errMethodNotAllowed         = errors.New("only GET and POST requests are supported")
+	errMutationOverGet          = errors.New("mutations could only be executed via POST requests")
+	errSubscriptionNotSupported = errors.New("subscriptions are not supported over this transport")
+	errInternalServerError      = errors.New("internal server error")
+	errMissingQuery             = &requestError{status: http.StatusBadRequest, message: "the request is missing the query parameter"}
+	errUnsupportedMediaType     = &requestError{status: http.StatusUnsupportedMediaType, message: "the request body must be of media type application/json"}
+	errRequestBodyTooLarge      = &requestError{status: http.StatusRequestEntityTooLarge, message: "the request body is too large"}
+	errBatchingNotSupported     = &requestError{status: http.StatusBadRequest, message: "batched requests are not supported"}
+	errEmptyBatch               = &requestError{status: http.StatusBadRequest, message: graphql.ErrEmptyBatch.Error(), err: graphql.ErrEmptyBatch}
+)
+
+// requestError is an error of a malformed GraphQL-over-HTTP request
+type requestError struct {
+	status  int
+	message string
+	// err is the underlying error, if any
+	err error
+}
+
+func (r *requestError) Error() string {
+	return r.message
+}
+
+func (r *requestError) Unwrap() error {
+	return r.err
+}
+
+func badRequest(format string, args ...any) *requestError {
+	return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+// postRequestBody is the JSON body of a POST request
+// query is a pointer to distinguish a missing query from an empty query
+type postRequestBody struct {
+	Query         *string         `json:"query"`
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables"`
+	Extensions    json.RawMessage `json:"extensions"`
+}
+
+// parseGetRequest parses the url encoded parameters of a GET request
+// the query could be omitted when the extensions are present, e.g. for persisted queries
+func parseGetRequest(r *http.Request, request *graphql.Request) error {
+	params := r.URL.Query()
+	if !params.Has(queryParamQuery) && !params.Has(queryParamExtensions) {
+		return errMissingQuery
+	}
+
+	request.Query = params.Get(queryParamQuery)
+	request.OperationName = params.Get(queryParamOperationName)
+
+	if variables := params.Get(queryParamVariables); variables != "" {
+		if !isJSONObject([]byte(variables)) {
+			return badRequest("the variables parameter must be a JSON object")
+		}
+		request.Variables = json.RawMessage(variables)
+	}
+
+	if extensions := params.Get(queryParamExtensions); extensions != "" {
+		if !isJSONObject([]byte(extensions)) {
+			return badRequest("the extensions parameter must be a JSON object")
+		}
+		request.Extensions = json.RawMessage(extensions)
+	}
+
+	return nil
+}
+
+// readPostRequestBody reads the JSON body of a POST request
+func readPostRequestBody(r *http.Request, maxBodySize int64) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(headerContentType))
+	if err != nil || mediaType != mediaTypeJSON {
+		return nil, errUnsupportedMediaType
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, badRequest("unable to read the request body: %s", err)
+	}
+	if int64(len(body)) > maxBodySize {
+		return nil, errRequestBodyTooLarge
+	}
+
+	return body, nil
+}
+
+// isBatchRequestBody returns true if the body is a JSON array of requests
+func isBatchRequestBody(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	return len(body) > 0 && body[0] == '['
+}
+
+// parseBatchRequestBody parses the JSON array of a batched POST request,
+// every operation of the batch has to be a valid request body
+func parseBatchRequestBody(body []byte) ([]*graphql.Request, error) {
+	var operations []json.RawMessage
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return nil, badRequest("the request body must be a JSON array of operations: %s", err)
+	}
+	if len(operations) == 0 {
+		return nil, errEmptyBatch
+	}
+
+	requests := make([]*graphql.Request, len(operations))
+	for i := range operations {
+		requests[i] = &graphql.Request{}
+		if err := parseRequestBody(operations[i], requests[i]); err != nil {
+			var requestErr *requestError
+			if errors.As(err, &requestErr) {
+				return nil, &requestError{status: requestErr.status, message: fmt.Sprintf("invalid operation at index %d: %s", i, requestErr.message)}
+			}
+			return nil, err
+		}
+	}
+
+	return requests, nil
+}
+
+// parseRequestBody parses the JSON body of a POST request or the operations field of a multipart request
+func parseRequestBody(body []byte, request *graphql.Request) error {
+	var requestBody postRequestBody
+	if err := json.Unmarshal(body, &requestBody); err != nil {
+		return badRequest("the request body must be a JSON object: %s", err)
+	}
+	if requestBody.Query == nil && isNull(requestBody.Extensions) {
+		return errMissingQuery
+	}
+	if !isNullOrJSONObject(requestBody.Variables) {
+		return badRequest("the variables parameter must be a JSON object")
+	}
+	if !isNullOrJSONObject(requestBody.Extensions) {
+		return badRequest("the extensions parameter must be a JSON object")
+	}
+
+	if requestBody.Query != nil {
+		request.Query = *requestBody.Query
+	}
+	request.OperationName = requestBody.OperationName
+	if !isNull(requestBody.Variables) {
+		request.Variables = requestBody.Variables
+	}
+	if !isNull(requestBody.Extensions) {
+		request.Extensions = requestBody.Extensions
+	}
+
+	return nil
+}
+
+func isNull(data json.RawMessage) bool {
+	return len(data) == 0 || string(data) == "null"
+}
+
+func isNullOrJSONObject(data json.RawMessage) bool {
+	return isNull(data) || isJSONObject(data)
+}
+
+func isJSONObject(data []byte) bool {
+	var object map[string]json.RawMessage
+	return json.Unmarshal(data, &object) == nil && object != nil
+}
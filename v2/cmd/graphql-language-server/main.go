@@ -0,0 +1,29 @@
+// Command graphql-language-server runs a GraphQL language server which communicates with the editor over stdin and stdout.
+//
+// Usage:
+//
+//	graphql-language-server -schema schema.graphql
+//
+// The schema can also be set by the editor with the initialization option "schema",
+// relative paths are resolved against the root of the workspace.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/languageserver"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "path of the schema file, files it imports with #import comments are loaded too")
+	flag.Parse()
+
+	server := languageserver.New(languageserver.Options{
+		SchemaPath: *schemaPath,
+	})
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -19,6 +19,7 @@ type EnumValueDefinitionList struct {
 type EnumValueDefinition struct {
 	Description   Description        // optional, describes enum value
 	EnumValue     ByteSliceReference // e.g. NORTH (Name but not true, false or null
+	Position      position.Position  // position of the enum value
 	HasDirectives bool
 	Directives    DirectiveList // optional, e.g. @foo
 }
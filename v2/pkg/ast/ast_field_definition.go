@@ -17,6 +17,7 @@ type FieldDefinitionList struct {
 type FieldDefinition struct {
 	Description             Description        // optional e.g. "FieldDefinition is ..."
 	Name                    ByteSliceReference // e.g. foo
+	Position                position.Position  // position of the name
 	HasArgumentsDefinitions bool
 	ArgumentsDefinition     InputValueDefinitionList // optional
 	Colon                   position.Position        // :
@@ -36,6 +36,15 @@ func (d *Document) InputObjectTypeDefinitionDescriptionString(ref int) string {
 	return unsafebytes.BytesToString(d.InputObjectTypeDefinitionDescriptionBytes(ref))
 }
 
+// InputObjectTypeDefinitionIsOneOf returns true if the input object is annotated with @oneOf,
+// which requires exactly one of its fields to be provided with a non-null value.
+func (d *Document) InputObjectTypeDefinitionIsOneOf(ref int) bool {
+	if !d.InputObjectTypeDefinitions[ref].HasDirectives {
+		return false
+	}
+	return d.InputObjectTypeDefinitions[ref].Directives.HasDirectiveByName(d, "oneOf")
+}
+
 func (d *Document) InputObjectTypeDefinitionInputValueDefinitionDefaultValueString(inputObjectTypeDefinitionName, inputValueDefinitionName string) string {
 	defaultValue := d.InputObjectTypeDefinitionInputValueDefinitionDefaultValue(inputObjectTypeDefinitionName, inputValueDefinitionName)
 	if defaultValue.Kind != ValueKindString {
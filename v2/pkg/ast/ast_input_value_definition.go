@@ -22,6 +22,7 @@ type DefaultValue struct {
 type InputValueDefinition struct {
 	Description   Description        // optional, e.g. "input Foo is..."
 	Name          ByteSliceReference // e.g. Foo
+	Position      position.Position  // position of the name
 	Colon         position.Position  // :
 	Type          int                // e.g. String
 	DefaultValue  DefaultValue       // e.g. = "Bar"
@@ -78,17 +79,34 @@ func (d *Document) InputValueDefinitionHasDirective(ref int, directiveName ByteS
 	return false
 }
 
+func (d *Document) InputValueDefinitionDirectiveByName(ref int, directiveName ByteSlice) (directiveRef int, exists bool) {
+	for _, i := range d.InputValueDefinitions[ref].Directives.Refs {
+		if bytes.Equal(directiveName, d.DirectiveNameBytes(i)) {
+			return i, true
+		}
+	}
+	return
+}
+
 func (d *Document) AddInputValueDefinition(inputValueDefinition InputValueDefinition) (ref int) {
 	d.InputValueDefinitions = append(d.InputValueDefinitions, inputValueDefinition)
 	return len(d.InputValueDefinitions) - 1
 }
 
 func (d *Document) ImportInputValueDefinition(name, description string, typeRef int, defaultValue DefaultValue) (ref int) {
+	return d.ImportInputValueDefinitionWithDirectives(name, description, typeRef, defaultValue, nil)
+}
+
+func (d *Document) ImportInputValueDefinitionWithDirectives(name, description string, typeRef int, defaultValue DefaultValue, directiveRefs []int) (ref int) {
 	inputValueDef := InputValueDefinition{
 		Description:  d.ImportDescription(description),
 		Name:         d.Input.AppendInputString(name),
 		Type:         typeRef,
 		DefaultValue: defaultValue,
+		Directives: DirectiveList{
+			Refs: directiveRefs,
+		},
+		HasDirectives: len(directiveRefs) > 0,
 	}
 
 	return d.AddInputValueDefinition(inputValueDef)
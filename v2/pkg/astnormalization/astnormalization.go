@@ -253,9 +253,17 @@ func (o *OperationNormalizer) setupOperationWalkers() {
 	})
 
 	if o.options.extractVariables {
+		// default values have to be extracted before the input coercion,
+		// so that they are coerced the same way as the provided variables
+		variablesDefaultValuesExtraction := astvisitor.NewWalker(48)
+		o.variablesDefaultValuesExtraction = extractVariablesDefaultValue(&variablesDefaultValuesExtraction)
+		o.operationWalkers = append(o.operationWalkers, walkerStage{
+			name:   "variablesDefaultValuesExtraction",
+			walker: &variablesDefaultValuesExtraction,
+		})
+
 		variablesProcessing := astvisitor.NewWalker(48)
 		inputCoercionForList(&variablesProcessing)
-		o.variablesDefaultValuesExtraction = extractVariablesDefaultValue(&variablesProcessing)
 		injectInputFieldDefaults(&variablesProcessing)
 
 		o.operationWalkers = append(o.operationWalkers, walkerStage{
@@ -273,6 +273,30 @@ func TestNormalizeOperation(t *testing.T) {
 			}`, `{"input":{"doubleList":{"foo":"bar","list":{"foo":"bar2","list":{"nested":{"foo":"bar3","list":{"foo":"bar4"}}}}}}}`,
 			`{"input":{"doubleList":[[{"foo":"bar","list":[{"foo":"bar2","list":[{"nested":{"foo":"bar3","list":[{"foo":"bar4"}]}}]}]}]]}}`)
 	})
+	t.Run("input list coercion of variable default value", func(t *testing.T) {
+		run(t, inputCoercionForListDefinition, `
+			query ($ids: [Int] = 1) {
+			  charactersByIds(ids: $ids) {
+				id
+			  }
+			}`, `query ($ids: [Int]) {
+			  charactersByIds(ids: $ids) {
+				id
+			  }
+			}`, `{}`, `{"ids":[1]}`)
+	})
+	t.Run("input list coercion of nested list variable default value", func(t *testing.T) {
+		run(t, inputCoercionForListDefinition, `
+			query ($ids: [[Int]] = [1, [2], null]) {
+			  nestedList(ids: $ids) {
+				id
+			  }
+			}`, `query ($ids: [[Int]]) {
+			  nestedList(ids: $ids) {
+				id
+			  }
+			}`, `{}`, `{"ids":[[1],[2],null]}`)
+	})
 }
 
 func TestOperationNormalizer_NormalizeOperation(t *testing.T) {
@@ -0,0 +1,326 @@
+package astnormalization
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// OperationSignature identifies an operation independent of its literal values, aliases, formatting and selection order.
+// Equivalent operations share the same signature, so usage reporting can group their requests by it.
+type OperationSignature struct {
+	// Signature is the normalized operation printed without indentation
+	Signature string
+	// Hash is the xxhash of Signature
+	Hash uint64
+}
+
+// NormalizeOperationSignature creates a SignatureNormalizer and returns the signature of the named operation.
+// In case you're using it in a hot path, create a SignatureNormalizer using NewSignatureNormalizer() instead and re-use it.
+func NormalizeOperationSignature(operation, definition *ast.Document, operationName []byte, report *operationreport.Report) OperationSignature {
+	return NewSignatureNormalizer().NormalizeSignature(operation, definition, operationName, report)
+}
+
+// SignatureNormalizer turns an operation into its signature:
+//   - all other operations and the fragment definitions not used by the operation are removed
+//   - aliases are removed
+//   - selections are sorted, fields first, then fragment spreads and inline fragments, each by name
+//   - arguments, variable definitions and fragment definitions are sorted by name
+//   - literal argument values are replaced by variables, the literals end up in the variables of the operation
+//
+// Directives keep their order and their arguments, the same way the variables extraction of the OperationNormalizer skips them.
+type SignatureNormalizer struct {
+	operationWalkers []walkerStage
+
+	removeOperationDefinitionsVisitor *removeOperationDefinitionsVisitor
+	variablesExtraction               *variablesExtractionVisitor
+
+	buf bytes.Buffer
+}
+
+// NewSignatureNormalizer creates a new SignatureNormalizer
+func NewSignatureNormalizer() *SignatureNormalizer {
+	normalizer := &SignatureNormalizer{}
+	normalizer.setupOperationWalkers()
+	return normalizer
+}
+
+func (s *SignatureNormalizer) setupOperationWalkers() {
+	s.operationWalkers = make([]walkerStage, 0, 3)
+
+	removeOperations := astvisitor.NewWalker(48)
+	s.removeOperationDefinitionsVisitor = removeOperationDefinitions(&removeOperations)
+	removeUnusedFragmentDefinitions(&removeOperations)
+	s.operationWalkers = append(s.operationWalkers, walkerStage{
+		name:   "removeOperationDefinitions, removeUnusedFragmentDefinitions",
+		walker: &removeOperations,
+	})
+
+	// selections and arguments are sorted before the variables extraction to generate the variable names in a stable order
+	sortSelections := astvisitor.NewWalker(48)
+	removeAliases(&sortSelections)
+	sortSelectionsAndArguments(&sortSelections)
+	s.operationWalkers = append(s.operationWalkers, walkerStage{
+		name:   "removeAliases, sortSelectionsAndArguments",
+		walker: &sortSelections,
+	})
+
+	extractVariablesWalker := astvisitor.NewWalker(48)
+	s.variablesExtraction = extractVariables(&extractVariablesWalker)
+	s.variablesExtraction.placeholders = true
+	s.operationWalkers = append(s.operationWalkers, walkerStage{
+		name:   "extractVariables",
+		walker: &extractVariablesWalker,
+	})
+}
+
+// NormalizeSignature normalizes the named operation in place and returns its signature.
+// The operation name may be empty if the document contains a single operation.
+func (s *SignatureNormalizer) NormalizeSignature(operation, definition *ast.Document, operationName []byte, report *operationreport.Report) OperationSignature {
+	if len(operationName) == 0 {
+		operationName = singleOperationName(operation)
+	}
+	s.removeOperationDefinitionsVisitor.operationName = operationName
+
+	for i := range s.operationWalkers {
+		if s.operationWalkers[i].walker == s.variablesExtraction.Walker {
+			operationRef, ok := remainingOperation(operation)
+			if !ok {
+				if len(operationName) == 0 {
+					report.AddExternalError(operationreport.ErrRequiredOperationNameIsMissing())
+				} else {
+					report.AddExternalError(operationreport.ErrOperationWithProvidedOperationNameNotFound(string(operationName)))
+				}
+				return OperationSignature{}
+			}
+			s.variablesExtraction.fragmentsOperationRef = operationRef
+		}
+
+		s.operationWalkers[i].walker.Walk(operation, definition, report)
+		if report.HasErrors() {
+			return OperationSignature{}
+		}
+	}
+
+	sortVariableDefinitions(operation)
+
+	s.buf.Reset()
+	if err := astprinter.Print(operation, definition, &s.buf); err != nil {
+		report.AddInternalError(err)
+		return OperationSignature{}
+	}
+	return OperationSignature{
+		Signature: s.buf.String(),
+		Hash:      xxhash.Sum64(s.buf.Bytes()),
+	}
+}
+
+// singleOperationName returns the name of the only operation of the document
+func singleOperationName(operation *ast.Document) []byte {
+	var name []byte
+	count := 0
+	for _, node := range operation.RootNodes {
+		if node.Kind == ast.NodeKindOperationDefinition {
+			name = operation.OperationDefinitionNameBytes(node.Ref)
+			count++
+		}
+	}
+	if count != 1 {
+		return nil
+	}
+	return name
+}
+
+// remainingOperation returns the operation left after all other operations were removed
+func remainingOperation(operation *ast.Document) (int, bool) {
+	ref, count := ast.InvalidRef, 0
+	for _, node := range operation.RootNodes {
+		if node.Kind == ast.NodeKindOperationDefinition {
+			ref = node.Ref
+			count++
+		}
+	}
+	return ref, count == 1
+}
+
+func removeUnusedFragmentDefinitions(walker *astvisitor.Walker) {
+	visitor := removeUnusedFragmentDefinitionsVisitor{}
+	walker.RegisterLeaveDocumentVisitor(&visitor)
+}
+
+// removeUnusedFragmentDefinitionsVisitor removes the fragment definitions which are not reachable from the remaining operations
+// and sorts the root nodes, operations come first, followed by the fragment definitions in order of their names
+type removeUnusedFragmentDefinitionsVisitor struct {
+	operation *ast.Document
+	used      map[string]struct{}
+}
+
+func (r *removeUnusedFragmentDefinitionsVisitor) LeaveDocument(operation, definition *ast.Document) {
+	r.operation = operation
+	r.used = make(map[string]struct{})
+	for _, node := range operation.RootNodes {
+		if node.Kind == ast.NodeKindOperationDefinition && operation.OperationDefinitions[node.Ref].HasSelections {
+			r.collectFragmentSpreads(operation.OperationDefinitions[node.Ref].SelectionSet)
+		}
+	}
+
+	for i := range operation.RootNodes {
+		if operation.RootNodes[i].Kind != ast.NodeKindFragmentDefinition {
+			continue
+		}
+		if _, ok := r.used[operation.FragmentDefinitionNameString(operation.RootNodes[i].Ref)]; !ok {
+			operation.RootNodes[i].Kind = ast.NodeKindUnknown
+		}
+	}
+
+	sort.SliceStable(operation.RootNodes, func(i, j int) bool {
+		left, right := operation.RootNodes[i], operation.RootNodes[j]
+		if rootNodeRank(left.Kind) != rootNodeRank(right.Kind) {
+			return rootNodeRank(left.Kind) < rootNodeRank(right.Kind)
+		}
+		return left.Kind == ast.NodeKindFragmentDefinition &&
+			operation.FragmentDefinitionNameString(left.Ref) < operation.FragmentDefinitionNameString(right.Ref)
+	})
+}
+
+func (r *removeUnusedFragmentDefinitionsVisitor) collectFragmentSpreads(selectionSet int) {
+	for _, selection := range r.operation.SelectionSets[selectionSet].SelectionRefs {
+		ref := r.operation.Selections[selection].Ref
+		switch r.operation.Selections[selection].Kind {
+		case ast.SelectionKindField:
+			if r.operation.Fields[ref].HasSelections {
+				r.collectFragmentSpreads(r.operation.Fields[ref].SelectionSet)
+			}
+		case ast.SelectionKindInlineFragment:
+			if r.operation.InlineFragments[ref].HasSelections {
+				r.collectFragmentSpreads(r.operation.InlineFragments[ref].SelectionSet)
+			}
+		case ast.SelectionKindFragmentSpread:
+			name := r.operation.FragmentSpreadNameString(ref)
+			if _, ok := r.used[name]; ok {
+				continue
+			}
+			r.used[name] = struct{}{}
+			fragment, ok := r.operation.FragmentDefinitionRef(r.operation.FragmentSpreadNameBytes(ref))
+			if ok && r.operation.FragmentDefinitions[fragment].HasSelections {
+				r.collectFragmentSpreads(r.operation.FragmentDefinitions[fragment].SelectionSet)
+			}
+		}
+	}
+}
+
+func rootNodeRank(kind ast.NodeKind) int {
+	switch kind {
+	case ast.NodeKindOperationDefinition:
+		return 0
+	case ast.NodeKindFragmentDefinition:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func removeAliases(walker *astvisitor.Walker) {
+	visitor := removeAliasesVisitor{}
+	walker.RegisterEnterDocumentVisitor(&visitor)
+	walker.RegisterEnterFieldVisitor(&visitor)
+}
+
+type removeAliasesVisitor struct {
+	operation *ast.Document
+}
+
+func (r *removeAliasesVisitor) EnterDocument(operation, definition *ast.Document) {
+	r.operation = operation
+}
+
+func (r *removeAliasesVisitor) EnterField(ref int) {
+	if r.operation.Fields[ref].Alias.IsDefined {
+		r.operation.RemoveFieldAlias(ref)
+	}
+}
+
+func sortSelectionsAndArguments(walker *astvisitor.Walker) {
+	visitor := sortSelectionsAndArgumentsVisitor{}
+	walker.RegisterEnterDocumentVisitor(&visitor)
+	walker.RegisterEnterSelectionSetVisitor(&visitor)
+	walker.RegisterEnterFieldVisitor(&visitor)
+}
+
+type sortSelectionsAndArgumentsVisitor struct {
+	operation *ast.Document
+}
+
+func (s *sortSelectionsAndArgumentsVisitor) EnterDocument(operation, definition *ast.Document) {
+	s.operation = operation
+}
+
+// EnterSelectionSet sorts the selections by kind and name.
+// Fields with the same name are ordered by the names of their arguments and directives, never by values, which are extracted later on.
+func (s *sortSelectionsAndArgumentsVisitor) EnterSelectionSet(ref int) {
+	selections := s.operation.SelectionSets[ref].SelectionRefs
+	sort.SliceStable(selections, func(i, j int) bool {
+		left, right := s.operation.Selections[selections[i]], s.operation.Selections[selections[j]]
+		if left.Kind != right.Kind {
+			return selectionRank(left.Kind) < selectionRank(right.Kind)
+		}
+		return s.selectionSortKey(left) < s.selectionSortKey(right)
+	})
+}
+
+func (s *sortSelectionsAndArgumentsVisitor) EnterField(ref int) {
+	arguments := s.operation.Fields[ref].Arguments.Refs
+	sort.SliceStable(arguments, func(i, j int) bool {
+		return s.operation.ArgumentNameString(arguments[i]) < s.operation.ArgumentNameString(arguments[j])
+	})
+}
+
+func (s *sortSelectionsAndArgumentsVisitor) selectionSortKey(selection ast.Selection) string {
+	switch selection.Kind {
+	case ast.SelectionKindField:
+		field := s.operation.Fields[selection.Ref]
+		names := []string{s.operation.FieldNameString(selection.Ref)}
+		for _, argument := range field.Arguments.Refs {
+			names = append(names, s.operation.ArgumentNameString(argument))
+		}
+		for _, directive := range field.Directives.Refs {
+			names = append(names, "@"+s.operation.DirectiveNameString(directive))
+		}
+		return strings.Join(names, " ")
+	case ast.SelectionKindFragmentSpread:
+		return s.operation.FragmentSpreadNameString(selection.Ref)
+	case ast.SelectionKindInlineFragment:
+		return s.operation.InlineFragmentTypeConditionNameString(selection.Ref)
+	default:
+		return ""
+	}
+}
+
+func selectionRank(kind ast.SelectionKind) int {
+	switch kind {
+	case ast.SelectionKindField:
+		return 0
+	case ast.SelectionKindFragmentSpread:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// sortVariableDefinitions sorts the variable definitions of all operations by name,
+// it runs after the variables extraction which appends the extracted variables
+func sortVariableDefinitions(operation *ast.Document) {
+	for i := range operation.OperationDefinitions {
+		refs := operation.OperationDefinitions[i].VariableDefinitions.Refs
+		sort.SliceStable(refs, func(i, j int) bool {
+			return operation.VariableDefinitionNameString(refs[i]) < operation.VariableDefinitionNameString(refs[j])
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package astnormalization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/testing/goldie"
+)
+
+func TestSignatureNormalizer_NormalizeSignature(t *testing.T) {
+	definition := unsafeparser.ParseGraphqlDocumentString(testDefinition)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+	normalizer := NewSignatureNormalizer()
+
+	signature := func(t *testing.T, operation, operationName string) (OperationSignature, *ast.Document) {
+		t.Helper()
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(operation)
+		report := operationreport.Report{}
+		out := normalizer.NormalizeSignature(&operationDocument, &definition, []byte(operationName), &report)
+		require.False(t, report.HasErrors(), report.Error())
+		return out, &operationDocument
+	}
+
+	t.Run("golden", func(t *testing.T) {
+		for _, tc := range []struct {
+			name, operation, operationName string
+		}{
+			{
+				name: "literals",
+				operation: `query Dogs($command: DogCommand!) {
+					findDog(complex: {name: "Rex", owner: "Jane"}) { name }
+					dog { doesKnowCommand(dogCommand: $command) isHousetrained(atOtherHomes: true) }
+					booleanList(booleanListArg: [true, false])
+				}`,
+			},
+			{
+				name: "aliases_and_sorting",
+				operation: `{
+					pet { ... on Dog { nickname } name ... on Cat { meowVolume } }
+					d2: dog { n: nickname barkVolume name }
+					catOrDog { ... on Cat { name } }
+				}`,
+			},
+			{
+				name: "fragments",
+				operation: `
+					fragment Unused on Dog { barkVolume }
+					query Other { cat { name } }
+					query Dog { dog { ...DogFields ...OwnerFields } }
+					fragment OwnerFields on Dog { owner { ...HumanFields } }
+					fragment HumanFields on Human { name }
+					fragment DogFields on Dog { doesKnowCommand(dogCommand: SIT) name }`,
+				operationName: "Dog",
+			},
+			{
+				name:      "directives",
+				operation: `query Dog($skip: Boolean!) { dog @skip(if: $skip) { name @include(if: true) nickname } }`,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				out, _ := signature(t, tc.operation, tc.operationName)
+				goldie.Assert(t, "operation_signature_"+tc.name, []byte(out.Signature))
+			})
+		}
+	})
+
+	t.Run("literals are moved into the variables", func(t *testing.T) {
+		_, operation := signature(t, `{ findDog(complex: {name: "Rex"}) { doesKnowCommand(dogCommand: SIT) } }`, "")
+		assert.Equal(t, `{"b":"SIT","a":{"name":"Rex"}}`, string(operation.Input.Variables))
+	})
+
+	t.Run("equivalent operations have the same signature", func(t *testing.T) {
+		expected, _ := signature(t, `query Dog {
+			dog { name nickname ...Owner }
+			findDog(complex: {name: "Rex"}) { name }
+		}
+		fragment Owner on Dog { owner { name } }`, "")
+
+		for _, operation := range []string{
+			`query Dog { findDog(complex: {name: "Bello"}) { name } dog { ...Owner nickname name } } fragment Owner on Dog { owner { name } }`,
+			`fragment Owner on Dog { owner { n: name } }
+			query Dog {
+				d: dog { nick: nickname, name, ...Owner }
+				found: findDog(complex: {name: "Rex", owner: "Jane"}) { name }
+			}`,
+			`fragment Unused on Dog { name } query Dog { findDog(complex: {}) { name } dog { name nickname ...Owner } } fragment Owner on Dog { owner { name } }`,
+		} {
+			actual, _ := signature(t, operation, "")
+			assert.Equal(t, expected, actual)
+		}
+
+		different, _ := signature(t, `query Dog { dog { name nickname barkVolume ...Owner } findDog(complex: {name: "Rex"}) { name } } fragment Owner on Dog { owner { name } }`, "")
+		assert.NotEqual(t, expected.Signature, different.Signature)
+		assert.NotEqual(t, expected.Hash, different.Hash)
+	})
+
+	t.Run("missing operations", func(t *testing.T) {
+		for _, tc := range []struct {
+			operation, operationName, expectedError string
+		}{
+			{
+				operation:     `query A { dog { name } } query B { cat { name } }`,
+				expectedError: "operation name is required when providing multiple operations",
+			},
+			{
+				operation:     `query A { dog { name } }`,
+				operationName: "B",
+				expectedError: "cannot find an operation with name: B",
+			},
+		} {
+			operationDocument := unsafeparser.ParseGraphqlDocumentString(tc.operation)
+			report := operationreport.Report{}
+			out := normalizer.NormalizeSignature(&operationDocument, &definition, []byte(tc.operationName), &report)
+			assert.Equal(t, OperationSignature{}, out)
+			require.True(t, report.HasErrors())
+			assert.Contains(t, report.Error(), tc.expectedError)
+		}
+	})
+}
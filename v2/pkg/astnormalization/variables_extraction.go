@@ -30,6 +30,12 @@ type variablesExtractionVisitor struct {
 	skip                      bool
 	extractedVariables        [][]byte
 	extractedVariableTypeRefs []int
+	// operationRef is the operation the variables of the current argument are added to
+	operationRef int
+	// placeholders extracts every literal into its own variable, including the literals of fragment definitions.
+	// The variables of fragments are added to fragmentsOperationRef, so the document must contain a single operation.
+	placeholders          bool
+	fragmentsOperationRef int
 }
 
 func (v *variablesExtractionVisitor) EnterOperationDefinition(ref int) {
@@ -48,7 +54,15 @@ func (v *variablesExtractionVisitor) EnterArgument(ref int) {
 	if v.operation.Arguments[ref].Value.Kind == ast.ValueKindVariable {
 		return
 	}
-	if len(v.Ancestors) == 0 || v.Ancestors[0].Kind != ast.NodeKindOperationDefinition {
+	if len(v.Ancestors) == 0 {
+		return
+	}
+	switch {
+	case v.Ancestors[0].Kind == ast.NodeKindOperationDefinition:
+		v.operationRef = v.Ancestors[0].Ref
+	case v.Ancestors[0].Kind == ast.NodeKindFragmentDefinition && v.placeholders && v.fragmentsOperationRef != ast.InvalidRef:
+		v.operationRef = v.fragmentsOperationRef
+	default:
 		return
 	}
 
@@ -81,7 +95,7 @@ func (v *variablesExtractionVisitor) EnterArgument(ref int) {
 		v.operation.Arguments[ref].Value.Ref = value
 		return
 	}
-	variableNameBytes := v.operation.GenerateUnusedVariableDefinitionName(v.Ancestors[0].Ref)
+	variableNameBytes := v.operation.GenerateUnusedVariableDefinitionName(v.operationRef)
 	v.operation.Input.Variables, err = sjson.SetRawBytes(v.operation.Input.Variables, unsafebytes.BytesToString(variableNameBytes), valueBytes)
 	if err != nil {
 		v.StopWithInternalErr(err)
@@ -121,9 +135,9 @@ func (v *variablesExtractionVisitor) EnterArgument(ref int) {
 
 	newVariableRef := len(v.operation.VariableDefinitions) - 1
 
-	v.operation.OperationDefinitions[v.Ancestors[0].Ref].VariableDefinitions.Refs =
-		append(v.operation.OperationDefinitions[v.Ancestors[0].Ref].VariableDefinitions.Refs, newVariableRef)
-	v.operation.OperationDefinitions[v.Ancestors[0].Ref].HasVariableDefinitions = true
+	v.operation.OperationDefinitions[v.operationRef].VariableDefinitions.Refs =
+		append(v.operation.OperationDefinitions[v.operationRef].VariableDefinitions.Refs, newVariableRef)
+	v.operation.OperationDefinitions[v.operationRef].HasVariableDefinitions = true
 }
 
 func (v *variablesExtractionVisitor) EnterDocument(operation, definition *ast.Document) {
@@ -185,7 +199,7 @@ func (v *variablesExtractionVisitor) extractObjectValue(objectField int, fieldVa
 		v.operation.ObjectFields[objectField].Value.Ref = value
 		return
 	}
-	variableNameBytes := v.operation.GenerateUnusedVariableDefinitionName(v.Ancestors[0].Ref)
+	variableNameBytes := v.operation.GenerateUnusedVariableDefinitionName(v.operationRef)
 	v.operation.Input.Variables, err = sjson.SetRawBytes(v.operation.Input.Variables, unsafebytes.BytesToString(variableNameBytes), valueBytes)
 	if err != nil {
 		v.StopWithInternalErr(err)
@@ -220,12 +234,16 @@ func (v *variablesExtractionVisitor) extractObjectValue(objectField int, fieldVa
 
 	newVariableRef := len(v.operation.VariableDefinitions) - 1
 
-	v.operation.OperationDefinitions[v.Ancestors[0].Ref].VariableDefinitions.Refs =
-		append(v.operation.OperationDefinitions[v.Ancestors[0].Ref].VariableDefinitions.Refs, newVariableRef)
-	v.operation.OperationDefinitions[v.Ancestors[0].Ref].HasVariableDefinitions = true
+	v.operation.OperationDefinitions[v.operationRef].VariableDefinitions.Refs =
+		append(v.operation.OperationDefinitions[v.operationRef].VariableDefinitions.Refs, newVariableRef)
+	v.operation.OperationDefinitions[v.operationRef].HasVariableDefinitions = true
 }
 
 func (v *variablesExtractionVisitor) variableExists(variableValue []byte, inputValueDefinition int) (exists bool, name []byte, definition int) {
+	if v.placeholders {
+		// equal literals get their own variables, so that the variables don't depend on the values
+		return false, nil, ast.InvalidRef
+	}
 	_ = jsonparser.ObjectEach(v.operation.Input.Variables, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
 		if !v.extractedVariablesContainsKey(key, inputValueDefinition) {
 			// skip variables that were not extracted but user defined
@@ -241,7 +259,7 @@ func (v *variablesExtractionVisitor) variableExists(variableValue []byte, inputV
 		return nil
 	})
 	if exists {
-		definition, exists = v.operation.VariableDefinitionByNameAndOperation(v.Ancestors[0].Ref, name)
+		definition, exists = v.operation.VariableDefinitionByNameAndOperation(v.operationRef, name)
 	}
 	return
 }
@@ -0,0 +1,30 @@
+package astnormalization
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// VariablesValuesNormalizer applies the rules which depend on the values of the variables:
+// coercion of single values into lists and injection of input object field default values.
+// It does not modify the operation itself, only operation.Input.Variables.
+// This allows to normalize the variables of an already normalized operation, e.g. a cached persisted operation.
+type VariablesValuesNormalizer struct {
+	walker *astvisitor.Walker
+}
+
+func NewVariablesValuesNormalizer() *VariablesValuesNormalizer {
+	walker := astvisitor.NewWalker(8)
+	inputCoercionForList(&walker)
+	injectInputFieldDefaults(&walker)
+
+	return &VariablesValuesNormalizer{
+		walker: &walker,
+	}
+}
+
+// NormalizeOperation normalizes the variables of an already normalized operation
+func (v *VariablesValuesNormalizer) NormalizeOperation(operation, definition *ast.Document, report *operationreport.Report) {
+	v.walker.Walk(operation, definition, report)
+}
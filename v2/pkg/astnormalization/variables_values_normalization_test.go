@@ -0,0 +1,64 @@
+package astnormalization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const variablesValuesNormalizationDefinition = `
+schema {
+	query: Query
+}
+
+type Character {
+	id: Int
+}
+
+input Filter {
+	name: String
+	limit: Int = 5
+	tags: [String]
+}
+
+type Query {
+	charactersByIds(ids: [Int]): [Character]
+	characters(filter: Filter): [Character]
+}`
+
+func TestVariablesValuesNormalizer(t *testing.T) {
+	definition := unsafeparser.ParseGraphqlDocumentString(variablesValuesNormalizationDefinition)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+	operation := unsafeparser.ParseGraphqlDocumentString(`
+		query ($ids: [Int], $filter: Filter) {
+			charactersByIds(ids: $ids) { id }
+			characters(filter: $filter) { id }
+		}`)
+	printedBefore := mustString(astprinter.PrintString(&operation, &definition))
+
+	normalizer := NewVariablesValuesNormalizer()
+
+	operation.Input.Variables = []byte(`{"ids":1,"filter":{"name":"Luke","tags":"jedi"}}`)
+	report := operationreport.Report{}
+	normalizer.NormalizeOperation(&operation, &definition, &report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	assert.Equal(t, `{"ids":[1],"filter":{"name":"Luke","tags":["jedi"],"limit":5}}`, string(operation.Input.Variables))
+	assert.Equal(t, printedBefore, mustString(astprinter.PrintString(&operation, &definition)))
+
+	t.Run("normalizer is reusable", func(t *testing.T) {
+		operation.Input.Variables = []byte(`{"ids":2}`)
+		report := operationreport.Report{}
+		normalizer.NormalizeOperation(&operation, &definition, &report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		assert.Equal(t, `{"ids":[2]}`, string(operation.Input.Variables))
+	})
+}
@@ -35,6 +35,45 @@ func ParseGraphqlDocumentBytes(input []byte) (ast.Document, operationreport.Repo
 	return doc, report
 }
 
+// ParseGraphqlDocumentStringWithLimits parses a raw GraphQL document like ParseGraphqlDocumentString,
+// parsing is aborted with an error as soon as the document exceeds one of the limits.
+func ParseGraphqlDocumentStringWithLimits(input string, limits Limits) (ast.Document, operationreport.Report) {
+	parser := NewParserWithLimits(limits)
+	doc := *ast.NewSmallDocument()
+	doc.Input.ResetInputBytes([]byte(input))
+	report := operationreport.Report{}
+	parser.Parse(&doc, &report)
+	return doc, report
+}
+
+// ParseGraphqlDocumentStringWithErrorRecovery parses a raw GraphQL document like ParseGraphqlDocumentString,
+// but doesn't stop at the first syntax error, see NewParserWithErrorRecovery.
+func ParseGraphqlDocumentStringWithErrorRecovery(input string) (ast.Document, operationreport.Report) {
+	parser := NewParserWithErrorRecovery()
+	doc := *ast.NewSmallDocument()
+	doc.Input.ResetInputBytes([]byte(input))
+	report := operationreport.Report{}
+	parser.Parse(&doc, &report)
+	return doc, report
+}
+
+// Limits restricts the size of parsed documents, so maliciously large documents are rejected
+// before the whole document is processed. A limit of 0 disables the check.
+type Limits struct {
+	// MaxTokens is the maximum number of tokens of the document, including comments. It is enforced during lexing.
+	MaxTokens int
+	// MaxDepth is the maximum nesting depth of selection sets
+	MaxDepth int
+	// MaxAliases is the maximum number of aliased fields of the document
+	MaxAliases int
+	// MaxDirectivesPerField is the maximum number of directives on a single field
+	MaxDirectivesPerField int
+	// MaxRootFields is the maximum number of selections of the selection set of an operation
+	MaxRootFields int
+	// MaxFragments is the maximum number of fragment definitions, fragment spreads and inline fragments of the document
+	MaxFragments int
+}
+
 // Parser takes a raw input and turns it into an AST
 // use NewParser() to create a parser
 // Don't create new parsers in the hot path, re-use them.
@@ -44,6 +83,17 @@ type Parser struct {
 	tokenizer            *Tokenizer
 	shouldIndex          bool
 	reportInternalErrors bool
+
+	limits             Limits
+	depth              int
+	aliases            int
+	fragments          int
+	isParsingOperation bool
+
+	recoverErrors      bool
+	definitionHasError bool
+	errorToken         int
+	limitExceeded      bool
 }
 
 // NewParser returns a new parser with all values properly initialized
@@ -55,6 +105,24 @@ func NewParser() *Parser {
 	}
 }
 
+// NewParserWithLimits returns a new parser which aborts parsing documents exceeding the limits
+func NewParserWithLimits(limits Limits) *Parser {
+	parser := NewParser()
+	parser.limits = limits
+	return parser
+}
+
+// NewParserWithErrorRecovery returns a new parser which doesn't stop at the first syntax error, e.g. for editor tooling.
+// After an error the parser skips the broken definition up to its closing brace or the next definition
+// starting a line, so it reports one error per broken definition.
+// Broken definitions are kept with all members which were parsed successfully,
+// so the document can still be used for validation and completion.
+func NewParserWithErrorRecovery() *Parser {
+	parser := NewParser()
+	parser.recoverErrors = true
+	return parser
+}
+
 // PrepareImport prepares the Parser for importing new Nodes into an AST without directly parsing the content
 func (p *Parser) PrepareImport(document *ast.Document, report *operationreport.Report) {
 	p.document = document
@@ -66,16 +134,53 @@ func (p *Parser) PrepareImport(document *ast.Document, report *operationreport.R
 func (p *Parser) Parse(document *ast.Document, report *operationreport.Report) {
 	p.document = document
 	p.report = report
-	p.tokenize()
+	if !p.tokenize() {
+		return
+	}
 	p.parse()
 }
 
-func (p *Parser) tokenize() {
-	p.tokenizer.Tokenize(&p.document.Input)
+func (p *Parser) tokenize() bool {
+	p.depth, p.aliases, p.fragments = 0, 0, 0
+	p.definitionHasError, p.limitExceeded = false, false
+
+	exceeding, ok := p.tokenizer.tokenize(&p.document.Input, p.limits.MaxTokens)
+	if !ok {
+		p.errLimitExceeded("number of tokens", p.limits.MaxTokens, exceeding.TextPosition)
+	}
+	return ok
+}
+
+func (p *Parser) errLimitExceeded(limitName string, limit int, pos position.Position) {
+	if p.hasError() {
+		return
+	}
+	p.limitExceeded = true
+	p.addExternalError(operationreport.ErrDocumentExceedsLimit(limitName, limit, pos))
+}
+
+// hasError reports whether parsing has to be stopped,
+// in recovery mode only errors of the current definition are taken into account
+func (p *Parser) hasError() bool {
+	if p.recoverErrors {
+		return p.definitionHasError
+	}
+	return p.report.HasErrors()
+}
+
+func (p *Parser) addExternalError(err operationreport.ExternalError) {
+	if !p.definitionHasError {
+		p.definitionHasError = true
+		p.errorToken = p.tokenizer.currentToken
+	}
+	p.report.AddExternalError(err)
 }
 
 func (p *Parser) parse() {
 	for {
+		p.definitionHasError = false
+		start := p.tokenizer.currentToken
+
 		key, literalReference := p.peekLiteral()
 
 		switch key {
@@ -118,12 +223,101 @@ func (p *Parser) parse() {
 			p.errUnexpectedToken(p.read(), keyword.EOF, keyword.LBRACE, keyword.COMMENT, keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT)
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
+			if !p.recoverErrors || p.limitExceeded {
+				return
+			}
+			p.synchronize(start)
+		}
+	}
+}
+
+// synchronize skips the remaining tokens of a definition which failed to parse.
+// Parsing continues at the first token after the error which starts a line
+// and could start a definition no further indented than the broken one,
+// or after the closing brace of the broken definition.
+// In case the line of the error starts with such a token, e.g. because of a missing closing brace,
+// parsing continues at this line.
+func (p *Parser) synchronize(start int) {
+	tokens := p.tokenizer.tokens[:p.tokenizer.maxTokens]
+	first := start + 1
+	if first >= len(tokens) {
+		return
+	}
+	column := tokens[first].TextPosition.CharStart
+
+	errorToken := p.errorToken
+	if errorToken < first {
+		errorToken = first
+	}
+	if errorToken >= len(tokens) {
+		errorToken = len(tokens) - 1
+	}
+
+	lineStart := errorToken
+	for lineStart > first && tokens[lineStart-1].TextPosition.LineEnd == tokens[errorToken].TextPosition.LineStart {
+		lineStart--
+	}
+	if lineStart > first && p.startsDefinition(lineStart, column) {
+		p.tokenizer.currentToken = lineStart - 1
+		return
+	}
+
+	braces := 0
+	for i := first; i <= errorToken; i++ {
+		braces += braceDelta(tokens[i].Keyword)
+	}
+
+	p.tokenizer.currentToken = errorToken
+	for next := errorToken + 1; next < len(tokens); next++ {
+		if p.startsDefinition(next, column) {
+			return
+		}
+		p.tokenizer.currentToken = next
+		delta := braceDelta(tokens[next].Keyword)
+		braces += delta
+		if delta < 0 && braces == 0 {
 			return
 		}
 	}
 }
 
+// startsDefinition reports whether the token at index i is the first token of its line,
+// indented no further than column and could start a definition
+func (p *Parser) startsDefinition(i int, column uint32) bool {
+	tok := p.tokenizer.tokens[i]
+	if tok.TextPosition.CharStart > column {
+		return false
+	}
+	if i > 0 && p.tokenizer.tokens[i-1].TextPosition.LineEnd == tok.TextPosition.LineStart {
+		return false
+	}
+
+	switch tok.Keyword {
+	case keyword.LBRACE, keyword.STRING, keyword.BLOCKSTRING:
+		return true
+	case keyword.IDENT:
+		switch p.identKeywordToken(tok) {
+		case identkeyword.ENUM, identkeyword.TYPE, identkeyword.UNION, identkeyword.QUERY, identkeyword.MUTATION,
+			identkeyword.SUBSCRIPTION, identkeyword.INPUT, identkeyword.EXTEND, identkeyword.SCHEMA, identkeyword.SCALAR,
+			identkeyword.FRAGMENT, identkeyword.INTERFACE, identkeyword.DIRECTIVE:
+			return true
+		}
+	}
+	return false
+}
+
+func braceDelta(key keyword.Keyword) int {
+	switch key {
+	case keyword.LBRACE:
+		return 1
+	case keyword.RBRACE:
+		return -1
+	default:
+		return 0
+	}
+}
+
 func (p *Parser) identKeywordToken(token token.Token) identkeyword.IdentKeyword {
 	return identkeyword.KeywordFromLiteral(p.document.Input.ByteSlice(token.Literal))
 }
@@ -134,11 +328,11 @@ func (p *Parser) identKeywordSliceRef(ref ast.ByteSliceReference) identkeyword.I
 
 func (p *Parser) errUnexpectedIdentKey(unexpected token.Token, unexpectedKey identkeyword.IdentKeyword, expectedKeywords ...identkeyword.IdentKeyword) {
 
-	if p.report.HasErrors() {
+	if p.hasError() {
 		return
 	}
 
-	p.report.AddExternalError(operationreport.ExternalError{
+	p.addExternalError(operationreport.ExternalError{
 		Message: fmt.Sprintf("unexpected literal - got: %s want one of: %v", unexpectedKey, expectedKeywords),
 		Locations: []operationreport.Location{
 			{
@@ -181,11 +375,11 @@ func (p *Parser) errUnexpectedIdentKey(unexpected token.Token, unexpectedKey ide
 
 func (p *Parser) errUnexpectedToken(unexpected token.Token, expectedKeywords ...keyword.Keyword) {
 
-	if p.report.HasErrors() {
+	if p.hasError() {
 		return
 	}
 
-	p.report.AddExternalError(operationreport.ExternalError{
+	p.addExternalError(operationreport.ExternalError{
 		Message: fmt.Sprintf("unexpected token - got: %s want one of: %v", unexpected.Keyword, expectedKeywords),
 		Locations: []operationreport.Location{
 			{
@@ -274,6 +468,9 @@ func (p *Parser) parseRootOperationTypeDefinitionList(list *ast.RootOperationTyp
 			_, operationType := p.mustReadOneOf(identkeyword.QUERY, identkeyword.MUTATION, identkeyword.SUBSCRIPTION)
 			colon := p.mustRead(keyword.COLON)
 			namedType := p.mustRead(keyword.IDENT)
+			if p.hasError() {
+				return
+			}
 
 			rootOperationTypeDefinition := ast.RootOperationTypeDefinition{
 				OperationType: p.operationTypeFromIdentKeyword(operationType),
@@ -303,7 +500,7 @@ func (p *Parser) parseRootOperationTypeDefinitionList(list *ast.RootOperationTyp
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -354,6 +551,10 @@ func (p *Parser) parseDirectiveList() (list ast.DirectiveList) {
 			directive.HasArguments = len(directive.Arguments.Refs) > 0
 		}
 
+		if p.hasError() {
+			return
+		}
+
 		p.document.Directives = append(p.document.Directives, directive)
 		ref := len(p.document.Directives) - 1
 
@@ -363,7 +564,7 @@ func (p *Parser) parseDirectiveList() (list ast.DirectiveList) {
 
 		list.Refs = append(list.Refs, ref)
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -389,6 +590,10 @@ Loop:
 		colon := p.mustRead(keyword.COLON)
 		value := p.ParseValue()
 
+		if p.hasError() {
+			return
+		}
+
 		argument := ast.Argument{
 			Name:     name.Literal,
 			Colon:    colon.TextPosition,
@@ -405,7 +610,7 @@ Loop:
 
 		list.Refs = append(list.Refs, ref)
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -484,7 +689,7 @@ func (p *Parser) parseObjectValue() (ref int, pos position.Position) {
 			return ast.InvalidRef, position.Position{}
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return ast.InvalidRef, position.Position{}
 		}
 	}
@@ -524,7 +729,7 @@ func (p *Parser) parseValueList() int {
 			list.Refs = append(list.Refs, ref)
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return ast.InvalidRef
 		}
 	}
@@ -765,7 +970,7 @@ func (p *Parser) parseImplementsInterfaces() (list ast.TypeList) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -787,6 +992,9 @@ func (p *Parser) parseFieldDefinitionList() (list ast.FieldDefinitionList) {
 			return
 		case keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT:
 			ref := p.parseFieldDefinition()
+			if p.hasError() {
+				return
+			}
 			if !refsInitialized {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 				refsInitialized = true
@@ -797,7 +1005,7 @@ func (p *Parser) parseFieldDefinitionList() (list ast.FieldDefinitionList) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -825,6 +1033,7 @@ func (p *Parser) parseFieldDefinition() int {
 	}
 
 	fieldDefinition.Name = nameToken.Literal
+	fieldDefinition.Position = nameToken.TextPosition
 	if p.peekEquals(keyword.LPAREN) {
 		fieldDefinition.ArgumentsDefinition = p.parseInputValueDefinitionList(keyword.RPAREN)
 		fieldDefinition.HasArgumentsDefinitions = len(fieldDefinition.ArgumentsDefinition.Refs) > 0
@@ -901,6 +1110,9 @@ func (p *Parser) parseInputValueDefinitionList(closingKeyword keyword.Keyword) (
 			return
 		case keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT:
 			ref := p.parseInputValueDefinition()
+			if p.hasError() {
+				return
+			}
 			if cap(list.Refs) == 0 {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
@@ -910,7 +1122,7 @@ func (p *Parser) parseInputValueDefinitionList(closingKeyword keyword.Keyword) (
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -931,7 +1143,9 @@ func (p *Parser) parseInputValueDefinition() int {
 		return ast.InvalidRef
 	}
 
-	inputValueDefinition.Name = p.read().Literal
+	nameToken := p.read()
+	inputValueDefinition.Name = nameToken.Literal
+	inputValueDefinition.Position = nameToken.TextPosition
 	inputValueDefinition.Colon = p.mustRead(keyword.COLON).TextPosition
 	inputValueDefinition.Type = p.ParseType()
 	if p.peekEquals(keyword.EQUALS) {
@@ -1100,7 +1314,7 @@ func (p *Parser) parseUnionMemberTypes() (list ast.TypeList) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -1142,6 +1356,9 @@ func (p *Parser) parseEnumValueDefinitionList() (list ast.EnumValueDefinitionLis
 		switch next {
 		case keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT:
 			ref := p.parseEnumValueDefinition()
+			if p.hasError() {
+				return
+			}
 			if cap(list.Refs) == 0 {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
@@ -1154,7 +1371,7 @@ func (p *Parser) parseEnumValueDefinitionList() (list ast.EnumValueDefinitionLis
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -1173,7 +1390,9 @@ func (p *Parser) parseEnumValueDefinition() int {
 		return ast.InvalidRef
 	}
 
-	enumValueDefinition.EnumValue = p.mustRead(keyword.IDENT).Literal
+	enumValue := p.mustRead(keyword.IDENT)
+	enumValueDefinition.EnumValue = enumValue.Literal
+	enumValueDefinition.Position = enumValue.TextPosition
 	if p.peekEquals(keyword.AT) {
 		enumValueDefinition.Directives = p.parseDirectiveList()
 		enumValueDefinition.HasDirectives = len(enumValueDefinition.Directives.Refs) > 0
@@ -1232,7 +1451,7 @@ func (p *Parser) parseDirectiveLocations(locations *ast.DirectiveLocations) {
 				raw := p.document.Input.ByteSlice(ident.Literal)
 				err := locations.SetFromRaw(raw)
 				if err != nil {
-					p.report.AddExternalError(operationreport.ExternalError{
+					p.addExternalError(operationreport.ExternalError{
 						Message: fmt.Sprintf("invalid directive location: %s", unsafebytes.BytesToString(raw)),
 						Locations: []operationreport.Location{
 							{
@@ -1267,7 +1486,7 @@ func (p *Parser) parseDirectiveLocations(locations *ast.DirectiveLocations) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -1281,6 +1500,13 @@ func (p *Parser) parseSelectionSet() (int, bool) {
 	lbraceToken := p.mustRead(keyword.LBRACE)
 	set.LBrace = lbraceToken.TextPosition
 
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.limits.MaxDepth > 0 && p.depth > p.limits.MaxDepth {
+		p.errLimitExceeded("depth", p.limits.MaxDepth, lbraceToken.TextPosition)
+		return ast.InvalidRef, false
+	}
+
 	for {
 		switch p.peek() {
 		case keyword.RBRACE:
@@ -1298,13 +1524,24 @@ func (p *Parser) parseSelectionSet() (int, bool) {
 			if cap(set.SelectionRefs) == 0 {
 				set.SelectionRefs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
+			if p.isParsingOperation && p.depth == 1 && p.limits.MaxRootFields > 0 && len(set.SelectionRefs) == p.limits.MaxRootFields {
+				p.errLimitExceeded("number of root fields", p.limits.MaxRootFields, p.read().TextPosition)
+				return ast.InvalidRef, false
+			}
 			ref := p.parseSelection()
-			set.SelectionRefs = append(set.SelectionRefs, ref)
+			if ref != ast.InvalidRef {
+				set.SelectionRefs = append(set.SelectionRefs, ref)
+			}
 		default:
 			p.errUnexpectedToken(p.read(), keyword.RBRACE, keyword.IDENT, keyword.SPREAD)
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
+			if p.recoverErrors && !p.limitExceeded && len(set.SelectionRefs) != 0 {
+				// keep the selections parsed so far, e.g. to complete fields of an incomplete document
+				p.document.SelectionSets = append(p.document.SelectionSets, set)
+				return len(p.document.SelectionSets) - 1, true
+			}
 			return ast.InvalidRef, false
 		}
 	}
@@ -1333,6 +1570,10 @@ func (p *Parser) parseFragmentSelection(spread position.Position) int {
 
 	var selection ast.Selection
 
+	if !p.countFragment(spread) {
+		return ast.InvalidRef
+	}
+
 	next, literal := p.peekLiteral()
 	switch next {
 	case keyword.LBRACE, keyword.AT:
@@ -1351,6 +1592,7 @@ func (p *Parser) parseFragmentSelection(spread position.Position) int {
 	default:
 		nextToken := p.read()
 		p.errUnexpectedToken(nextToken, keyword.IDENT)
+		return ast.InvalidRef
 	}
 
 	p.document.Selections = append(p.document.Selections, selection)
@@ -1367,6 +1609,11 @@ func (p *Parser) parseField() int {
 	}
 
 	if p.peek() == keyword.COLON {
+		p.aliases++
+		if p.limits.MaxAliases > 0 && p.aliases > p.limits.MaxAliases {
+			p.errLimitExceeded("number of aliases", p.limits.MaxAliases, firstToken.TextPosition)
+			return ast.InvalidRef
+		}
 		field.Alias.IsDefined = true
 		field.Alias.Name = firstToken.Literal
 		colonToken := p.read()
@@ -1385,6 +1632,10 @@ func (p *Parser) parseField() int {
 	if p.peekEquals(keyword.AT) {
 		field.Directives = p.parseDirectiveList()
 		field.HasDirectives = len(field.Directives.Refs) > 0
+		if p.limits.MaxDirectivesPerField > 0 && len(field.Directives.Refs) > p.limits.MaxDirectivesPerField {
+			p.errLimitExceeded("number of directives per field", p.limits.MaxDirectivesPerField, firstToken.TextPosition)
+			return ast.InvalidRef
+		}
 	}
 	if p.peekEquals(keyword.LBRACE) {
 		field.SelectionSet, field.HasSelections = p.parseSelectionSet()
@@ -1427,6 +1678,17 @@ func (p *Parser) parseInlineFragment(spread position.Position) int {
 	return len(p.document.InlineFragments) - 1
 }
 
+// countFragment counts fragment definitions, fragment spreads and inline fragments
+// and returns false if the document exceeds the maximum number of fragments
+func (p *Parser) countFragment(pos position.Position) bool {
+	p.fragments++
+	if p.limits.MaxFragments > 0 && p.fragments > p.limits.MaxFragments {
+		p.errLimitExceeded("number of fragments", p.limits.MaxFragments, pos)
+		return false
+	}
+	return true
+}
+
 func (p *Parser) parseTypeCondition() (typeCondition ast.TypeCondition) {
 	typeCondition.On = p.mustReadIdentKey(identkeyword.ON).TextPosition
 	typeCondition.Type = p.parseNamedType()
@@ -1437,6 +1699,9 @@ func (p *Parser) parseOperationDefinition() {
 
 	var operationDefinition ast.OperationDefinition
 
+	p.isParsingOperation = true
+	defer func() { p.isParsingOperation = false }()
+
 	next, literal := p.peekLiteral()
 	switch next {
 	case keyword.IDENT:
@@ -1509,6 +1774,9 @@ func (p *Parser) parseVariableDefinitionList() (list ast.VariableDefinitionList)
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
 			ref := p.parseVariableDefinition()
+			if p.hasError() {
+				return
+			}
 			if cap(list.Refs) == 0 {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
@@ -1518,7 +1786,7 @@ func (p *Parser) parseVariableDefinitionList() (list ast.VariableDefinitionList)
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasError() {
 			return
 		}
 	}
@@ -1557,6 +1825,9 @@ func (p *Parser) parseDefaultValue() ast.DefaultValue {
 func (p *Parser) parseFragmentDefinition() {
 	var fragmentDefinition ast.FragmentDefinition
 	fragmentDefinition.FragmentLiteral = p.mustReadIdentKey(identkeyword.FRAGMENT).TextPosition
+	if !p.countFragment(fragmentDefinition.FragmentLiteral) {
+		return
+	}
 	fragmentDefinition.Name = p.mustRead(keyword.IDENT).Literal
 	fragmentDefinition.TypeCondition = p.parseTypeCondition()
 	if p.peekEquals(keyword.AT) {
@@ -2320,6 +2320,177 @@ func TestErrorReport(t *testing.T) {
 	})
 }
 
+func TestParser_Limits(t *testing.T) {
+	run := func(t *testing.T, limits Limits, document, want string) {
+		t.Helper()
+
+		_, report := ParseGraphqlDocumentStringWithLimits(document, limits)
+		if want == "" {
+			if report.HasErrors() {
+				t.Fatalf("want no err, got: %s", report.Error())
+			}
+			return
+		}
+		if report.Error() != want {
+			t.Fatalf("want:\n%s\ngot:\n%s\n", want, report.Error())
+		}
+	}
+
+	t.Run("documents within the limits", func(t *testing.T) {
+		run(t, Limits{
+			MaxTokens:             30,
+			MaxDepth:              2,
+			MaxAliases:            1,
+			MaxDirectivesPerField: 1,
+			MaxRootFields:         2,
+			MaxFragments:          2,
+		}, `query Q { a: me { name @include(if: true) } ...F } fragment F on Query { me { id } }`, "")
+	})
+	t.Run("max tokens", func(t *testing.T) {
+		run(t, Limits{MaxTokens: 4}, `{ me { name } }`,
+			"external: the document exceeds the maximum number of tokens of 4, locations: [{Line:1 Column:13}], path: []")
+	})
+	t.Run("max depth", func(t *testing.T) {
+		run(t, Limits{MaxDepth: 2}, `{ me { friends { name } } }`,
+			"external: the document exceeds the maximum depth of 2, locations: [{Line:1 Column:16}], path: []")
+	})
+	t.Run("max aliases", func(t *testing.T) {
+		run(t, Limits{MaxAliases: 1}, `{ a: me { name } b: me { name } }`,
+			"external: the document exceeds the maximum number of aliases of 1, locations: [{Line:1 Column:18}], path: []")
+	})
+	t.Run("max directives per field", func(t *testing.T) {
+		run(t, Limits{MaxDirectivesPerField: 1}, `{ me @a @b { name } }`,
+			"external: the document exceeds the maximum number of directives per field of 1, locations: [{Line:1 Column:3}], path: []")
+	})
+	t.Run("max root fields", func(t *testing.T) {
+		run(t, Limits{MaxRootFields: 1}, `{ me { name id } other }`,
+			"external: the document exceeds the maximum number of root fields of 1, locations: [{Line:1 Column:18}], path: []")
+	})
+	t.Run("max root fields does not apply to fragment definitions", func(t *testing.T) {
+		run(t, Limits{MaxRootFields: 1}, `fragment F on Query { me other }`, "")
+	})
+	t.Run("max fragments", func(t *testing.T) {
+		run(t, Limits{MaxFragments: 2}, `{ ...F ... on Query { me } } fragment F on Query { me }`,
+			"external: the document exceeds the maximum number of fragments of 2, locations: [{Line:1 Column:30}], path: []")
+	})
+}
+
+func TestParser_ErrorRecovery(t *testing.T) {
+	messages := func(report operationreport.Report) (out []string) {
+		for _, err := range report.ExternalErrors {
+			out = append(out, fmt.Sprintf("%s %+v", err.Message, err.Locations))
+		}
+		return out
+	}
+	rootNodeNames := func(doc *ast.Document) (out []string) {
+		for _, node := range doc.RootNodes {
+			out = append(out, doc.NodeNameString(node))
+		}
+		return out
+	}
+	fieldNames := func(doc *ast.Document, refs []int) (out []string) {
+		for _, ref := range refs {
+			out = append(out, doc.FieldDefinitionNameString(ref))
+		}
+		return out
+	}
+
+	t.Run("valid document", func(t *testing.T) {
+		schema, err := os.ReadFile("./testdata/starwars.schema.graphql")
+		require.NoError(t, err)
+
+		recovered, report := ParseGraphqlDocumentStringWithErrorRecovery(string(schema))
+		require.False(t, report.HasErrors())
+
+		doc, _ := ParseGraphqlDocumentBytes(schema)
+		assert.Equal(t, rootNodeNames(&doc), rootNodeNames(&recovered))
+	})
+
+	t.Run("missing closing brace", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithErrorRecovery(`
+type Query {
+	user: User
+
+type User {
+	id: ID!
+	name: String
+}`)
+
+		assert.Equal(t, []string{"unexpected token - got: IDENT want one of: [COLON] [{Line:5 Column:6}]"}, messages(report))
+		assert.Equal(t, []string{"Query", "User"}, rootNodeNames(&doc))
+		assert.Equal(t, []string{"user"}, fieldNames(&doc, doc.ObjectTypeDefinitions[0].FieldsDefinition.Refs))
+		assert.Equal(t, []string{"id", "name"}, fieldNames(&doc, doc.ObjectTypeDefinitions[1].FieldsDefinition.Refs))
+	})
+
+	t.Run("one error per broken definition", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithErrorRecovery(`
+type A { a: Int b: }
+type B { b: Int }
+input C { c String }
+scalar D
+directive @e on UNKNOWN
+union F = A | B`)
+
+		assert.Equal(t, []string{
+			"unexpected token - got: RBRACE want one of: [IDENT LBRACK] [{Line:2 Column:20}]",
+			"unexpected token - got: IDENT want one of: [COLON] [{Line:4 Column:13}]",
+			"invalid directive location: UNKNOWN [{Line:6 Column:17}]",
+		}, messages(report))
+		assert.Equal(t, []string{"A", "B", "C", "D", "e", "F"}, rootNodeNames(&doc))
+		assert.Equal(t, []string{"a"}, fieldNames(&doc, doc.ObjectTypeDefinitions[0].FieldsDefinition.Refs))
+		assert.False(t, doc.InputObjectTypeDefinitions[0].HasInputFieldsDefinition)
+	})
+
+	t.Run("incomplete operation keeps the parsed selections", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithErrorRecovery(`
+query Q($id: ID!, $) {
+	user(id: $id) {
+		id
+		na`)
+
+		assert.Equal(t, []string{
+			"unexpected token - got: RPAREN want one of: [IDENT] [{Line:2 Column:20}]",
+		}, messages(report))
+		require.Len(t, doc.OperationDefinitions, 1)
+		operation := doc.OperationDefinitions[0]
+		assert.Len(t, operation.VariableDefinitions.Refs, 1)
+		assert.True(t, operation.HasSelections)
+
+		doc, report = ParseGraphqlDocumentStringWithErrorRecovery(`
+query Q($id: ID!) {
+	user(id: $id) {
+		id
+		na`)
+
+		assert.Equal(t, []string{
+			"unexpected token - got: EOF want one of: [RBRACE IDENT SPREAD] [{Line:0 Column:0}]",
+		}, messages(report))
+		require.Len(t, doc.OperationDefinitions, 1)
+		operation = doc.OperationDefinitions[0]
+		require.True(t, operation.HasSelections)
+		user := doc.Selections[doc.SelectionSets[operation.SelectionSet].SelectionRefs[0]].Ref
+		assert.Equal(t, "user", doc.FieldNameString(user))
+		require.True(t, doc.Fields[user].HasSelections)
+
+		var names []string
+		for _, ref := range doc.SelectionSets[doc.Fields[user].SelectionSet].SelectionRefs {
+			names = append(names, doc.FieldNameString(doc.Selections[ref].Ref))
+		}
+		assert.Equal(t, []string{"id", "na"}, names)
+	})
+
+	t.Run("limits abort parsing", func(t *testing.T) {
+		parser := NewParserWithErrorRecovery()
+		parser.limits = Limits{MaxDepth: 1}
+		doc := ast.NewSmallDocument()
+		doc.Input.ResetInputString(`{ a { b } } { c { d } }`)
+		report := operationreport.Report{}
+		parser.Parse(doc, &report)
+
+		assert.Len(t, report.ExternalErrors, 1)
+	})
+}
+
 func TestParseStarwars(t *testing.T) {
 
 	starWarsSchema, err := os.ReadFile("./testdata/starwars.schema.graphql")
@@ -26,15 +26,26 @@ func NewTokenizer() *Tokenizer {
 }
 
 func (t *Tokenizer) Tokenize(input *ast.Input) {
+	t.tokenize(input, 0)
+}
+
+// tokenize reads all tokens of the input, if the number of tokens exceeds the limit,
+// it stops reading and returns the first token exceeding the limit. A limit of 0 disables the check.
+func (t *Tokenizer) tokenize(input *ast.Input, limit int) (exceeding token.Token, ok bool) {
 	t.lexer.SetInput(input)
 	t.tokens = t.tokens[:0]
+	t.currentToken = -1
 
 	for {
 		next := t.lexer.Read()
 		if next.Keyword == keyword.EOF {
 			t.maxTokens = len(t.tokens)
-			t.currentToken = -1
-			return
+			return token.Token{}, true
+		}
+		if limit > 0 && len(t.tokens) == limit {
+			t.tokens = t.tokens[:0]
+			t.maxTokens = 0
+			return next, false
 		}
 		t.tokens = append(t.tokens, next)
 	}
@@ -65,6 +65,7 @@ type Printer struct {
 	walker     astvisitor.SimpleWalker
 	registered bool
 	debug      bool
+	comments   *commentIndex
 }
 
 // Print starts the actual AST printing
@@ -72,6 +73,7 @@ type Printer struct {
 func (p *Printer) Print(document, definition *ast.Document, out io.Writer) error {
 	p.visitor.indent = p.indent
 	p.visitor.debug = p.debug
+	p.visitor.comments = p.comments
 	p.visitor.err = nil
 	p.visitor.document = document
 	p.visitor.out = out
@@ -94,6 +96,9 @@ type printVisitor struct {
 	isFirstDirectiveLocation   bool
 	isDirectiveRepeatable      bool
 	debug                      bool
+	comments                   *commentIndex
+	// argumentDepth is the indentation of an argument list printed with one argument per line
+	argumentDepth int
 }
 
 func (p *printVisitor) write(data []byte) {
@@ -105,6 +110,10 @@ func (p *printVisitor) write(data []byte) {
 
 func (p *printVisitor) indentationDepth() (depth int) {
 
+	if p.argumentDepth != 0 {
+		return p.argumentDepth
+	}
+
 	if len(p.Ancestors) == 0 {
 		return 0
 	}
@@ -170,12 +179,6 @@ func (p *printVisitor) LeaveDirective(ref int) {
 	case ast.NodeKindField:
 		if p.document.FieldHasSelections(ancestor.Ref) {
 			p.write(literal.SPACE)
-		} else if len(p.SelectionsAfter) > 0 {
-			if p.indent != nil {
-				p.write(literal.LINETERMINATOR)
-			} else {
-				p.write(literal.SPACE)
-			}
 		}
 	case ast.NodeKindVariableDefinition:
 		if !p.document.VariableDefinitionsAfter(ancestor.Ref) {
@@ -209,8 +212,19 @@ func (p *printVisitor) LeaveDirective(ref int) {
 }
 
 func (p *printVisitor) EnterVariableDefinition(ref int) {
-	if !p.document.VariableDefinitionsBefore(ref) {
+	ancestor := p.Ancestors[len(p.Ancestors)-1]
+	isFirst := !p.document.VariableDefinitionsBefore(ref)
+	if isFirst {
 		p.write(literal.LPAREN)
+		if p.hasArgumentComments(ancestor) {
+			p.argumentDepth = p.indentationDepth() + 2
+		}
+	}
+
+	if p.argumentDepth != 0 {
+		p.write(literal.LINETERMINATOR)
+		p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindVariableDefinition, Ref: ref}), isFirst)
+		p.writeIndentation(p.argumentDepth)
 	}
 
 	p.must(p.document.PrintValue(p.document.VariableDefinitions[ref].VariableValue, p.out))
@@ -232,31 +246,48 @@ func (p *printVisitor) EnterVariableDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveVariableDefinition(ref int) {
+	p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindVariableDefinition, Ref: ref}))
 	if !p.document.VariableDefinitionsAfter(ref) {
+		p.leaveArguments(p.Ancestors[len(p.Ancestors)-1])
 		p.write(literal.RPAREN)
-	} else {
+	} else if p.argumentDepth == 0 {
 		p.write(literal.COMMA)
 		p.write(literal.SPACE)
 	}
 }
 
 func (p *printVisitor) EnterArgument(ref int) {
-	if len(p.document.ArgumentsBefore(p.Ancestors[len(p.Ancestors)-1], ref)) == 0 {
+	ancestor := p.Ancestors[len(p.Ancestors)-1]
+	isFirst := len(p.document.ArgumentsBefore(ancestor, ref)) == 0
+	if isFirst {
 		p.write(literal.LPAREN)
-	} else {
+		if ancestor.Kind == ast.NodeKindField && p.hasArgumentComments(ancestor) {
+			p.argumentDepth = p.indentationDepth() + 2
+		}
+	} else if p.argumentDepth == 0 {
 		p.write(literal.COMMA)
 		p.write(literal.SPACE)
 	}
+
+	if p.argumentDepth != 0 {
+		p.write(literal.LINETERMINATOR)
+		p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindArgument, Ref: ref}), isFirst)
+		p.writeIndentation(p.argumentDepth)
+	}
 	p.must(p.document.PrintArgument(ref, p.out))
 }
 
 func (p *printVisitor) LeaveArgument(ref int) {
-	if len(p.document.ArgumentsAfter(p.Ancestors[len(p.Ancestors)-1], ref)) == 0 {
+	ancestor := p.Ancestors[len(p.Ancestors)-1]
+	p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindArgument, Ref: ref}))
+	if len(p.document.ArgumentsAfter(ancestor, ref)) == 0 {
+		p.leaveArguments(ancestor)
 		p.write(literal.RPAREN)
 	}
 }
 
 func (p *printVisitor) EnterOperationDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindOperationDefinition, Ref: ref}), true)
 
 	hasName := p.document.OperationDefinitions[ref].Name.Length() > 0
 	hasVariables := p.document.OperationDefinitions[ref].HasVariableDefinitions
@@ -305,6 +336,9 @@ func (p *printVisitor) EnterSelectionSet(ref int) {
 	} else {
 		p.write(literal.LBRACE)
 	}
+	if len(p.Ancestors) != 0 {
+		p.writeTrailingComments(p.nodeComments(p.Ancestors[len(p.Ancestors)-1]))
+	}
 
 	if p.indent != nil {
 		p.write(literal.LINETERMINATOR)
@@ -315,7 +349,11 @@ func (p *printVisitor) LeaveSelectionSet(ref int) {
 	if p.indent != nil {
 		p.write(literal.LINETERMINATOR)
 	}
+	p.writeClosingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindSelectionSet, Ref: ref}), p.indentationDepth()+2)
 	p.writeIndented(literal.RBRACE)
+	if comments := p.nodeComments(p.Ancestors[len(p.Ancestors)-1]); comments != nil {
+		p.writeEndOfLineComments(comments.behind)
+	}
 }
 
 func (p *printVisitor) EnterField(ref int) {
@@ -324,6 +362,7 @@ func (p *printVisitor) EnterField(ref int) {
 		p.write([]byte(strconv.Itoa(ref)))
 		p.write(literal.LINETERMINATOR)
 	}
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindField, Ref: ref}), len(p.SelectionsBefore) == 0)
 
 	if p.document.Fields[ref].Alias.IsDefined {
 		p.writeIndented(p.document.Input.ByteSlice(p.document.Fields[ref].Alias.Name))
@@ -339,12 +378,11 @@ func (p *printVisitor) EnterField(ref int) {
 }
 
 func (p *printVisitor) LeaveField(ref int) {
+	if !p.document.FieldHasSelections(ref) {
+		// comments of fields with selections are written behind the opening brace
+		p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindField, Ref: ref}))
+	}
 	if len(p.SelectionsAfter) != 0 {
-		if !p.document.FieldHasSelections(ref) && p.document.FieldHasDirectives(ref) {
-			// space was added after directives
-			return
-		}
-
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
 		} else {
@@ -354,6 +392,7 @@ func (p *printVisitor) LeaveField(ref int) {
 }
 
 func (p *printVisitor) EnterFragmentSpread(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: ref}), len(p.SelectionsBefore) == 0)
 	p.writeIndented(literal.SPREAD)
 	p.write(p.document.Input.ByteSlice(p.document.FragmentSpreads[ref].FragmentName))
 	if p.document.FragmentSpreads[ref].HasDirectives {
@@ -362,6 +401,7 @@ func (p *printVisitor) EnterFragmentSpread(ref int) {
 }
 
 func (p *printVisitor) LeaveFragmentSpread(ref int) {
+	p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: ref}))
 	ancestor := p.Ancestors[len(p.Ancestors)-1]
 	if p.document.SelectionsAfterFragmentSpread(ref, ancestor) {
 		if p.indent != nil {
@@ -379,6 +419,7 @@ func (p *printVisitor) EnterInlineFragment(ref int) {
 		p.write([]byte(strconv.Itoa(ref)))
 		p.write(literal.LINETERMINATOR)
 	}
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInlineFragment, Ref: ref}), len(p.SelectionsBefore) == 0)
 	p.writeIndented(literal.SPREAD)
 
 	if p.document.InlineFragmentHasTypeCondition(ref) && !p.document.InlineFragmentIsOfTheSameType(ref) {
@@ -404,6 +445,8 @@ func (p *printVisitor) LeaveInlineFragment(ref int) {
 }
 
 func (p *printVisitor) EnterFragmentDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindFragmentDefinition, Ref: ref}), true)
+
 	p.write(literal.FRAGMENT)
 	p.write(literal.SPACE)
 	p.write(p.document.Input.ByteSlice(p.document.FragmentDefinitions[ref].Name))
@@ -427,6 +470,7 @@ func (p *printVisitor) LeaveFragmentDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterObjectTypeDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref}), true)
 
 	if p.document.ObjectTypeDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.ObjectTypeDefinitions[ref].Description, nil, 0, p.out))
@@ -457,6 +501,7 @@ func (p *printVisitor) EnterObjectTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveObjectTypeDefinition(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -468,6 +513,7 @@ func (p *printVisitor) LeaveObjectTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterObjectTypeExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindObjectTypeExtension, Ref: ref}), true)
 
 	if p.document.ObjectTypeExtensions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.ObjectTypeExtensions[ref].Description, nil, 0, p.out))
@@ -486,6 +532,7 @@ func (p *printVisitor) EnterObjectTypeExtension(ref int) {
 }
 
 func (p *printVisitor) LeaveObjectTypeExtension(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindObjectTypeExtension, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindObjectTypeExtension, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -503,6 +550,7 @@ func (p *printVisitor) EnterFieldDefinition(ref int) {
 			p.write(literal.LINETERMINATOR)
 		}
 	}
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}), p.document.FieldDefinitionIsFirst(ref, p.Ancestors[len(p.Ancestors)-1]))
 	if p.document.FieldDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.FieldDefinitions[ref].Description, p.indent, p.indentationDepth(), p.out))
 		p.write(literal.LINETERMINATOR)
@@ -514,11 +562,13 @@ func (p *printVisitor) LeaveFieldDefinition(ref int) {
 	if !p.document.FieldDefinitionHasDirectives(ref) {
 		p.writeFieldType(ref)
 	}
+	p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}))
 
 	if p.document.FieldDefinitionIsLast(ref, p.Ancestors[len(p.Ancestors)-1]) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
 		}
+		p.writeClosingComments(p.nodeComments(p.Ancestors[len(p.Ancestors)-1]), 2)
 		p.write(literal.RBRACE)
 	} else {
 		if p.indent != nil {
@@ -530,21 +580,33 @@ func (p *printVisitor) LeaveFieldDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterInputValueDefinition(ref int) {
-	if p.document.InputValueDefinitionIsFirst(ref, p.Ancestors[len(p.Ancestors)-1]) {
+	ancestor := p.Ancestors[len(p.Ancestors)-1]
+	if p.document.InputValueDefinitionIsFirst(ref, ancestor) {
 		p.write(p.inputValueDefinitionOpener)
+		if ancestor.Kind == ast.NodeKindFieldDefinition && p.hasArgumentComments(ancestor) {
+			p.argumentDepth = p.indentationDepth() + 2
+		}
 	}
 	if p.indent != nil {
-		switch p.Ancestors[len(p.Ancestors)-1].Kind {
+		switch ancestor.Kind {
 		case ast.NodeKindDirectiveDefinition, ast.NodeKindInputObjectTypeDefinition, ast.NodeKindInputObjectTypeExtension:
 			p.write(literal.LINETERMINATOR)
+		case ast.NodeKindFieldDefinition:
+			if p.argumentDepth != 0 {
+				p.write(literal.LINETERMINATOR)
+			}
 		}
 	}
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}), p.document.InputValueDefinitionIsFirst(ref, p.Ancestors[len(p.Ancestors)-1]))
 	if p.document.InputValueDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.InputValueDefinitions[ref].Description, p.indent, p.indentationDepth(), p.out))
 		p.write(literal.LINETERMINATOR)
 	}
-	switch p.Ancestors[len(p.Ancestors)-1].Kind {
-	case ast.NodeKindDirectiveDefinition, ast.NodeKindInputObjectTypeDefinition, ast.NodeKindInputObjectTypeExtension:
+	switch {
+	case ancestor.Kind == ast.NodeKindDirectiveDefinition,
+		ancestor.Kind == ast.NodeKindInputObjectTypeDefinition,
+		ancestor.Kind == ast.NodeKindInputObjectTypeExtension,
+		p.argumentDepth != 0:
 		p.writeIndented(p.document.InputValueDefinitionNameBytes(ref))
 	default:
 		p.write(p.document.InputValueDefinitionNameBytes(ref))
@@ -561,15 +623,19 @@ func (p *printVisitor) EnterInputValueDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveInputValueDefinition(ref int) {
+	p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}))
 	if p.document.InputValueDefinitionIsLast(ref, p.Ancestors[len(p.Ancestors)-1]) {
 		if p.indent != nil {
 			switch p.Ancestors[len(p.Ancestors)-1].Kind {
 			case ast.NodeKindDirectiveDefinition, ast.NodeKindInputObjectTypeDefinition, ast.NodeKindInputObjectTypeExtension:
 				p.write(literal.LINETERMINATOR)
+				p.writeClosingComments(p.nodeComments(p.Ancestors[len(p.Ancestors)-1]), 2)
+			case ast.NodeKindFieldDefinition:
+				p.leaveArguments(p.Ancestors[len(p.Ancestors)-1])
 			}
 		}
 		p.write(p.inputValueDefinitionCloser)
-	} else {
+	} else if p.argumentDepth == 0 {
 		if len(p.Ancestors) > 0 {
 			// check enclosing type kind
 			if p.Ancestors[len(p.Ancestors)-1].Kind == ast.NodeKindFieldDefinition {
@@ -584,6 +650,7 @@ func (p *printVisitor) LeaveInputValueDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterInterfaceTypeDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInterfaceTypeDefinition, Ref: ref}), true)
 
 	if p.document.InterfaceTypeDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.InterfaceTypeDefinitions[ref].Description, nil, 0, p.out))
@@ -600,6 +667,7 @@ func (p *printVisitor) EnterInterfaceTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveInterfaceTypeDefinition(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindInterfaceTypeDefinition, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindInterfaceTypeDefinition, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -611,6 +679,7 @@ func (p *printVisitor) LeaveInterfaceTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterInterfaceTypeExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInterfaceTypeExtension, Ref: ref}), true)
 
 	if p.document.InterfaceTypeExtensions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.InterfaceTypeExtensions[ref].Description, nil, 0, p.out))
@@ -629,6 +698,7 @@ func (p *printVisitor) EnterInterfaceTypeExtension(ref int) {
 }
 
 func (p *printVisitor) LeaveInterfaceTypeExtension(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindInterfaceTypeExtension, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindInterfaceTypeExtension, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -640,6 +710,7 @@ func (p *printVisitor) LeaveInterfaceTypeExtension(ref int) {
 }
 
 func (p *printVisitor) EnterScalarTypeDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindScalarTypeDefinition, Ref: ref}), true)
 
 	if p.document.ScalarTypeDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.ScalarTypeDefinitions[ref].Description, nil, 0, p.out))
@@ -666,6 +737,7 @@ func (p *printVisitor) LeaveScalarTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterScalarTypeExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindScalarTypeExtension, Ref: ref}), true)
 
 	if p.document.ScalarTypeExtensions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.ScalarTypeExtensions[ref].Description, nil, 0, p.out))
@@ -694,6 +766,7 @@ func (p *printVisitor) LeaveScalarTypeExtension(ref int) {
 }
 
 func (p *printVisitor) EnterUnionTypeDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindUnionTypeDefinition, Ref: ref}), true)
 
 	if p.document.UnionTypeDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.UnionTypeDefinitions[ref].Description, nil, 0, p.out))
@@ -720,6 +793,7 @@ func (p *printVisitor) LeaveUnionTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterUnionTypeExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindUnionTypeExtension, Ref: ref}), true)
 
 	if p.document.UnionTypeExtensions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.UnionTypeExtensions[ref].Description, nil, 0, p.out))
@@ -766,6 +840,7 @@ func (p *printVisitor) LeaveUnionMemberType(ref int) {
 }
 
 func (p *printVisitor) EnterEnumTypeDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindEnumTypeDefinition, Ref: ref}), true)
 
 	if p.document.EnumTypeDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.EnumTypeDefinitions[ref].Description, nil, 0, p.out))
@@ -781,6 +856,7 @@ func (p *printVisitor) EnterEnumTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveEnumTypeDefinition(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindEnumTypeDefinition, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindEnumTypeDefinition, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -792,6 +868,7 @@ func (p *printVisitor) LeaveEnumTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterEnumTypeExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindEnumTypeExtension, Ref: ref}), true)
 
 	if p.document.EnumTypeExtensions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.EnumTypeExtensions[ref].Description, nil, 0, p.out))
@@ -809,6 +886,7 @@ func (p *printVisitor) EnterEnumTypeExtension(ref int) {
 }
 
 func (p *printVisitor) LeaveEnumTypeExtension(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindEnumTypeExtension, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindEnumTypeExtension, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -827,6 +905,7 @@ func (p *printVisitor) EnterEnumValueDefinition(ref int) {
 			p.write(literal.LINETERMINATOR)
 		}
 	}
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: ref}), p.document.EnumValueDefinitionIsFirst(ref, p.Ancestors[len(p.Ancestors)-1]))
 	if p.document.EnumValueDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.EnumValueDefinitions[ref].Description, p.indent, p.indentationDepth(), p.out))
 		p.write(literal.LINETERMINATOR)
@@ -835,10 +914,12 @@ func (p *printVisitor) EnterEnumValueDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveEnumValueDefinition(ref int) {
+	p.writeTrailingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: ref}))
 	if p.document.EnumValueDefinitionIsLast(ref, p.Ancestors[len(p.Ancestors)-1]) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
 		}
+		p.writeClosingComments(p.nodeComments(p.Ancestors[len(p.Ancestors)-1]), 2)
 		p.write(literal.RBRACE)
 	} else {
 		if p.indent != nil {
@@ -850,6 +931,7 @@ func (p *printVisitor) LeaveEnumValueDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterInputObjectTypeDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: ref}), true)
 
 	if p.document.InputObjectTypeDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.InputObjectTypeDefinitions[ref].Description, nil, 0, p.out))
@@ -868,6 +950,7 @@ func (p *printVisitor) EnterInputObjectTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveInputObjectTypeDefinition(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -879,6 +962,7 @@ func (p *printVisitor) LeaveInputObjectTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterInputObjectTypeExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindInputObjectTypeExtension, Ref: ref}), true)
 
 	if p.document.InputObjectTypeExtensions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.InputObjectTypeExtensions[ref].Description, nil, 0, p.out))
@@ -899,6 +983,7 @@ func (p *printVisitor) EnterInputObjectTypeExtension(ref int) {
 }
 
 func (p *printVisitor) LeaveInputObjectTypeExtension(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindInputObjectTypeExtension, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindInputObjectTypeExtension, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -910,6 +995,8 @@ func (p *printVisitor) LeaveInputObjectTypeExtension(ref int) {
 }
 
 func (p *printVisitor) EnterDirectiveDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindDirectiveDefinition, Ref: ref}), true)
+
 	if p.document.DirectiveDefinitions[ref].Description.IsDefined {
 		p.must(p.document.PrintDescription(p.document.DirectiveDefinitions[ref].Description, nil, 0, p.out))
 		p.write(literal.LINETERMINATOR)
@@ -927,6 +1014,7 @@ func (p *printVisitor) EnterDirectiveDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveDirectiveDefinition(ref int) {
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindDirectiveDefinition, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindDirectiveDefinition, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -1002,6 +1090,8 @@ func (p *printVisitor) LeaveDirectiveLocation(location ast.DirectiveLocation) {
 }
 
 func (p *printVisitor) EnterSchemaDefinition(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindSchemaDefinition, Ref: ref}), true)
+
 	p.write(literal.SCHEMA)
 	p.write(literal.SPACE)
 }
@@ -1010,7 +1100,9 @@ func (p *printVisitor) LeaveSchemaDefinition(ref int) {
 	if p.indent != nil {
 		p.write(literal.LINETERMINATOR)
 	}
+	p.writeClosingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindSchemaDefinition, Ref: ref}), 2)
 	p.write(literal.RBRACE)
+	p.writeBehindComments(ast.Node{Kind: ast.NodeKindSchemaDefinition, Ref: ref})
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindSchemaDefinition, Ref: ref}) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -1022,6 +1114,8 @@ func (p *printVisitor) LeaveSchemaDefinition(ref int) {
 }
 
 func (p *printVisitor) EnterSchemaExtension(ref int) {
+	p.writeLeadingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindSchemaExtension, Ref: ref}), true)
+
 	p.write(literal.EXTEND)
 	p.write(literal.SPACE)
 	p.write(literal.SCHEMA)
@@ -1033,7 +1127,9 @@ func (p *printVisitor) LeaveSchemaExtension(ref int) {
 		p.write(literal.LINETERMINATOR)
 	}
 	if len(p.document.SchemaExtensions[ref].SchemaDefinition.RootOperationTypeDefinitions.Refs) > 0 {
+		p.writeClosingComments(p.nodeComments(ast.Node{Kind: ast.NodeKindSchemaExtension, Ref: ref}), 2)
 		p.write(literal.RBRACE)
+		p.writeBehindComments(ast.Node{Kind: ast.NodeKindSchemaExtension, Ref: ref})
 	}
 	if !p.document.NodeIsLastRootNode(ast.Node{Kind: ast.NodeKindSchemaExtension, Ref: ref}) {
 		if p.indent != nil {
@@ -1052,6 +1148,7 @@ func (p *printVisitor) EnterRootOperationTypeDefinition(ref int) {
 			p.write(literal.LINETERMINATOR)
 		}
 	}
+	p.writeLeadingComments(p.rootOperationTypeComments(ref), p.document.RootOperationTypeDefinitionIsFirstInSchemaDefinition(ref, p.Ancestors[len(p.Ancestors)-1]))
 	switch p.document.RootOperationTypeDefinitions[ref].OperationType {
 	case ast.OperationTypeQuery:
 		p.writeIndented(literal.QUERY)
@@ -1066,6 +1163,7 @@ func (p *printVisitor) EnterRootOperationTypeDefinition(ref int) {
 }
 
 func (p *printVisitor) LeaveRootOperationTypeDefinition(ref int) {
+	p.writeTrailingComments(p.rootOperationTypeComments(ref))
 	if !p.document.RootOperationTypeDefinitionIsLastInSchemaDefinition(ref, p.Ancestors[len(p.Ancestors)-1]) {
 		if p.indent != nil {
 			p.write(literal.LINETERMINATOR)
@@ -0,0 +1,693 @@
+package astprinter
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/keyword"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+)
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	// SortTypes orders the root definitions: schema definitions first, then directive definitions,
+	// then all type definitions and extensions by name. Executable definitions keep their order.
+	SortTypes bool
+	// SortFields orders the fields of object types, interfaces and input objects by name.
+	// Arguments and enum values keep their order as it is significant for introspection.
+	// Blank lines between reordered members are dropped, as they no longer group the same members.
+	SortFields bool
+}
+
+// Format parses a GraphQL document and prints it with indentation like PrintIndent
+// while keeping its comments and the blank lines between members.
+//
+// Comments are attached to the nearest node: root definitions, fields, input fields, arguments, enum values,
+// root operation types, variable definitions and the selections of executable definitions.
+// Comments behind a closing brace or parenthesis stay behind it.
+// Argument and variable lists containing comments are printed with one entry per line.
+// Comments inside values and directive arguments are moved in front of the enclosing node.
+// Formatting the result again yields the same output, so Format can be used to check that files are formatted.
+func Format(input []byte, options FormatOptions) ([]byte, error) {
+	document, report := astparser.ParseGraphqlDocumentBytes(input)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	comments := collectComments(&document)
+
+	if options.SortTypes {
+		sortRootNodes(&document)
+	}
+	if options.SortFields {
+		comments.reordered = sortFieldDefinitions(&document)
+	}
+
+	buff := &bytes.Buffer{}
+	printer := Printer{
+		indent:   literal.SPACE,
+		comments: comments,
+	}
+	if err := printer.Print(&document, nil, buff); err != nil {
+		return nil, err
+	}
+
+	if len(document.RootNodes) != 0 {
+		buff.Write(literal.LINETERMINATOR)
+	}
+	if len(comments.document) != 0 {
+		printer.visitor.out = buff
+		printer.visitor.writeComments(comments.document, 0, len(document.RootNodes) != 0 && comments.blankLineBefore(comments.document[0].line))
+	}
+
+	return buff.Bytes(), nil
+}
+
+// FormatString is the same as Format but accepts and returns strings.
+func FormatString(input string, options FormatOptions) (string, error) {
+	out, err := Format([]byte(input), options)
+	return string(out), err
+}
+
+type comment struct {
+	text []byte
+	line uint32
+}
+
+type nodeComments struct {
+	member   bool      // comments at the end of a member's line stay there instead of moving in front of it
+	line     uint32    // first line of the node including its description, excluding leading comments
+	ownLine  bool      // the node starts its line, a blank line in front of it is kept
+	leading  []comment // own line comments in front of the node
+	trailing []comment // comments at the end of the node's line
+	closing  []comment // comments in front of the closing brace of the node's members
+	behind   []comment // comments at the end of the line of the closing brace or parenthesis of the node's members
+
+	// owner is the node whose members contain the node, end is the index of the closing token.
+	// Comments behind the closing token belong to the owner, they are written behind the closing token if ownerBehind is set.
+	owner       *nodeComments
+	end         int
+	ownerBehind bool
+}
+
+// commentIndex holds the comments of a document attached to the nodes they belong to.
+type commentIndex struct {
+	nodes              map[ast.Node]*nodeComments
+	rootOperationTypes map[int]*nodeComments
+	document           []comment         // comments after the last definition
+	blank              []bool            // blank lines of the input, indexed by line number
+	reordered          map[ast.Node]bool // nodes with sorted members, blank lines between them are dropped
+}
+
+func (c *commentIndex) node(node ast.Node) *nodeComments {
+	comments, ok := c.nodes[node]
+	if !ok {
+		comments = &nodeComments{member: isMemberNodeKind(node.Kind)}
+		c.nodes[node] = comments
+	}
+	return comments
+}
+
+func (c *commentIndex) rootOperationType(ref int) *nodeComments {
+	comments, ok := c.rootOperationTypes[ref]
+	if !ok {
+		comments = &nodeComments{member: true}
+		c.rootOperationTypes[ref] = comments
+	}
+	return comments
+}
+
+func (c *commentIndex) blankLineBefore(line uint32) bool {
+	return line > 1 && int(line-1) < len(c.blank) && c.blank[line-1]
+}
+
+func collectComments(document *ast.Document) *commentIndex {
+	index := &commentIndex{
+		nodes:              map[ast.Node]*nodeComments{},
+		rootOperationTypes: map[int]*nodeComments{},
+		blank:              blankLines(document.Input.RawBytes),
+	}
+
+	input := &ast.Input{}
+	input.ResetInputBytes(document.Input.RawBytes)
+	lex := &lexer.Lexer{}
+	lex.SetInput(input)
+
+	type pendingComment struct {
+		comment
+		next      int  // index of the token following the comment
+		endOfLine bool // a token precedes the comment on the same line
+	}
+
+	var (
+		tokens   []position.Position
+		offsets  = map[uint32]int{}
+		pending  []pendingComment
+		lastLine uint32
+	)
+
+	for {
+		tok := lex.Read()
+		if tok.Keyword == keyword.EOF {
+			break
+		}
+		if tok.Keyword != keyword.COMMENT {
+			offsets[tok.Literal.Start] = len(tokens)
+			tokens = append(tokens, tok.TextPosition)
+			lastLine = tok.TextPosition.LineEnd
+			continue
+		}
+		// consecutive comment lines are lexed as a single token
+		for i, text := range bytes.Split(input.ByteSlice(tok.Literal), literal.LINETERMINATOR) {
+			text = bytes.TrimSpace(text)
+			if len(text) == 0 {
+				continue
+			}
+			line := tok.TextPosition.LineStart + uint32(i)
+			pending = append(pending, pendingComment{
+				comment:   comment{text: text, line: line},
+				next:      len(tokens),
+				endOfLine: len(tokens) != 0 && lastLine == line,
+			})
+		}
+	}
+
+	positions := make(map[position.Position]int, len(tokens))
+	for i, pos := range tokens {
+		positions[position.Position{LineStart: pos.LineStart, CharStart: pos.CharStart}] = i
+	}
+	tokenAt := func(pos position.Position) (int, bool) {
+		i, ok := positions[position.Position{LineStart: pos.LineStart, CharStart: pos.CharStart}]
+		return i, ok
+	}
+
+	anchors := map[int]*nodeComments{}
+	closers := map[int]*nodeComments{}
+	addAnchor := func(node *nodeComments, description ast.Description, start int, ok bool) {
+		if !ok {
+			return
+		}
+		if description.IsDefined {
+			start, ok = tokenAt(description.Position)
+			if !ok {
+				return
+			}
+		}
+		node.line = tokens[start].LineStart
+		node.ownLine = start == 0 || tokens[start-1].LineEnd != node.line
+		anchors[start] = node
+	}
+	addRootAnchor := func(node ast.Node, description ast.Description, keywordPosition position.Position) {
+		start, ok := tokenAt(keywordPosition)
+		addAnchor(index.node(node), description, start, ok)
+	}
+	addMemberAnchor := func(node ast.Node, description ast.Description, name ast.ByteSliceReference) {
+		start, ok := offsets[name.Start]
+		addAnchor(index.node(node), description, start, ok)
+	}
+	addCloser := func(node ast.Node, closer position.Position) {
+		if i, ok := tokenAt(closer); ok {
+			closers[i] = index.node(node)
+		}
+	}
+	addOwner := func(owner *nodeComments, members []*nodeComments, closer position.Position, ownerBehind bool) {
+		end, ok := tokenAt(closer)
+		if !ok {
+			return
+		}
+		for _, member := range members {
+			member.owner, member.end, member.ownerBehind = owner, end, ownerBehind
+		}
+	}
+	addInputValues := func(parent ast.Node, values ast.InputValueDefinitionList, ownerBehind bool) {
+		members := make([]*nodeComments, 0, len(values.Refs))
+		for _, ref := range values.Refs {
+			node := ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}
+			addMemberAnchor(node, document.InputValueDefinitions[ref].Description, document.InputValueDefinitions[ref].Name)
+			members = append(members, index.node(node))
+		}
+		if len(values.Refs) != 0 {
+			addCloser(parent, values.RPAREN)
+			addOwner(index.node(parent), members, values.RPAREN, ownerBehind)
+		}
+	}
+	addFields := func(parent ast.Node, fields ast.FieldDefinitionList) {
+		members := make([]*nodeComments, 0, len(fields.Refs))
+		for _, ref := range fields.Refs {
+			node := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}
+			addMemberAnchor(node, document.FieldDefinitions[ref].Description, document.FieldDefinitions[ref].Name)
+			// comments behind the closing parenthesis of the arguments stay at the end of the field's line
+			addInputValues(node, document.FieldDefinitions[ref].ArgumentsDefinition, false)
+			members = append(members, index.node(node))
+		}
+		if len(fields.Refs) != 0 {
+			addCloser(parent, fields.RBRACE)
+			addOwner(index.node(parent), members, fields.RBRACE, true)
+		}
+	}
+	addEnumValues := func(parent ast.Node, values ast.EnumValueDefinitionList) {
+		members := make([]*nodeComments, 0, len(values.Refs))
+		for _, ref := range values.Refs {
+			node := ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: ref}
+			addMemberAnchor(node, document.EnumValueDefinitions[ref].Description, document.EnumValueDefinitions[ref].EnumValue)
+			members = append(members, index.node(node))
+		}
+		if len(values.Refs) != 0 {
+			addCloser(parent, values.RBRACE)
+			addOwner(index.node(parent), members, values.RBRACE, true)
+		}
+	}
+	addVariableDefinitions := func(parent ast.Node, variables ast.VariableDefinitionList) {
+		members := make([]*nodeComments, 0, len(variables.Refs))
+		for _, ref := range variables.Refs {
+			node := ast.Node{Kind: ast.NodeKindVariableDefinition, Ref: ref}
+			start, ok := tokenAt(document.VariableValues[document.VariableDefinitions[ref].VariableValue.Ref].Dollar)
+			addAnchor(index.node(node), ast.Description{}, start, ok)
+			members = append(members, index.node(node))
+		}
+		if len(variables.Refs) != 0 {
+			addCloser(parent, variables.RPAREN)
+			// comments behind the closing parenthesis are written behind the opening brace of the selection set
+			addOwner(index.node(parent), members, variables.RPAREN, false)
+		}
+	}
+	addSelectionSet := func(owner ast.Node, ref int) {
+		selectionSet := document.SelectionSets[ref]
+		addCloser(ast.Node{Kind: ast.NodeKindSelectionSet, Ref: ref}, selectionSet.RBrace)
+
+		members := make([]*nodeComments, 0, len(selectionSet.SelectionRefs))
+		for _, selectionRef := range selectionSet.SelectionRefs {
+			selection := document.Selections[selectionRef]
+			switch selection.Kind {
+			case ast.SelectionKindField:
+				members = append(members, index.node(ast.Node{Kind: ast.NodeKindField, Ref: selection.Ref}))
+			case ast.SelectionKindInlineFragment:
+				members = append(members, index.node(ast.Node{Kind: ast.NodeKindInlineFragment, Ref: selection.Ref}))
+			case ast.SelectionKindFragmentSpread:
+				members = append(members, index.node(ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: selection.Ref}))
+			}
+		}
+		addOwner(index.node(owner), members, selectionSet.RBrace, true)
+	}
+	addRootOperationTypes := func(parent ast.Node, types ast.RootOperationTypeDefinitionList) {
+		members := make([]*nodeComments, 0, len(types.Refs))
+		for _, ref := range types.Refs {
+			// the operation type keyword is the token in front of the colon
+			colon, ok := tokenAt(document.RootOperationTypeDefinitions[ref].Colon)
+			addAnchor(index.rootOperationType(ref), ast.Description{}, colon-1, ok && colon > 0)
+			members = append(members, index.rootOperationType(ref))
+		}
+		if len(types.Refs) != 0 {
+			addCloser(parent, types.RBrace)
+			addOwner(index.node(parent), members, types.RBrace, true)
+		}
+	}
+
+	for _, node := range document.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindSchemaDefinition:
+			definition := document.SchemaDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.SchemaLiteral)
+			addRootOperationTypes(node, definition.RootOperationTypeDefinitions)
+		case ast.NodeKindSchemaExtension:
+			extension := document.SchemaExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+			addRootOperationTypes(node, extension.RootOperationTypeDefinitions)
+		case ast.NodeKindObjectTypeDefinition:
+			definition := document.ObjectTypeDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.TypeLiteral)
+			addFields(node, definition.FieldsDefinition)
+		case ast.NodeKindObjectTypeExtension:
+			extension := document.ObjectTypeExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+			addFields(node, extension.FieldsDefinition)
+		case ast.NodeKindInterfaceTypeDefinition:
+			definition := document.InterfaceTypeDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.InterfaceLiteral)
+			addFields(node, definition.FieldsDefinition)
+		case ast.NodeKindInterfaceTypeExtension:
+			extension := document.InterfaceTypeExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+			addFields(node, extension.FieldsDefinition)
+		case ast.NodeKindScalarTypeDefinition:
+			definition := document.ScalarTypeDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.ScalarLiteral)
+		case ast.NodeKindScalarTypeExtension:
+			extension := document.ScalarTypeExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+		case ast.NodeKindUnionTypeDefinition:
+			definition := document.UnionTypeDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.UnionLiteral)
+		case ast.NodeKindUnionTypeExtension:
+			extension := document.UnionTypeExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+		case ast.NodeKindEnumTypeDefinition:
+			definition := document.EnumTypeDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.EnumLiteral)
+			addEnumValues(node, definition.EnumValuesDefinition)
+		case ast.NodeKindEnumTypeExtension:
+			extension := document.EnumTypeExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+			addEnumValues(node, extension.EnumValuesDefinition)
+		case ast.NodeKindInputObjectTypeDefinition:
+			definition := document.InputObjectTypeDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.InputLiteral)
+			addInputValues(node, definition.InputFieldsDefinition, true)
+		case ast.NodeKindInputObjectTypeExtension:
+			extension := document.InputObjectTypeExtensions[node.Ref]
+			addRootAnchor(node, extension.Description, extension.ExtendLiteral)
+			addInputValues(node, extension.InputFieldsDefinition, true)
+		case ast.NodeKindDirectiveDefinition:
+			definition := document.DirectiveDefinitions[node.Ref]
+			addRootAnchor(node, definition.Description, definition.DirectiveLiteral)
+			addInputValues(node, definition.ArgumentsDefinition, true)
+		case ast.NodeKindOperationDefinition:
+			definition := document.OperationDefinitions[node.Ref]
+			start := definition.OperationTypeLiteral
+			if start.LineStart == 0 {
+				start = document.SelectionSets[definition.SelectionSet].LBrace
+			}
+			addRootAnchor(node, ast.Description{}, start)
+			index.node(node).member = true
+			addVariableDefinitions(node, definition.VariableDefinitions)
+			addSelectionSet(node, definition.SelectionSet)
+		case ast.NodeKindFragmentDefinition:
+			definition := document.FragmentDefinitions[node.Ref]
+			addRootAnchor(node, ast.Description{}, definition.FragmentLiteral)
+			index.node(node).member = true
+			addSelectionSet(node, definition.SelectionSet)
+		}
+	}
+
+	for ref, field := range document.Fields {
+		node := ast.Node{Kind: ast.NodeKindField, Ref: ref}
+		start := field.Name
+		if field.Alias.IsDefined {
+			start = field.Alias.Name
+		}
+		addMemberAnchor(node, ast.Description{}, start)
+		arguments := make([]*nodeComments, 0, len(field.Arguments.Refs))
+		for _, argument := range field.Arguments.Refs {
+			addMemberAnchor(ast.Node{Kind: ast.NodeKindArgument, Ref: argument}, ast.Description{}, document.Arguments[argument].Name)
+			arguments = append(arguments, index.node(ast.Node{Kind: ast.NodeKindArgument, Ref: argument}))
+		}
+		if len(arguments) != 0 {
+			addCloser(node, field.Arguments.RPAREN)
+			addOwner(index.node(node), arguments, field.Arguments.RPAREN, false)
+		}
+		if field.HasSelections {
+			addSelectionSet(node, field.SelectionSet)
+		}
+	}
+	for ref, fragment := range document.InlineFragments {
+		start, ok := tokenAt(fragment.Spread)
+		node := ast.Node{Kind: ast.NodeKindInlineFragment, Ref: ref}
+		addAnchor(index.node(node), ast.Description{}, start, ok)
+		if fragment.HasSelections {
+			addSelectionSet(node, fragment.SelectionSet)
+		}
+	}
+	for ref, spread := range document.FragmentSpreads {
+		start, ok := tokenAt(spread.Spread)
+		addAnchor(index.node(ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: ref}), ast.Description{}, start, ok)
+	}
+
+	starts := make([]int, 0, len(anchors))
+	for start := range anchors {
+		starts = append(starts, start)
+	}
+	sort.Ints(starts)
+
+	// enclosing returns the node which was started last in front of the token at next,
+	// skipping the members of nodes which were closed in between.
+	// behindCloser is true if the comments behind the closing token of the skipped members belong behind it.
+	enclosing := func(next int) (node *nodeComments, behindCloser bool) {
+		i := sort.SearchInts(starts, next)
+		if i == 0 {
+			return nil, false
+		}
+		node = anchors[starts[i-1]]
+		for node.owner != nil && node.end < next {
+			node, behindCloser = node.owner, node.ownerBehind
+		}
+		return node, behindCloser
+	}
+
+	for _, c := range pending {
+		if !c.endOfLine {
+			if node, ok := anchors[c.next]; ok {
+				node.leading = append(node.leading, c.comment)
+				continue
+			}
+			if c.next == len(tokens) {
+				index.document = append(index.document, c.comment)
+				continue
+			}
+			if node, ok := closers[c.next]; ok {
+				node.closing = append(node.closing, c.comment)
+				continue
+			}
+		}
+		node, behindCloser := enclosing(c.next)
+		if node == nil {
+			// comments in front of a document without type system or executable definitions
+			index.document = append(index.document, c.comment)
+			continue
+		}
+		if c.endOfLine && behindCloser {
+			node.behind = append(node.behind, c.comment)
+			continue
+		}
+		if c.endOfLine && node.member {
+			node.trailing = append(node.trailing, c.comment)
+			continue
+		}
+		node.leading = append(node.leading, c.comment)
+	}
+
+	return index
+}
+
+func isMemberNodeKind(kind ast.NodeKind) bool {
+	switch kind {
+	case ast.NodeKindFieldDefinition,
+		ast.NodeKindInputValueDefinition,
+		ast.NodeKindEnumValueDefinition,
+		ast.NodeKindField,
+		ast.NodeKindInlineFragment,
+		ast.NodeKindFragmentSpread,
+		ast.NodeKindArgument,
+		ast.NodeKindVariableDefinition:
+		return true
+	default:
+		return false
+	}
+}
+
+// blankLines reports for every line, starting at 1, whether it contains whitespace only
+func blankLines(input []byte) []bool {
+	blank := []bool{false}
+	for _, line := range bytes.Split(input, literal.LINETERMINATOR) {
+		blank = append(blank, len(bytes.TrimSpace(line)) == 0)
+	}
+	return blank
+}
+
+func sortRootNodes(document *ast.Document) {
+	group := func(node ast.Node) int {
+		switch node.Kind {
+		case ast.NodeKindSchemaDefinition, ast.NodeKindSchemaExtension:
+			return 0
+		case ast.NodeKindDirectiveDefinition:
+			return 1
+		case ast.NodeKindOperationDefinition, ast.NodeKindFragmentDefinition:
+			return 3
+		default:
+			return 2
+		}
+	}
+
+	sort.SliceStable(document.RootNodes, func(i, j int) bool {
+		left, right := document.RootNodes[i], document.RootNodes[j]
+		leftGroup, rightGroup := group(left), group(right)
+		if leftGroup != rightGroup {
+			return leftGroup < rightGroup
+		}
+		if leftGroup != 1 && leftGroup != 2 {
+			return false
+		}
+		return bytes.Compare(document.NodeNameBytes(left), document.NodeNameBytes(right)) < 0
+	})
+}
+
+// sortFieldDefinitions sorts the members of object types, interfaces and input objects
+// and returns the nodes whose members changed their order
+func sortFieldDefinitions(document *ast.Document) map[ast.Node]bool {
+	reordered := map[ast.Node]bool{}
+	sortMembers := func(node ast.Node, refs []int, name func(ref int) ast.ByteSlice) {
+		if sort.SliceIsSorted(refs, func(i, j int) bool { return bytes.Compare(name(refs[i]), name(refs[j])) < 0 }) {
+			return
+		}
+		sort.SliceStable(refs, func(i, j int) bool {
+			return bytes.Compare(name(refs[i]), name(refs[j])) < 0
+		})
+		reordered[node] = true
+	}
+
+	for i := range document.ObjectTypeDefinitions {
+		sortMembers(ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: i}, document.ObjectTypeDefinitions[i].FieldsDefinition.Refs, document.FieldDefinitionNameBytes)
+	}
+	for i := range document.ObjectTypeExtensions {
+		sortMembers(ast.Node{Kind: ast.NodeKindObjectTypeExtension, Ref: i}, document.ObjectTypeExtensions[i].FieldsDefinition.Refs, document.FieldDefinitionNameBytes)
+	}
+	for i := range document.InterfaceTypeDefinitions {
+		sortMembers(ast.Node{Kind: ast.NodeKindInterfaceTypeDefinition, Ref: i}, document.InterfaceTypeDefinitions[i].FieldsDefinition.Refs, document.FieldDefinitionNameBytes)
+	}
+	for i := range document.InterfaceTypeExtensions {
+		sortMembers(ast.Node{Kind: ast.NodeKindInterfaceTypeExtension, Ref: i}, document.InterfaceTypeExtensions[i].FieldsDefinition.Refs, document.FieldDefinitionNameBytes)
+	}
+	for i := range document.InputObjectTypeDefinitions {
+		sortMembers(ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: i}, document.InputObjectTypeDefinitions[i].InputFieldsDefinition.Refs, document.InputValueDefinitionNameBytes)
+	}
+	for i := range document.InputObjectTypeExtensions {
+		sortMembers(ast.Node{Kind: ast.NodeKindInputObjectTypeExtension, Ref: i}, document.InputObjectTypeExtensions[i].InputFieldsDefinition.Refs, document.InputValueDefinitionNameBytes)
+	}
+	return reordered
+}
+
+// nodeComments returns the comments attached to a node, it returns nil unless a document is formatted.
+func (p *printVisitor) nodeComments(node ast.Node) *nodeComments {
+	if p.comments == nil {
+		return nil
+	}
+	return p.comments.nodes[node]
+}
+
+// rootOperationTypeComments returns the comments attached to a root operation type definition.
+func (p *printVisitor) rootOperationTypeComments(ref int) *nodeComments {
+	if p.comments == nil {
+		return nil
+	}
+	return p.comments.rootOperationTypes[ref]
+}
+
+// writeLeadingComments writes the comments in front of a node and its description.
+// A blank line in front of a member is kept unless it is the first member of its parent or the members were sorted.
+func (p *printVisitor) writeLeadingComments(comments *nodeComments, isFirst bool) {
+	if comments == nil {
+		return
+	}
+
+	firstLine, ownLine := comments.line, comments.ownLine
+	if len(comments.leading) != 0 {
+		firstLine, ownLine = comments.leading[0].line, true
+	}
+	if !isFirst && ownLine && !p.comments.reordered[p.Ancestors[len(p.Ancestors)-1]] && p.comments.blankLineBefore(firstLine) {
+		p.write(literal.LINETERMINATOR)
+	}
+
+	p.writeComments(comments.leading, p.indentationDepth(), false)
+	if len(comments.leading) != 0 && p.comments.blankLineBefore(comments.line) {
+		p.write(literal.LINETERMINATOR)
+	}
+}
+
+// writeTrailingComments writes the comments at the end of a member's line.
+func (p *printVisitor) writeTrailingComments(comments *nodeComments) {
+	if comments == nil {
+		return
+	}
+	p.writeEndOfLineComments(comments.trailing)
+}
+
+// writeBehindComments writes the comments behind the closing brace or parenthesis of a definition's members.
+func (p *printVisitor) writeBehindComments(node ast.Node) {
+	if comments := p.nodeComments(node); comments != nil {
+		p.writeEndOfLineComments(comments.behind)
+	}
+}
+
+// writeEndOfLineComments writes comments behind the current line, the caller writes the line terminator
+func (p *printVisitor) writeEndOfLineComments(comments []comment) {
+	for _, c := range comments {
+		p.write(literal.SPACE)
+		p.write(c.text)
+	}
+}
+
+// writeClosingComments writes the comments in front of the closing brace or parenthesis of a node's members,
+// depth is the indentation of the members.
+func (p *printVisitor) writeClosingComments(comments *nodeComments, depth int) {
+	if comments == nil || len(comments.closing) == 0 {
+		return
+	}
+	p.writeComments(comments.closing, depth, p.comments.blankLineBefore(comments.closing[0].line))
+}
+
+// hasArgumentComments returns true if comments are attached to the arguments of a field or field definition
+// or to the variable definitions of an operation, the arguments are printed one per line in this case.
+func (p *printVisitor) hasArgumentComments(parent ast.Node) bool {
+	if p.comments == nil || p.indent == nil {
+		return false
+	}
+
+	var (
+		argumentKind ast.NodeKind
+		arguments    []int
+	)
+	switch parent.Kind {
+	case ast.NodeKindField:
+		argumentKind, arguments = ast.NodeKindArgument, p.document.Fields[parent.Ref].Arguments.Refs
+	case ast.NodeKindFieldDefinition:
+		argumentKind, arguments = ast.NodeKindInputValueDefinition, p.document.FieldDefinitions[parent.Ref].ArgumentsDefinition.Refs
+	case ast.NodeKindOperationDefinition:
+		argumentKind, arguments = ast.NodeKindVariableDefinition, p.document.OperationDefinitions[parent.Ref].VariableDefinitions.Refs
+	default:
+		return false
+	}
+	if comments := p.nodeComments(parent); comments != nil && len(comments.closing) != 0 {
+		return true
+	}
+	for _, ref := range arguments {
+		if comments := p.nodeComments(ast.Node{Kind: argumentKind, Ref: ref}); comments != nil && len(comments.leading)+len(comments.trailing) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeIndentation indents the next line of a multi-line argument list
+func (p *printVisitor) writeIndentation(depth int) {
+	for i := 0; i < depth; i++ {
+		p.write(p.indent)
+	}
+}
+
+// writeComments writes each comment on its own line and keeps blank lines between them.
+func (p *printVisitor) writeComments(comments []comment, depth int, blankLineBeforeFirst bool) {
+	for i, c := range comments {
+		if (i == 0 && blankLineBeforeFirst) || (i != 0 && p.comments.blankLineBefore(c.line)) {
+			p.write(literal.LINETERMINATOR)
+		}
+		for j := 0; j < depth; j++ {
+			p.write(p.indent)
+		}
+		p.write(c.text)
+		p.write(literal.LINETERMINATOR)
+	}
+}
+
+// leaveArguments closes an argument list printed with one argument per line
+func (p *printVisitor) leaveArguments(parent ast.Node) {
+	if p.argumentDepth == 0 {
+		return
+	}
+	p.write(literal.LINETERMINATOR)
+	p.writeClosingComments(p.nodeComments(parent), p.argumentDepth)
+	p.argumentDepth = 0
+	p.writeIndentation(p.indentationDepth())
+}
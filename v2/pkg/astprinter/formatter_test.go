@@ -0,0 +1,293 @@
+package astprinter
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/testing/goldie"
+)
+
+func TestFormat(t *testing.T) {
+	runFormat := func(t *testing.T, input, expected string, options FormatOptions) {
+		t.Helper()
+
+		actual, err := FormatString(input, options)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		again, err := FormatString(actual, options)
+		require.NoError(t, err)
+		assert.Equal(t, actual, again, "formatting must be idempotent")
+	}
+
+	t.Run("leading, trailing and closing comments", func(t *testing.T) {
+		runFormat(t, `
+# the schema
+schema {
+	# entry point
+	query: Query # trailing
+	# before closing brace
+}
+
+# users are looked up by id
+"""
+The query type
+"""
+type Query {
+	# deprecated soon
+	user(id: ID!): User # by id
+	# nothing follows
+}
+
+enum Role {
+	ADMIN # all permissions
+	# regular users
+	USER
+}
+
+input UserInput { name: String # required
+	# optional
+	email: String }
+
+directive @auth(
+	# the required role
+	role: Role
+) on FIELD_DEFINITION
+`, `# the schema
+schema {
+  # entry point
+  query: Query # trailing
+  # before closing brace
+}
+
+# users are looked up by id
+"""
+The query type
+"""
+type Query {
+  # deprecated soon
+  user(id: ID!): User # by id
+  # nothing follows
+}
+
+enum Role {
+  ADMIN # all permissions
+  # regular users
+  USER
+}
+
+input UserInput {
+  name: String # required
+  # optional
+  email: String
+}
+
+directive @auth(
+  # the required role
+  role: Role
+) on FIELD_DEFINITION
+`, FormatOptions{})
+	})
+
+	t.Run("blank lines between members and comments", func(t *testing.T) {
+		runFormat(t, `
+# license header
+
+# about users
+type User {
+
+	id: ID!
+	name: String
+
+
+	# contact details
+
+	# may be empty
+	email: String
+	phone: String
+}
+# end of file
+`, `# license header
+
+# about users
+type User {
+  id: ID!
+  name: String
+
+  # contact details
+
+  # may be empty
+  email: String
+  phone: String
+}
+# end of file
+`, FormatOptions{})
+	})
+
+	t.Run("sort types and fields", func(t *testing.T) {
+		runFormat(t, `
+type User {
+	name: String
+
+	# the id
+	id: ID!
+}
+directive @auth on FIELD_DEFINITION
+# entry point
+type Query { user: User }
+input UserInput { name: String email: String }
+enum Role { USER ADMIN }
+schema { query: Query }
+`, `schema {
+  query: Query
+}
+
+directive @auth on FIELD_DEFINITION
+
+# entry point
+type Query {
+  user: User
+}
+
+enum Role {
+  USER
+  ADMIN
+}
+
+type User {
+  # the id
+  id: ID!
+  name: String
+}
+
+input UserInput {
+  email: String
+  name: String
+}
+`, FormatOptions{SortTypes: true, SortFields: true})
+	})
+
+	t.Run("comments behind closing braces stay with their definition", func(t *testing.T) {
+		input := `
+type Q {
+	z(first: Int): Int # z
+	a: Int
+} # end of Q
+
+enum Role { USER ADMIN } # end of Role
+
+input UserInput {
+	name: String
+} # end of UserInput
+
+schema { query: Q } # end of schema
+`
+		runFormat(t, input, `type Q {
+  z(first: Int): Int # z
+  a: Int
+} # end of Q
+
+enum Role {
+  USER
+  ADMIN
+} # end of Role
+
+input UserInput {
+  name: String
+} # end of UserInput
+
+schema {
+  query: Q
+} # end of schema
+`, FormatOptions{})
+		runFormat(t, input, `schema {
+  query: Q
+} # end of schema
+
+type Q {
+  a: Int
+  z(first: Int): Int # z
+} # end of Q
+
+enum Role {
+  USER
+  ADMIN
+} # end of Role
+
+input UserInput {
+  name: String
+} # end of UserInput
+`, FormatOptions{SortTypes: true, SortFields: true})
+	})
+
+	t.Run("comments of variable definitions", func(t *testing.T) {
+		input := `
+type Query { user(id: ID, name: String): String }
+
+query Q($id: ID # c1
+# the name
+$name: String
+) # behind variables
+{ user(id: $id, name: $name) } # end of Q
+`
+		expected := `type Query {
+  user(id: ID, name: String): String
+}
+
+query Q(
+  $id: ID # c1
+  # the name
+  $name: String
+){ # behind variables
+  user(id: $id, name: $name)
+} # end of Q
+`
+		runFormat(t, input, expected, FormatOptions{})
+		runFormat(t, input, expected, FormatOptions{SortTypes: true, SortFields: true})
+	})
+
+	t.Run("formatted schema is unchanged", func(t *testing.T) {
+		schema, err := os.ReadFile("./testdata/starwars.schema.graphql")
+		require.NoError(t, err)
+
+		formatted, err := Format(schema, FormatOptions{})
+		require.NoError(t, err)
+
+		again, err := Format(formatted, FormatOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, string(formatted), string(again))
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := FormatString("type Query {", FormatOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestFormatGolden(t *testing.T) {
+	run := func(t *testing.T, name string, options FormatOptions) {
+		t.Helper()
+
+		input, err := os.ReadFile("./testdata/" + name + ".graphql")
+		require.NoError(t, err)
+
+		formatted, err := Format(input, options)
+		require.NoError(t, err)
+		goldie.Assert(t, name, formatted)
+
+		again, err := Format(formatted, options)
+		require.NoError(t, err)
+		assert.Equal(t, string(formatted), string(again), "formatting must be idempotent")
+	}
+
+	t.Run("comments of selections and arguments", func(t *testing.T) {
+		run(t, "formatter_comments", FormatOptions{})
+	})
+
+	t.Run("blank lines are dropped between sorted members only", func(t *testing.T) {
+		run(t, "formatter_sort_fields", FormatOptions{SortFields: true})
+	})
+}
@@ -10,6 +10,10 @@ import (
 
 func MergeDefinitionWithBaseSchema(definition *ast.Document) error {
 	definition.Input.AppendInputBytes(baseSchema)
+	if _, exists := definition.DirectiveDefinitionByNameBytes(oneOfDirectiveName); !exists {
+		// schemas written before @oneOf was specified usually declare the directive themselves
+		definition.Input.AppendInputBytes(oneOfDirectiveDefinition)
+	}
 	parser := astparser.NewParser()
 	report := operationreport.Report{}
 	parser.Parse(definition, &report)
@@ -164,7 +168,7 @@ directive @deprecated(
     [Markdown](https://daringfireball.net/projects/markdown/).
     """
     reason: String = "No longer supported"
-) on FIELD_DEFINITION | ENUM_VALUE
+) on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE
 
 """
 A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.
@@ -177,7 +181,7 @@ type __Directive {
     name: String!
     description: String
     locations: [__DirectiveLocation!]!
-    args: [__InputValue!]!
+    args(includeDeprecated: Boolean = false): [__InputValue!]!
     isRepeatable: Boolean!
 }
 
@@ -242,7 +246,7 @@ a name, potentially a list of arguments, and a return type.
 type __Field {
     name: String!
     description: String
-    args: [__InputValue!]!
+    args(includeDeprecated: Boolean = false): [__InputValue!]!
     type: __Type!
     isDeprecated: Boolean!
     deprecationReason: String
@@ -258,6 +262,8 @@ type __InputValue {
     type: __Type!
     "A GraphQL-formatted string representing the default value for this input value."
     defaultValue: String
+    isDeprecated: Boolean!
+    deprecationReason: String
 }
 
 """
@@ -296,8 +302,9 @@ type __Type {
     interfaces: [__Type!]
     possibleTypes: [__Type!]
     enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
-    inputFields: [__InputValue!]
+    inputFields(includeDeprecated: Boolean = false): [__InputValue!]
     ofType: __Type
+    isOneOf: Boolean
 }
 
 "An enum describing what kind of type a given '__Type' is."
@@ -319,3 +326,9 @@ enum __TypeKind {
     "Indicates this type is a non-null. 'ofType' is a valid field."
     NON_NULL
 }`)
+
+var oneOfDirectiveName = []byte("oneOf")
+
+var oneOfDirectiveDefinition = []byte(`
+"Indicates exactly one field must be supplied and this field must not be null."
+directive @oneOf on INPUT_OBJECT`)
@@ -148,4 +148,14 @@ func TestMergeDefinitionWithBaseSchema(t *testing.T) {
 				adminInformation: String!
 			}
 	`, "with_mutation_subscription"))
+	t.Run("with declared oneOf directive", runTestMerge(`
+			directive @oneOf on INPUT_OBJECT
+			type Query {
+				user(by: UserBy!): String
+			}
+			input UserBy @oneOf {
+				id: ID
+				email: String
+			}
+	`, "with_declared_one_of"))
 }
@@ -19,6 +19,7 @@ func DefaultDefinitionValidator() *DefinitionValidator {
 		ImplementTransitiveInterfaces(),
 		ImplementingTypesAreSupersets(),
 		DirectivesAreUniquePerLocation(),
+		RequiredInputValuesNotDeprecated(),
 	)
 }
 
@@ -8,13 +8,21 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 // Values validates if values are used properly
 func Values() Rule {
+	return ValuesWithScalarCodecs(nil)
+}
+
+// ValuesWithScalarCodecs validates if values are used properly
+// and additionally validates literals of custom scalars with the codecs of the registry
+func ValuesWithScalarCodecs(registry *scalars.Registry) Rule {
 	return func(walker *astvisitor.Walker) {
 		visitor := valuesVisitor{
-			Walker: walker,
+			Walker:       walker,
+			scalarCodecs: registry,
 		}
 		walker.RegisterEnterDocumentVisitor(&visitor)
 		walker.RegisterEnterArgumentVisitor(&visitor)
@@ -26,6 +34,7 @@ type valuesVisitor struct {
 	*astvisitor.Walker
 	operation, definition *ast.Document
 	importer              astimport.Importer
+	scalarCodecs          *scalars.Registry
 }
 
 func (v *valuesVisitor) EnterDocument(operation, definition *ast.Document) {
@@ -242,11 +251,13 @@ func (v *valuesVisitor) valueSatisfiesListType(value ast.Value, definitionTypeRe
 		return v.valueSatisfiesInputValueDefinitionType(value, listItemType)
 	}
 
+	nonNullListItemType := ast.InvalidRef
 	if v.definition.Types[listItemType].TypeKind == ast.TypeKindNonNull {
 		if len(v.operation.ListValues[value.Ref].Refs) == 0 {
 			// [] empty list is a valid input for [item!] lists
 			return true
 		}
+		nonNullListItemType = listItemType
 		listItemType = v.definition.Types[listItemType].OfType
 	}
 
@@ -254,6 +265,12 @@ func (v *valuesVisitor) valueSatisfiesListType(value ast.Value, definitionTypeRe
 
 	for _, i := range v.operation.ListValues[value.Ref].Refs {
 		listValue := v.operation.Value(i)
+		if listValue.Kind == ast.ValueKindNull && nonNullListItemType != ast.InvalidRef {
+			// null is not a valid item of [item!] lists
+			v.handleUnexpectedNullError(listValue, nonNullListItemType)
+			valid = false
+			continue
+		}
 		if !v.valueSatisfiesInputValueDefinitionType(listValue, listItemType) {
 			valid = false
 		}
@@ -313,10 +330,35 @@ func (v *valuesVisitor) valueSatisfiesScalar(value ast.Value, definitionTypeRef
 	case bytes.Equal(scalarName, literal.STRING):
 		return v.valueSatisfiesScalarString(value, definitionTypeRef, true)
 	default:
+		if codec, ok := v.scalarCodecs.Codec(v.definition, scalar); ok {
+			return v.valueSatisfiesCustomScalar(value, definitionTypeRef, codec)
+		}
 		return v.valueSatisfiesScalarString(value, definitionTypeRef, false)
 	}
 }
 
+func (v *valuesVisitor) valueSatisfiesCustomScalar(value ast.Value, definitionTypeRef int, codec scalars.Codec) bool {
+	jsonValue, err := v.operation.ValueToJSON(value)
+	if err != nil {
+		// values containing variables can't be converted to json, their variables are validated separately
+		return true
+	}
+
+	validationErr := codec.ValidateInput(jsonValue)
+	if validationErr == nil {
+		return true
+	}
+
+	printedValue, printedType, ok := v.printValueAndUnderlyingType(value, definitionTypeRef)
+	if !ok {
+		return false
+	}
+
+	v.Report.AddExternalError(operationreport.ErrValueDoesntSatisfyCustomScalar(printedValue, printedType, validationErr.Error(), value.Position))
+
+	return false
+}
+
 func (v *valuesVisitor) valueSatisfiesScalarID(value ast.Value, definitionTypeRef int) bool {
 	if value.Kind == ast.ValueKindString || value.Kind == ast.ValueKindInteger {
 		return true
@@ -448,6 +490,39 @@ func (v *valuesVisitor) valueSatisfiesInputObjectTypeDefinition(value ast.Value,
 		return false
 	}
 
+	if v.definition.InputObjectTypeDefinitionIsOneOf(inputObjectTypeDefinition) {
+		return v.objectValueSatisfiesOneOf(value, inputObjectTypeDefinition)
+	}
+
+	return true
+}
+
+// objectValueSatisfiesOneOf checks that exactly one field of a @oneOf input object is provided,
+// and that its value is neither null nor a nullable variable
+func (v *valuesVisitor) objectValueSatisfiesOneOf(objectValue ast.Value, inputObjectTypeDefinition int) bool {
+	typeName := v.definition.InputObjectTypeDefinitionNameBytes(inputObjectTypeDefinition)
+
+	objectFieldRefs := v.operation.ObjectValues[objectValue.Ref].Refs
+	if len(objectFieldRefs) != 1 {
+		v.Report.AddExternalError(operationreport.ErrOneOfInputObjectFieldCount(typeName, objectValue.Position))
+		return false
+	}
+
+	fieldValue := v.operation.ObjectFieldValue(objectFieldRefs[0])
+	switch fieldValue.Kind {
+	case ast.ValueKindNull:
+		fieldName := v.operation.ObjectFieldNameBytes(objectFieldRefs[0])
+		v.Report.AddExternalError(operationreport.ErrOneOfInputObjectNullField(typeName, fieldName, fieldValue.Position))
+		return false
+	case ast.ValueKindVariable:
+		variableDefinitionRef, exists := v.operationVariableDefinition(fieldValue.Ref)
+		if exists && !v.operation.TypeIsNonNull(v.operation.VariableDefinitions[variableDefinitionRef].Type) {
+			variableName := v.operation.VariableValueNameBytes(fieldValue.Ref)
+			v.Report.AddExternalError(operationreport.ErrOneOfInputObjectNullableVariable(variableName, typeName, fieldValue.Position))
+			return false
+		}
+	}
+
 	return true
 }
 
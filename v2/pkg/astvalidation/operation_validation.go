@@ -5,10 +5,28 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
+type OperationValidatorOptions struct {
+	ScalarCodecs *scalars.Registry
+}
+
+type Option func(options *OperationValidatorOptions)
+
+// WithScalarCodecs validates literals of custom scalars with the codecs of the registry
+func WithScalarCodecs(registry *scalars.Registry) Option {
+	return func(options *OperationValidatorOptions) {
+		options.ScalarCodecs = registry
+	}
+}
+
 // DefaultOperationValidator returns a fully initialized OperationValidator with all default rules registered
-func DefaultOperationValidator() *OperationValidator {
+func DefaultOperationValidator(options ...Option) *OperationValidator {
+	var opts OperationValidatorOptions
+	for _, option := range options {
+		option(&opts)
+	}
 
 	validator := OperationValidator{
 		walker: astvisitor.NewWalker(48),
@@ -22,7 +40,7 @@ func DefaultOperationValidator() *OperationValidator {
 	validator.RegisterRule(FieldSelectionMerging())
 	validator.RegisterRule(KnownArguments())
 	validator.RegisterRule(ValidArguments())
-	validator.RegisterRule(Values())
+	validator.RegisterRule(ValuesWithScalarCodecs(opts.ScalarCodecs))
 	validator.RegisterRule(ArgumentUniqueness())
 	validator.RegisterRule(RequiredArguments())
 	validator.RegisterRule(Fragments())
@@ -13,6 +13,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 type options struct {
@@ -3875,6 +3876,109 @@ func TestExecutionValidation(t *testing.T) {
 					))
 			})
 		})
+		t.Run("oneOf input objects", func(t *testing.T) {
+			oneOfDefinition := `
+				scalar ID
+				scalar String
+				directive @oneOf on INPUT_OBJECT
+				schema { query: Query }
+				type Query { user(by: UserBy!): String }
+				input UserBy @oneOf { id: ID email: String }
+			`
+
+			t.Run("exactly one field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ user(by: {id: "1"}) }`, Values(), Valid)
+			})
+			t.Run("non-nullable variable", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `query ($id: ID!) { user(by: {id: $id}) }`, Values(), Valid)
+			})
+			t.Run("more than one field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ user(by: {id: "1", email: "a@b.c"}) }`, Values(), Invalid,
+					withValidationErrors(`OneOf Input Object "UserBy" must specify exactly one key.`))
+			})
+			t.Run("no fields", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ user(by: {}) }`, Values(), Invalid,
+					withValidationErrors(`OneOf Input Object "UserBy" must specify exactly one key.`))
+			})
+			t.Run("null field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ user(by: {id: null}) }`, Values(), Invalid,
+					withValidationErrors(`Field "UserBy.id" must be non-null.`))
+			})
+			t.Run("nullable variable", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `query ($id: ID) { user(by: {id: $id}) }`, Values(), Invalid,
+					withValidationErrors(`Variable "$id" must be non-nullable to be used for OneOf Input Object "UserBy".`))
+			})
+		})
+		t.Run("list input coercion", func(t *testing.T) {
+			listDefinition := `
+				scalar Int
+				scalar Float
+				scalar String
+				schema { query: Query }
+				type Query { lists(ints: [[Int]], nonNull: [[Int!]!], floats: [Float!]): String }
+			`
+
+			t.Run("single values into lists at any depth", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(ints: 1, nonNull: [1, [2]], floats: 1) }`, Values(), Valid)
+			})
+			t.Run("integers as Float", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(floats: [1, 2.5]) }`, Values(), Valid)
+			})
+			t.Run("null items of nullable lists", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(ints: [null, [null]]) }`, Values(), Valid)
+			})
+			t.Run("null item of list with non-null items", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(floats: [1.5, null]) }`, Values(), Invalid,
+					withValidationErrors(`Expected value of type "Float!", found null`))
+			})
+			t.Run("null item of nested non-null list", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(nonNull: [[1], null]) }`, Values(), Invalid,
+					withValidationErrors(`Expected value of type "[Int!]!", found null`))
+			})
+			t.Run("null item in nested list with non-null items", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(nonNull: [[1, null]]) }`, Values(), Invalid,
+					withValidationErrors(`Expected value of type "Int!", found null`))
+			})
+			t.Run("Int out of 32-bit signed range in list", func(t *testing.T) {
+				runWithDefinition(t, listDefinition, `{ lists(ints: [[2147483648]]) }`, Values(), Invalid,
+					withValidationErrors(`Int cannot represent non 32-bit signed integer value: 2147483648`))
+			})
+		})
+
+		t.Run("custom scalars with codecs", func(t *testing.T) {
+			scalarDefinition := `
+				scalar String
+				scalar DateTime
+				scalar Timestamp @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")
+				scalar Custom
+				directive @specifiedBy(url: String!) on SCALAR
+				schema { query: Query }
+				type Query { events(at: DateTime, since: Timestamp, custom: Custom, between: [DateTime!]): String }
+			`
+			rule := ValuesWithScalarCodecs(scalars.NewDefaultRegistry())
+
+			t.Run("valid literal", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(at: "2024-01-02T03:04:05Z") }`, rule, Valid)
+			})
+			t.Run("invalid literal", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(at: "yesterday") }`, rule, Invalid,
+					withValidationErrors(`Expected value of type "DateTime", found "yesterday"; Expected an RFC 3339 date-time string.`))
+			})
+			t.Run("invalid literal of scalar with specifiedBy url", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(since: 123) }`, rule, Invalid,
+					withValidationErrors(`Expected value of type "Timestamp", found 123; Expected a string value.`))
+			})
+			t.Run("invalid literal in list", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(between: ["2024-01-02T03:04:05Z", "tomorrow"]) }`, rule, Invalid,
+					withValidationErrors(`Expected value of type "DateTime", found "tomorrow"; Expected an RFC 3339 date-time string.`))
+			})
+			t.Run("custom scalar without codec", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(custom: "anything") }`, rule, Valid)
+			})
+			t.Run("without registry custom scalars accept strings", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(at: "yesterday") }`, Values(), Valid)
+			})
+		})
 	})
 }
 
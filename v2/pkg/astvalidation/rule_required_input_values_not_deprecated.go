@@ -0,0 +1,63 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// RequiredInputValuesNotDeprecated validates that arguments and input fields
+// which are non-null and have no default value are not marked as @deprecated
+func RequiredInputValuesNotDeprecated() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &requiredInputValuesNotDeprecatedVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterInputValueDefinitionVisitor(visitor)
+	}
+}
+
+type requiredInputValuesNotDeprecatedVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+}
+
+func (r *requiredInputValuesNotDeprecatedVisitor) EnterDocument(operation, definition *ast.Document) {
+	r.definition = operation
+}
+
+func (r *requiredInputValuesNotDeprecatedVisitor) EnterInputValueDefinition(ref int) {
+	if r.definition.InputValueDefinitionArgumentIsOptional(ref) {
+		return
+	}
+	if !r.definition.InputValueDefinitionHasDirective(ref, []byte("deprecated")) {
+		return
+	}
+	if len(r.Ancestors) == 0 {
+		return
+	}
+
+	inputValueName := r.definition.InputValueDefinitionNameBytes(ref)
+	parent := r.Ancestors[len(r.Ancestors)-1]
+
+	switch parent.Kind {
+	case ast.NodeKindFieldDefinition:
+		if len(r.Ancestors) < 2 {
+			return
+		}
+		typeName := r.definition.NodeNameBytes(r.Ancestors[len(r.Ancestors)-2])
+		fieldName := r.definition.FieldDefinitionNameBytes(parent.Ref)
+		r.Report.AddExternalError(operationreport.ErrRequiredArgumentCannotBeDeprecated(typeName, fieldName, inputValueName))
+	case ast.NodeKindDirectiveDefinition:
+		directiveName := r.definition.DirectiveDefinitionNameBytes(parent.Ref)
+		r.Report.AddExternalError(operationreport.ErrRequiredDirectiveArgumentCannotBeDeprecated(directiveName, inputValueName))
+	case ast.NodeKindInputObjectTypeDefinition:
+		typeName := r.definition.InputObjectTypeDefinitionNameBytes(parent.Ref)
+		r.Report.AddExternalError(operationreport.ErrRequiredInputFieldCannotBeDeprecated(typeName, inputValueName))
+	case ast.NodeKindInputObjectTypeExtension:
+		typeName := r.definition.InputObjectTypeExtensionNameBytes(parent.Ref)
+		r.Report.AddExternalError(operationreport.ErrRequiredInputFieldCannotBeDeprecated(typeName, inputValueName))
+	}
+}
@@ -0,0 +1,114 @@
+package astvalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+)
+
+func TestRequiredInputValuesNotDeprecated(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("deprecated optional arguments", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						foo(a: String @deprecated, b: String! = "b" @deprecated): String
+					}
+					directive @bar(a: Int @deprecated, b: Int! = 1 @deprecated) on FIELD
+				`, Valid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("deprecated optional input fields", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input Foo {
+						a: String @deprecated
+						b: String! = "b" @deprecated(reason: "no longer used")
+					}
+					extend input Foo {
+						c: [String!] @deprecated
+					}
+				`, Valid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("deprecated required field argument", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						foo(a: String! @deprecated): String
+					}
+				`, Invalid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("deprecated required interface extension field argument", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Foo {
+						foo: String
+					}
+					extend interface Foo {
+						bar(a: [String]! @deprecated): String
+					}
+				`, Invalid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("deprecated required directive argument", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @bar(a: Int! @deprecated) on FIELD
+				`, Invalid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("deprecated required input field", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input Foo {
+						a: String! @deprecated
+					}
+				`, Invalid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("deprecated required input field in extension", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input Foo {
+						a: String
+					}
+					extend input Foo {
+						b: String! @deprecated
+					}
+				`, Invalid, RequiredInputValuesNotDeprecated(),
+			)
+		})
+
+		t.Run("error messages", func(t *testing.T) {
+			definition, report := astparser.ParseGraphqlDocumentString(`
+					type Query {
+						foo(a: String! @deprecated): String
+					}
+					directive @bar(a: Int! @deprecated) on FIELD
+					input Foo {
+						a: String! @deprecated
+					}
+				`)
+			require.False(t, report.HasErrors())
+			require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+			validator := NewDefinitionValidator(RequiredInputValuesNotDeprecated())
+			assert.Equal(t, Invalid, validator.Validate(&definition, &report))
+
+			messages := make([]string, 0, len(report.ExternalErrors))
+			for _, err := range report.ExternalErrors {
+				messages = append(messages, err.Message)
+			}
+			assert.Equal(t, []string{
+				"Required argument Query.foo(a:) cannot be deprecated.",
+				"Required argument @bar(a:) cannot be deprecated.",
+				"Required input field Foo.a cannot be deprecated.",
+			}, messages)
+		})
+	})
+}
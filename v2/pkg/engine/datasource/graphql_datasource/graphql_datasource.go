@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/buger/jsonparser"
 	"github.com/cespare/xxhash/v2"
@@ -1654,6 +1656,60 @@ func (s *Source) Load(ctx context.Context, input []byte, writer io.Writer) (err
 	return httpclient.Do(s.httpClient, ctx, input, writer)
 }
 
+// LoadWithFiles sends the input as multipart request, when files are mapped to variables of the input
+// Files of other variables belong to other fetches and are not forwarded
+func (s *Source) LoadWithFiles(ctx context.Context, input []byte, files []httpclient.File, writer io.Writer) (err error) {
+	files = s.filesOfInput(input, files)
+	if len(files) == 0 {
+		return s.Load(ctx, input, writer)
+	}
+
+	input = s.compactAndUnNullVariables(input)
+
+	// the variables of files are null, so they might have been removed while compacting the variables
+	for _, file := range files {
+		input, err = jsonparser.Set(input, literal.NULL, fileVariableKeys(file)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return httpclient.DoMultipartForm(s.httpClient, ctx, input, files, writer)
+}
+
+func (s *Source) filesOfInput(input []byte, files []httpclient.File) []httpclient.File {
+	var filesOfInput []httpclient.File
+	for _, file := range files {
+		keys := fileVariableKeys(file)
+		if keys == nil {
+			continue
+		}
+		if _, _, _, err := jsonparser.Get(input, keys...); err == nil {
+			filesOfInput = append(filesOfInput, file)
+		}
+	}
+	return filesOfInput
+}
+
+// fileVariableKeys returns the jsonparser keys of the file variable within the input,
+// e.g. variables.files.0 -> body, variables, files, [0]
+func fileVariableKeys(file httpclient.File) []string {
+	segments := strings.Split(file.VariablePath(), ".")
+	if len(segments) < 2 || segments[0] != "variables" {
+		return nil
+	}
+
+	keys := make([]string, 0, len(segments)+1)
+	keys = append(keys, "body", "variables")
+	for _, segment := range segments[1:] {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segment = "[" + segment + "]"
+		}
+		keys = append(keys, segment)
+	}
+	return keys
+}
+
 type GraphQLSubscriptionClient interface {
 	Subscribe(ctx *resolve.Context, options GraphQLSubscriptionOptions, updater resolve.SubscriptionUpdater) error
 	UniqueRequestID(ctx *resolve.Context, options GraphQLSubscriptionOptions, hash *xxhash.Digest) (err error)
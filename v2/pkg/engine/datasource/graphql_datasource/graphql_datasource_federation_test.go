@@ -1368,7 +1368,7 @@ func TestGraphQLDataSourceFederation(t *testing.T) {
 																				DependsOnFetchIDs:    []int{0, 3},
 																				DataSourceIdentifier: []byte("graphql_datasource.Source"),
 																				FetchConfiguration: resolve.FetchConfiguration{
-																					Input:               `{"method":"POST","url":"http://address.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Address {line3(test: "BOOM") zip}}}","variables":{"representations":[$$0$$]}}}`,
+																					Input:               `{"method":"POST","url":"http://address.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Address {line3__requires_37bcb8596b407f31: line3(test: "BOOM") zip}}}","variables":{"representations":[$$0$$]}}}`,
 																					DataSource:          &Source{},
 																					PostProcessing:      SingleEntityPostProcessingConfiguration,
 																					RequiresEntityFetch: true,
@@ -1457,7 +1457,7 @@ func TestGraphQLDataSourceFederation(t *testing.T) {
 																									{
 																										Name: []byte("line3"),
 																										Value: &resolve.String{
-																											Path: []string{"line3"},
+																											Path: []string{"line3__requires_37bcb8596b407f31"},
 																										},
 																										OnTypeNames: [][]byte{[]byte("Address")},
 																									},
@@ -4690,6 +4690,417 @@ func TestGraphQLDataSourceFederation(t *testing.T) {
 		})
 	})
 
+	t.Run("multiple keys selection by cost", func(t *testing.T) {
+		definition := `
+			type Product {
+				id: ID!
+				sku: String!
+				upc: String!
+				name: String!
+				price: Int!
+			}
+
+			type Query {
+				product: Product!
+			}
+		`
+
+		firstSubgraphSDL := `
+			type Product @key(fields: "id") @key(fields: "sku upc") {
+				id: ID!
+				sku: String!
+				upc: String!
+			}
+
+			type Query {
+				product: Product!
+			}
+		`
+
+		firstDatasourceConfiguration := mustDataSourceConfiguration(
+			t,
+			"first-service",
+			&plan.DataSourceMetadata{
+				RootNodes: []plan.TypeField{
+					{
+						TypeName:   "Query",
+						FieldNames: []string{"product"},
+					},
+					{
+						TypeName:   "Product",
+						FieldNames: []string{"id", "sku", "upc"},
+					},
+				},
+				FederationMetaData: plan.FederationMetaData{
+					Keys: plan.FederationFieldConfigurations{
+						{
+							TypeName:     "Product",
+							SelectionSet: "id",
+						},
+						{
+							TypeName:     "Product",
+							SelectionSet: "sku upc",
+						},
+					},
+				},
+			},
+			mustCustomConfiguration(t,
+				ConfigurationInput{
+					Fetch: &FetchConfiguration{
+						URL: "http://first.service",
+					},
+					SchemaConfiguration: mustSchema(t,
+						&FederationConfiguration{
+							Enabled:    true,
+							ServiceSDL: firstSubgraphSDL,
+						},
+						firstSubgraphSDL,
+					),
+				},
+			),
+		)
+
+		secondSubgraphSDL := `
+			type Product @key(fields: "sku upc") @key(fields: "id") {
+				id: ID!
+				sku: String!
+				upc: String!
+				name: String!
+			}
+		`
+
+		secondDatasourceConfiguration := mustDataSourceConfiguration(
+			t,
+			"second-service",
+			&plan.DataSourceMetadata{
+				RootNodes: []plan.TypeField{
+					{
+						TypeName:   "Product",
+						FieldNames: []string{"id", "sku", "upc", "name"},
+					},
+				},
+				FederationMetaData: plan.FederationMetaData{
+					Keys: plan.FederationFieldConfigurations{
+						{
+							TypeName:     "Product",
+							SelectionSet: "sku upc",
+						},
+						{
+							TypeName:     "Product",
+							SelectionSet: "id",
+						},
+					},
+				},
+			},
+			mustCustomConfiguration(t,
+				ConfigurationInput{
+					Fetch: &FetchConfiguration{
+						URL: "http://second.service",
+					},
+					SchemaConfiguration: mustSchema(t,
+						&FederationConfiguration{
+							Enabled:    true,
+							ServiceSDL: secondSubgraphSDL,
+						},
+						secondSubgraphSDL,
+					),
+				},
+			),
+		)
+
+		representationsVariable := func(keyFields ...string) []resolve.Variable {
+			fields := []*resolve.Field{
+				{
+					Name: []byte("__typename"),
+					Value: &resolve.String{
+						Path: []string{"__typename"},
+					},
+					OnTypeNames: [][]byte{[]byte("Product")},
+				},
+			}
+			for _, keyField := range keyFields {
+				fields = append(fields, &resolve.Field{
+					Name: []byte(keyField),
+					Value: &resolve.String{
+						Path: []string{keyField},
+					},
+					OnTypeNames: [][]byte{[]byte("Product")},
+				})
+			}
+
+			return []resolve.Variable{
+				&resolve.ResolvableObjectVariable{
+					Renderer: resolve.NewGraphQLVariableResolveRenderer(&resolve.Object{
+						Nullable: true,
+						Fields:   fields,
+					}),
+				},
+			}
+		}
+
+		t.Run("prefer the key which is already selected", func(t *testing.T) {
+			planConfiguration := plan.Configuration{
+				DataSources: []plan.DataSource{
+					firstDatasourceConfiguration,
+					secondDatasourceConfiguration,
+				},
+				DisableResolveFieldPositions: true,
+			}
+
+			RunWithPermutations(
+				t,
+				definition,
+				`
+				query Product {
+					product {
+						id
+						name
+					}
+				}`,
+				"Product",
+				&plan.SynchronousResponsePlan{
+					Response: &resolve.GraphQLResponse{
+						Data: &resolve.Object{
+							Fetch: &resolve.SingleFetch{
+								FetchConfiguration: resolve.FetchConfiguration{
+									Input:          `{"method":"POST","url":"http://first.service","body":{"query":"{product {id __typename}}"}}`,
+									PostProcessing: DefaultPostProcessingConfiguration,
+									DataSource:     &Source{},
+								},
+								DataSourceIdentifier: []byte("graphql_datasource.Source"),
+							},
+							Fields: []*resolve.Field{
+								{
+									Name: []byte("product"),
+									Value: &resolve.Object{
+										Path: []string{"product"},
+										Fields: []*resolve.Field{
+											{
+												Name: []byte("id"),
+												Value: &resolve.Scalar{
+													Path: []string{"id"},
+												},
+											},
+											{
+												Name: []byte("name"),
+												Value: &resolve.String{
+													Path: []string{"name"},
+												},
+											},
+										},
+										Fetch: &resolve.SingleFetch{
+											FetchID:           1,
+											DependsOnFetchIDs: []int{0},
+											FetchConfiguration: resolve.FetchConfiguration{
+												RequiresEntityFetch:                   true,
+												Input:                                 `{"method":"POST","url":"http://second.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {name}}}","variables":{"representations":[$$0$$]}}}`,
+												DataSource:                            &Source{},
+												SetTemplateOutputToNullOnVariableNull: true,
+												Variables:                             representationsVariable("id"),
+												PostProcessing:                        SingleEntityPostProcessingConfiguration,
+											},
+											DataSourceIdentifier: []byte("graphql_datasource.Source"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				planConfiguration,
+				WithMultiFetchPostProcessor(),
+			)
+		})
+
+		t.Run("fall back to the key which could be provided - hop through an intermediate subgraph", func(t *testing.T) {
+			firstSubgraphSDL := `
+				type Product @key(fields: "id") {
+					id: ID!
+				}
+
+				type Query {
+					product: Product!
+				}
+			`
+
+			firstDatasourceConfiguration := mustDataSourceConfiguration(
+				t,
+				"first-service",
+				&plan.DataSourceMetadata{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Query",
+							FieldNames: []string{"product"},
+						},
+						{
+							TypeName:   "Product",
+							FieldNames: []string{"id"},
+						},
+					},
+					FederationMetaData: plan.FederationMetaData{
+						Keys: plan.FederationFieldConfigurations{
+							{
+								TypeName:     "Product",
+								SelectionSet: "id",
+							},
+						},
+					},
+				},
+				mustCustomConfiguration(t,
+					ConfigurationInput{
+						Fetch: &FetchConfiguration{
+							URL: "http://first.service",
+						},
+						SchemaConfiguration: mustSchema(t,
+							&FederationConfiguration{
+								Enabled:    true,
+								ServiceSDL: firstSubgraphSDL,
+							},
+							firstSubgraphSDL,
+						),
+					},
+				),
+			)
+
+			thirdSubgraphSDL := `
+				type Product @key(fields: "upc") @key(fields: "sku upc") {
+					upc: String!
+					sku: String!
+					price: Int!
+				}
+			`
+
+			thirdDatasourceConfiguration := mustDataSourceConfiguration(
+				t,
+				"third-service",
+				&plan.DataSourceMetadata{
+					RootNodes: []plan.TypeField{
+						{
+							TypeName:   "Product",
+							FieldNames: []string{"upc", "sku", "price"},
+						},
+					},
+					FederationMetaData: plan.FederationMetaData{
+						Keys: plan.FederationFieldConfigurations{
+							{
+								TypeName:     "Product",
+								SelectionSet: "upc",
+							},
+							{
+								TypeName:     "Product",
+								SelectionSet: "sku upc",
+							},
+						},
+					},
+				},
+				mustCustomConfiguration(t,
+					ConfigurationInput{
+						Fetch: &FetchConfiguration{
+							URL: "http://third.service",
+						},
+						SchemaConfiguration: mustSchema(t,
+							&FederationConfiguration{
+								Enabled:    true,
+								ServiceSDL: thirdSubgraphSDL,
+							},
+							thirdSubgraphSDL,
+						),
+					},
+				),
+			)
+
+			planConfiguration := plan.Configuration{
+				DataSources: []plan.DataSource{
+					firstDatasourceConfiguration,
+					secondDatasourceConfiguration,
+					thirdDatasourceConfiguration,
+				},
+				DisableResolveFieldPositions: true,
+			}
+
+			RunWithPermutations(
+				t,
+				definition,
+				`
+				query Product {
+					product {
+						name
+						price
+					}
+				}`,
+				"Product",
+				&plan.SynchronousResponsePlan{
+					Response: &resolve.GraphQLResponse{
+						Data: &resolve.Object{
+							Fetch: &resolve.SingleFetch{
+								FetchConfiguration: resolve.FetchConfiguration{
+									Input:          `{"method":"POST","url":"http://first.service","body":{"query":"{product {__typename id}}"}}`,
+									PostProcessing: DefaultPostProcessingConfiguration,
+									DataSource:     &Source{},
+								},
+								DataSourceIdentifier: []byte("graphql_datasource.Source"),
+							},
+							Fields: []*resolve.Field{
+								{
+									Name: []byte("product"),
+									Value: &resolve.Object{
+										Path: []string{"product"},
+										Fields: []*resolve.Field{
+											{
+												Name: []byte("name"),
+												Value: &resolve.String{
+													Path: []string{"name"},
+												},
+											},
+											{
+												Name: []byte("price"),
+												Value: &resolve.Integer{
+													Path: []string{"price"},
+												},
+											},
+										},
+										Fetch: &resolve.SerialFetch{
+											Fetches: []resolve.Fetch{
+												&resolve.SingleFetch{
+													FetchID:           1,
+													DependsOnFetchIDs: []int{0},
+													FetchConfiguration: resolve.FetchConfiguration{
+														RequiresEntityFetch:                   true,
+														Input:                                 `{"method":"POST","url":"http://second.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {name sku upc}}}","variables":{"representations":[$$0$$]}}}`,
+														DataSource:                            &Source{},
+														SetTemplateOutputToNullOnVariableNull: true,
+														Variables:                             representationsVariable("id"),
+														PostProcessing:                        SingleEntityPostProcessingConfiguration,
+													},
+													DataSourceIdentifier: []byte("graphql_datasource.Source"),
+												},
+												&resolve.SingleFetch{
+													FetchID:           2,
+													DependsOnFetchIDs: []int{0, 1},
+													FetchConfiguration: resolve.FetchConfiguration{
+														RequiresEntityFetch:                   true,
+														Input:                                 `{"method":"POST","url":"http://third.service","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on Product {price}}}","variables":{"representations":[$$0$$]}}}`,
+														DataSource:                            &Source{},
+														SetTemplateOutputToNullOnVariableNull: true,
+														Variables:                             representationsVariable("sku", "upc"),
+														PostProcessing:                        SingleEntityPostProcessingConfiguration,
+													},
+													DataSourceIdentifier: []byte("graphql_datasource.Source"),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				planConfiguration,
+				WithMultiFetchPostProcessor(),
+			)
+		})
+	})
+
 	t.Run("key resolvable false", func(t *testing.T) {
 		t.Run("example 1", func(t *testing.T) {
 			definition := `
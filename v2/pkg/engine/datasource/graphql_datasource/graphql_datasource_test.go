@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -2810,6 +2811,100 @@ func TestGraphQLDataSource(t *testing.T) {
 			}))
 	})
 
+	t.Run("Query with oneOf input", func(t *testing.T) {
+		definition := `
+			directive @oneOf on INPUT_OBJECT
+			schema { query: Query }
+			type Query { user(by: UserBy!): User }
+			type User { id: ID! name: String! }
+			input UserBy @oneOf { id: ID email: String }
+		`
+
+		t.Run("run", RunTest(definition, `
+		query User($by: UserBy!) {
+			user(by: $by) {
+				name
+			}
+		}`, "User",
+			&plan.SynchronousResponsePlan{
+				Response: &resolve.GraphQLResponse{
+					Data: &resolve.Object{
+						Fetch: &resolve.SingleFetch{
+							FetchConfiguration: resolve.FetchConfiguration{
+								Input:      `{"method":"POST","url":"https://example.com/graphql","body":{"query":"query($by: UserBy!){user(by: $by){name}}","variables":{"by":$$0$$}}}`,
+								DataSource: &Source{},
+								Variables: resolve.NewVariables(
+									&resolve.ContextVariable{
+										Path:     []string{"by"},
+										Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["object"],"properties":{"email":{"type":["string","null"]},"id":{"type":["string","integer","null"]}},"additionalProperties":false,"maxProperties":1,"oneOf":[{"properties":{"id":{"not":{"type":["null"]}}},"required":["id"]},{"properties":{"email":{"not":{"type":["null"]}}},"required":["email"]}]}`),
+									},
+								),
+								PostProcessing: DefaultPostProcessingConfiguration,
+							},
+							DataSourceIdentifier: []byte("graphql_datasource.Source"),
+						},
+						Fields: []*resolve.Field{
+							{
+								Name: []byte("user"),
+								Value: &resolve.Object{
+									Nullable: true,
+									Path:     []string{"user"},
+									Fields: []*resolve.Field{
+										{
+											Name: []byte("name"),
+											Value: &resolve.String{
+												Path: []string{"name"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}, plan.Configuration{
+				DataSources: []plan.DataSource{
+					mustDataSourceConfiguration(
+						t,
+						"ds-id",
+						&plan.DataSourceMetadata{
+							RootNodes: []plan.TypeField{
+								{
+									TypeName:   "Query",
+									FieldNames: []string{"user"},
+								},
+							},
+							ChildNodes: []plan.TypeField{
+								{
+									TypeName:   "User",
+									FieldNames: []string{"id", "name"},
+								},
+							},
+						},
+						mustCustomConfiguration(t, ConfigurationInput{
+							Fetch: &FetchConfiguration{
+								URL: "https://example.com/graphql",
+							},
+							SchemaConfiguration: mustSchema(t, nil, definition),
+						}),
+					),
+				},
+				Fields: []plan.FieldConfiguration{
+					{
+						TypeName:  "Query",
+						FieldName: "user",
+						Arguments: []plan.ArgumentConfiguration{
+							{
+								Name:       "by",
+								SourceType: plan.FieldArgumentSource,
+							},
+						},
+					},
+				},
+				DisableResolveFieldPositions: true,
+			}))
+	})
+
 	t.Run("Query with Date input aka scalar", func(t *testing.T) {
 		t.Run("run", runTestOnTestDefinition(t, `
 		query HeroByBirthdate($birthdate: Date!) {
@@ -9085,6 +9180,97 @@ func TestSource_Load(t *testing.T) {
 	})
 }
 
+type testFile struct {
+	name         string
+	variablePath string
+	content      string
+}
+
+func (f testFile) Name() string {
+	return f.name
+}
+
+func (f testFile) VariablePath() string {
+	return f.variablePath
+}
+
+func (f testFile) Open() (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.content)), nil
+}
+
+func TestSource_LoadWithFiles(t *testing.T) {
+	// the server responds with the parts of the multipart form
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			body, _ := io.ReadAll(r.Body)
+			_, _ = fmt.Fprintf(w, `{"body":%s}`, string(body))
+			return
+		}
+
+		files := map[string]string{}
+		for fieldName, headers := range r.MultipartForm.File {
+			file, err := headers[0].Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			files[fieldName] = headers[0].Filename + ":" + string(content)
+		}
+		filesJSON, err := json.Marshal(files)
+		require.NoError(t, err)
+
+		_, _ = fmt.Fprintf(w, `{"operations":%s,"map":%s,"files":%s}`, r.FormValue("operations"), r.FormValue("map"), string(filesJSON))
+	}))
+	defer ts.Close()
+
+	src := &Source{httpClient: &http.Client{}}
+
+	input := func(variables string, flags ...string) []byte {
+		var input []byte
+		input = httpclient.SetInputBodyWithPath(input, []byte(variables), "variables")
+		input = httpclient.SetInputBodyWithPath(input, []byte(`"mutation($file: Upload, $files: [Upload]){upload(file: $file, files: $files)}"`), "query")
+		input = httpclient.SetInputURL(input, []byte(ts.URL))
+		for _, flag := range flags {
+			input = httpclient.SetInputFlag(input, flag)
+		}
+		return input
+	}
+
+	t.Run("forwards files as multipart form", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, src.LoadWithFiles(context.Background(), input(`{"file":null,"files":[null,null]}`), []httpclient.File{
+			testFile{name: "a.txt", variablePath: "variables.file", content: "a"},
+			testFile{name: "b.txt", variablePath: "variables.files.0", content: "b"},
+			testFile{name: "c.txt", variablePath: "variables.files.1", content: "c"},
+		}, buf))
+		assert.JSONEq(t, `{"operations":{"variables":{"file":null,"files":[null,null]},"query":"mutation($file: Upload, $files: [Upload]){upload(file: $file, files: $files)}"},"map":{"0":["variables.file"],"1":["variables.files.0"],"2":["variables.files.1"]},"files":{"0":"a.txt:a","1":"b.txt:b","2":"c.txt:c"}}`, buf.String())
+	})
+
+	t.Run("keeps file variables when null variables are removed", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, src.LoadWithFiles(context.Background(), input(`{"file":null,"other":null}`, httpclient.UNNULL_VARIABLES), []httpclient.File{
+			testFile{name: "a.txt", variablePath: "variables.file", content: "a"},
+		}, buf))
+		assert.JSONEq(t, `{"operations":{"variables":{"file":null},"query":"mutation($file: Upload, $files: [Upload]){upload(file: $file, files: $files)}"},"map":{"0":["variables.file"]},"files":{"0":"a.txt:a"}}`, buf.String())
+	})
+
+	t.Run("skips files of variables which are not part of the input", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, src.LoadWithFiles(context.Background(), input(`{"file":null}`), []httpclient.File{
+			testFile{name: "a.txt", variablePath: "variables.file", content: "a"},
+			testFile{name: "b.txt", variablePath: "variables.other", content: "b"},
+		}, buf))
+		assert.JSONEq(t, `{"operations":{"variables":{"file":null},"query":"mutation($file: Upload, $files: [Upload]){upload(file: $file, files: $files)}"},"map":{"0":["variables.file"]},"files":{"0":"a.txt:a"}}`, buf.String())
+	})
+
+	t.Run("sends a regular request without files of the input", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, src.LoadWithFiles(context.Background(), input(`{}`), []httpclient.File{
+			testFile{name: "a.txt", variablePath: "variables.file", content: "a"},
+		}, buf))
+		assert.JSONEq(t, `{"body":{"variables":{},"query":"mutation($file: Upload, $files: [Upload]){upload(file: $file, files: $files)}"}}`, buf.String())
+	})
+}
+
 func TestUnNullVariables(t *testing.T) {
 	t.Run("should not unnull variables if not enabled", func(t *testing.T) {
 		t.Run("two variables, one null", func(t *testing.T) {
@@ -165,13 +165,24 @@ func (v *representationVariableVisitor) EnterField(ref int) {
 	}
 	fieldDefinitionType := v.definition.FieldDefinitionType(fieldDefinition)
 
+	// required fields with arguments are added to the operation with an alias
+	// so the value has to be taken from the aliased path
+	path := []string{string(fieldName)}
+	if alias, ok := plan.RequiredFieldAlias(v.key, ref); ok {
+		path = []string{alias}
+	}
+
 	currentField := &resolve.Field{
 		Name:  fieldName,
-		Value: v.resolveFieldValue(ref, fieldDefinitionType, true, []string{string(fieldName)}),
+		Value: v.resolveFieldValue(ref, fieldDefinitionType, true, path),
 	}
 
-	if v.addOnType && v.currentFields[len(v.currentFields)-1].isRoot {
-		v.addTypeNameToField(currentField)
+	if v.currentFields[len(v.currentFields)-1].isRoot {
+		if v.addOnType {
+			v.addTypeNameToField(currentField)
+		}
+	} else {
+		currentField.OnTypeNames = v.inlineFragmentTypeNames()
 	}
 
 	*v.currentFields[len(v.currentFields)-1].fields = append(*v.currentFields[len(v.currentFields)-1].fields, currentField)
@@ -188,6 +199,47 @@ func (v *representationVariableVisitor) addTypeNameToField(field *resolve.Field)
 	}
 }
 
+// inlineFragmentTypeNames returns the possible type names of the inline fragment enclosing the current field
+// e.g. for `media { ... on Book { title } }` field title should be rendered only for the Book type
+func (v *representationVariableVisitor) inlineFragmentTypeNames() [][]byte {
+	if len(v.Walker.Ancestors) < 2 {
+		return nil
+	}
+	inlineFragment := v.Walker.Ancestors[len(v.Walker.Ancestors)-2]
+	if inlineFragment.Kind != ast.NodeKindInlineFragment {
+		return nil
+	}
+	typeName := v.key.InlineFragmentTypeConditionName(inlineFragment.Ref)
+	if typeName == nil {
+		return nil
+	}
+
+	node, exists := v.definition.NodeByName(typeName)
+	if !exists {
+		return nil
+	}
+
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		typeNames := make([][]byte, 0, 2)
+		for objectTypeDefinitionRef := range v.definition.ObjectTypeDefinitions {
+			if v.definition.ObjectTypeDefinitionImplementsInterface(objectTypeDefinitionRef, typeName) {
+				typeNames = append(typeNames, v.definition.ObjectTypeDefinitionNameBytes(objectTypeDefinitionRef))
+			}
+		}
+		return typeNames
+	case ast.NodeKindUnionTypeDefinition:
+		typeNames, _ := v.definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+		typeNamesBytes := make([][]byte, 0, len(typeNames))
+		for _, name := range typeNames {
+			typeNamesBytes = append(typeNamesBytes, []byte(name))
+		}
+		return typeNamesBytes
+	default:
+		return [][]byte{typeName}
+	}
+}
+
 func (v *representationVariableVisitor) LeaveField(ref int) {
 	if v.currentFields[len(v.currentFields)-1].popOnField == ref {
 		v.currentFields = v.currentFields[:len(v.currentFields)-1]
@@ -253,6 +305,15 @@ func (v *representationVariableVisitor) resolveFieldValue(fieldRef, typeRef int,
 				Path:     path,
 				Fields:   []*resolve.Field{},
 			}
+			if typeDefinitionNode.Kind.IsAbstractType() {
+				// representation of an abstract value should contain the concrete type name
+				object.Fields = append(object.Fields, &resolve.Field{
+					Name: []byte("__typename"),
+					Value: &resolve.String{
+						Path: []string{"__typename"},
+					},
+				})
+			}
 			v.Walker.DefferOnEnterField(func() {
 				v.currentFields = append(v.currentFields, objectFields{
 					popOnField: fieldRef,
@@ -172,7 +172,7 @@ func TestBuildRepresentationVariableNode(t *testing.T) {
 								{
 									Name: []byte("address"),
 									Value: &resolve.Object{
-										Path: []string{"address"},
+										Path: []string{"address__requires_127d73c525504dcb"},
 										Fields: []*resolve.Field{
 											{
 												Name: []byte("zip"),
@@ -190,6 +190,104 @@ func TestBuildRepresentationVariableNode(t *testing.T) {
 				},
 			})
 	})
+
+	t.Run("lists, arguments and abstract types", func(t *testing.T) {
+		runTest(t, `
+			scalar String
+			scalar Int
+
+			type User {
+				id: String!
+				reviews(first: Int): [Review!]!
+				media: [Media]
+			}
+
+			type Review {
+				body: String!
+			}
+
+			union Media = Book | Movie
+
+			type Book {
+				title: String!
+			}
+
+			type Movie {
+				duration: Int!
+			}
+		`,
+			`id reviews(first: 2) { body } media { ... on Book { title } ... on Movie { duration } }`,
+			plan.FederationMetaData{},
+			&resolve.Object{
+				Nullable: true,
+				Fields: []*resolve.Field{
+					{
+						Name: []byte("__typename"),
+						Value: &resolve.String{
+							Path: []string{"__typename"},
+						},
+						OnTypeNames: [][]byte{[]byte("User")},
+					},
+					{
+						Name: []byte("id"),
+						Value: &resolve.String{
+							Path: []string{"id"},
+						},
+						OnTypeNames: [][]byte{[]byte("User")},
+					},
+					{
+						Name: []byte("reviews"),
+						Value: &resolve.Array{
+							Path: []string{"reviews__requires_aad102b1a6dad46c"},
+							Item: &resolve.Object{
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("body"),
+										Value: &resolve.String{
+											Path: []string{"body"},
+										},
+									},
+								},
+							},
+						},
+						OnTypeNames: [][]byte{[]byte("User")},
+					},
+					{
+						Name: []byte("media"),
+						Value: &resolve.Array{
+							Path:     []string{"media"},
+							Nullable: true,
+							Item: &resolve.Object{
+								Nullable: true,
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("__typename"),
+										Value: &resolve.String{
+											Path: []string{"__typename"},
+										},
+									},
+									{
+										Name: []byte("title"),
+										Value: &resolve.String{
+											Path: []string{"title"},
+										},
+										OnTypeNames: [][]byte{[]byte("Book")},
+									},
+									{
+										Name: []byte("duration"),
+										Value: &resolve.Integer{
+											Path: []string{"duration"},
+										},
+										OnTypeNames: [][]byte{[]byte("Movie")},
+									},
+								},
+							},
+						},
+						OnTypeNames: [][]byte{[]byte("User")},
+					},
+				},
+			})
+	})
 }
 
 func TestMergeRepresentationVariableNodes(t *testing.T) {
@@ -0,0 +1,16 @@
+package httpclient
+
+import (
+	"io"
+)
+
+// File is a file upload of the GraphQL multipart request spec, which is forwarded to an origin
+type File interface {
+	// Name is the file name provided by the client
+	Name() string
+	// VariablePath is the path of the variable the file is mapped to, e.g. variables.files.0
+	VariablePath() string
+	// Open returns the content of the file
+	// It could be called multiple times, e.g. when the file is forwarded to multiple origins
+	Open() (io.ReadCloser, error)
+}
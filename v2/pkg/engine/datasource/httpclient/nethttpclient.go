@@ -6,9 +6,12 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +33,8 @@ const (
 )
 
 var (
+	ErrNoFiles = errors.New("multipart form requires at least one file")
+
 	DefaultNetHttpClient = &http.Client{
 		Timeout: time.Second * 10,
 		Transport: &http.Transport{
@@ -79,10 +84,78 @@ func setResponseStatusCode(ctx context.Context, statusCode int) {
 }
 
 func Do(client *http.Client, ctx context.Context, requestInput []byte, out io.Writer) (err error) {
+	url, method, body, headers, queryParams, enableTrace := requestInputParams(requestInput)
+
+	return makeHTTPRequest(client, ctx, url, method, headers, queryParams, bytes.NewReader(body), enableTrace, out, ContentTypeJSON)
+}
+
+// DoMultipartForm sends the request as multipart form according to the GraphQL multipart request spec,
+// the body of the request input is sent as operations field and each file is mapped to its variable path.
+// The form is streamed to the origin, so the files are never loaded into memory as a whole.
+// See https://github.com/jaydenseric/graphql-multipart-request-spec
+func DoMultipartForm(client *http.Client, ctx context.Context, requestInput []byte, files []File, out io.Writer) (err error) {
+	if len(files) == 0 {
+		return ErrNoFiles
+	}
 
 	url, method, body, headers, queryParams, enableTrace := requestInputParams(requestInput)
 
-	request, err := http.NewRequestWithContext(ctx, string(method), string(url), bytes.NewReader(body))
+	fileMap := make(map[string][]string, len(files))
+	for i := range files {
+		fileMap[strconv.Itoa(i)] = []string{files[i].VariablePath()}
+	}
+	fileMapBytes, err := json.Marshal(fileMap)
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	// closing the reader unblocks the writing goroutine in case the request failed before the body was read
+	defer reader.Close()
+
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeMultipartForm(form, body, fileMapBytes, files))
+	}()
+
+	return makeHTTPRequest(client, ctx, url, method, headers, queryParams, reader, enableTrace, out, form.FormDataContentType())
+}
+
+func writeMultipartForm(form *multipart.Writer, operations, fileMap []byte, files []File) error {
+	if err := form.WriteField("operations", string(operations)); err != nil {
+		return err
+	}
+	if err := form.WriteField("map", string(fileMap)); err != nil {
+		return err
+	}
+
+	for i := range files {
+		if err := writeMultipartFile(form, strconv.Itoa(i), files[i]); err != nil {
+			return err
+		}
+	}
+
+	return form.Close()
+}
+
+func writeMultipartFile(form *multipart.Writer, fieldName string, file File) error {
+	content, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	part, err := form.CreateFormFile(fieldName, file.Name())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, content)
+	return err
+}
+
+func makeHTTPRequest(client *http.Client, ctx context.Context, url, method, headers, queryParams []byte, body io.Reader, enableTrace bool, out io.Writer, contentType string) (err error) {
+	request, err := http.NewRequestWithContext(ctx, string(method), string(url), body)
 	if err != nil {
 		return err
 	}
@@ -136,7 +209,7 @@ func Do(client *http.Client, ctx context.Context, requestInput []byte, out io.Wr
 	}
 
 	request.Header.Add(AcceptHeader, ContentTypeJSON)
-	request.Header.Add(ContentTypeHeader, ContentTypeJSON)
+	request.Header.Add(ContentTypeHeader, contentType)
 	request.Header.Set(AcceptEncodingHeader, EncodingGzip)
 	request.Header.Add(AcceptEncodingHeader, EncodingDeflate)
 
@@ -62,6 +62,16 @@ func (f *IntrospectionConfigFactory) BuildFieldConfigurations() (planFields plan
 				},
 			},
 		},
+		{
+			TypeName:  "__Type",
+			FieldName: "inputFields",
+			Arguments: plan.ArgumentsConfigurations{
+				{
+					Name:       "includeDeprecated",
+					SourceType: plan.FieldArgumentSource,
+				},
+			},
+		},
 	}
 }
 
@@ -69,11 +79,13 @@ func (f *IntrospectionConfigFactory) BuildDataSourceConfigurations() []plan.Data
 	root, _ := f.buildRootDataSourceConfiguration()
 	fields, _ := f.buildFieldsConfiguration()
 	enums, _ := f.buildEnumsConfiguration()
+	inputFields, _ := f.buildInputFieldsConfiguration()
 
 	return []plan.DataSource{
 		root,
 		fields,
 		enums,
+		inputFields,
 	}
 }
 
@@ -95,7 +107,7 @@ func (f *IntrospectionConfigFactory) buildRootDataSourceConfiguration() (plan.Da
 				},
 				{
 					TypeName:   "__Type",
-					FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType"},
+					FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "ofType", "isOneOf"},
 				},
 				{
 					TypeName:   "__Field",
@@ -103,7 +115,7 @@ func (f *IntrospectionConfigFactory) buildRootDataSourceConfiguration() (plan.Da
 				},
 				{
 					TypeName:   "__InputValue",
-					FieldNames: []string{"name", "description", "type", "defaultValue"},
+					FieldNames: []string{"name", "description", "type", "defaultValue", "isDeprecated", "deprecationReason"},
 				},
 				{
 					TypeName:   "__Directive",
@@ -129,7 +141,7 @@ func (f *IntrospectionConfigFactory) buildFieldsConfiguration() (plan.DataSource
 			ChildNodes: []plan.TypeField{
 				{
 					TypeName:   "__Type",
-					FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType"},
+					FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "ofType", "isOneOf"},
 				},
 				{
 					TypeName:   "__Field",
@@ -137,7 +149,7 @@ func (f *IntrospectionConfigFactory) buildFieldsConfiguration() (plan.DataSource
 				},
 				{
 					TypeName:   "__InputValue",
-					FieldNames: []string{"name", "description", "type", "defaultValue"},
+					FieldNames: []string{"name", "description", "type", "defaultValue", "isDeprecated", "deprecationReason"},
 				},
 			},
 		},
@@ -167,6 +179,36 @@ func (f *IntrospectionConfigFactory) buildEnumsConfiguration() (plan.DataSourceC
 	)
 }
 
+func (f *IntrospectionConfigFactory) buildInputFieldsConfiguration() (plan.DataSourceConfiguration[Configuration], error) {
+	return plan.NewDataSourceConfiguration[Configuration](
+		resolve.IntrospectionTypeInputFieldsDataSourceID,
+		NewFactory[Configuration](f.introspectionData),
+		&plan.DataSourceMetadata{
+			RootNodes: []plan.TypeField{
+				{
+					TypeName:   "__Type",
+					FieldNames: []string{"inputFields"},
+				},
+			},
+			ChildNodes: f.inputValueChildNodes(),
+		},
+		Configuration{"Introspection: __Type.inputFields"},
+	)
+}
+
+func (f *IntrospectionConfigFactory) inputValueChildNodes() []plan.TypeField {
+	return []plan.TypeField{
+		{
+			TypeName:   "__Type",
+			FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "ofType", "isOneOf"},
+		},
+		{
+			TypeName:   "__InputValue",
+			FieldNames: []string{"name", "description", "type", "defaultValue", "isDeprecated", "deprecationReason"},
+		},
+	}
+}
+
 func (f *IntrospectionConfigFactory) dataSourceConfigQueryTypeName() string {
 	if f.introspectionData.Schema.QueryType == nil || len(f.introspectionData.Schema.QueryType.Name) == 0 {
 		return "Query"
@@ -12,13 +12,16 @@ const (
 	TypeRequestType
 	TypeFieldsRequestType
 	TypeEnumValuesRequestType
+	TypeInputFieldsRequestType
 )
 
 const (
-	schemaFieldName     = "__schema"
-	typeFieldName       = "__type"
-	fieldsFieldName     = "fields"
-	enumValuesFieldName = "enumValues"
+	schemaFieldName      = "__schema"
+	typeFieldName        = "__type"
+	fieldsFieldName      = "fields"
+	enumValuesFieldName  = "enumValues"
+	inputFieldsFieldName = "inputFields"
+	argsFieldName        = "args"
 )
 
 type introspectionInput struct {
@@ -26,19 +29,25 @@ type introspectionInput struct {
 	OnTypeName        *string     `json:"on_type_name"`
 	TypeName          *string     `json:"type_name"`
 	IncludeDeprecated bool        `json:"include_deprecated"`
+	// IncludeDeprecatedArgs is the includeDeprecated argument of each args selection by its response key
+	IncludeDeprecatedArgs map[string]bool `json:"include_deprecated_args"`
 }
 
 var (
-	lBrace                 = []byte("{")
-	rBrace                 = []byte("}")
-	comma                  = []byte(",")
-	requestTypeField       = []byte(`"request_type":`)
-	onTypeField            = []byte(`"on_type_name":"{{ .object.name }}"`)
-	typeNameField          = []byte(`"type_name":"{{ .arguments.name }}"`)
-	includeDeprecatedField = []byte(`"include_deprecated":{{ .arguments.includeDeprecated }}`)
+	lBrace                     = []byte("{")
+	rBrace                     = []byte("}")
+	comma                      = []byte(",")
+	requestTypeField           = []byte(`"request_type":`)
+	onTypeField                = []byte(`"on_type_name":"{{ .object.name }}"`)
+	typeNameField              = []byte(`"type_name":"{{ .arguments.name }}"`)
+	includeDeprecatedField     = []byte(`"include_deprecated":{{ .arguments.includeDeprecated }}`)
+	includeDeprecatedArgsField = []byte(`"include_deprecated_args":`)
 )
 
-func buildInput(fieldName string) string {
+// buildInput builds the input of the fetch for the root field.
+// includeDeprecatedArgs is the rendered includeDeprecated argument of each args selection within the fetch,
+// it is omitted when empty.
+func buildInput(fieldName string, includeDeprecatedArgs string) string {
 	buf := &bytes.Buffer{}
 	buf.Write(lBrace)
 
@@ -50,11 +59,16 @@ func buildInput(fieldName string) string {
 	case fieldsFieldName:
 		writeRequestTypeField(buf, TypeFieldsRequestType)
 		writeOnTypeFields(buf)
+		writeIncludeDeprecatedArgsField(buf, includeDeprecatedArgs)
 	case enumValuesFieldName:
 		writeRequestTypeField(buf, TypeEnumValuesRequestType)
 		writeOnTypeFields(buf)
+	case inputFieldsFieldName:
+		writeRequestTypeField(buf, TypeInputFieldsRequestType)
+		writeOnTypeFields(buf)
 	default:
 		writeRequestTypeField(buf, SchemaRequestType)
+		writeIncludeDeprecatedArgsField(buf, includeDeprecatedArgs)
 	}
 
 	buf.Write(rBrace)
@@ -73,3 +87,12 @@ func writeOnTypeFields(buf *bytes.Buffer) {
 	buf.Write(comma)
 	buf.Write(includeDeprecatedField)
 }
+
+func writeIncludeDeprecatedArgsField(buf *bytes.Buffer, includeDeprecatedArgs string) {
+	if includeDeprecatedArgs == "" {
+		return
+	}
+	buf.Write(comma)
+	buf.Write(includeDeprecatedArgsField)
+	buf.Write([]byte(includeDeprecatedArgs))
+}
@@ -9,18 +9,21 @@ import (
 )
 
 func TestBuildInput(t *testing.T) {
-	run := func(fieldName string, expectedJson string) func(t *testing.T) {
+	run := func(fieldName string, includeDeprecatedArgs string, expectedJson string) func(t *testing.T) {
 		t.Helper()
 		return func(t *testing.T) {
-			actualResult := buildInput(fieldName)
+			actualResult := buildInput(fieldName, includeDeprecatedArgs)
 			assert.Equal(t, expectedJson, actualResult)
 		}
 	}
 
-	t.Run("schema introspection", run(schemaFieldName, `{"request_type":1}`))
-	t.Run("type introspection", run(typeFieldName, `{"request_type":2,"type_name":"{{ .arguments.name }}"}`))
-	t.Run("type fields", run(fieldsFieldName, `{"request_type":3,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
-	t.Run("type enum values", run(enumValuesFieldName, `{"request_type":4,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
+	t.Run("schema introspection", run(schemaFieldName, "", `{"request_type":1}`))
+	t.Run("schema introspection with args", run(schemaFieldName, `{"args":false}`, `{"request_type":1,"include_deprecated_args":{"args":false}}`))
+	t.Run("type introspection", run(typeFieldName, "", `{"request_type":2,"type_name":"{{ .arguments.name }}"}`))
+	t.Run("type fields", run(fieldsFieldName, "", `{"request_type":3,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
+	t.Run("type fields with args", run(fieldsFieldName, `{"args":false,"all":$$0$$}`, `{"request_type":3,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }},"include_deprecated_args":{"args":false,"all":$$0$$}}`))
+	t.Run("type enum values", run(enumValuesFieldName, "", `{"request_type":4,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
+	t.Run("type input fields", run(inputFieldsFieldName, "", `{"request_type":5,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
 }
 
 func TestUnmarshalIntrospectionInput(t *testing.T) {
@@ -39,4 +42,6 @@ func TestUnmarshalIntrospectionInput(t *testing.T) {
 	t.Run("type introspection", run(`{"request_type":2,"type_name":"Foo"}`, introspectionInput{RequestType: TypeRequestType, TypeName: &foo}))
 	t.Run("type fields", run(`{"request_type":3,"on_type_name":"Foo","include_deprecated":true}`, introspectionInput{RequestType: TypeFieldsRequestType, OnTypeName: &foo, IncludeDeprecated: true}))
 	t.Run("type enum values", run(`{"request_type":4,"on_type_name":"Foo","include_deprecated":false}`, introspectionInput{RequestType: TypeEnumValuesRequestType, OnTypeName: &foo, IncludeDeprecated: false}))
+	t.Run("type input fields", run(`{"request_type":5,"on_type_name":"Foo","include_deprecated":true}`, introspectionInput{RequestType: TypeInputFieldsRequestType, OnTypeName: &foo, IncludeDeprecated: true}))
+	t.Run("type fields with args", run(`{"request_type":3,"on_type_name":"Foo","include_deprecated":false,"include_deprecated_args":{"args":false,"all":true}}`, introspectionInput{RequestType: TypeFieldsRequestType, OnTypeName: &foo, IncludeDeprecatedArgs: map[string]bool{"args": false, "all": true}}))
 }
@@ -1,7 +1,9 @@
 package introspection_datasource
 
 import (
+	"bytes"
 	"errors"
+	"strconv"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
@@ -20,6 +22,8 @@ type Planner[T Configuration] struct {
 	rootFieldName     string
 	rootFielPath      string
 	isArrayItem       bool
+	// argsFields are the args selections within the fetch, the fetch filters their deprecated arguments
+	argsFields []int
 }
 
 func (p *Planner[T]) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration[T]) (*ast.Document, bool) {
@@ -50,15 +54,66 @@ func (p *Planner[T]) EnterField(ref int) {
 	fieldName := p.v.Operation.FieldNameString(ref)
 	fieldAliasOrName := p.v.Operation.FieldAliasOrNameString(ref)
 	switch fieldName {
-	case typeFieldName, fieldsFieldName, enumValuesFieldName, schemaFieldName:
+	case typeFieldName, fieldsFieldName, enumValuesFieldName, inputFieldsFieldName, schemaFieldName:
 		p.rootField = ref
 		p.rootFieldName = fieldName
 		p.rootFielPath = fieldAliasOrName
+	case argsFieldName:
+		p.argsFields = append(p.argsFields, ref)
 	}
 }
 
-func (p *Planner[T]) configureInput() string {
-	return buildInput(p.rootFieldName)
+func (p *Planner[T]) configureInput(variables *resolve.Variables) string {
+	return buildInput(p.rootFieldName, p.includeDeprecatedArgs(variables))
+}
+
+// includeDeprecatedArgs renders the includeDeprecated argument of each args selection by its response key,
+// so the arguments of fields and directives are filtered within the fetch of the enclosing object.
+// Selections with the same response key have the same arguments, otherwise the operation would be invalid.
+func (p *Planner[T]) includeDeprecatedArgs(variables *resolve.Variables) string {
+	if len(p.argsFields) == 0 {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(lBrace)
+	rendered := make(map[string]struct{}, len(p.argsFields))
+	for _, ref := range p.argsFields {
+		responseKey := p.v.Operation.FieldAliasOrNameString(ref)
+		if _, ok := rendered[responseKey]; ok {
+			continue
+		}
+		if len(rendered) != 0 {
+			buf.Write(comma)
+		}
+		rendered[responseKey] = struct{}{}
+		buf.WriteString(strconv.Quote(responseKey))
+		buf.WriteString(":")
+		buf.WriteString(p.includeDeprecatedArgument(ref, variables))
+	}
+	buf.Write(rBrace)
+
+	return buf.String()
+}
+
+func (p *Planner[T]) includeDeprecatedArgument(ref int, variables *resolve.Variables) string {
+	arg, ok := p.v.Operation.FieldArgument(ref, []byte("includeDeprecated"))
+	if !ok {
+		return "false"
+	}
+
+	value := p.v.Operation.ArgumentValue(arg)
+	switch value.Kind {
+	case ast.ValueKindBoolean:
+		return strconv.FormatBool(bool(p.v.Operation.BooleanValue(value.Ref)))
+	case ast.ValueKindVariable:
+		variableName, _ := variables.AddVariable(&resolve.ContextVariable{
+			Path:     []string{p.v.Operation.VariableValueNameString(value.Ref)},
+			Renderer: resolve.NewPlainVariableRendererWithValidation(`{"type":["boolean","null"]}`),
+		})
+		return variableName
+	}
+	return "false"
 }
 
 func (p *Planner[T]) ConfigureFetch() resolve.FetchConfiguration {
@@ -72,12 +127,16 @@ func (p *Planner[T]) ConfigureFetch() resolve.FetchConfiguration {
 
 	requiresParallelListItemFetch := false
 	switch p.rootFieldName {
-	case fieldsFieldName, enumValuesFieldName:
+	case fieldsFieldName, enumValuesFieldName, inputFieldsFieldName:
 		requiresParallelListItemFetch = p.isArrayItem
 	}
 
+	var variables resolve.Variables
+	input := p.configureInput(&variables)
+
 	return resolve.FetchConfiguration{
-		Input:                         p.configureInput(),
+		Input:                         input,
+		Variables:                     variables,
 		RequiresParallelListItemFetch: requiresParallelListItemFetch,
 		DataSource: &Source{
 			introspectionData: p.introspectionData,
@@ -76,6 +76,21 @@ const (
 		}
 	`
 
+	schemaIntrospectionWithDeprecatedDirectiveArgs = `
+		query typeIntrospection {
+			__schema {
+				directives {
+					args {
+						name
+					}
+					all: args(includeDeprecated: true) {
+						name
+					}
+				}
+			}
+		}
+	`
+
 	typeIntrospectionWithArgs = `
 		query typeIntrospection {
 			__type(name: "Query") {
@@ -85,6 +100,9 @@ const (
 				enumValues(includeDeprecated: true) {
 					name
 				}
+				inputFields(includeDeprecated: true) {
+					name
+				}
 			}
 		}
 	`
@@ -239,6 +257,111 @@ func TestIntrospectionDataSourcePlanning(t *testing.T) {
 		},
 	))
 
+	t.Run("schema introspection request with deprecated directive args", runTest(schema, schemaIntrospectionWithDeprecatedDirectiveArgs,
+		&plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch: &resolve.SingleFetch{
+						DataSourceIdentifier: dataSourceIdentifier,
+						FetchConfiguration: resolve.FetchConfiguration{
+							Input:      `{"request_type":1,"include_deprecated_args":{"args":$$0$$,"all":$$1$$}}`,
+							DataSource: &Source{},
+							Variables: resolve.NewVariables(
+								&resolve.ContextVariable{
+									Path:     []string{"b"},
+									Renderer: resolve.NewPlainVariableRendererWithValidation(`{"type":["boolean","null"]}`),
+								},
+								&resolve.ContextVariable{
+									Path:     []string{"a"},
+									Renderer: resolve.NewPlainVariableRendererWithValidation(`{"type":["boolean","null"]}`),
+								},
+							),
+							PostProcessing: resolve.PostProcessingConfiguration{
+								MergePath: []string{"__schema"},
+							},
+						},
+					},
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("__schema"),
+							Position: resolve.Position{
+								Line:   3,
+								Column: 4,
+							},
+							Value: &resolve.Object{
+								Path: []string{"__schema"},
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("directives"),
+										Value: &resolve.Array{
+											Path: []string{"directives"},
+											Item: &resolve.Object{
+												Fields: []*resolve.Field{
+													{
+														Name: []byte("args"),
+														Value: &resolve.Array{
+															Path: []string{"args"},
+															Item: &resolve.Object{
+																Fields: []*resolve.Field{
+																	{
+																		Name: []byte("name"),
+																		Value: &resolve.String{
+																			Path: []string{"name"},
+																		},
+																		Position: resolve.Position{
+																			Line:   6,
+																			Column: 7,
+																		},
+																	},
+																},
+															},
+														},
+														Position: resolve.Position{
+															Line:   5,
+															Column: 6,
+														},
+													},
+													{
+														Name: []byte("all"),
+														Value: &resolve.Array{
+															Path: []string{"all"},
+															Item: &resolve.Object{
+																Fields: []*resolve.Field{
+																	{
+																		Name: []byte("name"),
+																		Value: &resolve.String{
+																			Path: []string{"name"},
+																		},
+																		Position: resolve.Position{
+																			Line:   9,
+																			Column: 7,
+																		},
+																	},
+																},
+															},
+														},
+														Position: resolve.Position{
+															Line:   8,
+															Column: 6,
+														},
+													},
+												},
+											},
+										},
+										Position: resolve.Position{
+											Line:   4,
+											Column: 5,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	))
+
 	t.Run("schema introspection request with custom root operation types", runTest(schemaWithCustomRootOperationTypes, schemaIntrospectionForAllRootOperationTypeNames,
 		&plan.SynchronousResponsePlan{
 			Response: &resolve.GraphQLResponse{
@@ -417,6 +540,27 @@ func TestIntrospectionDataSourcePlanning(t *testing.T) {
 												},
 											},
 										},
+										&resolve.SingleFetch{
+											FetchID:              3,
+											DataSourceIdentifier: dataSourceIdentifier,
+											FetchConfiguration: resolve.FetchConfiguration{
+												Input:      `{"request_type":5,"on_type_name":"$$0$$","include_deprecated":$$1$$}`,
+												DataSource: &Source{},
+												Variables: resolve.NewVariables(
+													&resolve.ObjectVariable{
+														Path:     []string{"name"},
+														Renderer: resolve.NewPlainVariableRenderer(),
+													},
+													&resolve.ContextVariable{
+														Path:     []string{"b"},
+														Renderer: resolve.NewPlainVariableRendererWithValidation(`{"type":["boolean","null"]}`),
+													},
+												),
+												PostProcessing: resolve.PostProcessingConfiguration{
+													MergePath: []string{"inputFields"},
+												},
+											},
+										},
 									},
 								},
 								Fields: []*resolve.Field{
@@ -468,6 +612,30 @@ func TestIntrospectionDataSourcePlanning(t *testing.T) {
 											Column: 5,
 										},
 									},
+									{
+										Name: []byte("inputFields"),
+										Value: &resolve.Array{
+											Path:     []string{"inputFields"},
+											Nullable: true,
+											Item: &resolve.Object{
+												Fields: []*resolve.Field{
+													{
+														Name: []byte("name"),
+														Value: &resolve.String{
+															Path: []string{"name"},
+														},
+														Position: resolve.Position{
+															Line:   11,
+															Column: 6,
+														},
+													},
+												},
+											},
+										}, Position: resolve.Position{
+											Line:   10,
+											Column: 5,
+										},
+									},
 								},
 							},
 						},
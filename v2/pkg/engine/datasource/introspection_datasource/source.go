@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"sort"
+	"strconv"
+
+	"github.com/tidwall/sjson"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
 )
@@ -28,26 +32,47 @@ func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error
 	case TypeEnumValuesRequestType:
 		return s.enumValuesForType(w, req.OnTypeName, req.IncludeDeprecated)
 	case TypeFieldsRequestType:
-		return s.fieldsForType(w, req.OnTypeName, req.IncludeDeprecated)
+		return s.fieldsForType(w, req.OnTypeName, req.IncludeDeprecated, req.IncludeDeprecatedArgs)
+	case TypeInputFieldsRequestType:
+		return s.inputFieldsForType(w, req.OnTypeName, req.IncludeDeprecated)
 	}
 
-	return json.NewEncoder(w).Encode(s.schemaWithoutTypeInfo())
+	return s.schemaWithoutTypeInfo(w, req.IncludeDeprecatedArgs)
 }
 
-func (s *Source) schemaWithoutTypeInfo() introspection.Schema {
+func (s *Source) schemaWithoutTypeInfo(w io.Writer, includeDeprecatedArgs map[string]bool) error {
 	types := make([]introspection.FullType, 0, len(s.introspectionData.Schema.Types))
 
 	for i := range s.introspectionData.Schema.Types {
 		types = append(types, s.typeWithoutFieldAndEnumValues(&s.introspectionData.Schema.Types[i]))
 	}
 
-	return introspection.Schema{
+	directives := make([]introspection.Directive, 0, len(s.introspectionData.Schema.Directives))
+	for _, directive := range s.introspectionData.Schema.Directives {
+		directive.Args = s.filterDeprecatedInputValues(directive.Args, includeDeprecatedArgs[argsFieldName])
+		directives = append(directives, directive)
+	}
+
+	data, err := json.Marshal(introspection.Schema{
 		QueryType:        s.introspectionData.Schema.QueryType,
 		MutationType:     s.introspectionData.Schema.MutationType,
 		SubscriptionType: s.introspectionData.Schema.SubscriptionType,
 		Types:            types,
-		Directives:       s.introspectionData.Schema.Directives,
+		Directives:       directives,
+	})
+	if err != nil {
+		return err
+	}
+
+	aliases := argsAliases(includeDeprecatedArgs)
+	for i, directive := range s.introspectionData.Schema.Directives {
+		data, err = s.setAliasedArgs(data, "directives."+strconv.Itoa(i), directive.Args, aliases, includeDeprecatedArgs)
+		if err != nil {
+			return err
+		}
 	}
+
+	return s.writeJSON(w, data)
 }
 
 func (s *Source) typeInfo(typeName *string) *introspection.FullType {
@@ -68,6 +93,12 @@ func (s *Source) writeNull(w io.Writer) error {
 	return err
 }
 
+// writeJSON writes the data with a trailing newline like json.Encoder
+func (s *Source) writeJSON(w io.Writer, data []byte) error {
+	_, err := w.Write(append(data, '\n'))
+	return err
+}
+
 func (s *Source) singleType(w io.Writer, typeName *string) error {
 	typeInfo := s.typeInfo(typeName)
 	if typeInfo == nil {
@@ -85,24 +116,36 @@ func (s *Source) typeWithoutFieldAndEnumValues(typeInfo *introspection.FullType)
 	return typeInfoCopy
 }
 
-func (s *Source) fieldsForType(w io.Writer, typeName *string, includeDeprecated bool) error {
+func (s *Source) fieldsForType(w io.Writer, typeName *string, includeDeprecated bool, includeDeprecatedArgs map[string]bool) error {
 	typeInfo := s.typeInfo(typeName)
 	if typeInfo == nil || len(typeInfo.Fields) == 0 {
 		return s.writeNull(w)
 	}
 
-	if includeDeprecated {
-		return json.NewEncoder(w).Encode(typeInfo.Fields)
-	}
-
 	fields := make([]introspection.Field, 0, len(typeInfo.Fields))
+	args := make([][]introspection.InputValue, 0, len(typeInfo.Fields))
 	for _, field := range typeInfo.Fields {
-		if !field.IsDeprecated {
+		if includeDeprecated || !field.IsDeprecated {
+			args = append(args, field.Args)
+			field.Args = s.filterDeprecatedInputValues(field.Args, includeDeprecatedArgs[argsFieldName])
 			fields = append(fields, field)
 		}
 	}
 
-	return json.NewEncoder(w).Encode(fields)
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	aliases := argsAliases(includeDeprecatedArgs)
+	for i := range args {
+		data, err = s.setAliasedArgs(data, strconv.Itoa(i), args[i], aliases, includeDeprecatedArgs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.writeJSON(w, data)
 }
 
 func (s *Source) enumValuesForType(w io.Writer, typeName *string, includeDeprecated bool) error {
@@ -124,3 +167,51 @@ func (s *Source) enumValuesForType(w io.Writer, typeName *string, includeDepreca
 
 	return json.NewEncoder(w).Encode(enumValues)
 }
+
+func (s *Source) inputFieldsForType(w io.Writer, typeName *string, includeDeprecated bool) error {
+	typeInfo := s.typeInfo(typeName)
+	if typeInfo == nil || len(typeInfo.InputFields) == 0 {
+		return s.writeNull(w)
+	}
+
+	return json.NewEncoder(w).Encode(s.filterDeprecatedInputValues(typeInfo.InputFields, includeDeprecated))
+}
+
+func (s *Source) filterDeprecatedInputValues(inputValues []introspection.InputValue, includeDeprecated bool) []introspection.InputValue {
+	filtered := make([]introspection.InputValue, 0, len(inputValues))
+	for _, inputValue := range inputValues {
+		if includeDeprecated || !inputValue.IsDeprecated {
+			filtered = append(filtered, inputValue)
+		}
+	}
+
+	return filtered
+}
+
+// setAliasedArgs sets the arguments of the aliased args selections next to the args of the object at the path,
+// each selection includes the deprecated arguments according to its own includeDeprecated argument
+func (s *Source) setAliasedArgs(data []byte, path string, args []introspection.InputValue, aliases []string, includeDeprecatedArgs map[string]bool) ([]byte, error) {
+	for _, alias := range aliases {
+		aliasedArgs, err := json.Marshal(s.filterDeprecatedInputValues(args, includeDeprecatedArgs[alias]))
+		if err != nil {
+			return nil, err
+		}
+		data, err = sjson.SetRawBytes(data, path+"."+alias, aliasedArgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// argsAliases returns the sorted response keys of the args selections other than args itself
+func argsAliases(includeDeprecatedArgs map[string]bool) []string {
+	aliases := make([]string, 0, len(includeDeprecatedArgs))
+	for responseKey := range includeDeprecatedArgs {
+		if responseKey != argsFieldName {
+			aliases = append(aliases, responseKey)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
@@ -38,15 +38,19 @@ func TestSource_Load(t *testing.T) {
 	}
 
 	t.Run("schema introspection", run(testSchema, `{"request_type":1}`, `schema_introspection`))
+	t.Run("schema introspection with deprecated args", run(testSchema, `{"request_type":1,"include_deprecated_args":{"args":true}}`, `schema_introspection_with_deprecated_args`))
+	t.Run("schema introspection with aliased args", run(testSchema, `{"request_type":1,"include_deprecated_args":{"args":false,"all":true}}`, `schema_introspection_with_aliased_args`))
 	t.Run("schema introspection with custom root operation types", run(testSchemaWithCustomRootOperationTypes, `{"request_type":1}`, `schema_introspection_with_custom_root_operation_types`))
 	t.Run("type introspection", run(testSchema, `{"request_type":2,"type_name":"Query"}`, `type_introspection`))
 	t.Run("type introspection of not existing type", run(testSchema, `{"request_type":2,"type_name":"NotExisting"}`, `not_existing_type`))
 
 	t.Run("type fields", func(t *testing.T) {
-		t.Run("include deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":true}`, `fields_with_deprecated`))
+		t.Run("include deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":true,"include_deprecated_args":{"args":true}}`, `fields_with_deprecated`))
 
 		t.Run("no deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":false}`, `fields_without_deprecated`))
 
+		t.Run("with aliased args", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":false,"include_deprecated_args":{"all":true,"args":false}}`, `fields_with_aliased_args`))
+
 		t.Run("of not existing type", run(testSchema, `{"request_type":3,"on_type_name":"NotExisting","include_deprecated":true}`, `not_existing_type`))
 	})
 
@@ -57,6 +61,14 @@ func TestSource_Load(t *testing.T) {
 
 		t.Run("of not existing type", run(testSchema, `{"request_type":4,"on_type_name":"NotExisting","include_deprecated":true}`, `not_existing_type`))
 	})
+
+	t.Run("type input fields", func(t *testing.T) {
+		t.Run("include deprecated", run(testSchema, `{"request_type":5,"on_type_name":"DroidFilter","include_deprecated":true}`, `input_fields_with_deprecated`))
+
+		t.Run("no deprecated", run(testSchema, `{"request_type":5,"on_type_name":"DroidFilter","include_deprecated":false}`, `input_fields_without_deprecated`))
+
+		t.Run("of not existing type", run(testSchema, `{"request_type":5,"on_type_name":"NotExisting","include_deprecated":true}`, `not_existing_type`))
+	})
 }
 
 const testSchema = `
@@ -66,7 +78,8 @@ schema {
 
 type Query {
     me: Droid @deprecated
-    droid(id: ID!): Droid
+    droid(id: ID!, serial: String @deprecated): Droid
+    droids(filter: DroidFilter): [Droid!]!
 }
 
 enum Episode {
@@ -78,6 +91,13 @@ enum Episode {
 type Droid {
     name: String!
 }
+
+input DroidFilter {
+    name: String
+    serial: String @deprecated(reason: "Use name")
+}
+
+directive @cached(ttl: Int, maxAge: Int @deprecated(reason: "Use ttl")) on FIELD_DEFINITION
 `
 
 const testSchemaWithCustomRootOperationTypes = `
@@ -4,6 +4,7 @@ import (
 	"github.com/jensneuse/abstractlogger"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 type Configuration struct {
@@ -17,6 +18,9 @@ type Configuration struct {
 	// In production, this should be set to false so that error messages are easier to understand
 	DisableResolveFieldPositions bool
 	CustomResolveMap             map[string]resolve.CustomResolve
+	// ScalarCodecs serializes the values of custom scalars in the response,
+	// String, Boolean, Int, Float and BigInt are rendered by their built-in nodes
+	ScalarCodecs *scalars.Registry
 
 	// Debug - configure debug options
 	Debug DebugConfiguration
@@ -24,6 +28,9 @@ type Configuration struct {
 	// e.g. the origin of a field, possible types, etc.
 	// This information is required to compute the schema usage info from a plan
 	IncludeInfo bool
+	// IncludeSelectionReasons will record the reasons why a datasource was selected for a field,
+	// selected node suggestions with their reasons are available via Planner.NodeSuggestions after planning
+	IncludeSelectionReasons bool
 }
 
 type DebugConfiguration struct {
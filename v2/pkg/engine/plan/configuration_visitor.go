@@ -1060,34 +1060,22 @@ func (c *configurationVisitor) planKeyRequiredFields(currentPlannerIdx int, type
 		return
 	}
 
-	for i := range c.planners {
-		if i == currentPlannerIdx {
-			continue // skip current planner
-		}
-		// we need to filter out the planners which do not have such parent path
-		// because we could have a planner with mathing datasource but on the wrong path
-		if !c.planners[i].HasPath(parentPath) {
-			continue
-		}
-		for _, possibleRequiredFieldConfig := range possibleRequiredFields {
-			if c.planners[i].DataSourceConfiguration().HasKeyRequirement(typeName, possibleRequiredFieldConfig.SelectionSet) {
-
-				isInterfaceObject := false
-				for _, interfaceObjCfg := range c.planners[i].DataSourceConfiguration().FederationConfiguration().InterfaceObjects {
-					if slices.Contains(interfaceObjCfg.ConcreteTypeNames, typeName) {
-						isInterfaceObject = true
-						break
-					}
-				}
-				skipTypename := forInterfaceObject && isInterfaceObject
+	candidate, ok := c.cheapestKeyCandidate(currentPlannerIdx, typeName, parentPath, possibleRequiredFields)
+	if !ok {
+		return FederationFieldConfiguration{}, false
+	}
 
-				c.planAddingRequiredFields(currentPlannerIdx, i, possibleRequiredFieldConfig, skipTypename)
-				return possibleRequiredFieldConfig, true
-			}
+	isInterfaceObject := false
+	for _, interfaceObjCfg := range c.planners[candidate.plannerIdx].DataSourceConfiguration().FederationConfiguration().InterfaceObjects {
+		if slices.Contains(interfaceObjCfg.ConcreteTypeNames, typeName) {
+			isInterfaceObject = true
+			break
 		}
 	}
+	skipTypename := forInterfaceObject && isInterfaceObject
 
-	return FederationFieldConfiguration{}, false
+	c.planAddingRequiredFields(currentPlannerIdx, candidate.plannerIdx, candidate.config, skipTypename)
+	return candidate.config, true
 }
 
 func (c *configurationVisitor) planAddingRequiredFields(currentPlannerIdx int, providedByPlannerIdx int, fieldConfiguration FederationFieldConfiguration, skipTypename bool) {
@@ -1167,13 +1155,14 @@ func (c *configurationVisitor) addRequiredFields(selectionSetRef int, requiredFi
 		operation:             c.operation,
 		definition:            c.definition,
 		report:                report,
+		operationDefinition:   c.walker.Ancestors[0].Ref,
 		operationSelectionSet: selectionSetRef,
 		parentPath:            parentPath,
 	}
 
 	skipFieldRefs, requiredFieldRefs := addRequiredFields(input)
 	if report.HasErrors() {
-		c.walker.StopWithInternalErr(fmt.Errorf("failed to add required fields for %s", typeName))
+		c.walker.StopWithInternalErr(fmt.Errorf("failed to add required fields for %s: %w", typeName, report))
 	}
 
 	c.skipFieldsRefs = append(c.skipFieldsRefs, skipFieldRefs...)
@@ -0,0 +1,132 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// keyCandidate is an entity key of the current datasource
+// which could be provided by one of the planners on the parent path
+type keyCandidate struct {
+	plannerIdx int
+	config     FederationFieldConfiguration
+	cost       keyCost
+}
+
+// keyCost describes how expensive it is to use a key to jump into an entity fetch
+type keyCost struct {
+	// fetchDepth is the number of sequential fetches which have to be executed
+	// before the planner providing the key fields could be executed itself
+	fetchDepth int
+	// missingFields is the number of key fields which are not yet selected in the operation
+	// and have to be added to the upstream query of the planner providing the key
+	missingFields int
+}
+
+func (k keyCost) less(other keyCost) bool {
+	if k.fetchDepth != other.fetchDepth {
+		return k.fetchDepth < other.fetchDepth
+	}
+	return k.missingFields < other.missingFields
+}
+
+// cheapestKeyCandidate selects the key and the planner providing it with the lowest cost.
+// When an entity has multiple keys, the key which could be provided by the planner
+// with the least amount of preceding fetches is preferred.
+// When multiple keys are equally expensive, the key requiring fewer additional fields wins.
+// On equal cost the order of the planners and the order of the keys are preserved.
+func (c *configurationVisitor) cheapestKeyCandidate(currentPlannerIdx int, typeName string, parentPath string, possibleRequiredFields []FederationFieldConfiguration) (candidate keyCandidate, ok bool) {
+	for i := range c.planners {
+		if i == currentPlannerIdx {
+			continue // skip current planner
+		}
+		// we need to filter out the planners which do not have such parent path
+		// because we could have a planner with mathing datasource but on the wrong path
+		if !c.planners[i].HasPath(parentPath) {
+			continue
+		}
+
+		fetchDepth := -1
+
+		for _, possibleRequiredFieldConfig := range possibleRequiredFields {
+			if !c.planners[i].DataSourceConfiguration().HasKeyRequirement(typeName, possibleRequiredFieldConfig.SelectionSet) {
+				continue
+			}
+
+			if fetchDepth == -1 {
+				fetchDepth = c.plannerFetchDepth(i, make(map[int]int))
+			}
+
+			cost := keyCost{
+				fetchDepth:    fetchDepth,
+				missingFields: c.keyMissingFieldsCount(typeName, possibleRequiredFieldConfig.SelectionSet),
+			}
+
+			if !ok || cost.less(candidate.cost) {
+				candidate = keyCandidate{
+					plannerIdx: i,
+					config:     possibleRequiredFieldConfig,
+					cost:       cost,
+				}
+				ok = true
+			}
+		}
+	}
+
+	return candidate, ok
+}
+
+// plannerFetchDepth returns the number of sequential fetches which precede the fetch of the planner.
+// depths memoizes the depth per planner, so a planner reached through multiple dependencies
+// contributes its full depth on each path.
+func (c *configurationVisitor) plannerFetchDepth(plannerIdx int, depths map[int]int) int {
+	if depth, ok := depths[plannerIdx]; ok {
+		return depth
+	}
+	// guards against cyclic dependencies, the planner doesn't add to the depth while it is being resolved
+	depths[plannerIdx] = 0
+
+	dependsOn := c.planners[plannerIdx].ObjectFetchConfiguration().dependsOnFetchIDs
+	if len(dependsOn) == 0 {
+		if len(*c.planners[plannerIdx].RequiredFields()) > 0 {
+			// the planner is an entity fetch, but dependencies are not yet known
+			depths[plannerIdx] = 1
+		}
+		return depths[plannerIdx]
+	}
+
+	depth := 0
+	for _, dependencyIdx := range dependsOn {
+		if dependencyIdx < 0 || dependencyIdx >= len(c.planners) {
+			continue
+		}
+		if dependencyDepth := c.plannerFetchDepth(dependencyIdx, depths) + 1; dependencyDepth > depth {
+			depth = dependencyDepth
+		}
+	}
+
+	depths[plannerIdx] = depth
+	return depth
+}
+
+// keyMissingFieldsCount returns the number of top level key fields which are not selected in the current selection set
+func (c *configurationVisitor) keyMissingFieldsCount(typeName, selectionSet string) int {
+	currentSelectionSet := c.currentSelectionSet()
+	if currentSelectionSet == ast.InvalidRef {
+		return 0
+	}
+
+	key, report := RequiredFieldsFragment(typeName, selectionSet, false)
+	if report.HasErrors() || len(key.FragmentDefinitions) == 0 {
+		return 0
+	}
+
+	missing := 0
+	for _, selectionRef := range key.SelectionSetFieldSelections(key.FragmentDefinitions[0].SelectionSet) {
+		fieldRef := key.Selections[selectionRef].Ref
+		if exists, _ := c.operation.SelectionSetHasFieldSelectionWithExactName(currentSelectionSet, key.FieldNameBytes(fieldRef)); !exists {
+			missing++
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,58 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationVisitor_PlannerFetchDepth(t *testing.T) {
+	planner := func(dependsOnFetchIDs ...int) PlannerConfiguration {
+		return &plannerConfiguration[any]{
+			objectFetchConfiguration: &objectFetchConfiguration{dependsOnFetchIDs: dependsOnFetchIDs},
+		}
+	}
+
+	t.Run("diamond dependency", func(t *testing.T) {
+		// 3 depends on 1 and 2, 2 depends on 1 as well, so 1 is reached on both paths
+		c := &configurationVisitor{
+			planners: []PlannerConfiguration{
+				planner(),
+				planner(0),
+				planner(1),
+				planner(1, 2),
+			},
+		}
+
+		assert.Equal(t, 0, c.plannerFetchDepth(0, make(map[int]int)))
+		assert.Equal(t, 1, c.plannerFetchDepth(1, make(map[int]int)))
+		assert.Equal(t, 2, c.plannerFetchDepth(2, make(map[int]int)))
+		assert.Equal(t, 3, c.plannerFetchDepth(3, make(map[int]int)))
+	})
+
+	t.Run("entity fetch without known dependencies", func(t *testing.T) {
+		c := &configurationVisitor{
+			planners: []PlannerConfiguration{
+				&plannerConfiguration[any]{
+					objectFetchConfiguration: &objectFetchConfiguration{},
+					requiredFields:           FederationFieldConfigurations{{TypeName: "User", SelectionSet: "id"}},
+				},
+				planner(0),
+			},
+		}
+
+		assert.Equal(t, 2, c.plannerFetchDepth(1, make(map[int]int)))
+	})
+
+	t.Run("cyclic dependency", func(t *testing.T) {
+		c := &configurationVisitor{
+			planners: []PlannerConfiguration{
+				planner(1),
+				planner(0),
+			},
+		}
+
+		// the cycle is cut at the planner whose depth is being resolved
+		assert.Equal(t, 2, c.plannerFetchDepth(0, make(map[int]int)))
+	})
+}
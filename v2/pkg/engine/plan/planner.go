@@ -2,6 +2,7 @@ package plan
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/jensneuse/abstractlogger"
@@ -166,8 +167,32 @@ func (p *Planner) Plan(operation, definition *ast.Document, operationName string
 	return p.planningVisitor.plan
 }
 
+// NodeSuggestions returns the selected node suggestions of the last planned operation
+// Fields which were added by the planner and are not part of the response are omitted
+func (p *Planner) NodeSuggestions() []NodeSuggestion {
+	suggestions := p.configurationVisitor.nodeSuggestions
+	if suggestions == nil {
+		return nil
+	}
+
+	out := make([]NodeSuggestion, 0, len(suggestions.items))
+	for _, item := range suggestions.items {
+		if !item.Selected {
+			continue
+		}
+		if slices.Contains(p.configurationVisitor.skipFieldsRefs, item.fieldRef) {
+			continue
+		}
+		out = append(out, *item)
+	}
+	return out
+}
+
 func (p *Planner) findPlanningPaths(operation, definition *ast.Document, report *operationreport.Report) {
 	dsFilter := NewDataSourceFilter(operation, definition, report)
+	if p.config.IncludeSelectionReasons {
+		dsFilter.EnableSelectionReasons()
+	}
 
 	if p.config.Debug.PrintOperationTransformations {
 		p.debugMessage("Initial operation:")
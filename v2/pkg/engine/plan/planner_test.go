@@ -14,6 +14,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 func TestPlanner_Plan(t *testing.T) {
@@ -726,6 +727,53 @@ func TestPlanner_Plan(t *testing.T) {
 				},
 			))
 		})
+
+		t.Run("with scalar codecs", func(t *testing.T) {
+			t.Run("field with json type", test(
+				schema, `
+				{
+					hero {
+						info
+					}
+				}
+			`, "",
+				&SynchronousResponsePlan{
+					FlushInterval: 0,
+					Response: &resolve.GraphQLResponse{
+						Data: &resolve.Object{
+							Fields: []*resolve.Field{
+								{
+									Name: []byte("hero"),
+									Value: &resolve.Object{
+										Path: []string{"hero"},
+										Fields: []*resolve.Field{
+											{
+												Name: []byte("info"),
+												Value: &resolve.Scalar{
+													Path:       []string{"info"},
+													Serializer: scalars.JSONCodec{},
+												},
+											},
+										},
+									},
+								},
+							},
+							Fetch: &resolve.SingleFetch{
+								FetchConfiguration: resolve.FetchConfiguration{
+									DataSource: &FakeDataSource{&StatefulSource{}},
+								},
+								DataSourceIdentifier: []byte("plan.FakeDataSource"),
+							},
+						},
+					},
+				},
+				Configuration{
+					DisableResolveFieldPositions: true,
+					DataSources:                  []DataSource{dsConfig},
+					ScalarCodecs:                 scalars.NewDefaultRegistry(),
+				},
+			))
+		})
 	})
 }
 
@@ -1,8 +1,11 @@
 package plan
 
 import (
+	"bytes"
 	"fmt"
 
+	"github.com/cespare/xxhash/v2"
+
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astimport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
@@ -13,6 +16,7 @@ import (
 const (
 	requiredFieldsFragmentTemplate             = `fragment Key on %s {%s}`
 	requiredFieldsFragmentTemplateWithTypeName = `fragment Key on %s { __typename %s}`
+	requiredFieldAliasTemplate                 = `%s__requires_%016x`
 )
 
 func RequiredFieldsFragment(typeName, requiredFields string, includeTypename bool) (*ast.Document, *operationreport.Report) {
@@ -25,9 +29,27 @@ func RequiredFieldsFragment(typeName, requiredFields string, includeTypename boo
 	return &key, &report
 }
 
+// RequiredFieldAlias returns the alias of a required field with arguments.
+// Such fields are always added to the operation under a deterministic alias,
+// so they could not collide with the same field selected with different arguments.
+// The alias is derived from the field name and the printed arguments.
+func RequiredFieldAlias(key *ast.Document, fieldRef int) (alias string, ok bool) {
+	if !key.FieldHasArguments(fieldRef) {
+		return "", false
+	}
+
+	buf := &bytes.Buffer{}
+	if err := key.PrintArguments(key.FieldArguments(fieldRef), buf); err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf(requiredFieldAliasTemplate, key.FieldNameString(fieldRef), xxhash.Sum64(buf.Bytes())), true
+}
+
 type addRequiredFieldsInput struct {
 	key, operation, definition *ast.Document
 	report                     *operationreport.Report
+	operationDefinition        int
 	operationSelectionSet      int
 	parentPath                 string
 }
@@ -47,6 +69,7 @@ func addRequiredFields(input *addRequiredFieldsInput) (skipFieldRefs []int, requ
 	walker.RegisterEnterDocumentVisitor(visitor)
 	walker.RegisterFieldVisitor(visitor)
 	walker.RegisterSelectionSetVisitor(visitor)
+	walker.RegisterInlineFragmentVisitor(visitor)
 
 	walker.Walk(input.key, input.definition, input.report)
 
@@ -72,18 +95,27 @@ func (v *requiredFieldsVisitor) EnterSelectionSet(_ int) {
 	if v.Walker.Depth == 2 {
 		return
 	}
-	fieldNode := v.OperationNodes[len(v.OperationNodes)-1]
+	parentNode := v.OperationNodes[len(v.OperationNodes)-1]
 
-	if fieldSelectionSetRef, ok := v.input.operation.FieldSelectionSet(fieldNode.Ref); ok {
+	if parentNode.Kind == ast.NodeKindInlineFragment {
+		// inline fragments are always added with a selection set
+		selectionSetRef, _ := v.input.operation.InlineFragmentSelectionSet(parentNode.Ref)
+		v.OperationNodes = append(v.OperationNodes, ast.Node{Kind: ast.NodeKindSelectionSet, Ref: selectionSetRef})
+		return
+	}
+
+	if fieldSelectionSetRef, ok := v.input.operation.FieldSelectionSet(parentNode.Ref); ok {
 		selectionSetNode := ast.Node{Kind: ast.NodeKindSelectionSet, Ref: fieldSelectionSetRef}
 		v.OperationNodes = append(v.OperationNodes, selectionSetNode)
+		v.addTypeNameToAbstractSelectionSet(fieldSelectionSetRef)
 		return
 	}
 
 	selectionSetNode := v.input.operation.AddSelectionSet()
-	v.input.operation.Fields[fieldNode.Ref].HasSelections = true
-	v.input.operation.Fields[fieldNode.Ref].SelectionSet = selectionSetNode.Ref
+	v.input.operation.Fields[parentNode.Ref].HasSelections = true
+	v.input.operation.Fields[parentNode.Ref].SelectionSet = selectionSetNode.Ref
 	v.OperationNodes = append(v.OperationNodes, selectionSetNode)
+	v.addTypeNameToAbstractSelectionSet(selectionSetNode.Ref)
 }
 
 func (v *requiredFieldsVisitor) LeaveSelectionSet(ref int) {
@@ -94,12 +126,88 @@ func (v *requiredFieldsVisitor) LeaveSelectionSet(ref int) {
 	v.OperationNodes = v.OperationNodes[:len(v.OperationNodes)-1]
 }
 
+// addTypeNameToAbstractSelectionSet - adds __typename to the selection set of a field with an interface or union type
+// the type name is required to render the representation of the abstract value
+func (v *requiredFieldsVisitor) addTypeNameToAbstractSelectionSet(selectionSetRef int) {
+	if !v.Walker.EnclosingTypeDefinition.Kind.IsAbstractType() {
+		return
+	}
+
+	operationHasField, operationFieldRef := v.input.operation.SelectionSetHasFieldSelectionWithExactName(selectionSetRef, []byte(typeNameField))
+	if operationHasField {
+		v.requiredFieldRefs = append(v.requiredFieldRefs, operationFieldRef)
+		return
+	}
+
+	v.addField(ast.Field{
+		Name:         v.input.operation.Input.AppendInputString(typeNameField),
+		SelectionSet: ast.InvalidRef,
+	}, selectionSetRef)
+}
+
+func (v *requiredFieldsVisitor) EnterInlineFragment(ref int) {
+	if !v.input.key.InlineFragmentHasTypeCondition(ref) {
+		v.Walker.StopWithInternalErr(fmt.Errorf("inline fragment in required fields of %s must have a type condition", v.input.parentPath))
+		return
+	}
+	typeCondition := v.input.key.InlineFragmentTypeConditionName(ref)
+
+	selectionSetRef := v.OperationNodes[len(v.OperationNodes)-1].Ref
+
+	for _, selectionRef := range v.input.operation.SelectionSetInlineFragmentSelections(selectionSetRef) {
+		inlineFragmentRef := v.input.operation.Selections[selectionRef].Ref
+		if v.input.operation.InlineFragmentHasDirectives(inlineFragmentRef) || !v.input.operation.InlineFragments[inlineFragmentRef].HasSelections {
+			continue
+		}
+
+		// reuse an inline fragment on the same type which is already present in the operation
+		if bytes.Equal(v.input.operation.InlineFragmentTypeConditionName(inlineFragmentRef), typeCondition) {
+			v.OperationNodes = append(v.OperationNodes, ast.Node{Kind: ast.NodeKindInlineFragment, Ref: inlineFragmentRef})
+			return
+		}
+	}
+
+	fragmentSelectionSet := v.input.operation.AddSelectionSet()
+	inlineFragmentRef := v.input.operation.AddInlineFragment(ast.InlineFragment{
+		TypeCondition: ast.TypeCondition{
+			Type: v.input.operation.AddNamedType(typeCondition),
+		},
+		SelectionSet:  fragmentSelectionSet.Ref,
+		HasSelections: true,
+	})
+	v.input.operation.AddSelection(selectionSetRef, ast.Selection{
+		Kind: ast.SelectionKindInlineFragment,
+		Ref:  inlineFragmentRef,
+	})
+
+	v.OperationNodes = append(v.OperationNodes, ast.Node{Kind: ast.NodeKindInlineFragment, Ref: inlineFragmentRef})
+}
+
+func (v *requiredFieldsVisitor) LeaveInlineFragment(_ int) {
+	v.OperationNodes = v.OperationNodes[:len(v.OperationNodes)-1]
+}
+
 func (v *requiredFieldsVisitor) EnterField(ref int) {
 	fieldName := v.input.key.FieldNameBytes(ref)
 
 	selectionSetRef := v.OperationNodes[len(v.OperationNodes)-1].Ref
 
-	operationHasField, operationFieldRef := v.input.operation.SelectionSetHasFieldSelectionWithExactName(selectionSetRef, fieldName)
+	var (
+		operationHasField bool
+		operationFieldRef int
+	)
+
+	alias, hasAlias := RequiredFieldAlias(v.input.key, ref)
+	if hasAlias {
+		if !v.argumentVariablesAreDefined(ref) {
+			return
+		}
+		// required field with arguments could be reused only when it was added with the same arguments
+		operationHasField, operationFieldRef = v.selectionSetHasFieldWithAlias(selectionSetRef, alias)
+	} else {
+		operationHasField, operationFieldRef = v.input.operation.SelectionSetHasFieldSelectionWithExactName(selectionSetRef, fieldName)
+	}
+
 	if operationHasField {
 		v.requiredFieldRefs = append(v.requiredFieldRefs, operationFieldRef)
 
@@ -113,7 +221,7 @@ func (v *requiredFieldsVisitor) EnterField(ref int) {
 		return
 	}
 
-	fieldNode := v.addRequiredField(ref, fieldName, selectionSetRef)
+	fieldNode := v.addRequiredField(ref, fieldName, alias, selectionSetRef)
 	if v.input.key.FieldHasSelections(ref) {
 		v.OperationNodes = append(v.OperationNodes, fieldNode)
 	}
@@ -125,12 +233,67 @@ func (v *requiredFieldsVisitor) LeaveField(ref int) {
 	}
 }
 
-func (v *requiredFieldsVisitor) addRequiredField(keyRef int, fieldName ast.ByteSlice, selectionSet int) ast.Node {
+func (v *requiredFieldsVisitor) selectionSetHasFieldWithAlias(selectionSetRef int, alias string) (exists bool, fieldRef int) {
+	for _, selectionRef := range v.input.operation.SelectionSets[selectionSetRef].SelectionRefs {
+		if v.input.operation.Selections[selectionRef].Kind != ast.SelectionKindField {
+			continue
+		}
+		fieldRef = v.input.operation.Selections[selectionRef].Ref
+		if v.input.operation.FieldAliasIsDefined(fieldRef) && v.input.operation.FieldAliasString(fieldRef) == alias {
+			return true, fieldRef
+		}
+	}
+	return false, ast.InvalidRef
+}
+
+// argumentVariablesAreDefined - checks that variables used in arguments of the required field
+// are defined on the operation, as their values will be passed from the operation
+func (v *requiredFieldsVisitor) argumentVariablesAreDefined(fieldRef int) bool {
+	for _, argRef := range v.input.key.FieldArguments(fieldRef) {
+		if !v.valueVariablesAreDefined(v.input.key.ArgumentValue(argRef)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *requiredFieldsVisitor) valueVariablesAreDefined(value ast.Value) bool {
+	switch value.Kind {
+	case ast.ValueKindVariable:
+		variableName := v.input.key.VariableValueNameBytes(value.Ref)
+		if _, exists := v.input.operation.VariableDefinitionByNameAndOperation(v.input.operationDefinition, variableName); !exists {
+			operationName := v.input.operation.OperationDefinitionNameBytes(v.input.operationDefinition)
+			v.Walker.StopWithExternalErr(operationreport.ErrVariableNotDefinedOnOperation(variableName, operationName))
+			return false
+		}
+	case ast.ValueKindList:
+		for _, ref := range v.input.key.ListValues[value.Ref].Refs {
+			if !v.valueVariablesAreDefined(v.input.key.Values[ref]) {
+				return false
+			}
+		}
+	case ast.ValueKindObject:
+		for _, ref := range v.input.key.ObjectValues[value.Ref].Refs {
+			if !v.valueVariablesAreDefined(v.input.key.ObjectFields[ref].Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (v *requiredFieldsVisitor) addRequiredField(keyRef int, fieldName ast.ByteSlice, alias string, selectionSet int) ast.Node {
 	field := ast.Field{
 		Name:         v.input.operation.Input.AppendInputBytes(fieldName),
 		SelectionSet: ast.InvalidRef,
 	}
-	addedField := v.input.operation.AddField(field)
+	if alias != "" {
+		field.Alias = ast.Alias{
+			IsDefined: true,
+			Name:      v.input.operation.Input.AppendInputString(alias),
+		}
+	}
+	addedField := v.addField(field, selectionSet)
 
 	if v.input.key.FieldHasArguments(keyRef) {
 		importedArgs := v.importer.ImportArguments(v.input.key.Fields[keyRef].Arguments.Refs, v.input.key, v.input.operation)
@@ -140,6 +303,12 @@ func (v *requiredFieldsVisitor) addRequiredField(keyRef int, fieldName ast.ByteS
 		}
 	}
 
+	return addedField
+}
+
+func (v *requiredFieldsVisitor) addField(field ast.Field, selectionSet int) ast.Node {
+	addedField := v.input.operation.AddField(field)
+
 	selection := ast.Selection{
 		Kind: ast.SelectionKindField,
 		Ref:  addedField.Ref,
@@ -0,0 +1,225 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeprinter"
+)
+
+func TestAddRequiredFields(t *testing.T) {
+	definitionSDL := `
+		type Query {
+			user: User
+		}
+
+		type User {
+			id: ID!
+			price(currency: String!): Int!
+			reviews(first: Int): [Review!]!
+			media: [Media!]!
+		}
+
+		type Review {
+			body: String!
+			rating(scale: Int): Int!
+		}
+
+		interface Media {
+			id: ID!
+		}
+
+		type Book implements Media {
+			id: ID!
+			title: String!
+		}
+
+		type Movie implements Media {
+			id: ID!
+			duration: Int!
+		}
+	`
+
+	type testCase struct {
+		requiredFields     string
+		operation          string
+		expectedOperation  string
+		expectedSkipFields int
+	}
+
+	run := func(t *testing.T, testCase testCase) {
+		t.Helper()
+
+		definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(definitionSDL)
+		operation := unsafeparser.ParseGraphqlDocumentString(testCase.operation)
+
+		key, report := RequiredFieldsFragment("User", testCase.requiredFields, false)
+		require.False(t, report.HasErrors())
+
+		userSelectionSet := ast.InvalidRef
+		for ref := range operation.Fields {
+			if operation.FieldNameString(ref) == "user" {
+				userSelectionSet = operation.Fields[ref].SelectionSet
+				break
+			}
+		}
+
+		skipFieldRefs, requiredFieldRefs := addRequiredFields(&addRequiredFieldsInput{
+			key:                   key,
+			operation:             &operation,
+			definition:            &definition,
+			report:                report,
+			operationDefinition:   0,
+			operationSelectionSet: userSelectionSet,
+			parentPath:            "query.user",
+		})
+		require.False(t, report.HasErrors(), report.Error())
+
+		assert.Len(t, skipFieldRefs, testCase.expectedSkipFields)
+		assert.GreaterOrEqual(t, len(requiredFieldRefs), len(skipFieldRefs))
+		assert.Equal(t, unsafeprinter.Prettify(testCase.expectedOperation), unsafeprinter.PrettyPrint(&operation, &definition))
+	}
+
+	t.Run("field with literal argument is aliased", func(t *testing.T) {
+		run(t, testCase{
+			requiredFields: `price(currency: "USD")`,
+			operation: `
+				query {
+					user {
+						price(currency: "EUR")
+					}
+				}`,
+			expectedOperation: `
+				query {
+					user {
+						price(currency: "EUR")
+						price__requires_022ec0ce9fed8c19: price(currency: "USD")
+					}
+				}`,
+			expectedSkipFields: 1,
+		})
+	})
+
+	t.Run("field with variable argument", func(t *testing.T) {
+		run(t, testCase{
+			requiredFields: `price(currency: $currency)`,
+			operation: `
+				query($currency: String!) {
+					user {
+						price(currency: $currency)
+					}
+				}`,
+			expectedOperation: `
+				query($currency: String!) {
+					user {
+						price(currency: $currency)
+						price__requires_0e281509e02f732e: price(currency: $currency)
+					}
+				}`,
+			expectedSkipFields: 1,
+		})
+	})
+
+	t.Run("aliased field is reused for the same arguments", func(t *testing.T) {
+		run(t, testCase{
+			requiredFields: `price(currency: "USD") id`,
+			operation: `
+				query {
+					user {
+						price__requires_022ec0ce9fed8c19: price(currency: "USD")
+					}
+				}`,
+			expectedOperation: `
+				query {
+					user {
+						price__requires_022ec0ce9fed8c19: price(currency: "USD")
+						id
+					}
+				}`,
+			expectedSkipFields: 1,
+		})
+	})
+
+	t.Run("nested lists of objects with arguments", func(t *testing.T) {
+		run(t, testCase{
+			requiredFields: `reviews(first: 2) { body rating(scale: 10) }`,
+			operation: `
+				query {
+					user {
+						reviews(first: 2) {
+							body
+						}
+					}
+				}`,
+			expectedOperation: `
+				query {
+					user {
+						reviews(first: 2) {
+							body
+						}
+						reviews__requires_aad102b1a6dad46c: reviews(first: 2) {
+							body
+							rating__requires_a98fd776b30e21a9: rating(scale: 10)
+						}
+					}
+				}`,
+			expectedSkipFields: 3,
+		})
+	})
+
+	t.Run("abstract type with inline fragments", func(t *testing.T) {
+		run(t, testCase{
+			requiredFields: `media { ... on Book { title } ... on Movie { duration } }`,
+			operation: `
+				query {
+					user {
+						media {
+							... on Book {
+								id
+							}
+						}
+					}
+				}`,
+			expectedOperation: `
+				query {
+					user {
+						media {
+							... on Book {
+								id
+								title
+							}
+							__typename
+							... on Movie {
+								duration
+							}
+						}
+					}
+				}`,
+			expectedSkipFields: 3,
+		})
+	})
+
+	t.Run("undefined variable", func(t *testing.T) {
+		definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(definitionSDL)
+		operation := unsafeparser.ParseGraphqlDocumentString(`query Q { user { id } }`)
+
+		key, report := RequiredFieldsFragment("User", `price(currency: $currency)`, false)
+		require.False(t, report.HasErrors())
+
+		addRequiredFields(&addRequiredFieldsInput{
+			key:                   key,
+			operation:             &operation,
+			definition:            &definition,
+			report:                report,
+			operationDefinition:   0,
+			operationSelectionSet: operation.Fields[0].SelectionSet,
+			parentPath:            "query.user",
+		})
+		require.True(t, report.HasErrors())
+		assert.Equal(t, "external: variable: currency not defined on operation: Q, locations: [], path: [User]", report.Error())
+	})
+}
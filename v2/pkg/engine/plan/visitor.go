@@ -669,11 +669,15 @@ func (v *Visitor) resolveFieldValue(fieldRef, typeRef int, nullable bool, path [
 				}
 				fallthrough
 			default:
-				return &resolve.Scalar{
+				scalar := &resolve.Scalar{
 					Path:     path,
 					Nullable: nullable,
 					Export:   fieldExport,
 				}
+				if codec, ok := v.Config.ScalarCodecs.Codec(v.Definition, typeDefinitionNode.Ref); ok {
+					scalar.Serializer = codec
+				}
+				return scalar
 			}
 		case ast.NodeKindEnumTypeDefinition:
 			return &resolve.String{
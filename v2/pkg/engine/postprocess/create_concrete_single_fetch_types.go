@@ -112,8 +112,9 @@ func (d *CreateConcreteSingleFetchTypes) createEntityBatchFetch(fetch *resolve.S
 				SetTemplateOutputToNullOnVariableNull: fetch.InputTemplate.SetTemplateOutputToNullOnVariableNull,
 			},
 		},
-		DataSource:     fetch.DataSource,
-		PostProcessing: fetch.PostProcessing,
+		DataSource:      fetch.DataSource,
+		PostProcessing:  fetch.PostProcessing,
+		MergedItemPaths: fetch.MergedItemPaths,
 	}
 }
 
@@ -0,0 +1,310 @@
+package postprocess
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const (
+	// singleEntityDataPathSuffix is the last element of the response data path of an entity fetch on an object,
+	// which selects the first item of the _entities response
+	singleEntityDataPathSuffix = "[0]"
+)
+
+// MergeEntityFetches is a postprocessor that merges entity fetches to the same subgraph at different response paths
+// e.g. `product.reviews.author` and `me.author` into a single batch entity fetch.
+//
+// Fetches are merged when they have the same input and post-processing, are on the same dependency level
+// and all dependencies of the merged fetch are loaded before the fetch it is merged into.
+// The fetch which is loaded first becomes the batch fetch, the other fetches are removed from the plan
+// and their paths are added to the MergedItemPaths of the batch fetch.
+//
+// It has to run after CreateMultiFetchTypes and before CreateConcreteSingleFetchTypes
+type MergeEntityFetches struct{}
+
+func (m *MergeEntityFetches) Process(pre plan.Plan) plan.Plan {
+	switch t := pre.(type) {
+	case *plan.SynchronousResponsePlan:
+		m.mergeFetches(t.Response.Data)
+	case *plan.SubscriptionResponsePlan:
+		m.mergeFetches(t.Response.Response.Data)
+	}
+	return pre
+}
+
+func (m *MergeEntityFetches) mergeFetches(data *resolve.Object) {
+	if data == nil {
+		return
+	}
+
+	collector := &entityFetchCollector{
+		fetches: make(map[int]*resolve.SingleFetch),
+		steps:   make(map[int]int),
+	}
+	collector.collectNode(data)
+	if collector.hasDuplicatedFetchIDs || len(collector.candidates) < 2 {
+		return
+	}
+
+	merged := collector.merge()
+	if len(merged) == 0 {
+		return
+	}
+
+	m.removeMergedFetches(data, merged)
+}
+
+func (m *MergeEntityFetches) removeMergedFetches(node resolve.Node, merged map[*resolve.SingleFetch]struct{}) {
+	switch n := node.(type) {
+	case *resolve.Object:
+		n.Fetch = m.removeMergedFetch(n.Fetch, merged)
+		for i := range n.Fields {
+			m.removeMergedFetches(n.Fields[i].Value, merged)
+		}
+	case *resolve.Array:
+		m.removeMergedFetches(n.Item, merged)
+	}
+}
+
+func (m *MergeEntityFetches) removeMergedFetch(fetch resolve.Fetch, merged map[*resolve.SingleFetch]struct{}) resolve.Fetch {
+	switch f := fetch.(type) {
+	case *resolve.SingleFetch:
+		if _, ok := merged[f]; ok {
+			return nil
+		}
+	case *resolve.ParallelFetch:
+		f.Fetches = m.removeMergedFetchesFromList(f.Fetches, merged)
+		switch len(f.Fetches) {
+		case 0:
+			return nil
+		case 1:
+			return f.Fetches[0]
+		}
+	case *resolve.SerialFetch:
+		f.Fetches = m.removeMergedFetchesFromList(f.Fetches, merged)
+		switch len(f.Fetches) {
+		case 0:
+			return nil
+		case 1:
+			return f.Fetches[0]
+		}
+	}
+	return fetch
+}
+
+func (m *MergeEntityFetches) removeMergedFetchesFromList(fetches []resolve.Fetch, merged map[*resolve.SingleFetch]struct{}) []resolve.Fetch {
+	out := fetches[:0]
+	for i := range fetches {
+		if fetch := m.removeMergedFetch(fetches[i], merged); fetch != nil {
+			out = append(out, fetch)
+		}
+	}
+	return out
+}
+
+type entityFetchCandidate struct {
+	fetch *resolve.SingleFetch
+	path  []string
+	step  int
+}
+
+// entityFetchCollector walks the response in the same order as the resolve.Loader
+// and records the step at which each fetch will be loaded
+type entityFetchCollector struct {
+	fetches    map[int]*resolve.SingleFetch
+	steps      map[int]int
+	levels     map[int]int
+	candidates []*entityFetchCandidate
+
+	path []string
+	step int
+	// nestedListDepth is greater than zero when we are inside of a list of lists,
+	// items of nested lists could not be addressed by a path
+	nestedListDepth       int
+	hasDuplicatedFetchIDs bool
+}
+
+func (c *entityFetchCollector) collectNode(node resolve.Node) {
+	switch n := node.(type) {
+	case *resolve.Object:
+		c.path = append(c.path, n.Path...)
+		c.collectFetch(n.Fetch)
+		for i := range n.Fields {
+			c.collectNode(n.Fields[i].Value)
+		}
+		c.path = c.path[:len(c.path)-len(n.Path)]
+	case *resolve.Array:
+		isNestedList := len(n.Path) == 0
+		if isNestedList {
+			c.nestedListDepth++
+		}
+		c.path = append(c.path, n.Path...)
+		c.collectNode(n.Item)
+		c.path = c.path[:len(c.path)-len(n.Path)]
+		if isNestedList {
+			c.nestedListDepth--
+		}
+	}
+}
+
+func (c *entityFetchCollector) collectFetch(fetch resolve.Fetch) {
+	switch f := fetch.(type) {
+	case *resolve.SingleFetch:
+		c.addFetch(f)
+		c.step++
+	case *resolve.SerialFetch:
+		for i := range f.Fetches {
+			c.collectFetch(f.Fetches[i])
+		}
+	case *resolve.ParallelFetch:
+		// parallel fetches are loaded at the same step
+		for i := range f.Fetches {
+			if single, ok := f.Fetches[i].(*resolve.SingleFetch); ok {
+				c.addFetch(single)
+			}
+		}
+		c.step++
+	}
+}
+
+func (c *entityFetchCollector) addFetch(fetch *resolve.SingleFetch) {
+	if _, exists := c.fetches[fetch.FetchID]; exists {
+		c.hasDuplicatedFetchIDs = true
+		return
+	}
+	c.fetches[fetch.FetchID] = fetch
+	c.steps[fetch.FetchID] = c.step
+
+	if c.nestedListDepth > 0 || !isMergeableEntityFetch(fetch) {
+		return
+	}
+
+	c.candidates = append(c.candidates, &entityFetchCandidate{
+		fetch: fetch,
+		path:  slices.Clone(c.path),
+		step:  c.step,
+	})
+}
+
+// merge merges compatible candidates into the candidate which is loaded first
+// and returns the set of fetches which were merged into other fetches
+func (c *entityFetchCollector) merge() map[*resolve.SingleFetch]struct{} {
+	c.levels = make(map[int]int, len(c.fetches))
+	merged := make(map[*resolve.SingleFetch]struct{})
+
+	for i, batch := range c.candidates {
+		if _, ok := merged[batch.fetch]; ok {
+			continue
+		}
+
+		for _, candidate := range c.candidates[i+1:] {
+			if _, ok := merged[candidate.fetch]; ok {
+				continue
+			}
+			if !c.canMerge(batch, candidate) {
+				continue
+			}
+
+			convertToBatchEntityFetch(batch.fetch)
+			batch.fetch.MergedItemPaths = append(batch.fetch.MergedItemPaths, candidate.path)
+			merged[candidate.fetch] = struct{}{}
+			// merged fetch will be loaded together with the batch fetch
+			c.steps[candidate.fetch.FetchID] = batch.step
+		}
+	}
+
+	return merged
+}
+
+func (c *entityFetchCollector) canMerge(batch, candidate *entityFetchCandidate) bool {
+	if candidate.step <= batch.step {
+		return false
+	}
+	if !entityFetchesAreCompatible(batch.fetch, candidate.fetch) {
+		return false
+	}
+	if c.dependencyLevel(batch.fetch.FetchID, nil) != c.dependencyLevel(candidate.fetch.FetchID, nil) {
+		return false
+	}
+
+	// all data required to render the representations of the candidate should be loaded before the batch fetch
+	for _, dependencyID := range candidate.fetch.DependsOnFetchIDs {
+		step, ok := c.steps[dependencyID]
+		if !ok || step >= batch.step {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencyLevel returns the length of the longest chain of dependencies of the fetch
+func (c *entityFetchCollector) dependencyLevel(fetchID int, visited []int) int {
+	if level, ok := c.levels[fetchID]; ok {
+		return level
+	}
+	fetch, ok := c.fetches[fetchID]
+	if !ok || slices.Contains(visited, fetchID) {
+		return 0
+	}
+	visited = append(visited, fetchID)
+
+	level := 0
+	for _, dependencyID := range fetch.DependsOnFetchIDs {
+		level = max(level, c.dependencyLevel(dependencyID, visited)+1)
+	}
+	c.levels[fetchID] = level
+	return level
+}
+
+func isMergeableEntityFetch(fetch *resolve.SingleFetch) bool {
+	if fetch.RequiresParallelListItemFetch {
+		return false
+	}
+	if fetch.RequiresEntityBatchFetch {
+		return true
+	}
+	if !fetch.RequiresEntityFetch {
+		return false
+	}
+	dataPath := fetch.PostProcessing.SelectResponseDataPath
+	return len(dataPath) > 0 && dataPath[len(dataPath)-1] == singleEntityDataPathSuffix
+}
+
+func entityFetchesAreCompatible(a, b *resolve.SingleFetch) bool {
+	if a.Input != b.Input ||
+		!bytes.Equal(a.DataSourceIdentifier, b.DataSourceIdentifier) ||
+		a.SetTemplateOutputToNullOnVariableNull != b.SetTemplateOutputToNullOnVariableNull {
+		return false
+	}
+	if (a.Info == nil) != (b.Info == nil) || (a.Info != nil && a.Info.DataSourceID != b.Info.DataSourceID) {
+		return false
+	}
+	if !reflect.DeepEqual(batchPostProcessing(a), batchPostProcessing(b)) {
+		return false
+	}
+	return reflect.DeepEqual(a.InputTemplate, b.InputTemplate)
+}
+
+// batchPostProcessing returns the post-processing of the fetch as it would be for a batch entity fetch
+func batchPostProcessing(fetch *resolve.SingleFetch) resolve.PostProcessingConfiguration {
+	postProcessing := fetch.PostProcessing
+	if fetch.RequiresEntityFetch {
+		postProcessing.SelectResponseDataPath = postProcessing.SelectResponseDataPath[:len(postProcessing.SelectResponseDataPath)-1]
+	}
+	return postProcessing
+}
+
+// convertToBatchEntityFetch converts an entity fetch on an object into a batch entity fetch,
+// which is required to load items from multiple paths
+func convertToBatchEntityFetch(fetch *resolve.SingleFetch) {
+	if !fetch.RequiresEntityFetch {
+		return
+	}
+	fetch.PostProcessing = batchPostProcessing(fetch)
+	fetch.RequiresEntityFetch = false
+	fetch.RequiresEntityBatchFetch = true
+}
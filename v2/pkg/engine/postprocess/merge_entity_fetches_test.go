@@ -0,0 +1,210 @@
+package postprocess
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestMergeEntityFetches_Process(t *testing.T) {
+	batchEntityFetch := func(fetchID int, input string, dependsOn ...int) *resolve.SingleFetch {
+		return &resolve.SingleFetch{
+			FetchID:           fetchID,
+			DependsOnFetchIDs: dependsOn,
+			FetchConfiguration: resolve.FetchConfiguration{
+				Input:                    input,
+				RequiresEntityBatchFetch: true,
+				PostProcessing: resolve.PostProcessingConfiguration{
+					SelectResponseDataPath: []string{"data", "_entities"},
+				},
+			},
+		}
+	}
+	entityFetch := func(fetchID int, input string, dependsOn ...int) *resolve.SingleFetch {
+		return &resolve.SingleFetch{
+			FetchID:           fetchID,
+			DependsOnFetchIDs: dependsOn,
+			FetchConfiguration: resolve.FetchConfiguration{
+				Input:               input,
+				RequiresEntityFetch: true,
+				PostProcessing: resolve.PostProcessingConfiguration{
+					SelectResponseDataPath: []string{"data", "_entities", "[0]"},
+				},
+			},
+		}
+	}
+	withMergedItemPaths := func(fetch *resolve.SingleFetch, paths ...[]string) *resolve.SingleFetch {
+		fetch.MergedItemPaths = paths
+		return fetch
+	}
+	list := func(path string, fetch resolve.Fetch) *resolve.Field {
+		return &resolve.Field{
+			Name: []byte(path),
+			Value: &resolve.Array{
+				Path: []string{path},
+				Item: &resolve.Object{
+					Fetch: fetch,
+				},
+			},
+		}
+	}
+	object := func(path string, fetch resolve.Fetch, fields ...*resolve.Field) *resolve.Field {
+		return &resolve.Field{
+			Name: []byte(path),
+			Value: &resolve.Object{
+				Path:   []string{path},
+				Fetch:  fetch,
+				Fields: fields,
+			},
+		}
+	}
+	response := func(fetch resolve.Fetch, fields ...*resolve.Field) plan.Plan {
+		return &plan.SynchronousResponsePlan{
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fetch:  fetch,
+					Fields: fields,
+				},
+			},
+		}
+	}
+
+	type TestCase struct {
+		name     string
+		pre      plan.Plan
+		expected plan.Plan
+	}
+
+	cases := []TestCase{
+		{
+			name: "batch entity fetches of sibling root fields",
+			pre: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", batchEntityFetch(1, "reviews", 0)),
+				list("all", batchEntityFetch(2, "reviews", 0)),
+			),
+			expected: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", withMergedItemPaths(batchEntityFetch(1, "reviews", 0), []string{"all"})),
+				list("all", nil),
+			),
+		},
+		{
+			name: "entity fetch on an object is converted into a batch entity fetch",
+			pre: response(
+				&resolve.SingleFetch{FetchID: 0},
+				object("me", entityFetch(1, "reviews", 0)),
+				list("users", batchEntityFetch(2, "reviews", 0)),
+			),
+			expected: response(
+				&resolve.SingleFetch{FetchID: 0},
+				object("me", withMergedItemPaths(batchEntityFetch(1, "reviews", 0), []string{"users"})),
+				list("users", nil),
+			),
+		},
+		{
+			name: "merged fetch is removed from a parallel fetch",
+			pre: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", batchEntityFetch(1, "reviews", 0)),
+				list("all", &resolve.ParallelFetch{
+					Fetches: []resolve.Fetch{
+						batchEntityFetch(2, "reviews", 0),
+						batchEntityFetch(3, "inventory", 0),
+					},
+				}),
+			),
+			expected: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", withMergedItemPaths(batchEntityFetch(1, "reviews", 0), []string{"all"})),
+				list("all", batchEntityFetch(3, "inventory", 0)),
+			),
+		},
+		{
+			name: "fetches with different input are not merged",
+			pre: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", batchEntityFetch(1, "reviews", 0)),
+				list("all", batchEntityFetch(2, "inventory", 0)),
+			),
+			expected: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", batchEntityFetch(1, "reviews", 0)),
+				list("all", batchEntityFetch(2, "inventory", 0)),
+			),
+		},
+		{
+			name: "fetch with a dependency loaded after the batch fetch is not merged",
+			pre: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("products", batchEntityFetch(1, "reviews", 0)),
+				object("me", entityFetch(2, "accounts", 0),
+					list("products", batchEntityFetch(3, "reviews", 2)),
+				),
+			),
+			expected: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("products", batchEntityFetch(1, "reviews", 0)),
+				object("me", entityFetch(2, "accounts", 0),
+					list("products", batchEntityFetch(3, "reviews", 2)),
+				),
+			),
+		},
+		{
+			name: "fetches inside of nested lists are not merged",
+			pre: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", batchEntityFetch(1, "reviews", 0)),
+				&resolve.Field{
+					Name: []byte("matrix"),
+					Value: &resolve.Array{
+						Path: []string{"matrix"},
+						Item: &resolve.Array{
+							Item: &resolve.Object{
+								Fetch: batchEntityFetch(2, "reviews", 0),
+							},
+						},
+					},
+				},
+			),
+			expected: response(
+				&resolve.SingleFetch{FetchID: 0},
+				list("first", batchEntityFetch(1, "reviews", 0)),
+				&resolve.Field{
+					Name: []byte("matrix"),
+					Value: &resolve.Array{
+						Path: []string{"matrix"},
+						Item: &resolve.Array{
+							Item: &resolve.Object{
+								Fetch: batchEntityFetch(2, "reviews", 0),
+							},
+						},
+					},
+				},
+			),
+		},
+	}
+
+	processor := &MergeEntityFetches{}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := processor.Process(c.pre)
+
+			if !assert.Equal(t, c.expected, actual) {
+				actualBytes, _ := json.MarshalIndent(actual, "", "  ")
+				expectedBytes, _ := json.MarshalIndent(c.expected, "", "  ")
+
+				if string(expectedBytes) != string(actualBytes) {
+					assert.Equal(t, string(expectedBytes), string(actualBytes))
+					t.Error(cmp.Diff(string(expectedBytes), string(actualBytes)))
+				}
+			}
+		})
+	}
+}
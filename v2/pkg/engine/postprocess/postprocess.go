@@ -17,6 +17,7 @@ func DefaultProcessor() *Processor {
 		[]PostProcessor{
 			&ResolveInputTemplates{},
 			&CreateMultiFetchTypes{},
+			&MergeEntityFetches{},
 			&CreateConcreteSingleFetchTypes{},
 		},
 	}
@@ -9,12 +9,16 @@ import (
 	"time"
 
 	"go.uber.org/atomic"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 )
 
 type Context struct {
-	ctx              context.Context
-	Variables        []byte
-	Request          Request
+	ctx       context.Context
+	Variables []byte
+	Request   Request
+	// Files are the uploaded files of the request, they are forwarded to data sources implementing FileUploadDataSource
+	Files            []httpclient.File
 	RenameTypeNames  []RenameTypeName
 	TracingOptions   TraceOptions
 	RateLimitOptions RateLimitOptions
@@ -23,8 +27,9 @@ type Context struct {
 	Stats            Stats
 	LoaderHooks      LoaderHooks
 
-	authorizer  Authorizer
-	rateLimiter RateLimiter
+	authorizer        Authorizer
+	rateLimiter       RateLimiter
+	fetchDeduplicator *FetchDeduplicator
 
 	subgraphErrors error
 }
@@ -56,6 +61,11 @@ func (c *Context) SetAuthorizer(authorizer Authorizer) {
 	c.authorizer = authorizer
 }
 
+// SetFetchDeduplicator shares the responses of identical fetches with all contexts using the same FetchDeduplicator
+func (c *Context) SetFetchDeduplicator(deduplicator *FetchDeduplicator) {
+	c.fetchDeduplicator = deduplicator
+}
+
 func (c *Context) SetEngineLoaderHooks(hooks LoaderHooks) {
 	c.LoaderHooks = hooks
 }
@@ -142,6 +152,7 @@ func (c *Context) clone(ctx context.Context) *Context {
 	cpy.ctx = ctx
 	cpy.Variables = append([]byte(nil), c.Variables...)
 	cpy.Request.Header = c.Request.Header.Clone()
+	cpy.Files = append([]httpclient.File(nil), c.Files...)
 	cpy.RenameTypeNames = append([]RenameTypeName(nil), c.RenameTypeNames...)
 	return &cpy
 }
@@ -150,12 +161,14 @@ func (c *Context) Free() {
 	c.ctx = nil
 	c.Variables = nil
 	c.Request.Header = nil
+	c.Files = nil
 	c.RenameTypeNames = nil
 	c.TracingOptions.DisableAll()
 	c.Extensions = nil
 	c.Stats.Reset()
 	c.subgraphErrors = nil
 	c.authorizer = nil
+	c.fetchDeduplicator = nil
 	c.LoaderHooks = nil
 }
 
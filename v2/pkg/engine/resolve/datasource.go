@@ -5,12 +5,21 @@ import (
 	"io"
 
 	"github.com/cespare/xxhash/v2"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 )
 
 type DataSource interface {
 	Load(ctx context.Context, input []byte, w io.Writer) (err error)
 }
 
+// FileUploadDataSource is implemented by data sources which are able to forward file uploads
+// LoadWithFiles is called instead of Load when the request contains files,
+// the data source has to pick the files which belong to the input
+type FileUploadDataSource interface {
+	LoadWithFiles(ctx context.Context, input []byte, files []httpclient.File, w io.Writer) (err error)
+}
+
 type SubscriptionDataSource interface {
 	Start(ctx *Context, input []byte, updater SubscriptionUpdater) error
 	UniqueRequestID(ctx *Context, input []byte, xxh *xxhash.Digest) (err error)
@@ -0,0 +1,88 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// FetchDeduplicator shares the responses of identical fetches between multiple resolve operations,
+// e.g. between the operations of a batched request.
+// Fetches are identical when they load from the same data source and their rendered inputs are equal.
+// Mutations and fetches with uploaded files are never deduplicated.
+// A FetchDeduplicator keeps all responses in memory, it should only live as long as the operations sharing it.
+type FetchDeduplicator struct {
+	mu      sync.Mutex
+	fetches map[deduplicationKey]*deduplicatedFetch
+}
+
+// fetchIdentity identifies the data source of a fetch,
+// different data sources might respond differently to the same input
+type fetchIdentity struct {
+	dataSourceID         string
+	dataSourceIdentifier string
+}
+
+type deduplicationKey struct {
+	fetchIdentity
+	input uint64
+}
+
+type deduplicatedFetch struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func NewFetchDeduplicator() *FetchDeduplicator {
+	return &FetchDeduplicator{
+		fetches: make(map[deduplicationKey]*deduplicatedFetch),
+	}
+}
+
+// load loads the input once per data source and writes the shared response to all callers with the same input
+func (d *FetchDeduplicator) load(ctx context.Context, identity fetchIdentity, source DataSource, input []byte, out io.Writer) error {
+	key := deduplicationKey{
+		fetchIdentity: identity,
+		input:         xxhash.Sum64(input),
+	}
+
+	d.mu.Lock()
+	fetch, ok := d.fetches[key]
+	if !ok {
+		fetch = &deduplicatedFetch{done: make(chan struct{})}
+		d.fetches[key] = fetch
+	}
+	d.mu.Unlock()
+
+	if ok {
+		if stats := GetSingleFlightStats(ctx); stats != nil {
+			stats.SingleFlightUsed = true
+			stats.SingleFlightSharedResponse = true
+		}
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if fetch.err != nil {
+			return fetch.err
+		}
+		_, err := out.Write(fetch.data)
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	fetch.err = source.Load(ctx, input, buf)
+	fetch.data = buf.Bytes()
+	close(fetch.done)
+
+	if fetch.err != nil {
+		return fetch.err
+	}
+	_, err := out.Write(fetch.data)
+	return err
+}
@@ -0,0 +1,116 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+type countingDataSource struct {
+	loads atomic.Int32
+	err   error
+}
+
+func (c *countingDataSource) Load(ctx context.Context, input []byte, w io.Writer) (err error) {
+	c.loads.Inc()
+	time.Sleep(10 * time.Millisecond)
+	if c.err != nil {
+		return c.err
+	}
+	_, err = w.Write(append([]byte("response:"), input...))
+	return
+}
+
+func TestFetchDeduplicator(t *testing.T) {
+	products := fetchIdentity{dataSourceID: "products", dataSourceIdentifier: "graphql_datasource.Source"}
+	reviews := fetchIdentity{dataSourceID: "reviews", dataSourceIdentifier: "graphql_datasource.Source"}
+
+	t.Run("identical fetches are loaded once", func(t *testing.T) {
+		source := &countingDataSource{}
+		deduplicator := NewFetchDeduplicator()
+
+		outs := make([]*bytes.Buffer, 5)
+		wg := &sync.WaitGroup{}
+		for i := range outs {
+			outs[i] = &bytes.Buffer{}
+			wg.Add(1)
+			go func(out *bytes.Buffer) {
+				defer wg.Done()
+				assert.NoError(t, deduplicator.load(context.Background(), products, source, []byte(`{"a":1}`), out))
+			}(outs[i])
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), source.loads.Load())
+		for _, out := range outs {
+			assert.Equal(t, `response:{"a":1}`, out.String())
+		}
+	})
+
+	t.Run("different fetches are loaded separately", func(t *testing.T) {
+		source := &countingDataSource{}
+		deduplicator := NewFetchDeduplicator()
+
+		first, second := &bytes.Buffer{}, &bytes.Buffer{}
+		require.NoError(t, deduplicator.load(context.Background(), products, source, []byte(`{"a":1}`), first))
+		require.NoError(t, deduplicator.load(context.Background(), products, source, []byte(`{"a":2}`), second))
+
+		assert.Equal(t, int32(2), source.loads.Load())
+		assert.Equal(t, `response:{"a":1}`, first.String())
+		assert.Equal(t, `response:{"a":2}`, second.String())
+	})
+
+	t.Run("same input to different data sources is loaded separately", func(t *testing.T) {
+		productsSource, reviewsSource := &countingDataSource{}, &countingDataSource{}
+		deduplicator := NewFetchDeduplicator()
+
+		first, second := &bytes.Buffer{}, &bytes.Buffer{}
+		require.NoError(t, deduplicator.load(context.Background(), products, productsSource, []byte(`{"a":1}`), first))
+		require.NoError(t, deduplicator.load(context.Background(), reviews, reviewsSource, []byte(`{"a":1}`), second))
+
+		assert.Equal(t, int32(1), productsSource.loads.Load())
+		assert.Equal(t, int32(1), reviewsSource.loads.Load())
+		assert.Equal(t, `response:{"a":1}`, first.String())
+		assert.Equal(t, `response:{"a":1}`, second.String())
+	})
+
+	t.Run("errors are shared", func(t *testing.T) {
+		loadErr := errors.New("load failed")
+		source := &countingDataSource{err: loadErr}
+		deduplicator := NewFetchDeduplicator()
+
+		out := &bytes.Buffer{}
+		assert.ErrorIs(t, deduplicator.load(context.Background(), products, source, []byte(`{}`), out), loadErr)
+		assert.ErrorIs(t, deduplicator.load(context.Background(), products, source, []byte(`{}`), out), loadErr)
+		assert.Equal(t, int32(1), source.loads.Load())
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("mutations are not deduplicated", func(t *testing.T) {
+		source := &countingDataSource{}
+		ctx := NewContext(context.Background())
+		ctx.SetFetchDeduplicator(NewFetchDeduplicator())
+		loader := &Loader{ctx: ctx}
+
+		mutationCtx := context.WithValue(context.Background(), disallowSingleFlightContextKey{}, true)
+		for i := 0; i < 2; i++ {
+			out := &bytes.Buffer{}
+			require.NoError(t, loader.loadSource(mutationCtx, source, products, []byte(`{}`), out))
+			assert.Equal(t, `response:{}`, out.String())
+		}
+		assert.Equal(t, int32(2), source.loads.Load())
+
+		out := &bytes.Buffer{}
+		require.NoError(t, loader.loadSource(context.Background(), source, products, []byte(`{}`), out))
+		require.NoError(t, loader.loadSource(context.Background(), source, products, []byte(`{}`), out))
+		assert.Equal(t, int32(3), source.loads.Load())
+	})
+}
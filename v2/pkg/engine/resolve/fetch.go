@@ -38,6 +38,9 @@ type SingleFetch struct {
 	DataSourceIdentifier []byte
 	Trace                *DataSourceLoadTrace
 	Info                 *FetchInfo
+	// MergedItemPaths is set by post-processing when compatible entity fetches at other response paths
+	// were merged into this fetch. It is only evaluated for entity batch fetches, see BatchEntityFetch.MergedItemPaths
+	MergedItemPaths [][]string
 }
 
 type PostProcessingConfiguration struct {
@@ -97,6 +100,11 @@ type BatchEntityFetch struct {
 	DataSourceIdentifier []byte
 	Trace                *DataSourceLoadTrace
 	Info                 *FetchInfo
+	// MergedItemPaths contains the response paths of entity fetches to the same subgraph which were merged into this fetch
+	// Each path is relative to the response data root, arrays on the path are flattened into their items
+	// The items found at these paths are added to the batch, so all entities are loaded within a single request,
+	// and the results are merged back into the items at each path
+	MergedItemPaths [][]string
 }
 
 type BatchInput struct {
@@ -26,9 +26,10 @@ import (
 )
 
 const (
-	IntrospectionSchemaTypeDataSourceID     = "introspection__schema&__type"
-	IntrospectionTypeFieldsDataSourceID     = "introspection__type__fields"
-	IntrospectionTypeEnumValuesDataSourceID = "introspection__type__enumValues"
+	IntrospectionSchemaTypeDataSourceID      = "introspection__schema&__type"
+	IntrospectionTypeFieldsDataSourceID      = "introspection__type__fields"
+	IntrospectionTypeEnumValuesDataSourceID  = "introspection__type__enumValues"
+	IntrospectionTypeInputFieldsDataSourceID = "introspection__type__inputFields"
 )
 
 type LoaderHooks interface {
@@ -39,7 +40,14 @@ type LoaderHooks interface {
 }
 
 func IsIntrospectionDataSource(dataSourceID string) bool {
-	return dataSourceID == IntrospectionSchemaTypeDataSourceID || dataSourceID == IntrospectionTypeFieldsDataSourceID || dataSourceID == IntrospectionTypeEnumValuesDataSourceID
+	switch dataSourceID {
+	case IntrospectionSchemaTypeDataSourceID,
+		IntrospectionTypeFieldsDataSourceID,
+		IntrospectionTypeEnumValuesDataSourceID,
+		IntrospectionTypeInputFieldsDataSourceID:
+		return true
+	}
+	return false
 }
 
 type Loader struct {
@@ -100,6 +108,10 @@ func (l *Loader) popArrayPath() {
 }
 
 func (l *Loader) renderPath() string {
+	return l.renderPathOf(l.path)
+}
+
+func (l *Loader) renderPathOf(path []string) string {
 	builder := strings.Builder{}
 	if l.info != nil {
 		switch l.info.OperationType {
@@ -112,16 +124,49 @@ func (l *Loader) renderPath() string {
 		case ast.OperationTypeUnknown:
 		}
 	}
-	if len(l.path) == 0 {
+	if len(path) == 0 {
 		return builder.String()
 	}
-	for i := range l.path {
+	for i := range path {
 		builder.WriteByte('.')
-		builder.WriteString(l.path[i])
+		builder.WriteString(path[i])
 	}
 	return builder.String()
 }
 
+// renderFetchPaths renders the path of the current fetch and the paths of the fetches which were merged into it
+func (l *Loader) renderFetchPaths(res *result) []string {
+	paths := make([]string, 0, len(res.mergedItemPaths)+1)
+	paths = append(paths, l.renderPath())
+	for _, path := range res.mergedItemPaths {
+		paths = append(paths, l.renderPathOf(l.itemPathFromRoot(path)))
+	}
+	return paths
+}
+
+// itemPathFromRoot returns the path relative to the data root with a "@" for each array on the path,
+// the same way as the path is tracked while walking the response
+func (l *Loader) itemPathFromRoot(path []string) []string {
+	itemPath := make([]string, 0, len(path)*2)
+	items := []int{l.dataRoot}
+	for i := range path {
+		itemPath = append(itemPath, path[i])
+		isArray := false
+		for _, item := range items {
+			field := l.data.Get(item, path[i:i+1])
+			if field != -1 && l.data.Nodes[field].Kind == astjson.NodeKindArray {
+				isArray = true
+				break
+			}
+		}
+		if isArray {
+			itemPath = append(itemPath, "@")
+		}
+		items = l.selectNodeItems(items, path[i:i+1])
+	}
+	return itemPath
+}
+
 func (l *Loader) walkObject(object *Object, parentItems []int) (err error) {
 	l.pushPath(object.Path)
 	defer l.popPath(object.Path)
@@ -183,6 +228,36 @@ func (l *Loader) selectNodeItems(parentItems []int, path []string) (items []int)
 	return
 }
 
+// fetchItems returns the items for the fetch
+// for a batch entity fetch with merged fetches the items at the merged paths are appended to the items of the current object
+func (l *Loader) fetchItems(fetch Fetch, items []int) []int {
+	batchFetch, ok := fetch.(*BatchEntityFetch)
+	if !ok || len(batchFetch.MergedItemPaths) == 0 {
+		return items
+	}
+
+	// items could be shared with the response data, so we have to copy them
+	merged := make([]int, 0, len(items)*(len(batchFetch.MergedItemPaths)+1))
+	merged = append(merged, items...)
+	for _, path := range batchFetch.MergedItemPaths {
+		merged = append(merged, l.selectItemsFromRoot(path)...)
+	}
+	return merged
+}
+
+// selectItemsFromRoot selects the items at the path relative to the data root
+// arrays on the path are flattened in the same way as while walking the response
+func (l *Loader) selectItemsFromRoot(path []string) []int {
+	items := []int{l.dataRoot}
+	for i := range path {
+		items = l.selectNodeItems(items, path[i:i+1])
+		if len(items) == 0 {
+			return nil
+		}
+	}
+	return items
+}
+
 func (l *Loader) itemsData(items []int, out io.Writer) error {
 	if len(items) == 0 {
 		return nil
@@ -234,12 +309,14 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 			}
 		}
 		results := make([]*result, len(f.Fetches))
+		fetchItems := make([][]int, len(f.Fetches))
 		g, ctx := errgroup.WithContext(l.ctx.ctx)
 		for i := range f.Fetches {
 			i := i
 			results[i] = &result{}
+			fetchItems[i] = l.fetchItems(f.Fetches[i], items)
 			g.Go(func() error {
-				return l.loadFetch(ctx, f.Fetches[i], items, results[i])
+				return l.loadFetch(ctx, f.Fetches[i], fetchItems[i], results[i])
 			})
 		}
 		err := g.Wait()
@@ -258,7 +335,7 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 					}
 				}
 			} else {
-				err = l.mergeResult(results[i], items)
+				err = l.mergeResult(results[i], fetchItems[i])
 				if l.ctx.LoaderHooks != nil && results[i].loaderHookContext != nil {
 					l.ctx.LoaderHooks.OnFinished(results[i].loaderHookContext, results[i].statusCode, results[i].subgraphName, goerrors.Join(results[i].err, l.ctx.subgraphErrors))
 				}
@@ -311,6 +388,7 @@ func (l *Loader) resolveAndMergeFetch(fetch Fetch, items []int) error {
 		}
 		return err
 	case *BatchEntityFetch:
+		items = l.fetchItems(f, items)
 		res := &result{
 			out: pool.BytesBuffer.Get(),
 		}
@@ -523,10 +601,12 @@ func (l *Loader) mergeResult(res *result, items []int) error {
 }
 
 type result struct {
-	postProcessing   PostProcessingConfiguration
-	out              *bytes.Buffer
-	batchStats       [][]int
-	fetchSkipped     bool
+	postProcessing PostProcessingConfiguration
+	out            *bytes.Buffer
+	batchStats     [][]int
+	fetchSkipped   bool
+	// mergedItemPaths are the paths of the entity fetches which were merged into the fetch, see BatchEntityFetch.MergedItemPaths
+	mergedItemPaths  [][]string
 	nestedMergeItems []*result
 
 	statusCode   int
@@ -577,7 +657,16 @@ func (l *Loader) mergeErrors(res *result, ref int) error {
 		})
 		l.errorsRoot = len(l.data.Nodes) - 1
 	}
-	path := l.renderPath()
+	// the errors are reported at every path the items of the fetch were loaded for
+	for _, path := range l.renderFetchPaths(res) {
+		if err := l.mergeErrorsAtPath(res, ref, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Loader) mergeErrorsAtPath(res *result, ref int, path string) error {
 	errorObject, err := l.data.AppendObject([]byte(l.renderSubgraphBaseError(res.subgraphName, path, failedToFetchNoReason)))
 	if err != nil {
 		return errors.WithStack(err)
@@ -653,14 +742,15 @@ const (
 )
 
 func (l *Loader) renderErrorsFailedToFetch(res *result, reason string) error {
-	path := l.renderPath()
-	l.ctx.appendSubgraphError(goerrors.Join(res.err, NewSubgraphError(res.subgraphName, path, reason, res.statusCode)))
-	errorObject, err := l.data.AppendObject([]byte(l.renderSubgraphBaseError(res.subgraphName, path, reason)))
-	if err != nil {
-		return errors.WithStack(err)
+	for _, path := range l.renderFetchPaths(res) {
+		l.ctx.appendSubgraphError(goerrors.Join(res.err, NewSubgraphError(res.subgraphName, path, reason, res.statusCode)))
+		errorObject, err := l.data.AppendObject([]byte(l.renderSubgraphBaseError(res.subgraphName, path, reason)))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		l.setSubgraphStatusCode(errorObject, res.statusCode)
+		l.data.Nodes[l.errorsRoot].ArrayValues = append(l.data.Nodes[l.errorsRoot].ArrayValues, errorObject)
 	}
-	l.setSubgraphStatusCode(errorObject, res.statusCode)
-	l.data.Nodes[l.errorsRoot].ArrayValues = append(l.data.Nodes[l.errorsRoot].ArrayValues, errorObject)
 	return nil
 }
 
@@ -848,7 +938,7 @@ func (l *Loader) loadSingleFetch(ctx context.Context, fetch *SingleFetch, items
 	if !allowed {
 		return nil
 	}
-	l.executeSourceLoad(ctx, fetch.DataSource, fetchInput, res, fetch.Trace)
+	l.executeSourceLoad(ctx, fetch.DataSource, fetch.DataSourceIdentifier, fetchInput, res, fetch.Trace)
 	return nil
 }
 
@@ -928,12 +1018,13 @@ func (l *Loader) loadEntityFetch(ctx context.Context, fetch *EntityFetch, items
 	if !allowed {
 		return nil
 	}
-	l.executeSourceLoad(ctx, fetch.DataSource, fetchInput, res, fetch.Trace)
+	l.executeSourceLoad(ctx, fetch.DataSource, fetch.DataSourceIdentifier, fetchInput, res, fetch.Trace)
 	return nil
 }
 
 func (l *Loader) loadBatchEntityFetch(ctx context.Context, fetch *BatchEntityFetch, items []int, res *result) error {
 	res.init(fetch.PostProcessing, fetch.Info)
+	res.mergedItemPaths = fetch.MergedItemPaths
 
 	if l.ctx.TracingOptions.Enable {
 		fetch.Trace = &DataSourceLoadTrace{}
@@ -1049,7 +1140,7 @@ WithNextItem:
 	if !allowed {
 		return nil
 	}
-	l.executeSourceLoad(ctx, fetch.DataSource, fetchInput, res, fetch.Trace)
+	l.executeSourceLoad(ctx, fetch.DataSource, fetch.DataSourceIdentifier, fetchInput, res, fetch.Trace)
 	return nil
 }
 
@@ -1115,7 +1206,21 @@ func setSingleFlightStats(ctx context.Context, stats *SingleFlightStats) context
 	return context.WithValue(ctx, singleFlightStatsKey{}, stats)
 }
 
-func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, input []byte, res *result, trace *DataSourceLoadTrace) {
+// loadSource passes the uploaded files of the request to data sources which are able to forward them
+// and shares the responses of identical fetches when a FetchDeduplicator is set
+func (l *Loader) loadSource(ctx context.Context, source DataSource, identity fetchIdentity, input []byte, out io.Writer) error {
+	if len(l.ctx.Files) > 0 {
+		if fileUploadSource, ok := source.(FileUploadDataSource); ok {
+			return fileUploadSource.LoadWithFiles(ctx, input, l.ctx.Files, out)
+		}
+	}
+	if l.ctx.fetchDeduplicator != nil && !SingleFlightDisallowed(ctx) {
+		return l.ctx.fetchDeduplicator.load(ctx, identity, source, input, out)
+	}
+	return source.Load(ctx, input, out)
+}
+
+func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, dataSourceIdentifier []byte, input []byte, res *result, trace *DataSourceLoadTrace) {
 	if l.ctx.Extensions != nil {
 		input, res.err = jsonparser.Set(input, l.ctx.Extensions, "body", "extensions")
 		if res.err != nil {
@@ -1228,24 +1333,30 @@ func (l *Loader) executeSourceLoad(ctx context.Context, source DataSource, input
 			ctx = httptrace.WithClientTrace(ctx, clientTrace)
 		}
 	}
-	if l.info != nil && l.info.OperationType == ast.OperationTypeMutation {
+	if (l.info != nil && l.info.OperationType == ast.OperationTypeMutation) || len(l.ctx.Files) > 0 {
+		// requests with files must not be deduplicated, because the files are not part of the input
 		ctx = context.WithValue(ctx, disallowSingleFlightContextKey{}, true)
 	}
 	var responseContext *httpclient.ResponseContext
 	ctx, responseContext = httpclient.InjectResponseContext(ctx)
 
+	identity := fetchIdentity{
+		dataSourceID:         res.subgraphName,
+		dataSourceIdentifier: string(dataSourceIdentifier),
+	}
+
 	if l.ctx.LoaderHooks != nil {
 		res.loaderHookContext = l.ctx.LoaderHooks.OnLoad(ctx, res.subgraphName)
 
 		// Prevent that the context is destroyed when the loader hook return an empty context
 		if res.loaderHookContext != nil {
-			res.err = source.Load(res.loaderHookContext, input, res.out)
+			res.err = l.loadSource(res.loaderHookContext, source, identity, input, res.out)
 		} else {
-			res.err = source.Load(ctx, input, res.out)
+			res.err = l.loadSource(ctx, source, identity, input, res.out)
 		}
 
 	} else {
-		res.err = source.Load(ctx, input, res.out)
+		res.err = l.loadSource(ctx, source, identity, input, res.out)
 	}
 
 	res.statusCode = responseContext.StatusCode
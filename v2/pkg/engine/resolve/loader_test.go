@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
 )
@@ -987,3 +989,189 @@ func TestLoader_RedactHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestLoader_MergedItemPaths(t *testing.T) {
+	const rootData = `{"me":{"__typename":"User","id":"1"},"reviews":[{"author":{"__typename":"User","id":"1"}},{"author":null},{"author":{"__typename":"User","id":"2"}}],"search":[{"__typename":"Post","id":"9"},{"__typename":"User","id":"3"}]}`
+
+	// usersFetch loads the users of me, merged with the authors of the reviews and the users of the search results
+	usersFetch := func(dataSource DataSource) *BatchEntityFetch {
+		onUser := [][]byte{[]byte("User")}
+		return &BatchEntityFetch{
+			Input: BatchInput{
+				Header: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`{"method":"POST","url":"http://users","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on User {name}}}","variables":{"representations":[`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				Items: []InputTemplate{
+					{
+						Segments: []TemplateSegment{
+							{
+								SegmentType:  VariableSegmentType,
+								VariableKind: ResolvableObjectVariableKind,
+								Renderer: NewGraphQLVariableResolveRenderer(&Object{
+									Nullable: true,
+									Fields: []*Field{
+										{
+											Name:        []byte("__typename"),
+											Value:       &String{Path: []string{"__typename"}},
+											OnTypeNames: onUser,
+										},
+										{
+											Name:        []byte("id"),
+											Value:       &String{Path: []string{"id"}},
+											OnTypeNames: onUser,
+										},
+									},
+								}),
+							},
+						},
+					},
+				},
+				Separator: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`,`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				Footer: InputTemplate{
+					Segments: []TemplateSegment{
+						{
+							Data:        []byte(`]}}}`),
+							SegmentType: StaticSegmentType,
+						},
+					},
+				},
+				SkipNullItems:        true,
+				SkipEmptyObjectItems: true,
+			},
+			DataSource: dataSource,
+			PostProcessing: PostProcessingConfiguration{
+				SelectResponseDataPath:   []string{"data", "_entities"},
+				SelectResponseErrorsPath: []string{"errors"},
+			},
+			Info: &FetchInfo{
+				DataSourceID: "users",
+			},
+			MergedItemPaths: [][]string{{"reviews", "author"}, {"search"}},
+		}
+	}
+
+	user := func(path string) *Object {
+		return &Object{
+			Path:     []string{path},
+			Nullable: true,
+			Fields: []*Field{
+				{
+					Name:  []byte("name"),
+					Value: &String{Path: []string{"name"}},
+				},
+			},
+		}
+	}
+
+	load := func(t *testing.T, fetch *BatchEntityFetch) string {
+		me := user("me")
+		me.Fetch = fetch
+		response := &GraphQLResponse{
+			Info: &GraphQLResponseInfo{
+				OperationType: ast.OperationTypeQuery,
+			},
+			Data: &Object{
+				Fetch: &SingleFetch{
+					InputTemplate: InputTemplate{
+						Segments: []TemplateSegment{
+							{
+								Data:        []byte(`{"method":"POST","url":"http://reviews"}`),
+								SegmentType: StaticSegmentType,
+							},
+						},
+					},
+					FetchConfiguration: FetchConfiguration{
+						DataSource: FakeDataSource(`{"data":` + rootData + `}`),
+						PostProcessing: PostProcessingConfiguration{
+							SelectResponseDataPath: []string{"data"},
+						},
+					},
+				},
+				Fields: []*Field{
+					{
+						Name:  []byte("me"),
+						Value: me,
+					},
+					{
+						Name: []byte("reviews"),
+						Value: &Array{
+							Path: []string{"reviews"},
+							Item: &Object{
+								Fields: []*Field{
+									{
+										Name:  []byte("author"),
+										Value: user("author"),
+									},
+								},
+							},
+						},
+					},
+					{
+						Name: []byte("search"),
+						Value: &Array{
+							Path: []string{"search"},
+							Item: &Object{
+								Fields: []*Field{
+									{
+										Name:        []byte("name"),
+										Value:       &String{Path: []string{"name"}},
+										OnTypeNames: [][]byte{[]byte("User")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		ctx := NewContext(context.Background())
+		resolvable := NewResolvable()
+		loader := &Loader{}
+		require.NoError(t, resolvable.Init(ctx, nil, ast.OperationTypeQuery))
+		require.NoError(t, loader.LoadGraphQLResponseData(ctx, response, resolvable))
+		out := &bytes.Buffer{}
+		require.NoError(t, resolvable.storage.PrintNode(resolvable.storage.Nodes[resolvable.storage.RootNode], out))
+		return out.String()
+	}
+
+	t.Run("items at merged paths are loaded once and merged back", func(t *testing.T) {
+		// user 1 is loaded once for me and the first review, the null author and the post are skipped
+		users := fakeDataSourceWithInputCheck(t,
+			[]byte(`{"method":"POST","url":"http://users","body":{"query":"query($representations: [_Any!]!){_entities(representations: $representations){__typename ... on User {name}}}","variables":{"representations":[{"__typename":"User","id":"1"},{"__typename":"User","id":"2"},{"__typename":"User","id":"3"}]}}}`),
+			[]byte(`{"data":{"_entities":[{"__typename":"User","name":"user-1"},{"__typename":"User","name":"user-2"},{"__typename":"User","name":"user-3"}]}}`))
+
+		assert.Equal(t,
+			`{"errors":[],"data":{"me":{"__typename":"User","id":"1","name":"user-1"},"reviews":[{"author":{"__typename":"User","id":"1","name":"user-1"}},{"author":null},{"author":{"__typename":"User","id":"2","name":"user-2"}}],"search":[{"__typename":"Post","id":"9"},{"__typename":"User","id":"3","name":"user-3"}]}}`,
+			load(t, usersFetch(users)))
+	})
+
+	t.Run("errors are reported at every merged path", func(t *testing.T) {
+		users := FakeDataSource(`{"errors":[{"message":"users unavailable"}],"data":{"_entities":null}}`)
+
+		assert.Equal(t,
+			`{"errors":[{"message":"Failed to fetch from Subgraph 'users' at Path 'query.me'."},{"message":"Failed to fetch from Subgraph 'users' at Path 'query.reviews.@.author'."},{"message":"Failed to fetch from Subgraph 'users' at Path 'query.search.@'."}],"data":{"me":{"__typename":"User","id":"1"},"reviews":[{"author":{"__typename":"User","id":"1"}},{"author":null},{"author":{"__typename":"User","id":"2"}}],"search":[{"__typename":"Post","id":"9"},{"__typename":"User","id":"3"}]}}`,
+			load(t, usersFetch(users)))
+	})
+
+	t.Run("failed fetches are reported at every merged path", func(t *testing.T) {
+		users := &countingDataSource{err: errors.New("connection refused")}
+
+		assert.Equal(t,
+			`{"errors":[{"message":"Failed to fetch from Subgraph 'users' at Path 'query.me'."},{"message":"Failed to fetch from Subgraph 'users' at Path 'query.reviews.@.author'."},{"message":"Failed to fetch from Subgraph 'users' at Path 'query.search.@'."}],"data":{"me":{"__typename":"User","id":"1"},"reviews":[{"author":{"__typename":"User","id":"1"}},{"author":null},{"author":{"__typename":"User","id":"2"}}],"search":[{"__typename":"Post","id":"9"},{"__typename":"User","id":"3"}]}}`,
+			load(t, usersFetch(users)))
+		assert.Equal(t, int32(1), users.loads.Load())
+	})
+}
@@ -1,9 +1,16 @@
 package resolve
 
+// ScalarSerializer converts the JSON value of a custom scalar resolved from a data source into its response form
+type ScalarSerializer interface {
+	Serialize(value []byte) ([]byte, error)
+}
+
 type Scalar struct {
 	Path     []string
 	Nullable bool
 	Export   *FieldExport `json:"export,omitempty"`
+	// Serializer is optional, without a Serializer the value is rendered as is
+	Serializer ScalarSerializer `json:"-"`
 }
 
 func (_ *Scalar) NodeKind() NodeKind {
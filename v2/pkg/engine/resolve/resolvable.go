@@ -801,12 +801,33 @@ func (r *Resolvable) walkScalar(s *Scalar, ref int) bool {
 		r.addNonNullableFieldError(ref, s.Path)
 		return r.err()
 	}
+	if s.Serializer != nil {
+		return r.walkSerializedScalar(s, ref)
+	}
 	if r.print {
 		r.printNode(ref)
 	}
 	return false
 }
 
+func (r *Resolvable) walkSerializedScalar(s *Scalar, ref int) bool {
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+	if err := r.storage.PrintNode(r.storage.Nodes[ref], buf); err != nil {
+		r.addError(err.Error(), s.Path)
+		return r.err()
+	}
+	serialized, err := s.Serializer.Serialize(buf.Bytes())
+	if err != nil {
+		r.addError(err.Error(), s.Path)
+		return r.err()
+	}
+	if r.print {
+		r.printBytes(serialized)
+	}
+	return false
+}
+
 func (r *Resolvable) walkEmptyObject(_ *EmptyObject) bool {
 	if r.print {
 		r.printBytes(lBrace)
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 func TestResolvable_Resolve(t *testing.T) {
@@ -469,6 +470,41 @@ func BenchmarkResolvable_ResolveWithErrorBubbleUp(b *testing.B) {
 	}
 }
 
+func TestResolvable_ResolveWithScalarSerializer(t *testing.T) {
+	data := `{"events":[{"at":"2024-01-02T03:04:05.000+00:00"},{"at":"yesterday"},{"at":null}]}`
+	res := NewResolvable()
+	err := res.Init(&Context{}, []byte(data), ast.OperationTypeQuery)
+	assert.NoError(t, err)
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("events"),
+				Value: &Array{
+					Path: []string{"events"},
+					Item: &Object{
+						Nullable: true,
+						Fields: []*Field{
+							{
+								Name: []byte("at"),
+								Value: &Scalar{
+									Path:       []string{"at"},
+									Nullable:   true,
+									Serializer: scalars.DateTimeCodec{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err = res.Resolve(context.Background(), object, out)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"errors":[{"message":"Expected an RFC 3339 date-time string.","path":["events",1,"at"]}],"data":{"events":[{"at":"2024-01-02T03:04:05Z"},null,{"at":null}]}}`, out.String())
+}
+
 func TestResolvable_WithTracingNotStarted(t *testing.T) {
 	res := NewResolvable()
 	// Do not start a trace with SetTraceStart(), but request it to be output
@@ -151,9 +151,12 @@ func (r *Resolver) putTools(t *tools) {
 
 func (r *Resolver) ResolveGraphQLResponse(ctx *Context, response *GraphQLResponse, data []byte, writer io.Writer) (err error) {
 	if response.Info == nil {
-		response.Info = &GraphQLResponseInfo{
+		// the response is shared between concurrent requests of a cached plan, so it must not be modified
+		withInfo := *response
+		withInfo.Info = &GraphQLResponseInfo{
 			OperationType: ast.OperationTypeQuery,
 		}
+		response = &withInfo
 	}
 
 	t := r.getTools()
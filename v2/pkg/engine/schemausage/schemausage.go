@@ -0,0 +1,360 @@
+// Package schemausage aggregates the schema usage of single plans, as reported by plan.GetSchemaUsageInfo,
+// across requests. Usage is counted per client and per operation over a sliding window,
+// which tells whether a field, argument, input field or enum value is still in use, e.g. before removing a deprecated field.
+package schemausage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+const (
+	DefaultClientNameHeader    = "graphql-client-name"
+	DefaultClientVersionHeader = "graphql-client-version"
+	DefaultBucketDuration      = time.Minute
+	DefaultBuckets             = 60
+)
+
+// Options configures an Aggregator, zero values are replaced by the defaults
+type Options struct {
+	// ClientNameHeader and ClientVersionHeader are the request headers the client is read from
+	ClientNameHeader    string
+	ClientVersionHeader string
+	// BucketDuration is the granularity of the sliding window, usage is counted in buckets of this duration
+	BucketDuration time.Duration
+	// Buckets is the number of buckets which are kept, the window spans Buckets * BucketDuration
+	Buckets int
+	// Now returns the current time, it defaults to time.Now
+	Now func() time.Time
+}
+
+// Client identifies the client which sent a request
+type Client struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type usageKind int
+
+const (
+	usageKindField usageKind = iota + 1
+	usageKindArgument
+	usageKindInputField
+	usageKindEnumValue
+)
+
+// usageKey identifies a schema coordinate, enum values use the enum type as typeName and the value as fieldName
+type usageKey struct {
+	kind         usageKind
+	typeName     string
+	fieldName    string
+	argumentName string
+}
+
+type usageCounts struct {
+	count      uint64
+	lastSeen   time.Time
+	clients    map[Client]uint64
+	operations map[uint64]uint64
+}
+
+func (u *usageCounts) add(other *usageCounts) {
+	u.count += other.count
+	if other.lastSeen.After(u.lastSeen) {
+		u.lastSeen = other.lastSeen
+	}
+	for client, count := range other.clients {
+		u.clients[client] += count
+	}
+	for operation, count := range other.operations {
+		u.operations[operation] += count
+	}
+}
+
+func newUsageCounts() *usageCounts {
+	return &usageCounts{
+		clients:    make(map[Client]uint64),
+		operations: make(map[uint64]uint64),
+	}
+}
+
+type bucket struct {
+	start  time.Time
+	usages map[usageKey]*usageCounts
+}
+
+// Aggregator counts schema usage across requests, it is safe for concurrent use
+type Aggregator struct {
+	options Options
+
+	mu sync.Mutex
+	// buckets are ordered by their start, buckets without usage are not created
+	buckets []*bucket
+	keys    map[usageKey]struct{}
+}
+
+// NewAggregator creates a new Aggregator
+func NewAggregator(options Options) *Aggregator {
+	if options.ClientNameHeader == "" {
+		options.ClientNameHeader = DefaultClientNameHeader
+	}
+	if options.ClientVersionHeader == "" {
+		options.ClientVersionHeader = DefaultClientVersionHeader
+	}
+	if options.BucketDuration <= 0 {
+		options.BucketDuration = DefaultBucketDuration
+	}
+	if options.Buckets <= 0 {
+		options.Buckets = DefaultBuckets
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &Aggregator{
+		options: options,
+		keys:    make(map[usageKey]struct{}),
+	}
+}
+
+// ClientFromHeader reads the client from the configured request headers
+func (a *Aggregator) ClientFromHeader(header http.Header) Client {
+	return Client{
+		Name:    header.Get(a.options.ClientNameHeader),
+		Version: header.Get(a.options.ClientVersionHeader),
+	}
+}
+
+// RecordRequest records the usage of a request, the client is read from the request headers
+func (a *Aggregator) RecordRequest(header http.Header, operationHash uint64, usage *plan.SchemaUsageInfo) {
+	a.Record(a.ClientFromHeader(header), operationHash, usage)
+}
+
+// Record records the usage of a single request.
+// Every schema coordinate is counted once per request, no matter how often the operation uses it.
+// operationHash identifies the operation, e.g. the hash of its astnormalization.OperationSignature.
+func (a *Aggregator) Record(client Client, operationHash uint64, usage *plan.SchemaUsageInfo) {
+	if usage == nil {
+		return
+	}
+
+	keys := make(map[usageKey]struct{}, len(usage.TypeFields)+len(usage.Arguments)+len(usage.InputTypeFields))
+	for _, field := range usage.TypeFields {
+		for _, typeName := range field.EnclosingTypeNames {
+			keys[usageKey{kind: usageKindField, typeName: typeName, fieldName: field.FieldName}] = struct{}{}
+		}
+	}
+	for _, argument := range usage.Arguments {
+		keys[usageKey{kind: usageKindArgument, typeName: argument.EnclosingTypeName, fieldName: argument.FieldName, argumentName: argument.ArgumentName}] = struct{}{}
+	}
+	for _, inputField := range usage.InputTypeFields {
+		if !inputField.IsRootVariable {
+			for _, typeName := range inputField.EnclosingTypeNames {
+				keys[usageKey{kind: usageKindInputField, typeName: typeName, fieldName: inputField.FieldName}] = struct{}{}
+			}
+		}
+		if inputField.IsEnumField {
+			for _, value := range inputField.EnumValues {
+				keys[usageKey{kind: usageKindEnumValue, typeName: inputField.FieldTypeName, fieldName: value}] = struct{}{}
+			}
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.options.Now()
+	b := a.currentBucket(now)
+	for key := range keys {
+		counts, ok := b.usages[key]
+		if !ok {
+			counts = newUsageCounts()
+			b.usages[key] = counts
+		}
+		counts.count++
+		counts.lastSeen = now
+		counts.clients[client]++
+		counts.operations[operationHash]++
+		a.keys[key] = struct{}{}
+	}
+}
+
+// currentBucket returns the bucket of now and drops the buckets which left the window, the caller must hold the lock
+func (a *Aggregator) currentBucket(now time.Time) *bucket {
+	a.expire(now)
+	start := now.Truncate(a.options.BucketDuration)
+	if len(a.buckets) != 0 && a.buckets[len(a.buckets)-1].start.Equal(start) {
+		return a.buckets[len(a.buckets)-1]
+	}
+	b := &bucket{
+		start:  start,
+		usages: make(map[usageKey]*usageCounts),
+	}
+	a.buckets = append(a.buckets, b)
+	return b
+}
+
+func (a *Aggregator) windowStart(now time.Time) time.Time {
+	return now.Truncate(a.options.BucketDuration).Add(-time.Duration(a.options.Buckets-1) * a.options.BucketDuration)
+}
+
+func (a *Aggregator) expire(now time.Time) {
+	windowStart := a.windowStart(now)
+	expired := 0
+	for expired < len(a.buckets) && a.buckets[expired].start.Before(windowStart) {
+		expired++
+	}
+	if expired == 0 {
+		return
+	}
+	a.buckets = append(a.buckets[:0], a.buckets[expired:]...)
+
+	a.keys = make(map[usageKey]struct{}, len(a.keys))
+	for _, b := range a.buckets {
+		for key := range b.usages {
+			a.keys[key] = struct{}{}
+		}
+	}
+}
+
+// Snapshot is the usage within the sliding window, it is sorted by schema coordinate
+type Snapshot struct {
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	Fields      []FieldUsage      `json:"fields"`
+	Arguments   []ArgumentUsage   `json:"arguments"`
+	InputFields []InputFieldUsage `json:"inputFields"`
+	EnumValues  []EnumValueUsage  `json:"enumValues"`
+}
+
+// Usage counts the requests which used a schema coordinate
+type Usage struct {
+	Count      uint64           `json:"count"`
+	LastSeen   time.Time        `json:"lastSeen"`
+	Clients    []ClientUsage    `json:"clients"`
+	Operations []OperationUsage `json:"operations"`
+}
+
+type ClientUsage struct {
+	Client
+	Count uint64 `json:"count"`
+}
+
+type OperationUsage struct {
+	// Hash is encoded as a string, JSON numbers can't hold every uint64
+	Hash  uint64 `json:"hash,string"`
+	Count uint64 `json:"count"`
+}
+
+type FieldUsage struct {
+	TypeName  string `json:"typeName"`
+	FieldName string `json:"fieldName"`
+	Usage
+}
+
+type ArgumentUsage struct {
+	TypeName     string `json:"typeName"`
+	FieldName    string `json:"fieldName"`
+	ArgumentName string `json:"argumentName"`
+	Usage
+}
+
+type InputFieldUsage struct {
+	TypeName  string `json:"typeName"`
+	FieldName string `json:"fieldName"`
+	Usage
+}
+
+type EnumValueUsage struct {
+	TypeName string `json:"typeName"`
+	Value    string `json:"value"`
+	Usage
+}
+
+// WriteJSON writes the snapshot as JSON
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Snapshot returns the usage within the sliding window
+func (a *Aggregator) Snapshot() Snapshot {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.options.Now()
+	a.expire(now)
+
+	snapshot := Snapshot{
+		Start:       a.windowStart(now),
+		End:         now,
+		Fields:      []FieldUsage{},
+		Arguments:   []ArgumentUsage{},
+		InputFields: []InputFieldUsage{},
+		EnumValues:  []EnumValueUsage{},
+	}
+
+	keys := make([]usageKey, 0, len(a.keys))
+	for key := range a.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].typeName != keys[j].typeName {
+			return keys[i].typeName < keys[j].typeName
+		}
+		if keys[i].fieldName != keys[j].fieldName {
+			return keys[i].fieldName < keys[j].fieldName
+		}
+		return keys[i].argumentName < keys[j].argumentName
+	})
+
+	for _, key := range keys {
+		counts := newUsageCounts()
+		for _, b := range a.buckets {
+			if bucketCounts, ok := b.usages[key]; ok {
+				counts.add(bucketCounts)
+			}
+		}
+		usage := exportUsage(counts)
+		switch key.kind {
+		case usageKindField:
+			snapshot.Fields = append(snapshot.Fields, FieldUsage{TypeName: key.typeName, FieldName: key.fieldName, Usage: usage})
+		case usageKindArgument:
+			snapshot.Arguments = append(snapshot.Arguments, ArgumentUsage{TypeName: key.typeName, FieldName: key.fieldName, ArgumentName: key.argumentName, Usage: usage})
+		case usageKindInputField:
+			snapshot.InputFields = append(snapshot.InputFields, InputFieldUsage{TypeName: key.typeName, FieldName: key.fieldName, Usage: usage})
+		case usageKindEnumValue:
+			snapshot.EnumValues = append(snapshot.EnumValues, EnumValueUsage{TypeName: key.typeName, Value: key.fieldName, Usage: usage})
+		}
+	}
+	return snapshot
+}
+
+func exportUsage(counts *usageCounts) Usage {
+	usage := Usage{
+		Count:      counts.count,
+		LastSeen:   counts.lastSeen,
+		Clients:    make([]ClientUsage, 0, len(counts.clients)),
+		Operations: make([]OperationUsage, 0, len(counts.operations)),
+	}
+	for client, count := range counts.clients {
+		usage.Clients = append(usage.Clients, ClientUsage{Client: client, Count: count})
+	}
+	sort.Slice(usage.Clients, func(i, j int) bool {
+		if usage.Clients[i].Name != usage.Clients[j].Name {
+			return usage.Clients[i].Name < usage.Clients[j].Name
+		}
+		return usage.Clients[i].Version < usage.Clients[j].Version
+	})
+	for operation, count := range counts.operations {
+		usage.Operations = append(usage.Operations, OperationUsage{Hash: operation, Count: count})
+	}
+	sort.Slice(usage.Operations, func(i, j int) bool {
+		return usage.Operations[i].Hash < usage.Operations[j].Hash
+	})
+	return usage
+}
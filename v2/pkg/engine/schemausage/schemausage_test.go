@@ -0,0 +1,153 @@
+package schemausage
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func userUsage() *plan.SchemaUsageInfo {
+	return &plan.SchemaUsageInfo{
+		TypeFields: []plan.TypeFieldUsageInfo{
+			{FieldName: "user", FieldTypeName: "User", EnclosingTypeNames: []string{"Query"}, Path: []string{"user"}},
+			{FieldName: "name", FieldTypeName: "String", EnclosingTypeNames: []string{"User"}, Path: []string{"user", "name"}},
+			{FieldName: "user", FieldTypeName: "User", EnclosingTypeNames: []string{"Query"}, Path: []string{"other"}},
+		},
+		Arguments: []plan.ArgumentUsageInfo{
+			{FieldName: "user", EnclosingTypeName: "Query", ArgumentName: "filter", ArgumentTypeName: "UserFilter"},
+		},
+		InputTypeFields: []plan.InputTypeFieldUsageInfo{
+			{IsRootVariable: true, Count: 1, FieldTypeName: "UserFilter"},
+			{Count: 1, FieldName: "role", FieldTypeName: "Role", EnclosingTypeNames: []string{"UserFilter"}, IsEnumField: true, EnumValues: []string{"ADMIN"}},
+		},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("counts usage per client and operation", func(t *testing.T) {
+		clock := &testClock{now: start}
+		aggregator := NewAggregator(Options{Now: clock.Now})
+
+		header := http.Header{}
+		header.Set("GraphQL-Client-Name", "web")
+		header.Set("GraphQL-Client-Version", "1.0.0")
+		aggregator.RecordRequest(header, 1, userUsage())
+		clock.now = start.Add(time.Minute)
+		aggregator.RecordRequest(header, 2, userUsage())
+		aggregator.Record(Client{Name: "ios", Version: "2.0.0"}, 1, &plan.SchemaUsageInfo{
+			TypeFields: []plan.TypeFieldUsageInfo{
+				{FieldName: "name", FieldTypeName: "String", EnclosingTypeNames: []string{"Admin", "User"}},
+			},
+		})
+
+		buf := &bytes.Buffer{}
+		snapshot := aggregator.Snapshot()
+		require.NoError(t, snapshot.WriteJSON(buf))
+		assert.JSONEq(t, `{
+			"start": "2024-01-01T11:02:00Z",
+			"end": "2024-01-01T12:01:00Z",
+			"fields": [
+				{"typeName": "Admin", "fieldName": "name", "count": 1, "lastSeen": "2024-01-01T12:01:00Z",
+					"clients": [{"name": "ios", "version": "2.0.0", "count": 1}],
+					"operations": [{"hash": "1", "count": 1}]},
+				{"typeName": "Query", "fieldName": "user", "count": 2, "lastSeen": "2024-01-01T12:01:00Z",
+					"clients": [{"name": "web", "version": "1.0.0", "count": 2}],
+					"operations": [{"hash": "1", "count": 1}, {"hash": "2", "count": 1}]},
+				{"typeName": "User", "fieldName": "name", "count": 3, "lastSeen": "2024-01-01T12:01:00Z",
+					"clients": [{"name": "ios", "version": "2.0.0", "count": 1}, {"name": "web", "version": "1.0.0", "count": 2}],
+					"operations": [{"hash": "1", "count": 2}, {"hash": "2", "count": 1}]}
+			],
+			"arguments": [
+				{"typeName": "Query", "fieldName": "user", "argumentName": "filter", "count": 2, "lastSeen": "2024-01-01T12:01:00Z",
+					"clients": [{"name": "web", "version": "1.0.0", "count": 2}],
+					"operations": [{"hash": "1", "count": 1}, {"hash": "2", "count": 1}]}
+			],
+			"inputFields": [
+				{"typeName": "UserFilter", "fieldName": "role", "count": 2, "lastSeen": "2024-01-01T12:01:00Z",
+					"clients": [{"name": "web", "version": "1.0.0", "count": 2}],
+					"operations": [{"hash": "1", "count": 1}, {"hash": "2", "count": 1}]}
+			],
+			"enumValues": [
+				{"typeName": "Role", "value": "ADMIN", "count": 2, "lastSeen": "2024-01-01T12:01:00Z",
+					"clients": [{"name": "web", "version": "1.0.0", "count": 2}],
+					"operations": [{"hash": "1", "count": 1}, {"hash": "2", "count": 1}]}
+			]
+		}`, buf.String())
+	})
+
+	t.Run("usage leaves the sliding window", func(t *testing.T) {
+		clock := &testClock{now: start}
+		aggregator := NewAggregator(Options{Now: clock.Now, BucketDuration: time.Minute, Buckets: 5})
+
+		aggregator.Record(Client{Name: "web"}, 1, userUsage())
+		clock.now = start.Add(3 * time.Minute)
+		aggregator.Record(Client{Name: "web"}, 2, &plan.SchemaUsageInfo{
+			TypeFields: []plan.TypeFieldUsageInfo{{FieldName: "name", EnclosingTypeNames: []string{"User"}}},
+		})
+
+		clock.now = start.Add(4*time.Minute + 59*time.Second)
+		snapshot := aggregator.Snapshot()
+		require.Len(t, snapshot.Fields, 2)
+		assert.Equal(t, uint64(2), snapshot.Fields[1].Count)
+
+		clock.now = start.Add(5 * time.Minute)
+		snapshot = aggregator.Snapshot()
+		assert.Equal(t, start.Add(time.Minute), snapshot.Start)
+		require.Len(t, snapshot.Fields, 1)
+		assert.Equal(t, FieldUsage{
+			TypeName:  "User",
+			FieldName: "name",
+			Usage: Usage{
+				Count:      1,
+				LastSeen:   start.Add(3 * time.Minute),
+				Clients:    []ClientUsage{{Client: Client{Name: "web"}, Count: 1}},
+				Operations: []OperationUsage{{Hash: 2, Count: 1}},
+			},
+		}, snapshot.Fields[0])
+		assert.Empty(t, snapshot.Arguments)
+
+		clock.now = start.Add(time.Hour)
+		snapshot = aggregator.Snapshot()
+		assert.Empty(t, snapshot.Fields)
+	})
+
+	t.Run("records concurrently", func(t *testing.T) {
+		aggregator := NewAggregator(Options{})
+		wg := sync.WaitGroup{}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					aggregator.Record(Client{Name: "web"}, uint64(i), userUsage())
+					if j%10 == 0 {
+						_ = aggregator.Snapshot()
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		snapshot := aggregator.Snapshot()
+		require.Len(t, snapshot.Fields, 2)
+		assert.Equal(t, uint64(800), snapshot.Fields[0].Count)
+		assert.Len(t, snapshot.Fields[0].Operations, 8)
+	})
+}
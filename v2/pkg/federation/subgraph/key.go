@@ -0,0 +1,112 @@
+package subgraph
+
+import (
+	"fmt"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+// keySelectionSet is the parsed representation of the fields argument of a @key directive
+type keySelectionSet struct {
+	raw    string
+	fields []keyField
+}
+
+type keyField struct {
+	name string
+	// selections is set when the key field is an object, e.g. `organization { id }`
+	selections []keyField
+}
+
+func parseKeySelectionSet(typeName, selectionSet string) (*keySelectionSet, error) {
+	doc, report := plan.RequiredFieldsFragment(typeName, selectionSet, false)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("subgraph: unable to parse @key(fields: \"%s\") on type '%s': %w", selectionSet, typeName, report)
+	}
+	if len(doc.FragmentDefinitions) != 1 {
+		return nil, fmt.Errorf("subgraph: invalid @key(fields: \"%s\") on type '%s'", selectionSet, typeName)
+	}
+
+	fields, err := keyFields(doc, doc.FragmentDefinitions[0].SelectionSet)
+	if err != nil {
+		return nil, fmt.Errorf("subgraph: invalid @key(fields: \"%s\") on type '%s': %w", selectionSet, typeName, err)
+	}
+
+	return &keySelectionSet{
+		raw:    selectionSet,
+		fields: fields,
+	}, nil
+}
+
+func keyFields(doc *ast.Document, selectionSetRef int) ([]keyField, error) {
+	selectionRefs := doc.SelectionSets[selectionSetRef].SelectionRefs
+	fields := make([]keyField, 0, len(selectionRefs))
+
+	for _, selectionRef := range selectionRefs {
+		if doc.Selections[selectionRef].Kind != ast.SelectionKindField {
+			return nil, fmt.Errorf("only field selections are supported")
+		}
+
+		fieldRef := doc.Selections[selectionRef].Ref
+		field := keyField{
+			name: doc.FieldNameString(fieldRef),
+		}
+
+		if nestedSelectionSetRef, ok := doc.FieldSelectionSet(fieldRef); ok {
+			nested, err := keyFields(doc, nestedSelectionSetRef)
+			if err != nil {
+				return nil, err
+			}
+			field.selections = nested
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// satisfiedBy reports whether the representation contains a non-null value for every field of the key
+func (k *keySelectionSet) satisfiedBy(representation []byte) bool {
+	return keyFieldsSatisfiedBy(k.fields, representation)
+}
+
+func keyFieldsSatisfiedBy(fields []keyField, data []byte) bool {
+	for i := range fields {
+		value, dataType, _, err := jsonparser.Get(data, fields[i].name)
+		if err != nil || dataType == jsonparser.NotExist || dataType == jsonparser.Null {
+			return false
+		}
+
+		if len(fields[i].selections) == 0 {
+			if dataType == jsonparser.Object || dataType == jsonparser.Array {
+				return false
+			}
+			continue
+		}
+
+		switch dataType {
+		case jsonparser.Object:
+			if !keyFieldsSatisfiedBy(fields[i].selections, value) {
+				return false
+			}
+		case jsonparser.Array:
+			satisfied := true
+			_, err = jsonparser.ArrayEach(value, func(item []byte, itemType jsonparser.ValueType, _ int, _ error) {
+				if itemType != jsonparser.Object || !keyFieldsSatisfiedBy(fields[i].selections, item) {
+					satisfied = false
+				}
+			})
+			if err != nil || !satisfied {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
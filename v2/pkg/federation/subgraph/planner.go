@@ -0,0 +1,107 @@
+package subgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const (
+	serviceInput = `{"kind":"service"}`
+	// entitiesInput passes the response path of the _entities field, so errors are reported at the aliased field
+	entitiesInput = `{"kind":"entities","path":%s,"representations":{{ .arguments.representations }}}`
+)
+
+type Factory[T Configuration] struct {
+	executionContext context.Context
+	subgraph         *Subgraph
+}
+
+func (f *Factory[T]) Planner(logger abstractlogger.Logger) plan.DataSourcePlanner[T] {
+	return &Planner[T]{subgraph: f.subgraph}
+}
+
+func (f *Factory[T]) Context() context.Context {
+	return f.executionContext
+}
+
+type Planner[T Configuration] struct {
+	subgraph      *Subgraph
+	v             *plan.Visitor
+	rootField     int
+	rootFieldName string
+	rootFieldPath string
+}
+
+func (p *Planner[T]) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration[T]) (*ast.Document, bool) {
+	return nil, false
+}
+
+func (p *Planner[T]) Register(visitor *plan.Visitor, _ plan.DataSourceConfiguration[T], _ plan.DataSourcePlannerConfiguration) error {
+	p.v = visitor
+	p.rootField = ast.InvalidRef
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	return nil
+}
+
+func (p *Planner[T]) DownstreamResponseFieldAlias(_ int) (alias string, exists bool) {
+	// the subgraph DataSourcePlanner doesn't rewrite upstream fields: skip
+	return
+}
+
+func (p *Planner[T]) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: true,
+	}
+}
+
+func (p *Planner[T]) EnterField(ref int) {
+	if p.rootField != ast.InvalidRef {
+		return
+	}
+
+	fieldName := p.v.Operation.FieldNameString(ref)
+	switch fieldName {
+	case serviceFieldName, entitiesFieldName:
+		p.rootField = ref
+		p.rootFieldName = fieldName
+		p.rootFieldPath = p.v.Operation.FieldAliasOrNameString(ref)
+	}
+}
+
+func (p *Planner[T]) ConfigureFetch() resolve.FetchConfiguration {
+	if p.rootField == ast.InvalidRef {
+		p.v.Walker.StopWithInternalErr(errors.New("subgraph root field is not set"))
+		return resolve.FetchConfiguration{}
+	}
+
+	input := serviceInput
+	if p.rootFieldName == entitiesFieldName {
+		input = fmt.Sprintf(entitiesInput, strconv.Quote(p.rootFieldPath))
+	}
+
+	return resolve.FetchConfiguration{
+		Input: input,
+		DataSource: &Source{
+			subgraph: p.subgraph,
+		},
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath:   []string{"data"},
+			SelectResponseErrorsPath: []string{"errors"},
+			MergePath:                []string{p.rootFieldPath},
+		},
+	}
+}
+
+func (p *Planner[T]) ConfigureSubscription() plan.SubscriptionConfiguration {
+	// the subgraph DataSourcePlanner doesn't have subscriptions
+	return plan.SubscriptionConfiguration{}
+}
@@ -0,0 +1,34 @@
+package subgraph
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Representation is a single item of the `representations` argument of the `_entities` field
+type Representation struct {
+	// TypeName is the value of the `__typename` field of the representation
+	TypeName string
+	// Data is the raw JSON object of the representation including the key fields
+	Data json.RawMessage
+}
+
+// Unmarshal decodes the raw representation into v
+func (r Representation) Unmarshal(v any) error {
+	return json.Unmarshal(r.Data, v)
+}
+
+// EntityResolver resolves all representations of a single entity type requested by one `_entities` call.
+// The returned slice must have the same length and order as the representations.
+// An entity which could not be found should be returned as nil or as `null`.
+// Entities are not required to contain the `__typename` field, it will be added if missing.
+type EntityResolver interface {
+	ResolveEntities(ctx context.Context, representations []Representation) ([]json.RawMessage, error)
+}
+
+// EntityResolverFunc is an adapter to allow the use of ordinary functions as EntityResolver
+type EntityResolverFunc func(ctx context.Context, representations []Representation) ([]json.RawMessage, error)
+
+func (f EntityResolverFunc) ResolveEntities(ctx context.Context, representations []Representation) ([]json.RawMessage, error) {
+	return f(ctx, representations)
+}
@@ -0,0 +1,215 @@
+package subgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/buger/jsonparser"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	serviceRequestKind  = "service"
+	entitiesRequestKind = "entities"
+)
+
+var (
+	null = []byte("null")
+)
+
+type sourceInput struct {
+	Kind string `json:"kind"`
+	// Path is the response path of the _entities field, it defaults to _entities
+	Path            string            `json:"path"`
+	Representations []json.RawMessage `json:"representations"`
+}
+
+type sourceError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+type sourceOutput struct {
+	Data   any           `json:"data"`
+	Errors []sourceError `json:"errors,omitempty"`
+}
+
+type Source struct {
+	subgraph *Subgraph
+}
+
+func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error) {
+	var req sourceInput
+	if err = json.Unmarshal(input, &req); err != nil {
+		return err
+	}
+
+	switch req.Kind {
+	case serviceRequestKind:
+		return json.NewEncoder(w).Encode(sourceOutput{
+			Data: map[string]string{
+				sdlFieldName: s.subgraph.config.ServiceSDL,
+			},
+		})
+	case entitiesRequestKind:
+		path := req.Path
+		if path == "" {
+			path = entitiesFieldName
+		}
+		entities, errs := s.resolveEntities(ctx, path, req.Representations)
+		return json.NewEncoder(w).Encode(sourceOutput{
+			Data:   entities,
+			Errors: errs,
+		})
+	}
+
+	return fmt.Errorf("subgraph: unknown request kind '%s'", req.Kind)
+}
+
+// entityBatch contains all representations of a single type and their position in the representations list
+type entityBatch struct {
+	path            string
+	typeName        string
+	resolver        EntityResolver
+	positions       []int
+	representations []Representation
+}
+
+func (s *Source) resolveEntities(ctx context.Context, path string, representations []json.RawMessage) ([]json.RawMessage, []sourceError) {
+	entities := make([]json.RawMessage, len(representations))
+	var errs []sourceError
+
+	batches := make([]*entityBatch, 0, 1)
+	batchByTypeName := make(map[string]*entityBatch, 1)
+
+	for i, representation := range representations {
+		entities[i] = null
+
+		typeName, err := s.validateRepresentation(representation)
+		if err != nil {
+			errs = append(errs, entityError(path, i, err.Error()))
+			continue
+		}
+
+		batch, ok := batchByTypeName[typeName]
+		if !ok {
+			batch = &entityBatch{
+				path:     path,
+				typeName: typeName,
+				resolver: s.subgraph.config.EntityResolvers[typeName],
+			}
+			batchByTypeName[typeName] = batch
+			batches = append(batches, batch)
+		}
+
+		batch.positions = append(batch.positions, i)
+		batch.representations = append(batch.representations, Representation{
+			TypeName: typeName,
+			Data:     representation,
+		})
+	}
+
+	batchErrors := make([][]sourceError, len(batches))
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for i := range batches {
+		batch, batchIndex := batches[i], i
+		g.Go(func() error {
+			batchErrors[batchIndex] = s.resolveBatch(gCtx, batch, entities)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for i := range batchErrors {
+		errs = append(errs, batchErrors[i]...)
+	}
+
+	return entities, errs
+}
+
+// resolveBatch calls the entity resolver once for all representations of the batch
+// and writes the results into the entities list at the position of the representations
+func (s *Source) resolveBatch(ctx context.Context, batch *entityBatch, entities []json.RawMessage) (errs []sourceError) {
+	if batch.resolver == nil {
+		for _, position := range batch.positions {
+			errs = append(errs, entityError(batch.path, position, fmt.Sprintf("no entity resolver registered for type '%s'", batch.typeName)))
+		}
+		return errs
+	}
+
+	resolved, err := batch.resolver.ResolveEntities(ctx, batch.representations)
+	if err != nil {
+		for _, position := range batch.positions {
+			errs = append(errs, entityError(batch.path, position, err.Error()))
+		}
+		return errs
+	}
+
+	if len(resolved) != len(batch.representations) {
+		for _, position := range batch.positions {
+			errs = append(errs, entityError(batch.path, position, fmt.Sprintf("entity resolver for type '%s' returned %d entities for %d representations", batch.typeName, len(resolved), len(batch.representations))))
+		}
+		return errs
+	}
+
+	for i, position := range batch.positions {
+		entity, err := withTypeName(batch.typeName, resolved[i])
+		if err != nil {
+			errs = append(errs, entityError(batch.path, position, err.Error()))
+			continue
+		}
+		entities[position] = entity
+	}
+
+	return errs
+}
+
+func (s *Source) validateRepresentation(representation json.RawMessage) (typeName string, err error) {
+	typeName, err = jsonparser.GetString(representation, typeNameField)
+	if err != nil || typeName == "" {
+		return "", fmt.Errorf("representation is missing the %s field", typeNameField)
+	}
+
+	e, ok := s.subgraph.entities[typeName]
+	if !ok {
+		return "", fmt.Errorf("type '%s' is not a resolvable entity", typeName)
+	}
+
+	for _, key := range e.keys {
+		if key.satisfiedBy(representation) {
+			return typeName, nil
+		}
+	}
+
+	return "", fmt.Errorf("representation of type '%s' does not satisfy any @key", typeName)
+}
+
+func withTypeName(typeName string, entity json.RawMessage) (json.RawMessage, error) {
+	entity = bytes.TrimSpace(entity)
+	if len(entity) == 0 || bytes.Equal(entity, null) {
+		return null, nil
+	}
+
+	_, dataType, _, err := jsonparser.Get(entity)
+	if err != nil || dataType != jsonparser.Object {
+		return null, fmt.Errorf("entity resolver for type '%s' returned a non object entity", typeName)
+	}
+
+	if _, err = jsonparser.GetString(entity, typeNameField); err == nil {
+		return entity, nil
+	}
+
+	return jsonparser.Set(entity, []byte(strconv.Quote(typeName)), typeNameField)
+}
+
+func entityError(path string, position int, message string) sourceError {
+	return sourceError{
+		Message: message,
+		Path:    []any{path, position},
+	}
+}
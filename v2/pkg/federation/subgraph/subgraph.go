@@ -0,0 +1,279 @@
+// Package subgraph contains a toolkit to implement an Apollo Federation subgraph on top of the v2 engine.
+//
+// A Subgraph answers the federation root fields on behalf of the service:
+//   - `_service { sdl }` is answered with the configured service SDL
+//   - `_entities(representations: ...)` is dispatched to the EntityResolver registered for each `__typename`
+//
+// Representations are validated against the `@key` selection sets of the entity before they are handed to a resolver.
+// All representations of the same type are passed to the resolver in a single batch.
+package subgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
+)
+
+const (
+	DataSourceID = "federation.subgraph"
+
+	serviceFieldName       = "_service"
+	entitiesFieldName      = "_entities"
+	representationsArgName = "representations"
+	serviceTypeName        = "_Service"
+	entityUnionTypeName    = "_Entity"
+	sdlFieldName           = "sdl"
+	keyDirectiveName       = "key"
+	keyFieldsArgumentName  = "fields"
+	keyResolvableArgName   = "resolvable"
+	typeNameField          = "__typename"
+	defaultQueryTypeName   = "Query"
+)
+
+var (
+	ErrMissingServiceSDL = errors.New("subgraph: service sdl must not be empty")
+)
+
+type Configuration struct {
+	// BaseSchema is the executable schema of the subgraph without federation directives.
+	BaseSchema string
+	// ServiceSDL is the schema of the subgraph including federation directives.
+	// It is returned as is for `_service { sdl }` and used to extract the entity keys.
+	ServiceSDL string
+	// EntityResolvers maps an entity type name to the resolver of its representations.
+	EntityResolvers map[string]EntityResolver
+}
+
+// Subgraph holds the federation configuration of a single service.
+type Subgraph struct {
+	config        Configuration
+	queryTypeName string
+	entities      map[string]*entity
+	// childNodes contains all object types fields of the service sdl which could be selected on entities
+	childNodes plan.TypeFields
+}
+
+type entity struct {
+	typeName string
+	keys     []*keySelectionSet
+}
+
+func New(config Configuration) (*Subgraph, error) {
+	if config.ServiceSDL == "" {
+		return nil, ErrMissingServiceSDL
+	}
+
+	doc, report := astparser.ParseGraphqlDocumentString(config.ServiceSDL)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("subgraph: unable to parse service sdl: %w", report)
+	}
+
+	s := &Subgraph{
+		config:        config,
+		queryTypeName: defaultQueryTypeName,
+		entities:      make(map[string]*entity),
+	}
+
+	if err := s.collectTypes(&doc); err != nil {
+		return nil, err
+	}
+
+	for typeName := range config.EntityResolvers {
+		if _, ok := s.entities[typeName]; !ok {
+			return nil, fmt.Errorf("subgraph: entity resolver registered for type '%s' which is not a resolvable entity", typeName)
+		}
+	}
+
+	return s, nil
+}
+
+// Schema returns the federated schema of the subgraph which should be used as the engine definition
+func (s *Subgraph) Schema() (string, error) {
+	baseSchema := s.config.BaseSchema
+	if baseSchema == "" {
+		return "", errors.New("subgraph: base schema must not be empty")
+	}
+	return federation.BuildFederationSchema(baseSchema, s.config.ServiceSDL)
+}
+
+// EntityTypeNames returns the names of all resolvable entities of the subgraph
+func (s *Subgraph) EntityTypeNames() []string {
+	out := make([]string, 0, len(s.entities))
+	for i := range s.childNodes {
+		if _, ok := s.entities[s.childNodes[i].TypeName]; ok {
+			out = append(out, s.childNodes[i].TypeName)
+		}
+	}
+	return out
+}
+
+// DataSource returns the datasource configuration which resolves the `_service` and `_entities` root fields,
+// the execution context is returned by the factory of the datasource
+func (s *Subgraph) DataSource(executionContext context.Context) (plan.DataSource, error) {
+	if executionContext == nil {
+		return nil, errors.New("subgraph: execution context is required")
+	}
+
+	childNodes := make(plan.TypeFields, 0, len(s.childNodes)+2)
+	childNodes = append(childNodes, plan.TypeField{
+		TypeName:   serviceTypeName,
+		FieldNames: []string{sdlFieldName},
+	})
+	if len(s.entities) > 0 {
+		childNodes = append(childNodes, plan.TypeField{
+			TypeName:   entityUnionTypeName,
+			FieldNames: []string{typeNameField},
+		})
+	}
+	childNodes = append(childNodes, s.childNodes...)
+
+	rootFieldNames := []string{serviceFieldName}
+	if len(s.entities) > 0 {
+		rootFieldNames = append(rootFieldNames, entitiesFieldName)
+	}
+
+	return plan.NewDataSourceConfiguration[Configuration](
+		DataSourceID,
+		&Factory[Configuration]{executionContext: executionContext, subgraph: s},
+		&plan.DataSourceMetadata{
+			RootNodes: plan.TypeFields{
+				{
+					TypeName:   s.queryTypeName,
+					FieldNames: rootFieldNames,
+				},
+			},
+			ChildNodes: childNodes,
+		},
+		s.config,
+	)
+}
+
+// FieldConfigurations returns the field configurations required to plan the `_entities` field
+func (s *Subgraph) FieldConfigurations() plan.FieldConfigurations {
+	return plan.FieldConfigurations{
+		{
+			TypeName:  s.queryTypeName,
+			FieldName: entitiesFieldName,
+			Arguments: plan.ArgumentsConfigurations{
+				{
+					Name:         representationsArgName,
+					SourceType:   plan.FieldArgumentSource,
+					RenderConfig: plan.RenderArgumentAsJSONValue,
+				},
+			},
+		},
+	}
+}
+
+func (s *Subgraph) collectTypes(doc *ast.Document) error {
+	for _, node := range doc.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindSchemaDefinition, ast.NodeKindSchemaExtension:
+			s.collectQueryTypeName(doc, node)
+		}
+	}
+
+	for _, node := range doc.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition, ast.NodeKindObjectTypeExtension:
+		default:
+			continue
+		}
+
+		typeName := doc.NodeNameString(node)
+		if typeName == s.queryTypeName {
+			continue
+		}
+
+		s.addChildNodes(typeName, doc, node)
+
+		for _, directiveRef := range doc.NodeDirectives(node) {
+			if doc.DirectiveNameString(directiveRef) != keyDirectiveName {
+				continue
+			}
+			if err := s.addKey(typeName, doc, directiveRef); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Subgraph) collectQueryTypeName(doc *ast.Document, node ast.Node) {
+	var refs []int
+	switch node.Kind {
+	case ast.NodeKindSchemaDefinition:
+		refs = doc.SchemaDefinitions[node.Ref].RootOperationTypeDefinitions.Refs
+	case ast.NodeKindSchemaExtension:
+		refs = doc.SchemaExtensions[node.Ref].RootOperationTypeDefinitions.Refs
+	}
+
+	for _, ref := range refs {
+		if doc.RootOperationTypeDefinitions[ref].OperationType == ast.OperationTypeQuery {
+			s.queryTypeName = doc.Input.ByteSliceString(doc.RootOperationTypeDefinitions[ref].NamedType.Name)
+		}
+	}
+}
+
+func (s *Subgraph) addChildNodes(typeName string, doc *ast.Document, node ast.Node) {
+	fieldRefs := doc.NodeFieldDefinitions(node)
+	if len(fieldRefs) == 0 {
+		return
+	}
+
+	fieldNames := make([]string, 0, len(fieldRefs)+1)
+	for _, ref := range fieldRefs {
+		fieldNames = append(fieldNames, doc.FieldDefinitionNameString(ref))
+	}
+
+	for i := range s.childNodes {
+		if s.childNodes[i].TypeName != typeName {
+			continue
+		}
+		for _, fieldName := range fieldNames {
+			if !s.childNodes.HasNode(typeName, fieldName) {
+				s.childNodes[i].FieldNames = append(s.childNodes[i].FieldNames, fieldName)
+			}
+		}
+		return
+	}
+
+	s.childNodes = append(s.childNodes, plan.TypeField{
+		TypeName:   typeName,
+		FieldNames: fieldNames,
+	})
+}
+
+func (s *Subgraph) addKey(typeName string, doc *ast.Document, directiveRef int) error {
+	if value, ok := doc.DirectiveArgumentValueByName(directiveRef, []byte(keyResolvableArgName)); ok {
+		if value.Kind == ast.ValueKindBoolean && !bool(doc.BooleanValue(value.Ref)) {
+			// entity could not be resolved by this subgraph via this key
+			return nil
+		}
+	}
+
+	value, ok := doc.DirectiveArgumentValueByName(directiveRef, []byte(keyFieldsArgumentName))
+	if !ok || value.Kind != ast.ValueKindString {
+		return fmt.Errorf("subgraph: @key directive on type '%s' is missing the fields argument", typeName)
+	}
+
+	key, err := parseKeySelectionSet(typeName, doc.StringValueContentString(value.Ref))
+	if err != nil {
+		return err
+	}
+
+	e, ok := s.entities[typeName]
+	if !ok {
+		e = &entity{typeName: typeName}
+		s.entities[typeName] = e
+	}
+	e.keys = append(e.keys, key)
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package subgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/postprocess"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const baseSchema = `
+	type Query {
+		topProducts: [Product]
+	}
+
+	type Product {
+		upc: String!
+		sku: String!
+		name: String!
+		price: Int!
+	}
+
+	type User {
+		id: ID!
+		username: String!
+		organization: Organization!
+	}
+
+	type Organization {
+		id: ID!
+	}
+`
+
+const serviceSDL = `
+	extend type Query {
+		topProducts: [Product]
+	}
+
+	type Product @key(fields: "upc") @key(fields: "sku") {
+		upc: String!
+		sku: String!
+		name: String!
+		price: Int!
+	}
+
+	type User @key(fields: "id organization { id }") {
+		id: ID!
+		username: String!
+		organization: Organization!
+	}
+
+	type Organization @key(fields: "id", resolvable: false) {
+		id: ID!
+	}
+`
+
+type productResolver struct {
+	calls atomic.Int32
+}
+
+func (r *productResolver) ResolveEntities(_ context.Context, representations []Representation) ([]json.RawMessage, error) {
+	r.calls.Add(1)
+	out := make([]json.RawMessage, 0, len(representations))
+	for _, representation := range representations {
+		var key struct {
+			Upc string `json:"upc"`
+			Sku string `json:"sku"`
+		}
+		if err := representation.Unmarshal(&key); err != nil {
+			return nil, err
+		}
+		switch {
+		case key.Upc == "top-1" || key.Sku == "sku-1":
+			out = append(out, json.RawMessage(`{"upc":"top-1","sku":"sku-1","name":"Trilby","price":11}`))
+		case key.Upc == "top-2":
+			out = append(out, json.RawMessage(`{"__typename":"Product","upc":"top-2","sku":"sku-2","name":"Fedora","price":22}`))
+		default:
+			out = append(out, nil)
+		}
+	}
+	return out, nil
+}
+
+func newTestSubgraph(t *testing.T, resolvers map[string]EntityResolver) *Subgraph {
+	t.Helper()
+	s, err := New(Configuration{
+		BaseSchema:      baseSchema,
+		ServiceSDL:      serviceSDL,
+		EntityResolvers: resolvers,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func planOperation(t *testing.T, s *Subgraph, operation string) *plan.SynchronousResponsePlan {
+	t.Helper()
+
+	schema, err := s.Schema()
+	require.NoError(t, err)
+
+	definition := unsafeparser.ParseGraphqlDocumentString(schema)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+
+	op := unsafeparser.ParseGraphqlDocumentString(operation)
+	report := &operationreport.Report{}
+	astnormalization.NewNormalizer(true, true).NormalizeOperation(&op, &definition, report)
+	require.False(t, report.HasErrors(), report.Error())
+	astvalidation.DefaultOperationValidator().Validate(&op, &definition, report)
+	require.False(t, report.HasErrors(), report.Error())
+
+	ds, err := s.DataSource(context.Background())
+	require.NoError(t, err)
+
+	planner, err := plan.NewPlanner(plan.Configuration{
+		DataSources:                  []plan.DataSource{ds},
+		Fields:                       s.FieldConfigurations(),
+		DisableResolveFieldPositions: true,
+	})
+	require.NoError(t, err)
+
+	p := planner.Plan(&op, &definition, "", report)
+	require.False(t, report.HasErrors(), report.Error())
+	p = postprocess.DefaultProcessor().Process(p)
+
+	syncPlan, ok := p.(*plan.SynchronousResponsePlan)
+	require.True(t, ok)
+	return syncPlan
+}
+
+func execute(t *testing.T, s *Subgraph, operation, variables string) string {
+	t.Helper()
+
+	syncPlan := planOperation(t, s, operation)
+
+	ctx := resolve.NewContext(context.Background())
+	ctx.Variables = []byte(variables)
+
+	resolver := resolve.New(context.Background(), resolve.ResolverOptions{
+		MaxConcurrency:          32,
+		PropagateSubgraphErrors: true,
+	})
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, resolver.ResolveGraphQLResponse(ctx, syncPlan.Response, nil, buf))
+	return buf.String()
+}
+
+func TestNew(t *testing.T) {
+	t.Run("requires service sdl", func(t *testing.T) {
+		_, err := New(Configuration{})
+		assert.ErrorIs(t, err, ErrMissingServiceSDL)
+	})
+
+	t.Run("rejects resolver for unknown entity", func(t *testing.T) {
+		_, err := New(Configuration{
+			ServiceSDL: serviceSDL,
+			EntityResolvers: map[string]EntityResolver{
+				"Organization": &productResolver{},
+			},
+		})
+		assert.EqualError(t, err, "subgraph: entity resolver registered for type 'Organization' which is not a resolvable entity")
+	})
+
+	t.Run("collects entities", func(t *testing.T) {
+		s := newTestSubgraph(t, nil)
+		assert.Equal(t, []string{"Product", "User"}, s.EntityTypeNames())
+		assert.Len(t, s.entities["Product"].keys, 2)
+		assert.Equal(t, "id organization { id }", s.entities["User"].keys[0].raw)
+	})
+}
+
+func TestSubgraph_DataSource(t *testing.T) {
+	t.Run("requires execution context", func(t *testing.T) {
+		s := newTestSubgraph(t, nil)
+		//nolint:staticcheck // a nil context is rejected
+		_, err := s.DataSource(nil)
+		assert.EqualError(t, err, "subgraph: execution context is required")
+	})
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Run("_service sdl", func(t *testing.T) {
+		s := newTestSubgraph(t, nil)
+		out := execute(t, s, `{ _service { sdl } }`, `{}`)
+
+		expected, err := json.Marshal(serviceSDL)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf(`{"data":{"_service":{"sdl":%s}}}`, expected), out)
+	})
+
+	t.Run("_entities batches representations per type", func(t *testing.T) {
+		products := &productResolver{}
+		var users atomic.Int32
+		s := newTestSubgraph(t, map[string]EntityResolver{
+			"Product": products,
+			"User": EntityResolverFunc(func(ctx context.Context, representations []Representation) ([]json.RawMessage, error) {
+				users.Add(1)
+				out := make([]json.RawMessage, len(representations))
+				for i := range representations {
+					out[i] = json.RawMessage(`{"id":"u1","username":"Me"}`)
+				}
+				return out, nil
+			}),
+		})
+
+		out := execute(t, s,
+			`query Entities($representations: [_Any!]!) { _entities(representations: $representations) { ... on Product { upc name price } ... on User { id username } } }`,
+			`{"representations":[{"__typename":"Product","upc":"top-1"},{"__typename":"User","id":"u1","organization":{"id":"o1"}},{"__typename":"Product","upc":"top-2"},{"__typename":"Product","sku":"sku-1"}]}`,
+		)
+
+		assert.Equal(t, `{"data":{"_entities":[{"upc":"top-1","name":"Trilby","price":11},{"id":"u1","username":"Me"},{"upc":"top-2","name":"Fedora","price":22},{"upc":"top-1","name":"Trilby","price":11}]}}`, out)
+		assert.Equal(t, int32(1), products.calls.Load())
+		assert.Equal(t, int32(1), users.Load())
+	})
+
+	t.Run("_entities with alias", func(t *testing.T) {
+		s := newTestSubgraph(t, map[string]EntityResolver{
+			"Product": &productResolver{},
+		})
+
+		out := execute(t, s,
+			`query Entities($representations: [_Any!]!) { items: _entities(representations: $representations) { __typename ... on Product { name } } }`,
+			`{"representations":[{"__typename":"Product","upc":"top-2"}]}`,
+		)
+
+		assert.Equal(t, `{"data":{"items":[{"__typename":"Product","name":"Fedora"}]}}`, out)
+	})
+
+	t.Run("_entities with alias reports errors at the alias", func(t *testing.T) {
+		s := newTestSubgraph(t, nil)
+
+		syncPlan := planOperation(t, s,
+			`query Entities($representations: [_Any!]!) { items: _entities(representations: $representations) { ... on Product { name } } }`,
+		)
+
+		fetch, ok := syncPlan.Response.Data.Fetch.(*resolve.SingleFetch)
+		require.True(t, ok)
+		require.NotEmpty(t, fetch.InputTemplate.Segments)
+		assert.Equal(t, `{"kind":"entities","path":"items","representations":`, string(fetch.InputTemplate.Segments[0].Data))
+	})
+}
+
+func TestSource_Load(t *testing.T) {
+	load := func(t *testing.T, s *Subgraph, representations string) string {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		source := &Source{subgraph: s}
+		err := source.Load(context.Background(), []byte(fmt.Sprintf(`{"kind":"entities","representations":%s}`, representations)), buf)
+		require.NoError(t, err)
+		return string(bytes.TrimSpace(buf.Bytes()))
+	}
+
+	t.Run("validates representations against keys", func(t *testing.T) {
+		s := newTestSubgraph(t, map[string]EntityResolver{
+			"Product": &productResolver{},
+			"User": EntityResolverFunc(func(ctx context.Context, representations []Representation) ([]json.RawMessage, error) {
+				return make([]json.RawMessage, len(representations)), nil
+			}),
+		})
+
+		out := load(t, s, `[{"upc":"top-1"},{"__typename":"Product","name":"Trilby"},{"__typename":"User","id":"u1"},{"__typename":"User","id":"u1","organization":{"id":null}},{"__typename":"Organization","id":"o1"},{"__typename":"Product","upc":"top-1"}]`)
+
+		assert.Equal(t, `{"data":[null,null,null,null,null,{"upc":"top-1","sku":"sku-1","name":"Trilby","price":11,"__typename":"Product"}],"errors":[`+
+			`{"message":"representation is missing the __typename field","path":["_entities",0]},`+
+			`{"message":"representation of type 'Product' does not satisfy any @key","path":["_entities",1]},`+
+			`{"message":"representation of type 'User' does not satisfy any @key","path":["_entities",2]},`+
+			`{"message":"representation of type 'User' does not satisfy any @key","path":["_entities",3]},`+
+			`{"message":"type 'Organization' is not a resolvable entity","path":["_entities",4]}]}`, out)
+	})
+
+	t.Run("isolates resolver errors per type", func(t *testing.T) {
+		s := newTestSubgraph(t, map[string]EntityResolver{
+			"Product": &productResolver{},
+			"User": EntityResolverFunc(func(ctx context.Context, representations []Representation) ([]json.RawMessage, error) {
+				return nil, errors.New("users unavailable")
+			}),
+		})
+
+		out := load(t, s, `[{"__typename":"User","id":"u1","organization":{"id":"o1"}},{"__typename":"Product","upc":"top-2"}]`)
+
+		assert.Equal(t, `{"data":[null,{"__typename":"Product","upc":"top-2","sku":"sku-2","name":"Fedora","price":22}],"errors":[{"message":"users unavailable","path":["_entities",0]}]}`, out)
+	})
+
+	t.Run("reports errors at the response path", func(t *testing.T) {
+		s := newTestSubgraph(t, nil)
+		buf := &bytes.Buffer{}
+		source := &Source{subgraph: s}
+		require.NoError(t, source.Load(context.Background(), []byte(`{"kind":"entities","path":"items","representations":[{"__typename":"Product","upc":"top-1"}]}`), buf))
+
+		assert.Equal(t, `{"data":[null],"errors":[{"message":"no entity resolver registered for type 'Product'","path":["items",0]}]}`, string(bytes.TrimSpace(buf.Bytes())))
+	})
+
+	t.Run("missing resolver", func(t *testing.T) {
+		s := newTestSubgraph(t, nil)
+
+		out := load(t, s, `[{"__typename":"Product","upc":"top-1"}]`)
+
+		assert.Equal(t, `{"data":[null],"errors":[{"message":"no entity resolver registered for type 'Product'","path":["_entities",0]}]}`, out)
+	})
+}
@@ -2,6 +2,7 @@ package graphqlerrors
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
@@ -49,6 +50,20 @@ func RequestErrorsFromError(err error) RequestErrors {
 	}
 }
 
+// IsRequestError returns true if the error is caused by the request, e.g. a validation error,
+// and could be returned to the client. All other errors are internal errors, which should not be exposed.
+func IsRequestError(err error) bool {
+	var requestErrors RequestErrors
+	if errors.As(err, &requestErrors) {
+		return true
+	}
+	var report operationreport.Report
+	if errors.As(err, &report) {
+		return len(report.InternalErrors) == 0 && len(report.ExternalErrors) > 0
+	}
+	return false
+}
+
 func RequestErrorsFromOperationReport(report operationreport.Report) (errors RequestErrors) {
 	if len(report.ExternalErrors) == 0 {
 		return nil
@@ -2,6 +2,8 @@ package graphqlerrors
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -112,3 +114,15 @@ func TestOperationValidationErrors_ErrorByIndex(t *testing.T) {
 	assert.Equal(t, existingValidationError, validationErrs.ErrorByIndex(0))
 	assert.Nil(t, validationErrs.ErrorByIndex(1))
 }
+
+func TestIsRequestError(t *testing.T) {
+	assert.True(t, IsRequestError(RequestErrors{{Message: "invalid operation"}}))
+	assert.True(t, IsRequestError(fmt.Errorf("execute: %w", RequestErrors{{Message: "invalid operation"}})))
+	assert.True(t, IsRequestError(operationreport.Report{ExternalErrors: []operationreport.ExternalError{{Message: "unknown field"}}}))
+	assert.False(t, IsRequestError(operationreport.Report{
+		ExternalErrors: []operationreport.ExternalError{{Message: "unknown field"}},
+		InternalErrors: []error{errors.New("planning failed")},
+	}))
+	assert.False(t, IsRequestError(operationreport.Report{}))
+	assert.False(t, IsRequestError(errors.New("connection refused")))
+}
@@ -111,22 +111,7 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 			return NewString(nonNull)
 		}
 		if typeDefinitionNode.Kind == ast.NodeKindScalarTypeDefinition {
-			switch name {
-			case "Boolean":
-				return NewBoolean(nonNull)
-			case "String":
-				return NewString(nonNull)
-			case "ID":
-				return NewID(nonNull)
-			case "Int":
-				return NewInteger(nonNull)
-			case "Float":
-				return NewNumber(nonNull)
-			case "_Any":
-				return NewObjectAny(nonNull)
-			default:
-				return NewAny()
-			}
+			return scalarJsonSchema(name, nonNull)
 		}
 		object := NewObject(nonNull)
 		isRootObject := false
@@ -148,6 +133,8 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 		if node, ok := definition.Index.FirstNodeByNameStr(name); ok {
 			switch node.Kind {
 			case ast.NodeKindInputObjectTypeDefinition:
+				// exactly one field of a @oneOf input object must be provided with a non-null value
+				isOneOf := definition.InputObjectTypeDefinitionIsOneOf(node.Ref)
 				for _, ref := range definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs {
 					fieldName := definition.Input.ByteSliceString(definition.InputValueDefinitions[ref].Name)
 					fieldType := definition.InputValueDefinitions[ref].Type
@@ -156,6 +143,12 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 					if definition.TypeIsNonNull(fieldType) {
 						object.Required = append(object.Required, fieldName)
 					}
+					if isOneOf {
+						object.OneOf = append(object.OneOf, NewRequiredProperty(fieldName))
+					}
+				}
+				if isOneOf {
+					object.MaxProperties = 1
 				}
 			case ast.NodeKindObjectTypeDefinition:
 				for _, ref := range definition.ObjectTypeDefinitions[node.Ref].FieldsDefinition.Refs {
@@ -178,6 +171,26 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 	return NewObject(nonNull)
 }
 
+// scalarJsonSchema returns the schema of a built-in scalar, custom scalars allow any value unless they are overridden
+func scalarJsonSchema(name string, nonNull bool) JsonSchema {
+	switch name {
+	case "Boolean":
+		return NewBoolean(nonNull)
+	case "String":
+		return NewString(nonNull)
+	case "ID":
+		return NewID(nonNull)
+	case "Int":
+		return NewInteger(nonNull)
+	case "Float":
+		return NewNumber(nonNull)
+	case "_Any":
+		return NewObjectAny(nonNull)
+	default:
+		return NewAny()
+	}
+}
+
 type Validator struct {
 	schema *jsonschema.Schema
 }
@@ -266,6 +279,10 @@ const (
 	RefKind
 	NullableRefKind
 	NullKind
+	NotKind
+	RequiredPropertyKind
+	OneOfKind
+	AnyOfKind
 )
 
 func maybeAppendNull(nonNull bool, types ...string) []string {
@@ -291,6 +308,7 @@ func (a Any) Kind() Kind {
 
 type String struct {
 	Type []string `json:"type"`
+	Enum []string `json:"enum,omitempty"`
 }
 
 func (String) Kind() Kind {
@@ -303,6 +321,14 @@ func NewString(nonNull bool) String {
 	}
 }
 
+// NewEnumString returns a non-null string schema which only allows the given values
+func NewEnumString(values ...string) String {
+	return String{
+		Type: []string{"string"},
+		Enum: values,
+	}
+}
+
 type ID struct {
 	Type []string `json:"type"`
 }
@@ -378,6 +404,8 @@ type Object struct {
 	Properties           map[string]JsonSchema `json:"properties,omitempty"`
 	Required             []string              `json:"required,omitempty"`
 	AdditionalProperties bool                  `json:"additionalProperties"`
+	MaxProperties        int                   `json:"maxProperties,omitempty"`
+	OneOf                []JsonSchema          `json:"oneOf,omitempty"`
 	Defs                 map[string]JsonSchema `json:"$defs,omitempty"`
 }
 
@@ -415,6 +443,39 @@ func NewNull() Null {
 	}
 }
 
+type Not struct {
+	Not JsonSchema `json:"not"`
+}
+
+func (Not) Kind() Kind {
+	return NotKind
+}
+
+func NewNotNull() Not {
+	return Not{
+		Not: NewNull(),
+	}
+}
+
+// RequiredProperty is satisfied by objects which contain the property with a non-null value
+type RequiredProperty struct {
+	Properties map[string]JsonSchema `json:"properties"`
+	Required   []string              `json:"required"`
+}
+
+func (RequiredProperty) Kind() Kind {
+	return RequiredPropertyKind
+}
+
+func NewRequiredProperty(name string) RequiredProperty {
+	return RequiredProperty{
+		Properties: map[string]JsonSchema{
+			name: NewNotNull(),
+		},
+		Required: []string{name},
+	}
+}
+
 type NullableRef struct {
 	OneOf []JsonSchema `json:"oneOf"`
 }
@@ -432,6 +493,36 @@ func NewNullableRef(definitionName string) NullableRef {
 	}
 }
 
+// OneOf is satisfied by values which match exactly one of the schemas
+type OneOf struct {
+	OneOf []JsonSchema `json:"oneOf"`
+}
+
+func (OneOf) Kind() Kind {
+	return OneOfKind
+}
+
+func NewOneOf(schemas ...JsonSchema) OneOf {
+	return OneOf{
+		OneOf: schemas,
+	}
+}
+
+// AnyOf is satisfied by values which match at least one of the schemas
+type AnyOf struct {
+	AnyOf []JsonSchema `json:"anyOf"`
+}
+
+func (AnyOf) Kind() Kind {
+	return AnyOfKind
+}
+
+func NewAnyOf(schemas ...JsonSchema) AnyOf {
+	return AnyOf{
+		AnyOf: schemas,
+	}
+}
+
 /*
  "owner":{
         "oneOf": [
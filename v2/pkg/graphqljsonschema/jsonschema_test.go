@@ -289,6 +289,21 @@ func TestJsonSchema(t *testing.T) {
 		[]string{},
 		[]string{},
 	))
+	t.Run("oneOf input object", runTest(
+		`scalar ID scalar String input UserBy @oneOf { id: ID email: String }`,
+		`query ($input: UserBy!){}`,
+		`{"type":["object"],"properties":{"id":{"type":["string","integer","null"]},"email":{"type":["string","null"]}},"additionalProperties":false,"maxProperties":1,"oneOf":[{"properties":{"id":{"not":{"type":["null"]}}},"required":["id"]},{"properties":{"email":{"not":{"type":["null"]}}},"required":["email"]}]}`,
+		[]string{
+			`{"id":"1"}`,
+			`{"email":"a@b.c"}`,
+		},
+		[]string{
+			`{}`,
+			`{"id":null}`,
+			`{"id":"1","email":"a@b.c"}`,
+			`{"id":"1","email":null}`,
+		},
+	))
 }
 
 const complexRecursiveSchema = `
@@ -0,0 +1,307 @@
+package graphqljsonschema
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// FromOperation builds a JSON Schema of the data of a response to the named operation.
+// The operation name may be empty if the document contains a single operation.
+// The operation is expected to be valid against the definition, fragments are resolved from the operation document.
+//
+// Properties are named by the alias of a field, fields skipped by @skip or @include are optional.
+// Selections on interfaces and unions become a oneOf with one object per possible type,
+// which is discriminated by __typename if it is selected for every type, otherwise an anyOf is used.
+func FromOperation(operation, definition *ast.Document, operationName string, opts ...Option) (JsonSchema, error) {
+	appliedOptions := &options{}
+	for _, opt := range opts {
+		opt(appliedOptions)
+	}
+
+	operationRef, ok := operationByName(operation, operationName)
+	if !ok {
+		if operationName == "" {
+			return nil, fmt.Errorf("operation name is required when the document contains multiple operations")
+		}
+		return nil, fmt.Errorf("operation with name %q not found", operationName)
+	}
+
+	operationType := operation.OperationDefinitions[operationRef].OperationType
+	rootTypeName := rootOperationTypeName(definition, operationType)
+	if _, ok := definition.Index.FirstNodeByNameStr(rootTypeName); !ok {
+		return nil, fmt.Errorf("root operation type %q not found in definition", rootTypeName)
+	}
+
+	resolver := &fromOperationResolver{
+		operation:  operation,
+		definition: definition,
+		overrides:  appliedOptions.overrides,
+	}
+	var selectionSets []int
+	if operation.OperationDefinitions[operationRef].HasSelections {
+		selectionSets = append(selectionSets, operation.OperationDefinitions[operationRef].SelectionSet)
+	}
+	jsonSchema, _ := resolver.objectSchema(rootTypeName, selectionSets, true)
+	return resolveJsonSchemaPath(jsonSchema, appliedOptions.path), nil
+}
+
+func operationByName(operation *ast.Document, operationName string) (ref int, ok bool) {
+	ref, count := ast.InvalidRef, 0
+	for _, node := range operation.RootNodes {
+		if node.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		if operationName == "" {
+			ref = node.Ref
+			count++
+			continue
+		}
+		if operation.OperationDefinitionNameString(node.Ref) == operationName {
+			return node.Ref, true
+		}
+	}
+	return ref, count == 1
+}
+
+func rootOperationTypeName(definition *ast.Document, operationType ast.OperationType) string {
+	switch operationType {
+	case ast.OperationTypeMutation:
+		if len(definition.Index.MutationTypeName) != 0 {
+			return string(definition.Index.MutationTypeName)
+		}
+		return "Mutation"
+	case ast.OperationTypeSubscription:
+		if len(definition.Index.SubscriptionTypeName) != 0 {
+			return string(definition.Index.SubscriptionTypeName)
+		}
+		return "Subscription"
+	default:
+		if len(definition.Index.QueryTypeName) != 0 {
+			return string(definition.Index.QueryTypeName)
+		}
+		return "Query"
+	}
+}
+
+type fromOperationResolver struct {
+	operation, definition *ast.Document
+	overrides             map[string]JsonSchema
+}
+
+// responseField collects the fields selected under the same response key
+type responseField struct {
+	key    string
+	fields []int
+	// required is true if at least one of the fields is selected unconditionally
+	required bool
+}
+
+// objectSchema returns the schema of an object of the given type with the fields of the selection sets merged,
+// requiresTypename is true if __typename is selected unconditionally
+func (r *fromOperationResolver) objectSchema(typeName string, selectionSets []int, nonNull bool) (object Object, requiresTypename bool) {
+	object = NewObject(nonNull)
+	var fields []*responseField
+	for _, selectionSet := range selectionSets {
+		fields = r.collectFields(selectionSet, typeName, false, fields, map[string]struct{}{})
+	}
+	for _, field := range fields {
+		object.Properties[field.key] = r.fieldSchema(typeName, field)
+		if !field.required {
+			continue
+		}
+		object.Required = append(object.Required, field.key)
+		if r.operation.FieldNameString(field.fields[0]) == "__typename" {
+			requiresTypename = true
+		}
+	}
+	return object, requiresTypename
+}
+
+// collectFields adds the fields of a selection set which apply to the type, including the fields of matching fragments
+func (r *fromOperationResolver) collectFields(selectionSet int, typeName string, conditional bool, fields []*responseField, visitedFragments map[string]struct{}) []*responseField {
+	for _, selection := range r.operation.SelectionSets[selectionSet].SelectionRefs {
+		ref := r.operation.Selections[selection].Ref
+		switch r.operation.Selections[selection].Kind {
+		case ast.SelectionKindField:
+			fields = r.addField(fields, ref, conditional || r.isConditional(r.operation.Fields[ref].Directives.Refs))
+		case ast.SelectionKindInlineFragment:
+			inlineFragment := r.operation.InlineFragments[ref]
+			if !inlineFragment.HasSelections {
+				continue
+			}
+			if r.operation.InlineFragmentHasTypeCondition(ref) && !r.typeConditionMatches(typeName, r.operation.InlineFragmentTypeConditionNameString(ref)) {
+				continue
+			}
+			fields = r.collectFields(inlineFragment.SelectionSet, typeName, conditional || r.isConditional(inlineFragment.Directives.Refs), fields, visitedFragments)
+		case ast.SelectionKindFragmentSpread:
+			name := r.operation.FragmentSpreadNameString(ref)
+			if _, ok := visitedFragments[name]; ok {
+				continue
+			}
+			fragment, ok := r.operation.FragmentDefinitionRef(r.operation.FragmentSpreadNameBytes(ref))
+			if !ok || !r.operation.FragmentDefinitions[fragment].HasSelections {
+				continue
+			}
+			if !r.typeConditionMatches(typeName, r.operation.FragmentDefinitionTypeNameString(fragment)) {
+				continue
+			}
+			visitedFragments[name] = struct{}{}
+			conditional := conditional || r.isConditional(r.operation.FragmentSpreads[ref].Directives.Refs)
+			fields = r.collectFields(r.operation.FragmentDefinitions[fragment].SelectionSet, typeName, conditional, fields, visitedFragments)
+			delete(visitedFragments, name)
+		}
+	}
+	return fields
+}
+
+func (r *fromOperationResolver) addField(fields []*responseField, ref int, conditional bool) []*responseField {
+	key := r.operation.FieldAliasOrNameString(ref)
+	for _, field := range fields {
+		if field.key == key {
+			field.fields = append(field.fields, ref)
+			field.required = field.required || !conditional
+			return fields
+		}
+	}
+	return append(fields, &responseField{
+		key:      key,
+		fields:   []int{ref},
+		required: !conditional,
+	})
+}
+
+// isConditional reports whether the directives contain @skip or @include, which may remove the selection from the response
+func (r *fromOperationResolver) isConditional(directives []int) bool {
+	for _, directive := range directives {
+		switch r.operation.DirectiveNameString(directive) {
+		case "skip", "include":
+			return true
+		}
+	}
+	return false
+}
+
+// typeConditionMatches reports whether an object of the given type satisfies the type condition
+func (r *fromOperationResolver) typeConditionMatches(typeName, typeCondition string) bool {
+	if typeName == typeCondition {
+		return true
+	}
+	node, ok := r.definition.Index.FirstNodeByNameStr(typeCondition)
+	if !ok {
+		return false
+	}
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		return r.definition.TypeDefinitionContainsImplementsInterface([]byte(typeName), []byte(typeCondition))
+	case ast.NodeKindUnionTypeDefinition:
+		memberTypeNames, _ := r.definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+		for _, memberTypeName := range memberTypeNames {
+			if memberTypeName == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *fromOperationResolver) fieldSchema(enclosingTypeName string, field *responseField) JsonSchema {
+	fieldName := r.operation.FieldNameString(field.fields[0])
+	if fieldName == "__typename" {
+		return NewEnumString(enclosingTypeName)
+	}
+	node, ok := r.definition.Index.FirstNodeByNameStr(enclosingTypeName)
+	if !ok {
+		return NewAny()
+	}
+	fieldDefinition, ok := r.definition.NodeFieldDefinitionByName(node, []byte(fieldName))
+	if !ok {
+		return NewAny()
+	}
+	var selectionSets []int
+	for _, ref := range field.fields {
+		if r.operation.Fields[ref].HasSelections {
+			selectionSets = append(selectionSets, r.operation.Fields[ref].SelectionSet)
+		}
+	}
+	return r.typeSchema(r.definition.FieldDefinitions[fieldDefinition].Type, selectionSets)
+}
+
+func (r *fromOperationResolver) typeSchema(typeRef int, selectionSets []int) JsonSchema {
+	t := r.definition.Types[typeRef]
+
+	nonNull := false
+	if r.definition.TypeIsNonNull(typeRef) {
+		t = r.definition.Types[t.OfType]
+		nonNull = true
+	}
+
+	if t.TypeKind == ast.TypeKindList {
+		return NewArray(r.typeSchema(t.OfType, selectionSets), nonNull)
+	}
+
+	name := r.definition.Input.ByteSliceString(t.Name)
+	if schema, ok := r.overrides[name]; ok {
+		return schema
+	}
+	node, ok := r.definition.Index.FirstNodeByNameStr(name)
+	if !ok {
+		return NewAny()
+	}
+	switch node.Kind {
+	case ast.NodeKindEnumTypeDefinition:
+		return r.enumSchema(node.Ref, nonNull)
+	case ast.NodeKindScalarTypeDefinition:
+		return scalarJsonSchema(name, nonNull)
+	case ast.NodeKindObjectTypeDefinition:
+		object, _ := r.objectSchema(name, selectionSets, nonNull)
+		return object
+	case ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		return r.abstractSchema(node, selectionSets, nonNull)
+	}
+	return NewAny()
+}
+
+// enumSchema returns a string schema which only allows the values of the enum, a nullable enum also allows null
+func (r *fromOperationResolver) enumSchema(ref int, nonNull bool) JsonSchema {
+	valueRefs := r.definition.EnumTypeDefinitions[ref].EnumValuesDefinition.Refs
+	values := make([]string, 0, len(valueRefs))
+	for _, valueRef := range valueRefs {
+		values = append(values, r.definition.EnumValueDefinitionNameString(valueRef))
+	}
+	if nonNull {
+		return NewEnumString(values...)
+	}
+	return NewOneOf(NewEnumString(values...), NewNull())
+}
+
+// abstractSchema returns one object schema per possible type of an interface or union
+func (r *fromOperationResolver) abstractSchema(node ast.Node, selectionSets []int, nonNull bool) JsonSchema {
+	var possibleTypeNames []string
+	if node.Kind == ast.NodeKindInterfaceTypeDefinition {
+		possibleTypeNames, _ = r.definition.InterfaceTypeDefinitionImplementedByObjectWithNames(node.Ref)
+	} else {
+		possibleTypeNames, _ = r.definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+	}
+	if len(possibleTypeNames) == 0 {
+		return NewAny()
+	}
+
+	schemas := make([]JsonSchema, 0, len(possibleTypeNames)+1)
+	discriminated := true
+	for _, typeName := range possibleTypeNames {
+		object, requiresTypename := r.objectSchema(typeName, selectionSets, true)
+		if !requiresTypename {
+			discriminated = false
+		}
+		schemas = append(schemas, object)
+	}
+	if !nonNull {
+		schemas = append(schemas, NewNull())
+	}
+	if discriminated {
+		return NewOneOf(schemas...)
+	}
+	// without __typename the objects of different types may look the same, so more than one schema may match
+	return NewAnyOf(schemas...)
+}
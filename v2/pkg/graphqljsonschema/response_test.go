@@ -0,0 +1,339 @@
+package graphqljsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+)
+
+const responseTestSchema = `
+scalar DateTime
+
+schema { query: Query }
+
+type Query {
+	user(id: ID!): User
+	users: [User!]!
+	search(term: String!): [SearchResult]!
+	node(id: ID!): Node
+}
+
+interface Node { id: ID! }
+
+type User implements Node {
+	id: ID!
+	name: String!
+	nickname: String
+	role: Role!
+	previousRole: Role
+	createdAt: DateTime
+	friends: [User]
+}
+
+type Post implements Node {
+	id: ID!
+	title: String!
+	author: User!
+}
+
+enum Role { ADMIN USER }
+
+union SearchResult = User | Post
+`
+
+func runResponseTest(operation, operationName, expectedJsonSchema string, valid []string, invalid []string, opts ...Option) func(t *testing.T) {
+	return func(t *testing.T) {
+		definition := unsafeparser.ParseGraphqlDocumentString(responseTestSchema)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+		operationDoc := unsafeparser.ParseGraphqlDocumentString(operation)
+
+		jsonSchemaDefinition, err := FromOperation(&operationDoc, &definition, operationName, opts...)
+		require.NoError(t, err)
+		actualSchema, err := json.Marshal(jsonSchemaDefinition)
+		assert.NoError(t, err)
+		assert.Equal(t, prettyPrint(expectedJsonSchema), prettyPrint(string(actualSchema)))
+
+		validator, err := NewValidatorFromString(string(actualSchema))
+		require.NoError(t, err)
+
+		for _, input := range valid {
+			assert.NoError(t, validator.Validate(context.Background(), []byte(input)), "Incorrectly judged invalid: %v", input)
+		}
+
+		for _, input := range invalid {
+			assert.Error(t, validator.Validate(context.Background(), []byte(input)), "Incorrectly judged valid: %v", input)
+		}
+	}
+}
+
+func TestFromOperation(t *testing.T) {
+	t.Run("fields, aliases and nullability", runResponseTest(
+		`query User { me: user(id: 1) { id name nickname role } users { id } }`,
+		"",
+		`{
+			"type": ["object"],
+			"properties": {
+				"me": {
+					"type": ["object", "null"],
+					"properties": {
+						"id": {"type": ["string", "integer"]},
+						"name": {"type": ["string"]},
+						"nickname": {"type": ["string", "null"]},
+						"role": {"type": ["string"], "enum": ["ADMIN", "USER"]}
+					},
+					"required": ["id", "name", "nickname", "role"],
+					"additionalProperties": false
+				},
+				"users": {
+					"type": ["array"],
+					"items": {
+						"type": ["object"],
+						"properties": {"id": {"type": ["string", "integer"]}},
+						"required": ["id"],
+						"additionalProperties": false
+					}
+				}
+			},
+			"required": ["me", "users"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"me": {"id": "1", "name": "Jane", "nickname": null, "role": "ADMIN"}, "users": []}`,
+			`{"me": null, "users": [{"id": 1}]}`,
+		},
+		[]string{
+			`{"user": null, "users": []}`,
+			`{"me": null, "users": [null]}`,
+			`{"me": {"id": "1", "name": null, "nickname": null, "role": "ADMIN"}, "users": []}`,
+			`{"me": {"id": "1", "name": "Jane", "role": "ADMIN"}, "users": []}`,
+			`{"me": {"id": "1", "name": "Jane", "nickname": null, "role": "GUEST"}, "users": []}`,
+		},
+	))
+	t.Run("merged selections, fragments and conditional fields", runResponseTest(
+		`query User($withFriends: Boolean!) {
+			user(id: 1) { id ...UserFields }
+			user(id: 1) { friends @include(if: $withFriends) { name } }
+		}
+		fragment UserFields on User { name ... on Node { id } }`,
+		"User",
+		`{
+			"type": ["object"],
+			"properties": {
+				"user": {
+					"type": ["object", "null"],
+					"properties": {
+						"id": {"type": ["string", "integer"]},
+						"name": {"type": ["string"]},
+						"friends": {
+							"type": ["array", "null"],
+							"items": {
+								"type": ["object", "null"],
+								"properties": {"name": {"type": ["string"]}},
+								"required": ["name"],
+								"additionalProperties": false
+							}
+						}
+					},
+					"required": ["id", "name"],
+					"additionalProperties": false
+				}
+			},
+			"required": ["user"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"user": {"id": "1", "name": "Jane"}}`,
+			`{"user": {"id": "1", "name": "Jane", "friends": [{"name": "John"}, null]}}`,
+		},
+		[]string{
+			`{"user": {"id": "1"}}`,
+			`{"user": {"id": "1", "name": "Jane", "friends": [{"id": "2"}]}}`,
+		},
+	))
+	t.Run("abstract types with __typename", runResponseTest(
+		`{ search(term: "graphql") { __typename ... on User { name } ... on Post { title } } }`,
+		"",
+		`{
+			"type": ["object"],
+			"properties": {
+				"search": {
+					"type": ["array"],
+					"items": {
+						"oneOf": [
+							{
+								"type": ["object"],
+								"properties": {"__typename": {"type": ["string"], "enum": ["User"]}, "name": {"type": ["string"]}},
+								"required": ["__typename", "name"],
+								"additionalProperties": false
+							},
+							{
+								"type": ["object"],
+								"properties": {"__typename": {"type": ["string"], "enum": ["Post"]}, "title": {"type": ["string"]}},
+								"required": ["__typename", "title"],
+								"additionalProperties": false
+							},
+							{"type": ["null"]}
+						]
+					}
+				}
+			},
+			"required": ["search"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"search": [{"__typename": "User", "name": "Jane"}, {"__typename": "Post", "title": "GraphQL"}, null]}`,
+		},
+		[]string{
+			`{"search": [{"__typename": "User", "title": "GraphQL"}]}`,
+			`{"search": [{"__typename": "Post", "name": "Jane"}]}`,
+			`{"search": [{"name": "Jane"}]}`,
+		},
+	))
+	t.Run("abstract types without __typename", runResponseTest(
+		`{ node(id: 1) { id ... on Post { author { name } } } }`,
+		"",
+		`{
+			"type": ["object"],
+			"properties": {
+				"node": {
+					"anyOf": [
+						{
+							"type": ["object"],
+							"properties": {"id": {"type": ["string", "integer"]}},
+							"required": ["id"],
+							"additionalProperties": false
+						},
+						{
+							"type": ["object"],
+							"properties": {
+								"id": {"type": ["string", "integer"]},
+								"author": {
+									"type": ["object"],
+									"properties": {"name": {"type": ["string"]}},
+									"required": ["name"],
+									"additionalProperties": false
+								}
+							},
+							"required": ["id", "author"],
+							"additionalProperties": false
+						},
+						{"type": ["null"]}
+					]
+				}
+			},
+			"required": ["node"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"node": {"id": "1"}}`,
+			`{"node": {"id": "1", "author": {"name": "Jane"}}}`,
+			`{"node": null}`,
+		},
+		[]string{
+			`{"node": {"id": "1", "author": null}}`,
+			`{"node": {"id": "1", "name": "Jane"}}`,
+		},
+	))
+	t.Run("enums of abstract types without __typename", runResponseTest(
+		`{ search(term: "graphql") { ... on User { role previousRole } ... on Post { title } } }`,
+		"",
+		`{
+			"type": ["object"],
+			"properties": {
+				"search": {
+					"type": ["array"],
+					"items": {
+						"anyOf": [
+							{
+								"type": ["object"],
+								"properties": {
+									"role": {"type": ["string"], "enum": ["ADMIN", "USER"]},
+									"previousRole": {"oneOf": [{"type": ["string"], "enum": ["ADMIN", "USER"]}, {"type": ["null"]}]}
+								},
+								"required": ["role", "previousRole"],
+								"additionalProperties": false
+							},
+							{
+								"type": ["object"],
+								"properties": {"title": {"type": ["string"]}},
+								"required": ["title"],
+								"additionalProperties": false
+							},
+							{"type": ["null"]}
+						]
+					}
+				}
+			},
+			"required": ["search"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"search": [{"role": "ADMIN", "previousRole": null}, {"role": "USER", "previousRole": "ADMIN"}, {"title": "GraphQL"}, null]}`,
+		},
+		[]string{
+			`{"search": [{"role": "GUEST", "previousRole": null}]}`,
+			`{"search": [{"role": "ADMIN", "previousRole": "GUEST"}]}`,
+			`{"search": [{"role": null, "previousRole": null}]}`,
+		},
+	))
+	t.Run("custom scalar with override", runResponseTest(
+		`{ user(id: 1) { createdAt } }`,
+		"",
+		`{
+			"type": ["object"],
+			"properties": {
+				"user": {
+					"type": ["object", "null"],
+					"properties": {"createdAt": {"type": ["string", "null"]}},
+					"required": ["createdAt"],
+					"additionalProperties": false
+				}
+			},
+			"required": ["user"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"user": {"createdAt": "2024-01-01T00:00:00Z"}}`,
+		},
+		[]string{
+			`{"user": {"createdAt": 1704067200}}`,
+		},
+		WithOverrides(map[string]JsonSchema{
+			"DateTime": NewString(false),
+		}),
+	))
+	t.Run("sub path", runResponseTest(
+		`{ user(id: 1) { name } }`,
+		"",
+		`{
+			"type": ["object", "null"],
+			"properties": {"name": {"type": ["string"]}},
+			"required": ["name"],
+			"additionalProperties": false
+		}`,
+		[]string{
+			`{"name": "Jane"}`,
+			`null`,
+		},
+		[]string{
+			`{}`,
+		},
+		WithPath([]string{"user"}),
+	))
+
+	t.Run("missing operation", func(t *testing.T) {
+		definition := unsafeparser.ParseGraphqlDocumentString(responseTestSchema)
+		operation := unsafeparser.ParseGraphqlDocumentString(`query A { users { id } } query B { users { name } }`)
+
+		_, err := FromOperation(&operation, &definition, "")
+		assert.EqualError(t, err, "operation name is required when the document contains multiple operations")
+		_, err = FromOperation(&operation, &definition, "C")
+		assert.EqualError(t, err, `operation with name "C" not found`)
+	})
+}
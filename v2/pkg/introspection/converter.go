@@ -126,10 +126,16 @@ func (j *JsonConverter) importInputObject(fullType FullType) error {
 		return err
 	}
 
-	j.doc.ImportInputObjectTypeDefinition(
+	var directiveRefs []int
+	if fullType.IsOneOf != nil && *fullType.IsOneOf {
+		directiveRefs = append(directiveRefs, j.doc.ImportDirective(OneOfDirectiveName, nil))
+	}
+
+	j.doc.ImportInputObjectTypeDefinitionWithDirectives(
 		fullType.Name,
 		fullType.Description,
-		argRefs)
+		argRefs,
+		directiveRefs)
 
 	return nil
 }
@@ -219,8 +225,13 @@ func (j *JsonConverter) importInputField(field InputValue) (ref int, err error)
 		return -1, err
 	}
 
-	return j.doc.ImportInputValueDefinition(
-		field.Name, field.Description, typeRef, defaultValue), nil
+	var directiveRefs []int
+	if field.IsDeprecated {
+		directiveRefs = append(directiveRefs, j.importDeprecatedDirective(field.DeprecationReason))
+	}
+
+	return j.doc.ImportInputValueDefinitionWithDirectives(
+		field.Name, field.Description, typeRef, defaultValue, directiveRefs), nil
 }
 
 func (j *JsonConverter) importType(typeRef TypeRef) (ref int) {
@@ -12,6 +12,7 @@ import (
 const (
 	DeprecatedDirectiveName  = "deprecated"
 	DeprecationReasonArgName = "reason"
+	OneOfDirectiveName       = "oneOf"
 )
 
 type Generator struct {
@@ -131,6 +132,14 @@ func (i *introspectionVisitor) EnterInputValueDefinition(ref int) {
 		DefaultValue: defaultValue,
 	}
 
+	if i.definition.InputValueDefinitions[ref].HasDirectives {
+		directiveRef, exists := i.definition.InputValueDefinitionDirectiveByName(ref, []byte(DeprecatedDirectiveName))
+		if exists {
+			inputValue.IsDeprecated = true
+			inputValue.DeprecationReason = i.deprecationReason(directiveRef)
+		}
+	}
+
 	switch i.Ancestors[len(i.Ancestors)-1].Kind {
 	case ast.NodeKindInputObjectTypeDefinition:
 		i.currentType.InputFields = append(i.currentType.InputFields, inputValue)
@@ -253,6 +262,8 @@ func (i *introspectionVisitor) EnterInputObjectTypeDefinition(ref int) {
 	i.currentType.Kind = INPUTOBJECT
 	i.currentType.Name = i.definition.InputObjectTypeDefinitionNameString(ref)
 	i.currentType.Description = i.definition.InputObjectTypeDefinitionDescriptionString(ref)
+	isOneOf := i.definition.InputObjectTypeDefinitionIsOneOf(ref)
+	i.currentType.IsOneOf = &isOneOf
 }
 
 func (i *introspectionVisitor) LeaveInputObjectTypeDefinition(ref int) {
@@ -0,0 +1,539 @@
+package languageserver
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/keyword"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/token"
+)
+
+var (
+	executableKeywords = []string{"query", "mutation", "subscription", "fragment"}
+	typeSystemKeywords = []string{"type", "interface", "union", "enum", "input", "scalar", "directive", "schema", "extend"}
+)
+
+type frameKind int
+
+const (
+	frameKindSelectionSet frameKind = iota + 1
+	frameKindArguments
+	frameKindVariableDefinitions
+	// frameKindValue is an object or list value or a list type
+	frameKindValue
+	// frameKindTypeSystem is a body or argument list of a type system definition
+	frameKindTypeSystem
+)
+
+type frame struct {
+	kind frameKind
+	// typeName is the enclosing type of a selection set
+	typeName string
+	// arguments are the argument definitions of the field or directive of an argument list
+	arguments []int
+}
+
+// completionScanner reads the tokens in front of the cursor and keeps track of the selection sets and argument lists they open,
+// the tokens don't need to form a valid document
+type completionScanner struct {
+	definition *ast.Document
+	input      *ast.Input
+	tokens     []token.Token
+	frames     []frame
+	// keyword is the first name of the current top level definition, e.g. query or type
+	keyword string
+	// pendingType and pendingArguments belong to the last field or directive, they apply to the next selection set or argument list
+	pendingType      string
+	pendingArguments []int
+	// variables are the variables defined by the current operation
+	variables []string
+}
+
+// scanCompletionTokens returns the tokens in front of offset, it returns false if the offset is inside a string or comment.
+// A name the cursor is placed in or right behind is not returned because it is the prefix of the completion.
+func scanCompletionTokens(content []byte, offset int) (*ast.Input, []token.Token, bool) {
+	input := &ast.Input{}
+	input.ResetInputBytes(content)
+	lex := &lexer.Lexer{}
+	lex.SetInput(input)
+	var tokens []token.Token
+	for {
+		start := input.InputPosition
+		for start < len(input.RawBytes) && isIgnoredByte(input.RawBytes[start]) {
+			start++
+		}
+		tok := lex.Read()
+		if tok.Keyword == keyword.EOF || start >= offset {
+			return input, tokens, true
+		}
+		end := input.InputPosition
+		if tok.Keyword == keyword.COMMENT {
+			end = int(tok.Literal.End)
+		}
+		if end < offset {
+			if tok.Keyword != keyword.COMMENT {
+				tokens = append(tokens, tok)
+			}
+			continue
+		}
+		switch tok.Keyword {
+		case keyword.IDENT:
+			return input, tokens, true
+		case keyword.COMMENT, keyword.STRING, keyword.BLOCKSTRING:
+			return nil, nil, false
+		default:
+			return input, append(tokens, tok), true
+		}
+	}
+}
+
+// isIgnoredByte reports whether the lexer skips the byte between tokens
+func isIgnoredByte(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == ','
+}
+
+func newCompletionScanner(input *ast.Input, tokens []token.Token, definition *ast.Document) *completionScanner {
+	s := &completionScanner{
+		definition: definition,
+		input:      input,
+		tokens:     tokens,
+	}
+	for i := range tokens {
+		s.read(i)
+	}
+	return s
+}
+
+func (s *completionScanner) literal(i int) string {
+	if i < 0 || s.tokens[i].Keyword != keyword.IDENT {
+		return ""
+	}
+	return s.input.ByteSliceString(s.tokens[i].Literal)
+}
+
+func (s *completionScanner) keywordAt(i int) keyword.Keyword {
+	if i < 0 {
+		return keyword.UNDEFINED
+	}
+	return s.tokens[i].Keyword
+}
+
+func (s *completionScanner) top() *frame {
+	if len(s.frames) == 0 {
+		return nil
+	}
+	return &s.frames[len(s.frames)-1]
+}
+
+func (s *completionScanner) inFrame(kind frameKind) bool {
+	for i := range s.frames {
+		if s.frames[i].kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *completionScanner) isOperation() bool {
+	switch s.keyword {
+	case "", "query", "mutation", "subscription", "fragment":
+		return true
+	}
+	return false
+}
+
+func (s *completionScanner) push(kind frameKind) {
+	f := frame{kind: kind}
+	switch kind {
+	case frameKindSelectionSet:
+		f.typeName, s.pendingType = s.pendingType, ""
+	case frameKindArguments:
+		f.arguments, s.pendingArguments = s.pendingArguments, nil
+	}
+	s.frames = append(s.frames, f)
+}
+
+func (s *completionScanner) read(i int) {
+	top := s.top()
+	switch s.tokens[i].Keyword {
+	case keyword.LBRACE:
+		switch {
+		case top == nil && s.isOperation():
+			if s.keyword == "" {
+				s.pendingType = rootOperationTypeName(s.definition, ast.OperationTypeQuery)
+			}
+			s.push(frameKindSelectionSet)
+		case top == nil:
+			s.push(frameKindTypeSystem)
+		case top.kind == frameKindSelectionSet:
+			s.push(frameKindSelectionSet)
+		case top.kind == frameKindTypeSystem:
+			s.push(frameKindTypeSystem)
+		default:
+			s.push(frameKindValue)
+		}
+	case keyword.LPAREN:
+		switch {
+		case s.keywordAt(i-2) == keyword.AT && s.isOperation():
+			s.push(frameKindArguments)
+		case top == nil && s.isOperation():
+			s.push(frameKindVariableDefinitions)
+		case top != nil && top.kind == frameKindSelectionSet:
+			s.push(frameKindArguments)
+		default:
+			s.push(frameKindTypeSystem)
+		}
+	case keyword.LBRACK:
+		if top == nil || top.kind == frameKindTypeSystem {
+			s.push(frameKindTypeSystem)
+		} else {
+			s.push(frameKindValue)
+		}
+	case keyword.RBRACE, keyword.RPAREN, keyword.RBRACK:
+		if top == nil {
+			return
+		}
+		s.frames = s.frames[:len(s.frames)-1]
+		if len(s.frames) == 0 && s.tokens[i].Keyword == keyword.RBRACE {
+			s.keyword, s.variables = "", nil
+		}
+	case keyword.SPREAD:
+		if top != nil && top.kind == frameKindSelectionSet {
+			// an inline fragment without type condition keeps the enclosing type
+			s.pendingType = top.typeName
+		}
+	case keyword.IDENT:
+		s.readName(i, top)
+	}
+}
+
+func (s *completionScanner) readName(i int, top *frame) {
+	name := s.literal(i)
+	switch {
+	case s.keywordAt(i-1) == keyword.AT:
+		s.pendingArguments = nil
+		if s.definition != nil {
+			if directive, ok := s.definition.DirectiveDefinitionByName(name); ok {
+				s.pendingArguments = s.definition.DirectiveDefinitions[directive].ArgumentsDefinition.Refs
+			}
+		}
+	case s.keywordAt(i-1) == keyword.DOLLAR:
+		if top != nil && top.kind == frameKindVariableDefinitions {
+			s.variables = append(s.variables, name)
+		}
+	case s.isTypeCondition(i - 1):
+		s.pendingType = name
+	case top == nil:
+		if s.keyword == "" {
+			s.keyword = name
+		}
+		switch name {
+		case "query":
+			s.pendingType = rootOperationTypeName(s.definition, ast.OperationTypeQuery)
+		case "mutation":
+			s.pendingType = rootOperationTypeName(s.definition, ast.OperationTypeMutation)
+		case "subscription":
+			s.pendingType = rootOperationTypeName(s.definition, ast.OperationTypeSubscription)
+		}
+	case top.kind == frameKindSelectionSet && s.keywordAt(i-1) != keyword.SPREAD:
+		s.pendingType, s.pendingArguments = "", nil
+		if fieldDefinition, ok := fieldDefinitionByName(s.definition, top.typeName, name); ok {
+			s.pendingType = s.definition.FieldDefinitionTypeNameString(fieldDefinition)
+			s.pendingArguments = s.definition.FieldDefinitionArgumentsDefinitions(fieldDefinition)
+		}
+	}
+}
+
+// isTypeCondition reports whether the name at i is the "on" of an inline fragment or fragment definition
+func (s *completionScanner) isTypeCondition(i int) bool {
+	if s.literal(i) != "on" {
+		return false
+	}
+	if len(s.frames) == 0 {
+		return s.keyword == "fragment"
+	}
+	return s.keywordAt(i-1) == keyword.SPREAD
+}
+
+// completeOperation returns the completion items at the offset of an operation document
+func completeOperation(doc *document, documents []*document, definition *ast.Document, offset int) []CompletionItem {
+	input, tokens, ok := scanCompletionTokens(doc.text.content, offset)
+	if !ok {
+		return nil
+	}
+	s := newCompletionScanner(input, tokens, definition)
+	last := len(tokens) - 1
+	previous := s.keywordAt(last)
+	top := s.top()
+
+	switch {
+	case previous == keyword.AT:
+		return directiveItems(definition, true)
+	case previous == keyword.DOLLAR:
+		return variableItems(s.variables, "")
+	case s.isTypeCondition(last):
+		return typeItems(definition, isCompositeType)
+	case top == nil:
+		if previous == keyword.UNDEFINED || previous == keyword.RBRACE {
+			return keywordItems(executableKeywords)
+		}
+	case top.kind == frameKindSelectionSet:
+		if previous == keyword.SPREAD {
+			return append(fragmentItems(documents), CompletionItem{Label: "on", Kind: CompletionItemKindKeyword})
+		}
+		return fieldItems(definition, top.typeName)
+	case top.kind == frameKindArguments:
+		if previous == keyword.COLON {
+			return argumentValueItems(definition, top.arguments, s.literal(last-1), s.variables)
+		}
+		return argumentItems(definition, top.arguments)
+	case s.inFrame(frameKindVariableDefinitions):
+		if previous == keyword.COLON || previous == keyword.LBRACK {
+			return typeItems(definition, isInputType)
+		}
+	}
+	return nil
+}
+
+// completeSchema returns the completion items at the offset of a schema document
+func completeSchema(content []byte, definition *ast.Document, offset int) []CompletionItem {
+	input, tokens, ok := scanCompletionTokens(content, offset)
+	if !ok {
+		return nil
+	}
+	s := newCompletionScanner(input, tokens, definition)
+	last := len(tokens) - 1
+	previous := s.keywordAt(last)
+
+	switch {
+	case previous == keyword.AT:
+		return directiveItems(definition, false)
+	case s.literal(last) == "implements" || previous == keyword.AND:
+		return typeItems(definition, func(kind ast.NodeKind) bool {
+			return kind == ast.NodeKindInterfaceTypeDefinition
+		})
+	case s.top() == nil:
+		switch previous {
+		case keyword.EQUALS, keyword.PIPE:
+			return typeItems(definition, func(kind ast.NodeKind) bool {
+				return kind == ast.NodeKindObjectTypeDefinition
+			})
+		case keyword.UNDEFINED, keyword.RBRACE, keyword.STRING, keyword.BLOCKSTRING:
+			return keywordItems(typeSystemKeywords)
+		}
+	case previous == keyword.COLON || previous == keyword.LBRACK:
+		if s.keyword == "input" || s.keyword == "directive" || inParentheses(tokens) {
+			return typeItems(definition, isInputType)
+		}
+		return typeItems(definition, func(kind ast.NodeKind) bool {
+			return kind != ast.NodeKindInputObjectTypeDefinition
+		})
+	}
+	return nil
+}
+
+// inParentheses reports whether the tokens leave an argument list open
+func inParentheses(tokens []token.Token) bool {
+	depth := 0
+	for i := range tokens {
+		switch tokens[i].Keyword {
+		case keyword.LPAREN:
+			depth++
+		case keyword.RPAREN:
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+func keywordItems(keywords []string) []CompletionItem {
+	items := make([]CompletionItem, 0, len(keywords))
+	for _, k := range keywords {
+		items = append(items, CompletionItem{Label: k, Kind: CompletionItemKindKeyword})
+	}
+	return items
+}
+
+func fieldItems(definition *ast.Document, typeName string) []CompletionItem {
+	node, ok := typeDefinitionByName(definition, typeName)
+	if !ok {
+		return nil
+	}
+	var items []CompletionItem
+	for _, fieldDefinition := range definition.NodeFieldDefinitions(node) {
+		items = append(items, CompletionItem{
+			Label:         definition.FieldDefinitionNameString(fieldDefinition),
+			Kind:          CompletionItemKindField,
+			Detail:        printType(definition, definition.FieldDefinitionType(fieldDefinition)),
+			Documentation: definition.FieldDefinitionDescriptionString(fieldDefinition),
+		})
+	}
+	if !hasItem(items, "__typename") {
+		items = append(items, CompletionItem{Label: "__typename", Kind: CompletionItemKindField, Detail: "String!"})
+	}
+	return items
+}
+
+func hasItem(items []CompletionItem, label string) bool {
+	for i := range items {
+		if items[i].Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+func argumentItems(definition *ast.Document, arguments []int) []CompletionItem {
+	items := make([]CompletionItem, 0, len(arguments))
+	for _, argument := range arguments {
+		items = append(items, CompletionItem{
+			Label:         definition.InputValueDefinitionNameString(argument),
+			Kind:          CompletionItemKindProperty,
+			Detail:        printType(definition, definition.InputValueDefinitionType(argument)),
+			Documentation: definition.InputValueDefinitionDescriptionString(argument),
+		})
+	}
+	return items
+}
+
+// argumentValueItems offers the values of enum and boolean arguments and the variables of the operation
+func argumentValueItems(definition *ast.Document, arguments []int, argumentName string, variables []string) []CompletionItem {
+	argument, ok := inputValueDefinitionByName(definition, arguments, argumentName)
+	if !ok {
+		return variableItems(variables, "$")
+	}
+	typeName := definition.ResolveTypeNameString(definition.InputValueDefinitionType(argument))
+	var items []CompletionItem
+	if typeName == "Boolean" {
+		items = append(items,
+			CompletionItem{Label: "true", Kind: CompletionItemKindValue},
+			CompletionItem{Label: "false", Kind: CompletionItemKindValue},
+		)
+	}
+	if node, ok := typeDefinitionByName(definition, typeName); ok && node.Kind == ast.NodeKindEnumTypeDefinition {
+		for _, enumValue := range definition.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs {
+			items = append(items, CompletionItem{
+				Label:         definition.EnumValueDefinitionNameString(enumValue),
+				Kind:          CompletionItemKindEnumValue,
+				Detail:        typeName,
+				Documentation: definition.EnumValueDefinitionDescriptionString(enumValue),
+			})
+		}
+	}
+	return append(items, variableItems(variables, "$")...)
+}
+
+func variableItems(variables []string, prefix string) []CompletionItem {
+	items := make([]CompletionItem, 0, len(variables))
+	for _, variable := range variables {
+		items = append(items, CompletionItem{Label: prefix + variable, Kind: CompletionItemKindVariable})
+	}
+	return items
+}
+
+func directiveItems(definition *ast.Document, executable bool) []CompletionItem {
+	if definition == nil {
+		return nil
+	}
+	var items []CompletionItem
+	for ref := range definition.DirectiveDefinitions {
+		if !hasDirectiveLocation(definition.DirectiveDefinitions[ref].DirectiveLocations, executable) {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:         definition.DirectiveDefinitionNameString(ref),
+			Kind:          CompletionItemKindFunction,
+			Detail:        "@" + definition.DirectiveDefinitionNameString(ref) + argumentsSignature(definition, definition.DirectiveDefinitions[ref].ArgumentsDefinition.Refs),
+			Documentation: definition.DirectiveDefinitionDescriptionString(ref),
+		})
+	}
+	return items
+}
+
+// hasDirectiveLocation reports whether the directive is allowed in operations or, if executable is false, in schemas
+func hasDirectiveLocation(locations ast.DirectiveLocations, executable bool) bool {
+	first, last := ast.ExecutableDirectiveLocationQuery, ast.ExecutableDirectiveLocationVariableDefinition
+	if !executable {
+		first, last = ast.TypeSystemDirectiveLocationSchema, ast.TypeSystemDirectiveLocationInputFieldDefinition
+	}
+	for location := first; location <= last; location++ {
+		if locations.Get(location) {
+			return true
+		}
+	}
+	return false
+}
+
+func fragmentItems(documents []*document) []CompletionItem {
+	var items []CompletionItem
+	for _, doc := range documents {
+		for ref := range doc.ast.FragmentDefinitions {
+			name := doc.ast.FragmentDefinitionNameString(ref)
+			if name == "" || hasItem(items, name) {
+				continue
+			}
+			items = append(items, CompletionItem{
+				Label:  name,
+				Kind:   CompletionItemKindReference,
+				Detail: "fragment " + name + " on " + doc.ast.FragmentDefinitionTypeNameString(ref),
+			})
+		}
+	}
+	return items
+}
+
+func isCompositeType(kind ast.NodeKind) bool {
+	switch kind {
+	case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		return true
+	}
+	return false
+}
+
+func isInputType(kind ast.NodeKind) bool {
+	switch kind {
+	case ast.NodeKindScalarTypeDefinition, ast.NodeKindEnumTypeDefinition, ast.NodeKindInputObjectTypeDefinition:
+		return true
+	}
+	return false
+}
+
+// typeItems returns the types of the schema matching the filter, introspection types are left out
+func typeItems(definition *ast.Document, filter func(kind ast.NodeKind) bool) []CompletionItem {
+	if definition == nil {
+		return nil
+	}
+	var items []CompletionItem
+	for _, node := range definition.RootNodes {
+		kind, ok := typeItemKind(node.Kind)
+		if !ok || !filter(node.Kind) {
+			continue
+		}
+		name := definition.NodeNameString(node)
+		if len(name) > 1 && name[:2] == "__" {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:         name,
+			Kind:          kind,
+			Detail:        typeKeyword(node.Kind) + " " + name,
+			Documentation: nodeDescription(definition, node),
+		})
+	}
+	return items
+}
+
+func typeItemKind(kind ast.NodeKind) (CompletionItemKind, bool) {
+	switch kind {
+	case ast.NodeKindObjectTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		return CompletionItemKindClass, true
+	case ast.NodeKindInterfaceTypeDefinition:
+		return CompletionItemKindInterface, true
+	case ast.NodeKindEnumTypeDefinition:
+		return CompletionItemKindEnum, true
+	case ast.NodeKindInputObjectTypeDefinition:
+		return CompletionItemKindStruct, true
+	case ast.NodeKindScalarTypeDefinition:
+		return CompletionItemKindValue, true
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package languageserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadTestSchema loads testdata/schema.graphql and the files it imports
+func loadTestSchema(t *testing.T) *schema {
+	t.Helper()
+	s, err := loadSchema("testdata/schema.graphql", nil)
+	require.NoError(t, err)
+	require.NotNil(t, s.definition)
+	return s
+}
+
+func TestCompleteOperation(t *testing.T) {
+	s := loadTestSchema(t)
+	fragments := newDocument("file:///fragments.graphql", []byte("fragment UserFields on User { id }"), s.definition)
+
+	run := func(t *testing.T, operation string, expected []string) {
+		t.Helper()
+		offset := strings.Index(operation, "|")
+		require.NotEqual(t, -1, offset, "the operation needs a | to mark the cursor")
+		content := []byte(operation[:offset] + operation[offset+1:])
+		doc := newDocument("file:///operation.graphql", content, s.definition)
+		items := completeOperation(doc, []*document{fragments, doc}, s.definition, offset)
+		assert.Equal(t, expected, labels(items))
+	}
+
+	t.Run("keywords at the top level", func(t *testing.T) {
+		run(t, "|", []string{"query", "mutation", "subscription", "fragment"})
+		run(t, "query { user(id: 1) { id } }\nqu|", []string{"query", "mutation", "subscription", "fragment"})
+	})
+	t.Run("fields of the root operation type", func(t *testing.T) {
+		run(t, "{ | }", []string{"user", "users", "__schema", "__type", "__typename"})
+		run(t, "query Users($first: Int) { us| }", []string{"user", "users", "__schema", "__type", "__typename"})
+	})
+	t.Run("fields of the type of the enclosing field", func(t *testing.T) {
+		run(t, "{ users(first: 10) { id friends(first: 1) { | } } }", []string{"id", "name", "role", "friends", "__typename"})
+		run(t, "{ users(role: ADMIN) @include(if: true) { | } }", []string{"id", "name", "role", "friends", "__typename"})
+		run(t, "{ me: user(id: 1) { | } }", []string{"id", "name", "role", "friends", "__typename"})
+	})
+	t.Run("fields of fragments", func(t *testing.T) {
+		run(t, "fragment F on User { friends { ... on User { | } } }", []string{"id", "name", "role", "friends", "__typename"})
+		run(t, "{ user(id: 1) { ... { | } } }", []string{"id", "name", "role", "friends", "__typename"})
+	})
+	t.Run("fields of unknown types", func(t *testing.T) {
+		run(t, "{ unknown { | } }", []string{})
+	})
+	t.Run("arguments", func(t *testing.T) {
+		run(t, "{ users(|) }", []string{"role", "first"})
+		run(t, "{ users(role: ADMIN, f|) }", []string{"role", "first"})
+		run(t, "{ user(id: 1) { id @include(|) } }", []string{"if"})
+	})
+	t.Run("argument values", func(t *testing.T) {
+		run(t, "{ users(role: |) }", []string{"ADMIN", "USER"})
+		run(t, "query Users($role: Role) { users(role: |) }", []string{"ADMIN", "USER", "$role"})
+		run(t, "query Users($role: Role) { users(first: 1, role: $|) }", []string{"role"})
+		run(t, "{ user(id: 1) { id @skip(if: |) } }", []string{"true", "false"})
+	})
+	t.Run("directives", func(t *testing.T) {
+		run(t, "{ user(id: 1) @|", []string{"uppercase", "include", "skip"})
+	})
+	t.Run("fragment spreads and type conditions", func(t *testing.T) {
+		run(t, "{ user(id: 1) { ...| } }", []string{"UserFields", "on"})
+		run(t, "{ user(id: 1) { ... on | } }", []string{"Query", "User"})
+		run(t, "fragment F on |", []string{"Query", "User"})
+	})
+	t.Run("variable types", func(t *testing.T) {
+		run(t, "query Users($role: |", []string{"Role", "Int", "Float", "String", "Boolean", "ID"})
+		run(t, "query Users($roles: [|", []string{"Role", "Int", "Float", "String", "Boolean", "ID"})
+	})
+	t.Run("no completion in strings and comments", func(t *testing.T) {
+		run(t, "# a comment |\n{ id }", []string{})
+		run(t, "{ users(role: \"|\") }", []string{})
+	})
+}
+
+func TestCompleteSchema(t *testing.T) {
+	s := loadTestSchema(t)
+
+	run := func(t *testing.T, sdl string, expected []string) {
+		t.Helper()
+		offset := strings.Index(sdl, "|")
+		require.NotEqual(t, -1, offset, "the schema needs a | to mark the cursor")
+		items := completeSchema([]byte(sdl[:offset]+sdl[offset+1:]), s.definition, offset)
+		assert.Equal(t, expected, labels(items))
+	}
+
+	t.Run("keywords at the top level", func(t *testing.T) {
+		run(t, "type A { a: String }\n|", []string{"type", "interface", "union", "enum", "input", "scalar", "directive", "schema", "extend"})
+	})
+	t.Run("types of fields and arguments", func(t *testing.T) {
+		run(t, "type A { a(role: |) }", []string{"Role", "Int", "Float", "String", "Boolean", "ID"})
+		run(t, "type A { a(first: Int): [|] }", []string{"Query", "User", "Role", "Int", "Float", "String", "Boolean", "ID"})
+		run(t, "input A { a: |", []string{"Role", "Int", "Float", "String", "Boolean", "ID"})
+	})
+	t.Run("union members", func(t *testing.T) {
+		run(t, "union A = |", []string{"Query", "User"})
+	})
+	t.Run("directives", func(t *testing.T) {
+		run(t, "type A { a: String @| }", []string{"deprecated", "oneOf"})
+	})
+}
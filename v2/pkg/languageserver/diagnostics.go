@@ -0,0 +1,305 @@
+package languageserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/variablesvalidation"
+)
+
+const diagnosticSource = "graphql"
+
+var (
+	quotedNameRegex = regexp.MustCompile(`["']\$?([_A-Za-z][_0-9A-Za-z]*(?:\.[_A-Za-z][_0-9A-Za-z]*)*)["']`)
+	nameRegex       = regexp.MustCompile(`[_A-Za-z][_0-9A-Za-z]*`)
+)
+
+// variablesFilePath returns the path of the JSON file which holds the variables of the operations in an operation document,
+// e.g. the variables of query.graphql are read from query.variables.json
+func variablesFilePath(uri string) string {
+	path := uriToPath(uri)
+	if path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".variables.json"
+}
+
+// operationDiagnostics reports syntax errors of the document and, if it is syntactically valid,
+// the validation errors against the schema and of the variables in the variables file
+func operationDiagnostics(doc *document, definition *ast.Document) []Diagnostic {
+	diagnostics := reportDiagnostics(doc.report, doc.errorRange)
+	if doc.report.HasErrors() || definition == nil {
+		return diagnostics
+	}
+
+	report := operationreport.Report{}
+	astvalidation.DefaultOperationValidator().Validate(doc.ast, definition, &report)
+	diagnostics = append(diagnostics, reportDiagnostics(report, doc.errorRange)...)
+	if report.HasErrors() {
+		return diagnostics
+	}
+
+	variables, err := os.ReadFile(variablesFilePath(doc.uri))
+	if err != nil {
+		return diagnostics
+	}
+	if err := variablesvalidation.NewVariablesValidator().Validate(doc.ast, definition, variables); err != nil {
+		diagnostics = append(diagnostics, variablesDiagnostic(doc, err))
+	}
+	return diagnostics
+}
+
+// variablesDiagnostic places an error of the variables validation on the variable it mentions
+func variablesDiagnostic(doc *document, err error) Diagnostic {
+	diagnostic := Diagnostic{
+		Range:    doc.text.nameRange(0),
+		Severity: DiagnosticSeverityError,
+		Source:   diagnosticSource,
+		Message:  err.Error(),
+	}
+	var report operationreport.Report
+	if errors.As(err, &report) {
+		diagnostic.Message = externalErrorMessage(report)
+	}
+	for _, definition := range doc.ast.VariableDefinitions {
+		name := doc.ast.VariableValueNameString(definition.VariableValue.Ref)
+		if strings.Contains(diagnostic.Message, `"$`+name+`"`) {
+			nameRef := doc.ast.VariableValues[definition.VariableValue.Ref].Name
+			diagnostic.Range = doc.text.rangeOf(int(nameRef.Start)-1, int(nameRef.End))
+			break
+		}
+	}
+	return diagnostic
+}
+
+func externalErrorMessage(report operationreport.Report) string {
+	messages := make([]string, 0, len(report.ExternalErrors))
+	for _, externalError := range report.ExternalErrors {
+		messages = append(messages, externalError.Message)
+	}
+	if len(messages) == 0 {
+		return report.Error()
+	}
+	return strings.Join(messages, ", ")
+}
+
+// reportDiagnostics turns the errors of a report into diagnostics, internal errors are placed like errors without location
+func reportDiagnostics(report operationreport.Report, locate func(externalError operationreport.ExternalError) Range) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(report.ExternalErrors)+len(report.InternalErrors))
+	for _, externalError := range report.ExternalErrors {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    locate(externalError),
+			Severity: DiagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  externalError.Message,
+		})
+	}
+	for _, internalError := range report.InternalErrors {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    locate(operationreport.ExternalError{}),
+			Severity: DiagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  internalError.Error(),
+		})
+	}
+	return diagnostics
+}
+
+// locationRange returns the range of the first location of the error, lineOffset is subtracted from its line
+func locationRange(t *text, externalError operationreport.ExternalError, lineOffset int) (Range, bool) {
+	if len(externalError.Locations) == 0 {
+		return Range{}, false
+	}
+	location := externalError.Locations[0]
+	if location.Line == 0 {
+		// the parser reports unexpected ends of the document without a line
+		return t.nameRange(len(t.content)), true
+	}
+	return t.nameRange(t.locationOffset(location.Line-uint32(lineOffset), location.Column)), true
+}
+
+// messageNames returns the names quoted in an error message and all words of the message
+func messageNames(message string) (quoted, words []string) {
+	for _, match := range quotedNameRegex.FindAllStringSubmatch(message, -1) {
+		quoted = append(quoted, strings.Split(match[1], ".")...)
+	}
+	return quoted, nameRegex.FindAllString(message, -1)
+}
+
+// findOccurrence returns the first occurrence between start and end with one of the names, the names are tried in order
+func findOccurrence(occurrences []occurrence, names []string, start, end int) (occurrence, bool) {
+	for _, name := range names {
+		for _, o := range occurrences {
+			if o.symbol.name == name && o.start >= start && o.end <= end {
+				return o, true
+			}
+		}
+	}
+	return occurrence{}, false
+}
+
+// errorRange locates an error of the operation document.
+// Most validation errors have no location, their path narrows the search down to a field and the names in the message are looked up within.
+func (d *document) errorRange(externalError operationreport.ExternalError) Range {
+	if r, ok := locationRange(d.text, externalError, 0); ok {
+		return r
+	}
+	start, end, scoped := d.pathRange(externalError.Path)
+	if !scoped {
+		start, end = 0, len(d.text.content)
+	}
+	names, words := messageNames(externalError.Message)
+	if scoped {
+		// the words of the message are only looked up within a field, they would match too much of the document
+		names = append(names, words...)
+	}
+	if o, ok := findOccurrence(d.occurrences, names, start, end); ok {
+		return d.text.rangeOf(o.start, o.end)
+	}
+	return d.text.nameRange(start)
+}
+
+func (d *document) offset(p position.Position) int {
+	return d.text.locationOffset(p.LineStart, p.CharStart)
+}
+
+// pathRange returns the byte range of the operation, field or inline fragment the path of an error points to
+func (d *document) pathRange(path ast.Path) (start, end int, ok bool) {
+	if len(path) == 0 {
+		return 0, 0, false
+	}
+	var operationType ast.OperationType
+	switch string(path[0].FieldName) {
+	case "query":
+		operationType = ast.OperationTypeQuery
+	case "mutation":
+		operationType = ast.OperationTypeMutation
+	case "subscription":
+		operationType = ast.OperationTypeSubscription
+	default:
+		return 0, 0, false
+	}
+
+	operation := d.ast
+	for _, node := range operation.RootNodes {
+		if node.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		definition := operation.OperationDefinitions[node.Ref]
+		if definition.OperationType != operationType || !definition.HasSelections {
+			continue
+		}
+		if len(path) == 1 {
+			return d.offset(definition.OperationTypeLiteral), d.offset(operation.SelectionSets[definition.SelectionSet].RBrace) + 1, true
+		}
+		if start, end, ok := d.selectionSetPathRange(definition.SelectionSet, path[1:]); ok {
+			return start, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (d *document) selectionSetPathRange(selectionSet int, path ast.Path) (start, end int, ok bool) {
+	operation := d.ast
+	item := path[0]
+	for _, selection := range operation.SelectionSets[selectionSet].SelectionRefs {
+		ref := operation.Selections[selection].Ref
+		var hasSelections bool
+		var nestedSelectionSet int
+		switch operation.Selections[selection].Kind {
+		case ast.SelectionKindField:
+			field := operation.Fields[ref]
+			if item.Kind != ast.FieldName || operation.FieldAliasOrNameString(ref) != string(item.FieldName) {
+				continue
+			}
+			start, end = int(field.Name.Start), int(field.Name.End)
+			if field.Alias.IsDefined {
+				start = int(field.Alias.Name.Start)
+			}
+			hasSelections, nestedSelectionSet = field.HasSelections, field.SelectionSet
+		case ast.SelectionKindInlineFragment:
+			inlineFragment := operation.InlineFragments[ref]
+			if item.Kind != ast.InlineFragmentName || operation.InlineFragmentTypeConditionNameString(ref) != string(item.FieldName) {
+				continue
+			}
+			start = d.offset(inlineFragment.Spread)
+			end = start + len("...")
+			hasSelections, nestedSelectionSet = inlineFragment.HasSelections, inlineFragment.SelectionSet
+		default:
+			continue
+		}
+		if hasSelections {
+			end = d.offset(operation.SelectionSets[nestedSelectionSet].RBrace) + 1
+		}
+		if len(path) == 1 {
+			return start, end, true
+		}
+		if hasSelections {
+			if start, end, ok := d.selectionSetPathRange(nestedSelectionSet, path[1:]); ok {
+				return start, end, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// schemaDiagnostics returns the diagnostics of every schema file, errors of the combined schema are mapped back to the files
+func schemaDiagnostics(s *schema) map[string][]Diagnostic {
+	out := make(map[string][]Diagnostic, len(s.files))
+	for _, file := range s.files {
+		t := file.text
+		out[file.uri] = reportDiagnostics(file.report, func(externalError operationreport.ExternalError) Range {
+			if r, ok := locationRange(t, externalError, 0); ok {
+				return r
+			}
+			return t.nameRange(0)
+		})
+	}
+
+	for _, externalError := range s.report.ExternalErrors {
+		file, r := s.errorRange(externalError)
+		out[file.uri] = append(out[file.uri], Diagnostic{
+			Range:    r,
+			Severity: DiagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  externalError.Message,
+		})
+	}
+	root := s.files[0]
+	for _, internalError := range s.report.InternalErrors {
+		out[root.uri] = append(out[root.uri], Diagnostic{
+			Range:    root.text.nameRange(0),
+			Severity: DiagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  internalError.Error(),
+		})
+	}
+	return out
+}
+
+// errorRange locates an error of the combined schema in one of the schema files.
+// Errors without location are placed on the first occurrence of a name quoted in the message.
+func (s *schema) errorRange(externalError operationreport.ExternalError) (*schemaFile, Range) {
+	if len(externalError.Locations) != 0 && externalError.Locations[0].Line != 0 {
+		line := int(externalError.Locations[0].Line) - 1
+		// errors located after the last file are located in the base schema
+		if file := s.fileAtLine(line); file != nil && line < file.startLine+len(file.text.lines) {
+			r, _ := locationRange(file.text, externalError, file.startLine)
+			return file, r
+		}
+	}
+	names, _ := messageNames(externalError.Message)
+	for _, file := range s.files {
+		if o, ok := findOccurrence(file.occurrences, names, 0, len(file.text.content)); ok {
+			return file, file.text.rangeOf(o.start, o.end)
+		}
+	}
+	return s.files[0], s.files[0].text.nameRange(0)
+}
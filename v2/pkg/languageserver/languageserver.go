@@ -0,0 +1,421 @@
+// Package languageserver implements a Language Server Protocol server for GraphQL documents.
+//
+// The server loads the schema from a file and all files it imports with #import comments.
+// Every other GraphQL document opened in the editor is treated as an operation document.
+// It provides diagnostics of the parser, the validators and of the variables of operations,
+// completion, hover, go to definition and find references.
+package languageserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+const serverName = "graphql-language-server"
+
+// ErrExitWithoutShutdown is returned by Serve when the client sends exit before shutdown
+var ErrExitWithoutShutdown = errors.New("exit notification received before shutdown request")
+
+type Options struct {
+	// SchemaPath is the file the schema is loaded from, the initialization options of the client take precedence
+	SchemaPath string
+}
+
+type Server struct {
+	options    Options
+	conn       *conn
+	rootPath   string
+	schemaPath string
+	schema     *schema
+	schemaErr  error
+	// documents are the operation documents opened in the editor
+	documents map[string]*document
+	// schemaOverlay holds the content of the schema files opened in the editor
+	schemaOverlay map[string][]byte
+	// published are the uris which currently have diagnostics so they can be cleared
+	published map[string]bool
+	shutdown  bool
+}
+
+func New(options Options) *Server {
+	return &Server{
+		options:       options,
+		documents:     map[string]*document{},
+		schemaOverlay: map[string][]byte{},
+		published:     map[string]bool{},
+	}
+}
+
+// Serve handles the messages read from in and writes the responses to out until the client sends exit or in is closed
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.conn = newConn(in, out)
+	for {
+		content, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(content, &req); err != nil {
+			if err := s.conn.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+
+		result, err := s.handle(&req)
+		if req.isNotification() {
+			continue
+		}
+		if err := s.conn.reply(req.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func decodeParams(req *request, params interface{}) error {
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// handle dispatches a request, a panic while handling it is returned as an internal error so the server keeps serving
+func (s *Server) handle(req *request) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &responseError{Code: codeInternalError, Message: fmt.Sprintf("%s: %v", req.Method, r)}
+		}
+	}()
+
+	switch req.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params)
+	case "initialized":
+		return nil, s.loadSchema()
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.update(params.TextDocument.URI, []byte(params.TextDocument.Text))
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		return nil, s.update(params.TextDocument.URI, []byte(params.ContentChanges[len(params.ContentChanges)-1].Text))
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.close(params.TextDocument.URI)
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.save(params.TextDocument.URI)
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(params), nil
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params), nil
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params), nil
+	case "textDocument/references":
+		var params ReferenceParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.references(params), nil
+	}
+	if req.isNotification() {
+		// notifications the server doesn't support, e.g. $/cancelRequest, are ignored
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+}
+
+func (s *Server) initialize(params InitializeParams) (InitializeResult, error) {
+	s.rootPath = uriToPath(params.RootURI)
+	s.schemaPath = s.options.SchemaPath
+	if len(params.InitializationOptions) != 0 {
+		var options InitializationOptions
+		if err := json.Unmarshal(params.InitializationOptions, &options); err != nil {
+			return InitializeResult{}, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		if options.Schema != "" {
+			s.schemaPath = options.Schema
+		}
+	}
+	if s.schemaPath != "" && !filepath.IsAbs(s.schemaPath) && s.rootPath != "" {
+		s.schemaPath = filepath.Join(s.rootPath, s.schemaPath)
+	}
+
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    TextDocumentSyncKindFull,
+				Save:      true,
+			},
+			CompletionProvider: CompletionOptions{
+				TriggerCharacters: []string{"@", "$", ".", "(", ":", "{"},
+			},
+			HoverProvider:      true,
+			DefinitionProvider: true,
+			ReferencesProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: serverName},
+	}, nil
+}
+
+// loadSchema (re)loads the schema and publishes the diagnostics of the schema files and all operation documents
+func (s *Server) loadSchema() error {
+	if s.schemaPath == "" {
+		return nil
+	}
+	for {
+		s.schema, s.schemaErr = loadSchema(s.schemaPath, s.schemaOverlay)
+		// documents opened as operation documents become part of the schema when a schema file starts importing them
+		imported := false
+		for uri, doc := range s.documents {
+			if s.schema.file(uri) != nil {
+				s.schemaOverlay[uri] = doc.text.content
+				delete(s.documents, uri)
+				imported = true
+			}
+		}
+		if !imported {
+			break
+		}
+	}
+	if s.schemaErr != nil {
+		if err := s.conn.notify("window/showMessage", ShowMessageParams{
+			Type:    MessageTypeError,
+			Message: fmt.Sprintf("failed to load schema %s: %s", s.schemaPath, s.schemaErr),
+		}); err != nil {
+			return err
+		}
+	}
+
+	diagnostics := map[string][]Diagnostic{}
+	if s.schema != nil {
+		diagnostics = schemaDiagnostics(s.schema)
+	}
+	for uri, doc := range s.documents {
+		*doc = *newDocument(uri, doc.text.content, s.schemaDefinition())
+		diagnostics[uri] = operationDiagnostics(doc, s.schemaDefinition())
+	}
+	for uri := range s.published {
+		if _, ok := diagnostics[uri]; !ok {
+			diagnostics[uri] = nil
+		}
+	}
+	uris := make([]string, 0, len(diagnostics))
+	for uri := range diagnostics {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		if err := s.publish(uri, diagnostics[uri]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) schemaDefinition() *ast.Document {
+	if s.schema == nil {
+		return nil
+	}
+	return s.schema.definition
+}
+
+func (s *Server) isSchemaFile(uri string) bool {
+	return s.schema.file(uri) != nil || (s.schemaPath != "" && uri == pathToURI(s.schemaPath))
+}
+
+func (s *Server) update(uri string, content []byte) error {
+	uri = normalizeURI(uri)
+	if s.isSchemaFile(uri) {
+		s.schemaOverlay[uri] = content
+		return s.loadSchema()
+	}
+	delete(s.schemaOverlay, uri)
+	doc := newDocument(uri, content, s.schemaDefinition())
+	s.documents[uri] = doc
+	return s.publish(uri, operationDiagnostics(doc, s.schemaDefinition()))
+}
+
+func (s *Server) close(uri string) error {
+	uri = normalizeURI(uri)
+	if _, ok := s.schemaOverlay[uri]; ok {
+		// the schema is loaded from the disk again, unsaved changes are dropped
+		delete(s.schemaOverlay, uri)
+		return s.loadSchema()
+	}
+	delete(s.documents, uri)
+	return s.publish(uri, nil)
+}
+
+func (s *Server) save(uri string) error {
+	uri = normalizeURI(uri)
+	if s.isSchemaFile(uri) {
+		// imports might have changed
+		return s.loadSchema()
+	}
+	doc, ok := s.documents[uri]
+	if !ok {
+		return nil
+	}
+	// the variables file might have changed
+	return s.publish(uri, operationDiagnostics(doc, s.schemaDefinition()))
+}
+
+func (s *Server) publish(uri string, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	if len(diagnostics) == 0 {
+		delete(s.published, uri)
+	} else {
+		s.published[uri] = true
+	}
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+	})
+}
+
+// openDocuments returns the operation documents sorted by uri
+func (s *Server) openDocuments() []*document {
+	documents := make([]*document, 0, len(s.documents))
+	for _, doc := range s.documents {
+		documents = append(documents, doc)
+	}
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].uri < documents[j].uri
+	})
+	return documents
+}
+
+func (s *Server) completion(params TextDocumentPositionParams) CompletionList {
+	uri := normalizeURI(params.TextDocument.URI)
+	var items []CompletionItem
+	if file := s.schema.file(uri); file != nil {
+		items = completeSchema(file.text.content, s.schemaDefinition(), file.text.offset(params.Position))
+	} else if doc, ok := s.documents[uri]; ok {
+		items = completeOperation(doc, s.openDocuments(), s.schemaDefinition(), doc.text.offset(params.Position))
+	}
+	if items == nil {
+		items = []CompletionItem{}
+	}
+	return CompletionList{Items: items}
+}
+
+// occurrenceAt returns the name at the position and the text of the document it is in
+func (s *Server) occurrenceAt(params TextDocumentPositionParams) (occurrence, *text, bool) {
+	uri := normalizeURI(params.TextDocument.URI)
+	if file := s.schema.file(uri); file != nil {
+		o, ok := occurrenceAt(file.occurrences, file.text.offset(params.Position))
+		return o, file.text, ok
+	}
+	if doc, ok := s.documents[uri]; ok {
+		o, ok := occurrenceAt(doc.occurrences, doc.text.offset(params.Position))
+		return o, doc.text, ok
+	}
+	return occurrence{}, nil, false
+}
+
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	o, t, ok := s.occurrenceAt(params)
+	if !ok {
+		return nil
+	}
+	content, ok := hover(s.schemaDefinition(), s.openDocuments(), o.symbol)
+	if !ok {
+		return nil
+	}
+	r := t.rangeOf(o.start, o.end)
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: content},
+		Range:    &r,
+	}
+}
+
+// locations returns the locations of the occurrences of the symbol in the schema files and the operation documents
+func (s *Server) locations(target symbol, includeReferences, includeDefinitions bool) []Location {
+	locations := []Location{}
+	add := func(uri string, t *text, occurrences []occurrence) {
+		for _, o := range occurrences {
+			if o.symbol != target || (o.definition && !includeDefinitions) || (!o.definition && !includeReferences) {
+				continue
+			}
+			locations = append(locations, Location{URI: uri, Range: t.rangeOf(o.start, o.end)})
+		}
+	}
+	if s.schema != nil {
+		for _, file := range s.schema.files {
+			add(file.uri, file.text, file.occurrences)
+		}
+	}
+	for _, doc := range s.openDocuments() {
+		add(doc.uri, doc.text, doc.occurrences)
+	}
+	return locations
+}
+
+func (s *Server) definition(params TextDocumentPositionParams) []Location {
+	o, _, ok := s.occurrenceAt(params)
+	if !ok {
+		return []Location{}
+	}
+	return s.locations(o.symbol, false, true)
+}
+
+func (s *Server) references(params ReferenceParams) []Location {
+	o, _, ok := s.occurrenceAt(params.TextDocumentPositionParams)
+	if !ok {
+		return []Location{}
+	}
+	return s.locations(o.symbol, true, params.Context.IncludeDeclaration)
+}
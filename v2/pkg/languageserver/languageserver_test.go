@@ -0,0 +1,285 @@
+package languageserver
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient talks to a server over pipes like an editor would
+type testClient struct {
+	t             *testing.T
+	conn          *conn
+	nextID        int
+	messages      chan []byte
+	notifications []notification
+	served        chan error
+	closeInput    func() error
+}
+
+func newTestClient(t *testing.T, options Options) *testClient {
+	return newTestClientForServer(t, New(options))
+}
+
+func newTestClientForServer(t *testing.T, server *Server) *testClient {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	c := &testClient{
+		t:          t,
+		conn:       newConn(clientIn, clientOut),
+		messages:   make(chan []byte, 64),
+		served:     make(chan error, 1),
+		closeInput: clientOut.Close,
+	}
+	go func() {
+		c.served <- server.Serve(serverIn, serverOut)
+		_ = serverOut.Close()
+	}()
+	// the pipes are unbuffered, so the messages of the server are read continuously to not block it while the client writes
+	go func() {
+		defer close(c.messages)
+		for {
+			content, err := c.conn.read()
+			if err != nil {
+				return
+			}
+			c.messages <- content
+		}
+	}()
+	return c
+}
+
+// call sends a request and returns its result, notifications sent by the server in the meantime are collected
+func (c *testClient) call(method string, params interface{}, result interface{}) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id, err := json.Marshal(c.nextID)
+	require.NoError(c.t, err)
+	rawParams, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.conn.write(request{JSONRPC: "2.0", ID: id, Method: method, Params: rawParams}))
+
+	for content := range c.messages {
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		require.NoError(c.t, json.Unmarshal(content, &message))
+		if message.Method != "" {
+			c.notifications = append(c.notifications, notification{Method: message.Method, Params: message.Params})
+			continue
+		}
+		require.Equal(c.t, string(id), string(message.ID))
+		if message.Error != nil {
+			return message.Error
+		}
+		if result != nil {
+			require.NoError(c.t, json.Unmarshal(message.Result, result))
+		}
+		return nil
+	}
+	c.t.Fatal("server closed the connection")
+	return nil
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	rawParams, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.conn.write(request{JSONRPC: "2.0", Method: method, Params: rawParams}))
+}
+
+// diagnostics returns the diagnostics last published for the uri
+func (c *testClient) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	// the server handles messages in order, so all notifications have been handled once the response arrives
+	require.Nil(c.t, c.call("textDocument/hover", TextDocumentPositionParams{}, nil))
+	var diagnostics []Diagnostic
+	found := false
+	for _, n := range c.notifications {
+		if n.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		require.NoError(c.t, json.Unmarshal(n.Params.(json.RawMessage), &params))
+		if params.URI == uri {
+			diagnostics, found = params.Diagnostics, true
+		}
+	}
+	require.True(c.t, found, "no diagnostics published for %s", uri)
+	return diagnostics
+}
+
+func testdataURI(t *testing.T, path string) string {
+	absolutePath, err := filepath.Abs(filepath.Join("testdata", path))
+	require.NoError(t, err)
+	return pathToURI(absolutePath)
+}
+
+func initializeTestClient(t *testing.T) *testClient {
+	c := newTestClient(t, Options{})
+	var result InitializeResult
+	require.Nil(t, c.call("initialize", map[string]interface{}{
+		"rootUri":               testdataURI(t, ""),
+		"initializationOptions": InitializationOptions{Schema: "schema.graphql"},
+	}, &result))
+	assert.True(t, result.Capabilities.HoverProvider)
+	assert.Equal(t, TextDocumentSyncKindFull, result.Capabilities.TextDocumentSync.Change)
+	c.notify("initialized", struct{}{})
+	return c
+}
+
+func (c *testClient) open(uri, content string) {
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "graphql", Version: 1, Text: content},
+	})
+}
+
+func (c *testClient) exit() {
+	c.t.Helper()
+	require.Nil(c.t, c.call("shutdown", nil, nil))
+	c.notify("exit", nil)
+	require.NoError(c.t, <-c.served)
+}
+
+func TestServer(t *testing.T) {
+	operationURI := testdataURI(t, "operations/user.graphql")
+
+	t.Run("loads the schema with its imports and validates it", func(t *testing.T) {
+		c := initializeTestClient(t)
+		assert.Empty(t, c.diagnostics(testdataURI(t, "schema.graphql")))
+		assert.Empty(t, c.diagnostics(testdataURI(t, "types/user.graphql")))
+		c.exit()
+	})
+
+	t.Run("reports syntax and validation errors of operations", func(t *testing.T) {
+		c := initializeTestClient(t)
+		c.open(operationURI, "query {\n  user(id: 1) {\n    age\n  }\n}\nquery {")
+		diagnostics := c.diagnostics(operationURI)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, Position{Line: 5, Character: 7}, diagnostics[0].Range.Start)
+
+		c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+			TextDocument:   VersionedTextDocumentIdentifier{URI: operationURI, Version: 2},
+			ContentChanges: []TextDocumentContentChangeEvent{{Text: "query {\n  user(id: 1) {\n    age\n  }\n}"}},
+		})
+		diagnostics = c.diagnostics(operationURI)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, Range{Start: Position{Line: 2, Character: 4}, End: Position{Line: 2, Character: 7}}, diagnostics[0].Range)
+		assert.Contains(t, diagnostics[0].Message, "age")
+
+		c.notify("textDocument/didClose", DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: operationURI}})
+		assert.Empty(t, c.diagnostics(operationURI))
+		c.exit()
+	})
+
+	t.Run("validates variables from the variables file", func(t *testing.T) {
+		c := initializeTestClient(t)
+		c.open(operationURI, "query User($id: ID!) {\n  user(id: $id) {\n    name\n  }\n}")
+		diagnostics := c.diagnostics(operationURI)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, `Variable "$id" of required type "ID!" was not provided.`, diagnostics[0].Message)
+		assert.Equal(t, Range{Start: Position{Line: 0, Character: 11}, End: Position{Line: 0, Character: 14}}, diagnostics[0].Range)
+		c.exit()
+	})
+
+	t.Run("reports errors of schema files opened in the editor", func(t *testing.T) {
+		c := initializeTestClient(t)
+		userURI := testdataURI(t, "types/user.graphql")
+		c.open(userURI, "type User {\n  id: ID!\n  role: Unknown\n}\nenum Role { ADMIN }\n")
+		diagnostics := c.diagnostics(userURI)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, Position{Line: 2, Character: 8}, diagnostics[0].Range.Start)
+		assert.Empty(t, c.diagnostics(testdataURI(t, "schema.graphql")))
+		c.exit()
+	})
+
+	t.Run("completion, hover, definition and references", func(t *testing.T) {
+		c := initializeTestClient(t)
+		c.open(operationURI, "query {\n  user(id: 1) {\n    \n  }\n}")
+
+		var completion CompletionList
+		require.Nil(t, c.call("textDocument/completion", TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: operationURI},
+			Position:     Position{Line: 2, Character: 4},
+		}, &completion))
+		assert.Equal(t, []string{"id", "name", "role", "friends", "__typename"}, labels(completion.Items))
+
+		position := TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: operationURI},
+			Position:     Position{Line: 1, Character: 3},
+		}
+		var hoverResult Hover
+		require.Nil(t, c.call("textDocument/hover", position, &hoverResult))
+		assert.Equal(t, "```graphql\nQuery.user(id: ID!): User\n```\n\nLooks up a user by id", hoverResult.Contents.Value)
+
+		var definitions []Location
+		require.Nil(t, c.call("textDocument/definition", position, &definitions))
+		assert.Equal(t, []Location{{
+			URI:   testdataURI(t, "schema.graphql"),
+			Range: Range{Start: Position{Line: 7, Character: 2}, End: Position{Line: 7, Character: 6}},
+		}}, definitions)
+
+		var references []Location
+		require.Nil(t, c.call("textDocument/references", ReferenceParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: testdataURI(t, "types/user.graphql")},
+				Position:     Position{Line: 8, Character: 6},
+			},
+			Context: ReferenceContext{IncludeDeclaration: true},
+		}, &references))
+		assert.Equal(t, []Location{
+			{URI: testdataURI(t, "schema.graphql"), Range: Range{Start: Position{Line: 8, Character: 14}, End: Position{Line: 8, Character: 18}}},
+			{URI: testdataURI(t, "types/user.graphql"), Range: Range{Start: Position{Line: 8, Character: 5}, End: Position{Line: 8, Character: 9}}},
+			{URI: testdataURI(t, "types/user.graphql"), Range: Range{Start: Position{Line: 4, Character: 8}, End: Position{Line: 4, Character: 12}}},
+		}, references)
+		c.exit()
+	})
+
+	t.Run("unknown requests fail and exit without shutdown is an error", func(t *testing.T) {
+		c := newTestClient(t, Options{})
+		err := c.call("textDocument/rename", struct{}{}, nil)
+		require.NotNil(t, err)
+		assert.Equal(t, codeMethodNotFound, err.Code)
+		c.notify("exit", nil)
+		assert.Equal(t, ErrExitWithoutShutdown, <-c.served)
+	})
+
+	t.Run("a panic while handling a request is returned as internal error", func(t *testing.T) {
+		server := New(Options{})
+		// opening a document stores it in the nil map and panics
+		server.documents = nil
+		c := newTestClientForServer(t, server)
+		err := c.call("textDocument/didOpen", DidOpenTextDocumentParams{
+			TextDocument: TextDocumentItem{URI: testdataURI(t, "query.graphql"), Text: "{ user }"},
+		}, nil)
+		require.NotNil(t, err)
+		assert.Equal(t, codeInternalError, err.Code)
+		assert.Contains(t, err.Message, "textDocument/didOpen")
+
+		var hover *Hover
+		assert.Nil(t, c.call("textDocument/hover", TextDocumentPositionParams{}, &hover))
+		c.exit()
+	})
+
+	t.Run("closing the input stops the server", func(t *testing.T) {
+		c := newTestClient(t, Options{})
+		require.NoError(t, c.closeInput())
+		assert.NoError(t, <-c.served)
+	})
+}
+
+func labels(items []CompletionItem) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.Label)
+	}
+	return out
+}
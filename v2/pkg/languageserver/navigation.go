@@ -0,0 +1,458 @@
+package languageserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+type symbolKind int
+
+const (
+	symbolKindType symbolKind = iota + 1
+	symbolKindField
+	symbolKindArgument
+	symbolKindDirective
+	symbolKindDirectiveArgument
+	symbolKindFragment
+)
+
+// symbol identifies what a name in a document refers to
+type symbol struct {
+	kind symbolKind
+	// typeName is the enclosing type of fields and field arguments
+	typeName string
+	// parent is the field of a field argument or the directive of a directive argument
+	parent string
+	name   string
+}
+
+// occurrence is a name in a document, definition marks the names which declare the symbol
+type occurrence struct {
+	symbol     symbol
+	start, end int
+	definition bool
+}
+
+func occurrenceAt(occurrences []occurrence, offset int) (occurrence, bool) {
+	for _, o := range occurrences {
+		if o.start <= offset && offset <= o.end {
+			return o, true
+		}
+	}
+	return occurrence{}, false
+}
+
+type occurrenceCollector struct {
+	document    *ast.Document
+	occurrences []occurrence
+}
+
+func (c *occurrenceCollector) add(s symbol, name ast.ByteSliceReference, definition bool) {
+	if name.Length() == 0 {
+		return
+	}
+	c.occurrences = append(c.occurrences, occurrence{
+		symbol:     s,
+		start:      int(name.Start),
+		end:        int(name.End),
+		definition: definition,
+	})
+}
+
+func (c *occurrenceCollector) addNamedType(typeRef int) {
+	if typeRef == ast.InvalidRef {
+		return
+	}
+	typeRef = c.document.ResolveUnderlyingType(typeRef)
+	name := c.document.Types[typeRef].Name
+	c.add(symbol{kind: symbolKindType, name: c.document.Input.ByteSliceString(name)}, name, false)
+}
+
+func (c *occurrenceCollector) addDirectives(refs []int) {
+	for _, ref := range refs {
+		directiveName := c.document.DirectiveNameString(ref)
+		c.add(symbol{kind: symbolKindDirective, name: directiveName}, c.document.Directives[ref].Name, false)
+		for _, argument := range c.document.Directives[ref].Arguments.Refs {
+			c.add(symbol{
+				kind:   symbolKindDirectiveArgument,
+				parent: directiveName,
+				name:   c.document.ArgumentNameString(argument),
+			}, c.document.Arguments[argument].Name, false)
+		}
+	}
+}
+
+// operationOccurrences collects the names of an operation document,
+// fields and arguments are only resolved to the schema if the definition is available
+func operationOccurrences(operation, definition *ast.Document) []occurrence {
+	c := &operationOccurrenceCollector{
+		occurrenceCollector: occurrenceCollector{document: operation},
+		definition:          definition,
+	}
+	for _, node := range operation.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindOperationDefinition:
+			operationDefinition := operation.OperationDefinitions[node.Ref]
+			for _, variableDefinition := range operationDefinition.VariableDefinitions.Refs {
+				c.addNamedType(operation.VariableDefinitions[variableDefinition].Type)
+				c.addDirectives(operation.VariableDefinitions[variableDefinition].Directives.Refs)
+			}
+			c.addDirectives(operationDefinition.Directives.Refs)
+			if operationDefinition.HasSelections {
+				c.selectionSet(operationDefinition.SelectionSet, rootOperationTypeName(definition, operationDefinition.OperationType))
+			}
+		case ast.NodeKindFragmentDefinition:
+			fragment := operation.FragmentDefinitions[node.Ref]
+			c.add(symbol{kind: symbolKindFragment, name: operation.FragmentDefinitionNameString(node.Ref)}, fragment.Name, true)
+			c.addNamedType(fragment.TypeCondition.Type)
+			c.addDirectives(fragment.Directives.Refs)
+			if fragment.HasSelections {
+				c.selectionSet(fragment.SelectionSet, operation.FragmentDefinitionTypeName(node.Ref).String())
+			}
+		}
+	}
+	return c.occurrences
+}
+
+type operationOccurrenceCollector struct {
+	occurrenceCollector
+	definition *ast.Document
+}
+
+func (c *operationOccurrenceCollector) selectionSet(ref int, typeName string) {
+	operation := c.document
+	for _, selection := range operation.SelectionSets[ref].SelectionRefs {
+		switch operation.Selections[selection].Kind {
+		case ast.SelectionKindField:
+			c.field(operation.Selections[selection].Ref, typeName)
+		case ast.SelectionKindFragmentSpread:
+			spread := operation.FragmentSpreads[operation.Selections[selection].Ref]
+			c.add(symbol{kind: symbolKindFragment, name: operation.Input.ByteSliceString(spread.FragmentName)}, spread.FragmentName, false)
+			c.addDirectives(spread.Directives.Refs)
+		case ast.SelectionKindInlineFragment:
+			inlineFragment := operation.InlineFragments[operation.Selections[selection].Ref]
+			enclosingTypeName := typeName
+			if inlineFragment.TypeCondition.Type != ast.InvalidRef {
+				c.addNamedType(inlineFragment.TypeCondition.Type)
+				enclosingTypeName = operation.TypeNameString(inlineFragment.TypeCondition.Type)
+			}
+			c.addDirectives(inlineFragment.Directives.Refs)
+			if inlineFragment.HasSelections {
+				c.selectionSet(inlineFragment.SelectionSet, enclosingTypeName)
+			}
+		}
+	}
+}
+
+func (c *operationOccurrenceCollector) field(ref int, typeName string) {
+	operation := c.document
+	field := operation.Fields[ref]
+	fieldName := operation.FieldNameString(ref)
+	c.add(symbol{kind: symbolKindField, typeName: typeName, name: fieldName}, field.Name, false)
+	for _, argument := range field.Arguments.Refs {
+		c.add(symbol{
+			kind:     symbolKindArgument,
+			typeName: typeName,
+			parent:   fieldName,
+			name:     operation.ArgumentNameString(argument),
+		}, operation.Arguments[argument].Name, false)
+	}
+	c.addDirectives(field.Directives.Refs)
+	if !field.HasSelections {
+		return
+	}
+	fieldTypeName := ""
+	if fieldDefinition, ok := fieldDefinitionByName(c.definition, typeName, fieldName); ok {
+		fieldTypeName = c.definition.FieldDefinitionTypeNameString(fieldDefinition)
+	}
+	c.selectionSet(field.SelectionSet, fieldTypeName)
+}
+
+// schemaOccurrences collects the names of a schema document
+func schemaOccurrences(document *ast.Document) []occurrence {
+	c := &occurrenceCollector{document: document}
+	typeDefinition := func(name ast.ByteSliceReference, fields []int, inputFields []int, definition bool) {
+		typeName := document.Input.ByteSliceString(name)
+		c.add(symbol{kind: symbolKindType, name: typeName}, name, definition)
+		for _, field := range fields {
+			fieldName := document.FieldDefinitionNameString(field)
+			c.add(symbol{kind: symbolKindField, typeName: typeName, name: fieldName}, document.FieldDefinitions[field].Name, true)
+			for _, argument := range document.FieldDefinitions[field].ArgumentsDefinition.Refs {
+				c.add(symbol{
+					kind:     symbolKindArgument,
+					typeName: typeName,
+					parent:   fieldName,
+					name:     document.InputValueDefinitionNameString(argument),
+				}, document.InputValueDefinitions[argument].Name, true)
+			}
+		}
+		for _, field := range inputFields {
+			c.add(symbol{
+				kind:     symbolKindField,
+				typeName: typeName,
+				name:     document.InputValueDefinitionNameString(field),
+			}, document.InputValueDefinitions[field].Name, true)
+		}
+	}
+
+	for _, node := range document.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition:
+			definition := document.ObjectTypeDefinitions[node.Ref]
+			typeDefinition(definition.Name, definition.FieldsDefinition.Refs, nil, true)
+		case ast.NodeKindObjectTypeExtension:
+			definition := document.ObjectTypeExtensions[node.Ref]
+			typeDefinition(definition.Name, definition.FieldsDefinition.Refs, nil, false)
+		case ast.NodeKindInterfaceTypeDefinition:
+			definition := document.InterfaceTypeDefinitions[node.Ref]
+			typeDefinition(definition.Name, definition.FieldsDefinition.Refs, nil, true)
+		case ast.NodeKindInterfaceTypeExtension:
+			definition := document.InterfaceTypeExtensions[node.Ref]
+			typeDefinition(definition.Name, definition.FieldsDefinition.Refs, nil, false)
+		case ast.NodeKindInputObjectTypeDefinition:
+			definition := document.InputObjectTypeDefinitions[node.Ref]
+			typeDefinition(definition.Name, nil, definition.InputFieldsDefinition.Refs, true)
+		case ast.NodeKindInputObjectTypeExtension:
+			definition := document.InputObjectTypeExtensions[node.Ref]
+			typeDefinition(definition.Name, nil, definition.InputFieldsDefinition.Refs, false)
+		case ast.NodeKindUnionTypeDefinition:
+			typeDefinition(document.UnionTypeDefinitions[node.Ref].Name, nil, nil, true)
+		case ast.NodeKindUnionTypeExtension:
+			typeDefinition(document.UnionTypeExtensions[node.Ref].Name, nil, nil, false)
+		case ast.NodeKindEnumTypeDefinition:
+			typeDefinition(document.EnumTypeDefinitions[node.Ref].Name, nil, nil, true)
+		case ast.NodeKindEnumTypeExtension:
+			typeDefinition(document.EnumTypeExtensions[node.Ref].Name, nil, nil, false)
+		case ast.NodeKindScalarTypeDefinition:
+			typeDefinition(document.ScalarTypeDefinitions[node.Ref].Name, nil, nil, true)
+		case ast.NodeKindScalarTypeExtension:
+			typeDefinition(document.ScalarTypeExtensions[node.Ref].Name, nil, nil, false)
+		case ast.NodeKindDirectiveDefinition:
+			directiveName := document.DirectiveDefinitionNameString(node.Ref)
+			c.add(symbol{kind: symbolKindDirective, name: directiveName}, document.DirectiveDefinitions[node.Ref].Name, true)
+			for _, argument := range document.DirectiveDefinitions[node.Ref].ArgumentsDefinition.Refs {
+				c.add(symbol{
+					kind:   symbolKindDirectiveArgument,
+					parent: directiveName,
+					name:   document.InputValueDefinitionNameString(argument),
+				}, document.InputValueDefinitions[argument].Name, true)
+			}
+		}
+	}
+
+	for ref := range document.Types {
+		if document.Types[ref].TypeKind == ast.TypeKindNamed {
+			c.addNamedType(ref)
+		}
+	}
+	c.addDirectives(allRefs(len(document.Directives)))
+	return c.occurrences
+}
+
+func allRefs(count int) []int {
+	refs := make([]int, count)
+	for i := range refs {
+		refs[i] = i
+	}
+	return refs
+}
+
+func rootOperationTypeName(definition *ast.Document, operationType ast.OperationType) string {
+	var name ast.ByteSlice
+	if definition != nil {
+		switch operationType {
+		case ast.OperationTypeQuery:
+			name = definition.Index.QueryTypeName
+		case ast.OperationTypeMutation:
+			name = definition.Index.MutationTypeName
+		case ast.OperationTypeSubscription:
+			name = definition.Index.SubscriptionTypeName
+		}
+	}
+	if len(name) != 0 {
+		return string(name)
+	}
+	switch operationType {
+	case ast.OperationTypeMutation:
+		return "Mutation"
+	case ast.OperationTypeSubscription:
+		return "Subscription"
+	default:
+		return "Query"
+	}
+}
+
+func typeDefinitionByName(definition *ast.Document, typeName string) (ast.Node, bool) {
+	if definition == nil || typeName == "" {
+		return ast.Node{}, false
+	}
+	return definition.Index.FirstNonExtensionNodeByNameBytes([]byte(typeName))
+}
+
+func fieldDefinitionByName(definition *ast.Document, typeName, fieldName string) (int, bool) {
+	node, ok := typeDefinitionByName(definition, typeName)
+	if !ok {
+		return ast.InvalidRef, false
+	}
+	return definition.NodeFieldDefinitionByName(node, []byte(fieldName))
+}
+
+// inputValueDefinitionByName looks up an argument of a field or directive or a field of an input object type
+func inputValueDefinitionByName(definition *ast.Document, refs []int, name string) (int, bool) {
+	for _, ref := range refs {
+		if definition.InputValueDefinitionNameString(ref) == name {
+			return ref, true
+		}
+	}
+	return ast.InvalidRef, false
+}
+
+func symbolArgumentDefinitions(definition *ast.Document, s symbol) []int {
+	switch s.kind {
+	case symbolKindArgument:
+		if fieldDefinition, ok := fieldDefinitionByName(definition, s.typeName, s.parent); ok {
+			return definition.FieldDefinitionArgumentsDefinitions(fieldDefinition)
+		}
+	case symbolKindDirectiveArgument:
+		if directiveDefinition, ok := definition.DirectiveDefinitionByName(s.parent); ok {
+			return definition.DirectiveDefinitions[directiveDefinition].ArgumentsDefinition.Refs
+		}
+	}
+	return nil
+}
+
+// hover describes the symbol with its signature and description
+func hover(definition *ast.Document, documents []*document, s symbol) (string, bool) {
+	if s.kind == symbolKindFragment {
+		for _, doc := range documents {
+			for ref := range doc.ast.FragmentDefinitions {
+				if doc.ast.FragmentDefinitionNameString(ref) == s.name {
+					return markdown(fmt.Sprintf("fragment %s on %s", s.name, doc.ast.FragmentDefinitionTypeName(ref)), ""), true
+				}
+			}
+		}
+		return "", false
+	}
+	if definition == nil {
+		return "", false
+	}
+
+	switch s.kind {
+	case symbolKindType:
+		node, ok := typeDefinitionByName(definition, s.name)
+		if !ok {
+			return "", false
+		}
+		return markdown(typeKeyword(node.Kind)+" "+s.name, nodeDescription(definition, node)), true
+	case symbolKindField:
+		node, ok := typeDefinitionByName(definition, s.typeName)
+		if !ok {
+			return "", false
+		}
+		if node.Kind == ast.NodeKindInputObjectTypeDefinition {
+			inputField, ok := inputValueDefinitionByName(definition, definition.NodeInputFieldDefinitions(node), s.name)
+			if !ok {
+				return "", false
+			}
+			return markdown(s.typeName+"."+inputValueSignature(definition, inputField), definition.InputValueDefinitionDescriptionString(inputField)), true
+		}
+		fieldDefinition, ok := definition.NodeFieldDefinitionByName(node, []byte(s.name))
+		if !ok {
+			return "", false
+		}
+		return markdown(s.typeName+"."+fieldSignature(definition, fieldDefinition), definition.FieldDefinitionDescriptionString(fieldDefinition)), true
+	case symbolKindArgument, symbolKindDirectiveArgument:
+		argument, ok := inputValueDefinitionByName(definition, symbolArgumentDefinitions(definition, s), s.name)
+		if !ok {
+			return "", false
+		}
+		return markdown(inputValueSignature(definition, argument), definition.InputValueDefinitionDescriptionString(argument)), true
+	case symbolKindDirective:
+		directiveDefinition, ok := definition.DirectiveDefinitionByName(s.name)
+		if !ok {
+			return "", false
+		}
+		signature := "directive @" + s.name + argumentsSignature(definition, definition.DirectiveDefinitions[directiveDefinition].ArgumentsDefinition.Refs)
+		return markdown(signature, definition.DirectiveDefinitionDescriptionString(directiveDefinition)), true
+	}
+	return "", false
+}
+
+func markdown(signature, description string) string {
+	out := "```graphql\n" + signature + "\n```"
+	if description = strings.TrimSpace(description); description != "" {
+		out += "\n\n" + description
+	}
+	return out
+}
+
+func typeKeyword(kind ast.NodeKind) string {
+	switch kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		return "interface"
+	case ast.NodeKindUnionTypeDefinition:
+		return "union"
+	case ast.NodeKindEnumTypeDefinition:
+		return "enum"
+	case ast.NodeKindInputObjectTypeDefinition:
+		return "input"
+	case ast.NodeKindScalarTypeDefinition:
+		return "scalar"
+	default:
+		return "type"
+	}
+}
+
+func nodeDescription(definition *ast.Document, node ast.Node) string {
+	var description ast.Description
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		description = definition.ObjectTypeDefinitions[node.Ref].Description
+	case ast.NodeKindInterfaceTypeDefinition:
+		description = definition.InterfaceTypeDefinitions[node.Ref].Description
+	case ast.NodeKindUnionTypeDefinition:
+		description = definition.UnionTypeDefinitions[node.Ref].Description
+	case ast.NodeKindEnumTypeDefinition:
+		description = definition.EnumTypeDefinitions[node.Ref].Description
+	case ast.NodeKindInputObjectTypeDefinition:
+		description = definition.InputObjectTypeDefinitions[node.Ref].Description
+	case ast.NodeKindScalarTypeDefinition:
+		description = definition.ScalarTypeDefinitions[node.Ref].Description
+	}
+	if !description.IsDefined {
+		return ""
+	}
+	return definition.Input.ByteSliceString(description.Content)
+}
+
+func printType(definition *ast.Document, typeRef int) string {
+	out, err := definition.PrintTypeBytes(typeRef, nil)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+func fieldSignature(definition *ast.Document, fieldDefinition int) string {
+	return definition.FieldDefinitionNameString(fieldDefinition) +
+		argumentsSignature(definition, definition.FieldDefinitionArgumentsDefinitions(fieldDefinition)) +
+		": " + printType(definition, definition.FieldDefinitionType(fieldDefinition))
+}
+
+func argumentsSignature(definition *ast.Document, arguments []int) string {
+	if len(arguments) == 0 {
+		return ""
+	}
+	signatures := make([]string, 0, len(arguments))
+	for _, argument := range arguments {
+		signatures = append(signatures, inputValueSignature(definition, argument))
+	}
+	return "(" + strings.Join(signatures, ", ") + ")"
+}
+
+func inputValueSignature(definition *ast.Document, inputValueDefinition int) string {
+	return definition.InputValueDefinitionNameString(inputValueDefinition) + ": " +
+		printType(definition, definition.InputValueDefinitionType(inputValueDefinition))
+}
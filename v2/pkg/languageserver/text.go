@@ -0,0 +1,139 @@
+package languageserver
+
+import (
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// text converts between byte offsets, which are used by the ast, and LSP positions,
+// which count characters in UTF-16 code units
+type text struct {
+	content []byte
+	lines   []int // byte offset of the start of every line
+}
+
+func newText(content []byte) *text {
+	t := &text{
+		content: content,
+		lines:   []int{0},
+	}
+	for i, b := range content {
+		if b == '\n' {
+			t.lines = append(t.lines, i+1)
+		}
+	}
+	return t
+}
+
+func (t *text) lineEnd(line int) int {
+	if line+1 < len(t.lines) {
+		return t.lines[line+1] - 1
+	}
+	return len(t.content)
+}
+
+// offset returns the byte offset of the position, positions outside the text are clamped
+func (t *text) offset(position Position) int {
+	if position.Line < 0 {
+		return 0
+	}
+	if position.Line >= len(t.lines) {
+		return len(t.content)
+	}
+	offset, end := t.lines[position.Line], t.lineEnd(position.Line)
+	for character := 0; character < position.Character && offset < end; {
+		r, size := utf8.DecodeRune(t.content[offset:end])
+		character += utf16Length(r)
+		offset += size
+	}
+	return offset
+}
+
+func (t *text) position(offset int) Position {
+	if offset > len(t.content) {
+		offset = len(t.content)
+	}
+	line := sort.SearchInts(t.lines, offset+1) - 1
+	character := 0
+	for _, r := range string(t.content[t.lines[line]:offset]) {
+		character += utf16Length(r)
+	}
+	return Position{Line: line, Character: character}
+}
+
+// utf16Length returns the number of UTF-16 code units of the rune, runes outside the basic multilingual plane need a surrogate pair
+func utf16Length(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+func (t *text) rangeOf(start, end int) Range {
+	return Range{Start: t.position(start), End: t.position(end)}
+}
+
+// locationOffset converts the one-based line and byte column of an ast position or error location into a byte offset
+func (t *text) locationOffset(line, column uint32) int {
+	if line == 0 || int(line) > len(t.lines) {
+		return 0
+	}
+	offset := t.lines[line-1]
+	if column > 0 {
+		offset += int(column) - 1
+	}
+	if end := t.lineEnd(int(line) - 1); offset > end {
+		return end
+	}
+	return offset
+}
+
+// nameRange returns the range of the name or token starting at offset, it spans at least one character if possible
+func (t *text) nameRange(offset int) Range {
+	end := offset
+	for end < len(t.content) && isNameByte(t.content[end]) {
+		end++
+	}
+	if end == offset && end < len(t.content) && t.content[end] != '\n' && t.content[end] != '\r' {
+		_, size := utf8.DecodeRune(t.content[end:])
+		end += size
+	}
+	return t.rangeOf(offset, end)
+}
+
+func isNameByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := url.URL{Scheme: "file", Path: path}
+	return u.String()
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	path := u.Path
+	// drop the leading slash in front of windows drive letters, e.g. /C:/schema.graphql
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+// normalizeURI gives file uris a canonical form, editors are not consistent about escaping
+func normalizeURI(uri string) string {
+	if path := uriToPath(uri); path != "" {
+		return pathToURI(filepath.Clean(path))
+	}
+	return uri
+}
@@ -0,0 +1,46 @@
+package languageserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	// "é" takes two bytes and one UTF-16 code unit, "𝔾" takes four bytes and two UTF-16 code units
+	tx := newText([]byte("type A {\n  é𝔾: String\n}"))
+
+	t.Run("offset", func(t *testing.T) {
+		assert.Equal(t, 0, tx.offset(Position{Line: 0, Character: 0}))
+		assert.Equal(t, 11, tx.offset(Position{Line: 1, Character: 2}))
+		assert.Equal(t, 13, tx.offset(Position{Line: 1, Character: 3}))
+		assert.Equal(t, 17, tx.offset(Position{Line: 1, Character: 5}))
+		assert.Equal(t, 8, tx.offset(Position{Line: 0, Character: 100}), "characters behind the end of the line are clamped")
+		assert.Equal(t, len(tx.content), tx.offset(Position{Line: 10, Character: 0}))
+	})
+
+	t.Run("position", func(t *testing.T) {
+		assert.Equal(t, Position{Line: 0, Character: 5}, tx.position(5))
+		assert.Equal(t, Position{Line: 1, Character: 3}, tx.position(13))
+		assert.Equal(t, Position{Line: 1, Character: 5}, tx.position(17))
+		assert.Equal(t, Position{Line: 2, Character: 1}, tx.position(len(tx.content)))
+	})
+
+	t.Run("location offset", func(t *testing.T) {
+		assert.Equal(t, 9, tx.locationOffset(2, 1))
+		assert.Equal(t, 17, tx.locationOffset(2, 9))
+		assert.Equal(t, 0, tx.locationOffset(0, 1))
+	})
+
+	t.Run("name range", func(t *testing.T) {
+		assert.Equal(t, Range{Start: Position{Line: 0, Character: 0}, End: Position{Line: 0, Character: 4}}, tx.nameRange(0))
+		assert.Equal(t, Range{Start: Position{Line: 0, Character: 7}, End: Position{Line: 0, Character: 8}}, tx.nameRange(7))
+	})
+}
+
+func TestURI(t *testing.T) {
+	assert.Equal(t, "file:///schemas/my%20schema.graphql", pathToURI("/schemas/my schema.graphql"))
+	assert.Equal(t, "/schemas/my schema.graphql", uriToPath("file:///schemas/my%20schema.graphql"))
+	assert.Equal(t, "", uriToPath("untitled:Untitled-1"))
+	assert.Equal(t, "file:///schemas/schema.graphql", normalizeURI("file:///schemas/./types/../schema.graphql"))
+}
@@ -0,0 +1,184 @@
+package languageserver
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/imports"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// document is an operation document opened in the editor
+type document struct {
+	uri         string
+	text        *text
+	ast         *ast.Document
+	report      operationreport.Report
+	occurrences []occurrence
+}
+
+func newDocument(uri string, content []byte, definition *ast.Document) *document {
+	parsed, report := astparser.ParseGraphqlDocumentStringWithErrorRecovery(string(content))
+	d := &document{
+		uri:    uri,
+		text:   newText(content),
+		ast:    &parsed,
+		report: report,
+	}
+	d.occurrences = operationOccurrences(d.ast, definition)
+	return d
+}
+
+// schemaFile is one of the files the schema is combined from
+type schemaFile struct {
+	uri  string
+	text *text
+	// ast is parsed from the file alone, so positions are relative to the file
+	ast         *ast.Document
+	report      operationreport.Report
+	occurrences []occurrence
+	// startLine is the zero-based line the file starts at in the combined schema
+	startLine int
+}
+
+// schema is loaded from a root file and all files it imports with #import comments
+type schema struct {
+	files []*schemaFile
+	// definition is the combined schema merged with the base schema and normalized,
+	// it is nil if any of the files has errors
+	definition *ast.Document
+	// report contains the errors of the combined schema, their locations refer to the combined schema
+	report operationreport.Report
+}
+
+// loadSchema resolves the imports of the file at path and combines all files into one definition.
+// The content of files opened in the editor is taken from the overlay instead of the disk.
+func loadSchema(path string, overlay map[string][]byte) (*schema, error) {
+	paths, err := schemaFilePaths(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema{}
+	combined := &bytes.Buffer{}
+	hasSyntaxErrors := false
+	line := 0
+	for _, filePath := range paths {
+		uri := pathToURI(filePath)
+		content, ok := overlay[uri]
+		if !ok {
+			content, err = os.ReadFile(filePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		parsed, report := astparser.ParseGraphqlDocumentStringWithErrorRecovery(string(content))
+		file := &schemaFile{
+			uri:       uri,
+			text:      newText(content),
+			ast:       &parsed,
+			report:    report,
+			startLine: line,
+		}
+		file.occurrences = schemaOccurrences(file.ast)
+		s.files = append(s.files, file)
+		hasSyntaxErrors = hasSyntaxErrors || report.HasErrors()
+
+		combined.Write(content)
+		line += bytes.Count(content, []byte{'\n'})
+		if !bytes.HasSuffix(content, []byte{'\n'}) {
+			combined.WriteByte('\n')
+			line++
+		}
+	}
+
+	if hasSyntaxErrors {
+		return s, nil
+	}
+
+	definition, report := astparser.ParseGraphqlDocumentBytes(combined.Bytes())
+	if report.HasErrors() {
+		s.report = report
+		return s, nil
+	}
+	if err := asttransform.MergeDefinitionWithBaseSchema(&definition); err != nil {
+		return nil, err
+	}
+	astvalidation.DefaultDefinitionValidator().Validate(&definition, &s.report)
+	if s.report.HasErrors() {
+		return s, nil
+	}
+	astnormalization.NormalizeDefinition(&definition, &s.report)
+	if s.report.HasErrors() {
+		return s, nil
+	}
+
+	s.definition = &definition
+	return s, nil
+}
+
+// schemaFilePaths returns the absolute paths of the root file followed by the files it imports, each file only once
+func schemaFilePaths(path string) ([]string, error) {
+	scanner := imports.Scanner{}
+	root, err := scanner.ScanFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	known := map[string]struct{}{}
+	var collect func(file imports.GraphQLFile) error
+	collect = func(file imports.GraphQLFile) error {
+		if file.RelativePath != "" {
+			absolutePath, err := filepath.Abs(file.RelativePath)
+			if err != nil {
+				return err
+			}
+			if _, ok := known[absolutePath]; !ok {
+				known[absolutePath] = struct{}{}
+				paths = append(paths, absolutePath)
+			}
+		}
+		for _, imported := range file.Imports {
+			if err := collect(imported); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := collect(*root); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (s *schema) file(uri string) *schemaFile {
+	if s == nil {
+		return nil
+	}
+	for _, file := range s.files {
+		if file.uri == uri {
+			return file
+		}
+	}
+	return nil
+}
+
+// fileAtLine returns the file containing the zero-based line of the combined schema
+func (s *schema) fileAtLine(line int) *schemaFile {
+	var found *schemaFile
+	for _, file := range s.files {
+		if file.startLine > line {
+			break
+		}
+		found = file
+	}
+	return found
+}
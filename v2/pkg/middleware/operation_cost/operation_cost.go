@@ -0,0 +1,661 @@
+/*
+package operation_cost implements the cost analysis of the GraphQL cost directive specification.
+
+OperationCostCalculator takes a schema definition and an operation and calculates two values:
+
+1. Estimated cost, the upper bound of the cost calculated from the operation before it is executed
+2. Actual cost, the cost calculated from the data of the response after the operation was executed
+
+The cost is configured in the schema with these two directives, see DirectiveDefinitions:
+
+- directive @cost(weight: String!) on ARGUMENT_DEFINITION | ENUM | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | OBJECT | SCALAR
+- directive @listSize(assumedSize: Int, slicingArguments: [String!], sizedFields: [String!], requireOneSlicingArgument: Boolean = true) on FIELD_DEFINITION
+
+cost:
+The weight of a field, a type or an argument. Fields returning a composite type have a default weight of 1,
+fields returning a leaf type and arguments have a default weight of 0. The weight of a field takes precedence over the weight of its type.
+
+listSize:
+The number of items returned by a list field. The size is the value of the slicing arguments,
+otherwise the assumed size, otherwise Config.DefaultListSize. The size is applied to the sized fields instead of the field itself,
+e.g. to the edges of a connection.
+
+The cost of a field is the cost of its arguments plus the weight of the field and the cost of its selections,
+multiplied by the number of items if the field returns a list.
+For abstract types the estimated cost is the maximum cost of the possible types.
+*/
+package operation_cost
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// DirectiveDefinitions contains the definitions of the @cost and @listSize directives, which must be part of the schema
+const DirectiveDefinitions = `
+directive @cost(weight: String!) on ARGUMENT_DEFINITION | ENUM | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | OBJECT | SCALAR
+directive @listSize(assumedSize: Int, slicingArguments: [String!], sizedFields: [String!], requireOneSlicingArgument: Boolean = true) on FIELD_DEFINITION
+`
+
+const DefaultListSize = 10
+
+var (
+	costDirectiveName     = []byte("cost")
+	listSizeDirectiveName = []byte("listSize")
+
+	weightArgumentName                    = []byte("weight")
+	assumedSizeArgumentName               = []byte("assumedSize")
+	slicingArgumentsArgumentName          = []byte("slicingArguments")
+	sizedFieldsArgumentName               = []byte("sizedFields")
+	requireOneSlicingArgumentArgumentName = []byte("requireOneSlicingArgument")
+
+	typeNameFieldName = []byte("__typename")
+)
+
+type Config struct {
+	// DefaultListSize is the size of list fields without the @listSize directive, defaults to DefaultListSize
+	DefaultListSize int
+}
+
+type OperationCostCalculator struct {
+	config Config
+}
+
+func NewOperationCostCalculator(config Config) *OperationCostCalculator {
+	if config.DefaultListSize <= 0 {
+		config.DefaultListSize = DefaultListSize
+	}
+	return &OperationCostCalculator{
+		config: config,
+	}
+}
+
+// EstimatedCost calculates the cost of the operation with the given name.
+// If the operation name is empty, the maximum cost of all operations of the document is returned.
+// Variables are read from the input of the operation document.
+func (o *OperationCostCalculator) EstimatedCost(operation, definition *ast.Document, operationName string, report *operationreport.Report) int {
+	c := o.newCalculator(operation, definition, report)
+	cost := 0.0
+	for _, ref := range c.operationDefinitions(operationName) {
+		rootTypeName := c.rootTypeName(ref)
+		if rootTypeName == nil {
+			continue
+		}
+		cost = math.Max(cost, c.estimatedSelectionSetCost(operation.OperationDefinitions[ref].SelectionSet, rootTypeName, nil))
+	}
+	return roundCost(cost)
+}
+
+// ActualCost calculates the cost of the operation with the given name from the data of its response.
+// Abstract types are resolved by the __typename field of the data, if it was not selected, all fragments are counted.
+func (o *OperationCostCalculator) ActualCost(operation, definition *ast.Document, operationName string, data []byte, report *operationreport.Report) int {
+	c := o.newCalculator(operation, definition, report)
+	cost := 0.0
+	for _, ref := range c.operationDefinitions(operationName) {
+		rootTypeName := c.rootTypeName(ref)
+		if rootTypeName == nil {
+			continue
+		}
+		cost = math.Max(cost, c.actualSelectionSetCost(operation.OperationDefinitions[ref].SelectionSet, rootTypeName, data))
+	}
+	return roundCost(cost)
+}
+
+func (o *OperationCostCalculator) newCalculator(operation, definition *ast.Document, report *operationreport.Report) *calculator {
+	return &calculator{
+		operation:       operation,
+		definition:      definition,
+		report:          report,
+		defaultListSize: float64(o.config.DefaultListSize),
+	}
+}
+
+func roundCost(cost float64) int {
+	return int(math.Ceil(cost))
+}
+
+type calculator struct {
+	operation, definition *ast.Document
+	report                *operationreport.Report
+	defaultListSize       float64
+}
+
+// sizedFields is the list size of a parent field, which applies to the named child fields
+type sizedFields struct {
+	names [][]byte
+	size  float64
+}
+
+func (s *sizedFields) sizeOf(fieldName []byte) (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	for _, name := range s.names {
+		if bytes.Equal(name, fieldName) {
+			return s.size, true
+		}
+	}
+	return 0, false
+}
+
+func (c *calculator) operationDefinitions(operationName string) []int {
+	refs := make([]int, 0, len(c.operation.OperationDefinitions))
+	for i := range c.operation.RootNodes {
+		if c.operation.RootNodes[i].Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		ref := c.operation.RootNodes[i].Ref
+		if operationName != "" && c.operation.OperationDefinitionNameString(ref) != operationName {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func (c *calculator) rootTypeName(operationDefinition int) []byte {
+	switch c.operation.OperationDefinitions[operationDefinition].OperationType {
+	case ast.OperationTypeQuery:
+		return c.definition.Index.QueryTypeName
+	case ast.OperationTypeMutation:
+		return c.definition.Index.MutationTypeName
+	case ast.OperationTypeSubscription:
+		return c.definition.Index.SubscriptionTypeName
+	default:
+		return nil
+	}
+}
+
+// estimatedSelectionSetCost sums the cost of the fields, which apply to all possible types,
+// and adds the maximum cost of the fragments of a single type condition
+func (c *calculator) estimatedSelectionSetCost(set int, enclosingTypeName []byte, sized *sizedFields) float64 {
+	cost := 0.0
+	var fragmentCosts map[string]float64
+
+	for _, selectionRef := range c.operation.SelectionSets[set].SelectionRefs {
+		selection := c.operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			cost += c.estimatedFieldCost(selection.Ref, enclosingTypeName, sized)
+		case ast.SelectionKindInlineFragment:
+			fragmentSet, ok := c.operation.InlineFragmentSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			typeCondition := enclosingTypeName
+			if c.operation.InlineFragmentHasTypeCondition(selection.Ref) {
+				typeCondition = c.operation.InlineFragmentTypeConditionName(selection.Ref)
+			}
+			fragmentCost := c.estimatedSelectionSetCost(fragmentSet, typeCondition, sized)
+			if bytes.Equal(typeCondition, enclosingTypeName) {
+				cost += fragmentCost
+				continue
+			}
+			if fragmentCosts == nil {
+				fragmentCosts = make(map[string]float64)
+			}
+			fragmentCosts[string(typeCondition)] += fragmentCost
+		case ast.SelectionKindFragmentSpread:
+			fragmentDefinition, ok := c.operation.FragmentDefinitionRef(c.operation.FragmentSpreadNameBytes(selection.Ref))
+			if !ok {
+				continue
+			}
+			typeCondition := c.operation.FragmentDefinitionTypeName(fragmentDefinition)
+			fragmentCost := c.estimatedSelectionSetCost(c.operation.FragmentDefinitions[fragmentDefinition].SelectionSet, typeCondition, sized)
+			if bytes.Equal(typeCondition, enclosingTypeName) {
+				cost += fragmentCost
+				continue
+			}
+			if fragmentCosts == nil {
+				fragmentCosts = make(map[string]float64)
+			}
+			fragmentCosts[string(typeCondition)] += fragmentCost
+		}
+	}
+
+	maxFragmentCost := 0.0
+	for _, fragmentCost := range fragmentCosts {
+		maxFragmentCost = math.Max(maxFragmentCost, fragmentCost)
+	}
+
+	return cost + maxFragmentCost
+}
+
+func (c *calculator) estimatedFieldCost(field int, enclosingTypeName []byte, sized *sizedFields) float64 {
+	fieldDefinition, ok := c.fieldDefinition(field, enclosingTypeName)
+	if !ok {
+		return 0
+	}
+
+	cost := c.argumentsCost(field, fieldDefinition)
+
+	fieldType := c.definition.FieldDefinitionType(fieldDefinition)
+	typeName := c.definition.ResolveTypeNameBytes(fieldType)
+
+	multiplier := 1.0
+	size, listSize := c.listSize(field, fieldDefinition)
+	if parentSize, ok := sized.sizeOf(c.definition.FieldDefinitionNameBytes(fieldDefinition)); ok {
+		multiplier = parentSize
+	} else if c.definition.TypeIsList(fieldType) {
+		multiplier = size
+	}
+
+	selectionsCost := 0.0
+	if set, ok := c.operation.FieldSelectionSet(field); ok {
+		var childSized *sizedFields
+		if listSize != nil && len(listSize.names) > 0 {
+			childSized = listSize
+		}
+		selectionsCost = c.estimatedSelectionSetCost(set, typeName, childSized)
+	}
+
+	return cost + multiplier*(c.fieldWeight(fieldDefinition, typeName)+selectionsCost)
+}
+
+// listSize returns the size of a list field and the sized fields it applies to,
+// the sized fields are nil if the field has no @listSize directive
+func (c *calculator) listSize(field, fieldDefinition int) (float64, *sizedFields) {
+	directive, ok := c.definition.FieldDefinitionDirectiveByName(fieldDefinition, listSizeDirectiveName)
+	if !ok {
+		return c.defaultListSize, nil
+	}
+
+	sized := &sizedFields{
+		size: c.defaultListSize,
+	}
+	if value, ok := c.definition.DirectiveArgumentValueByName(directive, assumedSizeArgumentName); ok && value.Kind == ast.ValueKindInteger {
+		sized.size = float64(c.definition.IntValueAsInt(value.Ref))
+	}
+	if value, ok := c.definition.DirectiveArgumentValueByName(directive, sizedFieldsArgumentName); ok && value.Kind == ast.ValueKindList {
+		for _, ref := range c.definition.ListValues[value.Ref].Refs {
+			sized.names = append(sized.names, c.definition.StringValueContentBytes(c.definition.Values[ref].Ref))
+		}
+	}
+
+	slicingArguments, ok := c.definition.DirectiveArgumentValueByName(directive, slicingArgumentsArgumentName)
+	if !ok || slicingArguments.Kind != ast.ValueKindList || len(c.definition.ListValues[slicingArguments.Ref].Refs) == 0 {
+		return sized.size, sized
+	}
+
+	requireOneSlicingArgument := true
+	if value, ok := c.definition.DirectiveArgumentValueByName(directive, requireOneSlicingArgumentArgumentName); ok && value.Kind == ast.ValueKindBoolean {
+		requireOneSlicingArgument = bool(c.definition.BooleanValue(value.Ref))
+	}
+
+	names := make([]string, 0, len(c.definition.ListValues[slicingArguments.Ref].Refs))
+	provided, size := 0, 0.0
+	for _, ref := range c.definition.ListValues[slicingArguments.Ref].Refs {
+		name := c.definition.StringValueContentString(c.definition.Values[ref].Ref)
+		names = append(names, name)
+		value, ok := c.slicingArgumentValue(field, fieldDefinition, name)
+		if !ok {
+			continue
+		}
+		provided++
+		size = math.Max(size, value)
+	}
+
+	if requireOneSlicingArgument && provided != 1 {
+		c.report.AddExternalError(operationreport.ExternalError{
+			Message: fmt.Sprintf("exactly one of the slicing arguments %v must be provided on field: %s", names, c.operation.FieldNameString(field)),
+		})
+	}
+
+	if provided > 0 {
+		sized.size = size
+	}
+	return sized.size, sized
+}
+
+// slicingArgumentValue returns the numeric value of a slicing argument, nested input fields are referenced with a dotted path
+func (c *calculator) slicingArgumentValue(field, fieldDefinition int, path string) (float64, bool) {
+	keys := bytes.Split([]byte(path), []byte("."))
+	argumentDefinition, ok := c.argumentDefinition(fieldDefinition, keys[0])
+	if !ok {
+		return 0, false
+	}
+
+	var value []byte
+	if argument, ok := c.operation.FieldArgument(field, keys[0]); ok {
+		value = c.operationValueJSON(c.operation.ArgumentValue(argument))
+	} else if c.definition.InputValueDefinitionHasDefaultValue(argumentDefinition) {
+		value, _ = c.definition.ValueToJSON(c.definition.InputValueDefinitionDefaultValue(argumentDefinition))
+	}
+
+	nestedKeys := make([]string, 0, len(keys)-1)
+	for _, key := range keys[1:] {
+		nestedKeys = append(nestedKeys, string(key))
+	}
+	value, valueType, _, err := jsonparser.Get(value, nestedKeys...)
+	if err != nil || valueType != jsonparser.Number {
+		return 0, false
+	}
+	size, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return 0, false
+	}
+	return size, true
+}
+
+func (c *calculator) argumentsCost(field, fieldDefinition int) float64 {
+	cost := 0.0
+	for _, argument := range c.operation.FieldArguments(field) {
+		argumentDefinition, ok := c.argumentDefinition(fieldDefinition, c.operation.ArgumentNameBytes(argument))
+		if !ok {
+			continue
+		}
+		value := c.operationValueJSON(c.operation.ArgumentValue(argument))
+		cost += c.inputValueCost(argumentDefinition, value)
+	}
+	return cost
+}
+
+// inputValueCost is the weight of an argument or input field, if it is provided, plus the cost of its value
+func (c *calculator) inputValueCost(inputValueDefinition int, value []byte) float64 {
+	value, valueType, _, err := jsonparser.Get(value)
+	if err != nil || valueType == jsonparser.Null {
+		return 0
+	}
+	weight, _ := c.weight(c.definition.InputValueDefinitions[inputValueDefinition].Directives.Refs)
+	return weight + c.inputTypeCost(c.definition.InputValueDefinitionType(inputValueDefinition), value, valueType)
+}
+
+func (c *calculator) inputTypeCost(typeRef int, value []byte, valueType jsonparser.ValueType) float64 {
+	if valueType == jsonparser.Null {
+		return 0
+	}
+
+	switch c.definition.Types[typeRef].TypeKind {
+	case ast.TypeKindNonNull:
+		return c.inputTypeCost(c.definition.Types[typeRef].OfType, value, valueType)
+	case ast.TypeKindList:
+		itemType := c.definition.Types[typeRef].OfType
+		if valueType != jsonparser.Array {
+			return c.inputTypeCost(itemType, value, valueType)
+		}
+		cost := 0.0
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemValueType jsonparser.ValueType, _ int, _ error) {
+			cost += c.inputTypeCost(itemType, item, itemValueType)
+		})
+		return cost
+	}
+
+	node, ok := c.definition.Index.FirstNodeByNameBytes(c.definition.ResolveTypeNameBytes(typeRef))
+	if !ok {
+		return 0
+	}
+	if node.Kind != ast.NodeKindInputObjectTypeDefinition {
+		weight, _ := c.weight(c.definition.NodeDirectives(node))
+		return weight
+	}
+	if valueType != jsonparser.Object {
+		return 0
+	}
+
+	cost := 0.0
+	_ = jsonparser.ObjectEach(value, func(key []byte, fieldValue []byte, fieldValueType jsonparser.ValueType, _ int) error {
+		inputFieldDefinition := c.definition.InputObjectTypeDefinitionInputValueDefinitionByName(node.Ref, key)
+		if inputFieldDefinition == -1 || fieldValueType == jsonparser.Null {
+			return nil
+		}
+		weight, _ := c.weight(c.definition.InputValueDefinitions[inputFieldDefinition].Directives.Refs)
+		cost += weight + c.inputTypeCost(c.definition.InputValueDefinitionType(inputFieldDefinition), fieldValue, fieldValueType)
+		return nil
+	})
+	return cost
+}
+
+// actualSelectionSetCost calculates the cost of the selections from the data of the enclosing object,
+// fields which are selected multiple times are counted once
+func (c *calculator) actualSelectionSetCost(set int, enclosingTypeName []byte, data []byte) float64 {
+	typeName := enclosingTypeName
+	if value, valueType, _, err := jsonparser.Get(data, string(typeNameFieldName)); err == nil && valueType == jsonparser.String {
+		typeName = value
+	}
+
+	counted := make(map[string]struct{})
+	return c.actualSelectionsCost(set, typeName, data, counted)
+}
+
+func (c *calculator) actualSelectionsCost(set int, typeName []byte, data []byte, counted map[string]struct{}) float64 {
+	cost := 0.0
+	for _, selectionRef := range c.operation.SelectionSets[set].SelectionRefs {
+		selection := c.operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			responseKey := c.operation.FieldAliasOrNameBytes(selection.Ref)
+			if _, ok := counted[string(responseKey)]; ok {
+				continue
+			}
+			value, valueType, _, err := jsonparser.Get(data, string(responseKey))
+			if err != nil {
+				continue
+			}
+			counted[string(responseKey)] = struct{}{}
+			cost += c.actualFieldCost(selection.Ref, typeName, value, valueType)
+		case ast.SelectionKindInlineFragment:
+			fragmentSet, ok := c.operation.InlineFragmentSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			if c.operation.InlineFragmentHasTypeCondition(selection.Ref) && !c.typeConditionApplies(c.operation.InlineFragmentTypeConditionName(selection.Ref), typeName) {
+				continue
+			}
+			cost += c.actualSelectionsCost(fragmentSet, typeName, data, counted)
+		case ast.SelectionKindFragmentSpread:
+			fragmentDefinition, ok := c.operation.FragmentDefinitionRef(c.operation.FragmentSpreadNameBytes(selection.Ref))
+			if !ok || !c.typeConditionApplies(c.operation.FragmentDefinitionTypeName(fragmentDefinition), typeName) {
+				continue
+			}
+			cost += c.actualSelectionsCost(c.operation.FragmentDefinitions[fragmentDefinition].SelectionSet, typeName, data, counted)
+		}
+	}
+	return cost
+}
+
+func (c *calculator) actualFieldCost(field int, enclosingTypeName []byte, value []byte, valueType jsonparser.ValueType) float64 {
+	fieldDefinition, ok := c.fieldDefinition(field, enclosingTypeName)
+	if !ok {
+		return 0
+	}
+
+	cost := c.argumentsCost(field, fieldDefinition)
+	typeName := c.definition.FieldDefinitionTypeNameBytes(fieldDefinition)
+
+	if valueType == jsonparser.Null {
+		weight, _ := c.weight(c.definition.FieldDefinitions[fieldDefinition].Directives.Refs)
+		return cost + weight
+	}
+
+	return cost + c.actualValueCost(field, fieldDefinition, typeName, value, valueType)
+}
+
+// actualValueCost is the weight and the cost of the selections of each item of a list
+func (c *calculator) actualValueCost(field, fieldDefinition int, typeName []byte, value []byte, valueType jsonparser.ValueType) float64 {
+	switch valueType {
+	case jsonparser.Null:
+		return 0
+	case jsonparser.Array:
+		cost := 0.0
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemValueType jsonparser.ValueType, _ int, _ error) {
+			cost += c.actualValueCost(field, fieldDefinition, typeName, item, itemValueType)
+		})
+		return cost
+	case jsonparser.Object:
+		if itemTypeName, itemValueType, _, err := jsonparser.Get(value, string(typeNameFieldName)); err == nil && itemValueType == jsonparser.String {
+			typeName = itemTypeName
+		}
+		cost := c.fieldWeight(fieldDefinition, typeName)
+		if set, ok := c.operation.FieldSelectionSet(field); ok {
+			cost += c.actualSelectionSetCost(set, typeName, value)
+		}
+		return cost
+	default:
+		return c.fieldWeight(fieldDefinition, typeName)
+	}
+}
+
+func (c *calculator) typeConditionApplies(typeCondition, typeName []byte) bool {
+	if bytes.Equal(typeCondition, typeName) {
+		return true
+	}
+	typeNode, ok := c.definition.Index.FirstNodeByNameBytes(typeName)
+	if !ok || typeNode.Kind != ast.NodeKindObjectTypeDefinition {
+		// the concrete type is unknown, so all fragments are counted
+		return true
+	}
+	conditionNode, ok := c.definition.Index.FirstNodeByNameBytes(typeCondition)
+	if !ok {
+		return false
+	}
+	switch conditionNode.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		return c.definition.NodeImplementsInterface(typeNode, typeCondition)
+	case ast.NodeKindUnionTypeDefinition:
+		return c.definition.NodeIsUnionMember(typeNode, conditionNode)
+	default:
+		return false
+	}
+}
+
+func (c *calculator) fieldDefinition(field int, enclosingTypeName []byte) (int, bool) {
+	if bytes.Equal(c.operation.FieldNameBytes(field), typeNameFieldName) {
+		return -1, false
+	}
+	node, ok := c.definition.Index.FirstNodeByNameBytes(enclosingTypeName)
+	if !ok {
+		return -1, false
+	}
+	return c.definition.NodeFieldDefinitionByName(node, c.operation.FieldNameBytes(field))
+}
+
+func (c *calculator) argumentDefinition(fieldDefinition int, argumentName []byte) (int, bool) {
+	for _, ref := range c.definition.FieldDefinitionArgumentsDefinitions(fieldDefinition) {
+		if bytes.Equal(c.definition.InputValueDefinitionNameBytes(ref), argumentName) {
+			return ref, true
+		}
+	}
+	return -1, false
+}
+
+// fieldWeight returns the weight of the field, otherwise the weight of its type,
+// otherwise the default weight of 1 for composite types and 0 for leaf types
+func (c *calculator) fieldWeight(fieldDefinition int, typeName []byte) float64 {
+	if weight, ok := c.weight(c.definition.FieldDefinitions[fieldDefinition].Directives.Refs); ok {
+		return weight
+	}
+	node, ok := c.definition.Index.FirstNodeByNameBytes(typeName)
+	if !ok {
+		return 0
+	}
+	if weight, ok := c.weight(c.definition.NodeDirectives(node)); ok {
+		return weight
+	}
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// weight returns the weight of the @cost directive in the directives
+func (c *calculator) weight(directives []int) (float64, bool) {
+	for _, directive := range directives {
+		if !bytes.Equal(c.definition.DirectiveNameBytes(directive), costDirectiveName) {
+			continue
+		}
+		value, ok := c.definition.DirectiveArgumentValueByName(directive, weightArgumentName)
+		if !ok {
+			return 0, false
+		}
+		var literal string
+		switch value.Kind {
+		case ast.ValueKindString:
+			literal = c.definition.StringValueContentString(value.Ref)
+		case ast.ValueKindInteger:
+			return float64(c.definition.IntValueAsInt(value.Ref)), true
+		case ast.ValueKindFloat:
+			literal = string(c.definition.FloatValueRaw(value.Ref))
+			if c.definition.FloatValueIsNegative(value.Ref) {
+				literal = "-" + literal
+			}
+		default:
+			return 0, false
+		}
+		weight, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			c.report.AddExternalError(operationreport.ExternalError{
+				Message: fmt.Sprintf("invalid weight of @cost directive: %s", literal),
+			})
+			return 0, false
+		}
+		return weight, true
+	}
+	return 0, false
+}
+
+// operationValueJSON returns the value as JSON, variables are replaced with their values
+func (c *calculator) operationValueJSON(value ast.Value) []byte {
+	if !c.operation.ValueContainsVariable(value) {
+		out, err := c.operation.ValueToJSON(value)
+		if err != nil {
+			return nil
+		}
+		return out
+	}
+
+	buf := &bytes.Buffer{}
+	c.writeOperationValueJSON(buf, value)
+	return buf.Bytes()
+}
+
+func (c *calculator) writeOperationValueJSON(buf *bytes.Buffer, value ast.Value) {
+	switch value.Kind {
+	case ast.ValueKindVariable:
+		variable, valueType, offset, err := jsonparser.Get(c.operation.Input.Variables, c.operation.VariableValueNameString(value.Ref))
+		if err != nil {
+			buf.WriteString("null")
+			return
+		}
+		if valueType == jsonparser.String {
+			// strings are returned without quotes
+			variable = c.operation.Input.Variables[offset-len(variable)-2 : offset]
+		}
+		buf.Write(variable)
+	case ast.ValueKindList:
+		buf.WriteByte('[')
+		for i, ref := range c.operation.ListValues[value.Ref].Refs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			c.writeOperationValueJSON(buf, c.operation.Values[ref])
+		}
+		buf.WriteByte(']')
+	case ast.ValueKindObject:
+		buf.WriteByte('{')
+		for i, ref := range c.operation.ObjectValues[value.Ref].Refs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Quote(c.operation.ObjectFieldNameString(ref)))
+			buf.WriteByte(':')
+			c.writeOperationValueJSON(buf, c.operation.ObjectFieldValue(ref))
+		}
+		buf.WriteByte('}')
+	default:
+		out, err := c.operation.ValueToJSON(value)
+		if err != nil {
+			buf.WriteString("null")
+			return
+		}
+		buf.Write(out)
+	}
+}
@@ -0,0 +1,181 @@
+package operation_cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestOperationCostCalculator_EstimatedCost(t *testing.T) {
+	run := func(t *testing.T, operation, variables, operationName string, expectedCost int, expectedErrors ...string) {
+		t.Helper()
+
+		def := unsafeparser.ParseGraphqlDocumentString(testDefinition + DirectiveDefinitions)
+		op := unsafeparser.ParseGraphqlDocumentString(operation)
+		op.Input.Variables = []byte(variables)
+		report := operationreport.Report{}
+
+		if operationName == "" {
+			astnormalization.NormalizeOperation(&op, &def, &report)
+		}
+		require.False(t, report.HasErrors(), report.Error())
+
+		cost := NewOperationCostCalculator(Config{}).EstimatedCost(&op, &def, operationName, &report)
+		assert.Equal(t, expectedCost, cost)
+
+		messages := make([]string, 0, len(report.ExternalErrors))
+		for _, externalError := range report.ExternalErrors {
+			messages = append(messages, externalError.Message)
+		}
+		assert.Equal(t, len(expectedErrors), len(messages), "unexpected errors: %v", messages)
+		for i := range expectedErrors {
+			assert.Equal(t, expectedErrors[i], messages[i])
+		}
+	}
+
+	t.Run("weights of fields and leaf lists", func(t *testing.T) {
+		run(t, `{ expensive tags }`, `{}`, "", 10)
+	})
+	t.Run("list size from slicing argument", func(t *testing.T) {
+		run(t, `{ users(first: 5) { id name address { city } } }`, `{}`, "", 15)
+	})
+	t.Run("list size from variable", func(t *testing.T) {
+		run(t, `query Users($first: Int) { users(first: $first) { id } }`, `{"first":3}`, "", 6)
+	})
+	t.Run("default list size without slicing argument", func(t *testing.T) {
+		run(t, `{ posts { id } }`, `{}`, "", 10)
+	})
+	t.Run("exactly one slicing argument is required", func(t *testing.T) {
+		run(t, `{ users(first: 5, last: 3) { id } }`, `{}`, "", 10,
+			"exactly one of the slicing arguments [first last] must be provided on field: users")
+	})
+	t.Run("sized fields of a connection", func(t *testing.T) {
+		run(t, `query Connection($first: Int) { usersConnection(input: {first: $first}) { edges { node { name } } } }`, `{"first":3}`, "Connection", 10)
+	})
+	t.Run("maximum cost of the possible types and weights of arguments", func(t *testing.T) {
+		run(t, `{ search(term: "a", filter: {status: ACTIVE}) { ... on User { friends { id } } ... on Post { title } } }`, `{}`, "", 110)
+	})
+	t.Run("mutation with weighted argument", func(t *testing.T) {
+		run(t, `mutation { createUser(input: {name: "a"}) { id } }`, `{}`, "", 7)
+	})
+	t.Run("float weights", func(t *testing.T) {
+		run(t, `{ ratings { stars } }`, `{}`, "", 1)
+	})
+	t.Run("operation name", func(t *testing.T) {
+		const operations = `query Posts { posts(first: 2) { id } } query Users { users(first: 5) { id } }`
+		run(t, operations, `{}`, "Posts", 2)
+		run(t, operations, `{}`, "Users", 10)
+	})
+}
+
+func TestOperationCostCalculator_ActualCost(t *testing.T) {
+	run := func(t *testing.T, operation, data string, expectedCost int) {
+		t.Helper()
+
+		def := unsafeparser.ParseGraphqlDocumentString(testDefinition + DirectiveDefinitions)
+		op := unsafeparser.ParseGraphqlDocumentString(operation)
+		report := operationreport.Report{}
+
+		astnormalization.NormalizeOperation(&op, &def, &report)
+		require.False(t, report.HasErrors(), report.Error())
+
+		cost := NewOperationCostCalculator(Config{}).ActualCost(&op, &def, "", []byte(data), &report)
+		require.False(t, report.HasErrors(), report.Error())
+		assert.Equal(t, expectedCost, cost)
+	}
+
+	t.Run("actual list sizes", func(t *testing.T) {
+		run(t, `{ users(first: 5) { id name address { city } } }`,
+			`{"users":[{"id":"1","name":"a","address":{"city":"x"}},{"id":"2","name":"b","address":null}]}`, 5)
+	})
+	t.Run("types are resolved by __typename", func(t *testing.T) {
+		run(t, `{ search(term: "a") { __typename ... on User { friends { id } } ... on Post { title } } }`,
+			`{"search":[{"__typename":"User","friends":[{"id":"1"}]},{"__typename":"Post","title":"t"}]}`, 5)
+	})
+	t.Run("null fields with weight", func(t *testing.T) {
+		run(t, `{ expensive }`, `{"expensive":null}`, 10)
+	})
+}
+
+const testDefinition = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	users(first: Int, last: Int): [User!]! @listSize(slicingArguments: ["first", "last"])
+	posts(first: Int): [Post!]! @listSize(slicingArguments: ["first"], requireOneSlicingArgument: false)
+	search(term: String!, filter: Filter): [SearchResult!]! @listSize(assumedSize: 5)
+	usersConnection(input: PageInput!): UserConnection! @listSize(slicingArguments: ["input.first"], sizedFields: ["edges"])
+	expensive: String @cost(weight: "10")
+	tags: [String!]!
+	ratings: [Rating!]! @listSize(assumedSize: 10)
+}
+
+type Mutation {
+	createUser(input: UserInput! @cost(weight: "2")): User @cost(weight: "5")
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node @cost(weight: "2") {
+	id: ID!
+	name: String
+	address: Address
+	friends: [User!]!
+}
+
+type Rating @cost(weight: 0.1) {
+	stars: Int
+}
+
+type Address {
+	city: String
+}
+
+type Post implements Node {
+	id: ID!
+	title: String
+}
+
+union SearchResult = User | Post
+
+type UserConnection {
+	edges: [UserEdge!]!
+}
+
+type UserEdge {
+	node: User!
+}
+
+input PageInput {
+	first: Int
+}
+
+input Filter {
+	status: Status @cost(weight: "3")
+}
+
+enum Status @cost(weight: "1.5") {
+	ACTIVE
+	INACTIVE
+}
+
+input UserInput {
+	name: String
+	tags: [String!]
+}
+
+scalar ID
+scalar String
+scalar Int
+scalar Boolean
+`
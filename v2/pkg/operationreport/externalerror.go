@@ -26,6 +26,10 @@ const (
 	UnknownFieldOfInputObjectErrMsg         = `Field "%s" is not defined by type "%s".`
 	DuplicatedFieldInputObjectErrMsg        = `There can be only one input field named "%s".`
 	ValueIsNotAnInputObjectTypeErrMsg       = `Expected value of type "%s", found %s.`
+	OneOfInputObjectFieldCountErrMsg        = `OneOf Input Object "%s" must specify exactly one key.`
+	OneOfInputObjectNullFieldErrMsg         = `Field "%s.%s" must be non-null.`
+	OneOfInputObjectNullableVariableErrMsg  = `Variable "$%s" must be non-nullable to be used for OneOf Input Object "%s".`
+	InvalidCustomScalarValueErrMsg          = `Expected value of type "%s", found %s; %s`
 )
 
 type ExternalError struct {
@@ -48,6 +52,12 @@ func ErrDocumentDoesntContainExecutableOperation() (err ExternalError) {
 	return
 }
 
+func ErrDocumentExceedsLimit(limitName string, limit int, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf("the document exceeds the maximum %s of %d", limitName, limit)
+	err.Locations = LocationsFromPosition(position)
+	return err
+}
+
 func ErrFieldUndefinedOnType(fieldName, typeName ast.ByteSlice) (err ExternalError) {
 	err.Message = fmt.Sprintf("field: %s not defined on type: %s", fieldName, typeName)
 	return err
@@ -192,6 +202,34 @@ func ErrUnknownFieldOfInputObject(objName, fieldName ast.ByteSlice, position pos
 	return err
 }
 
+func ErrOneOfInputObjectFieldCount(objName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(OneOfInputObjectFieldCountErrMsg, objName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrOneOfInputObjectNullField(objName, fieldName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(OneOfInputObjectNullFieldErrMsg, objName, fieldName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrOneOfInputObjectNullableVariable(variableName, objName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(OneOfInputObjectNullableVariableErrMsg, variableName, objName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrValueDoesntSatisfyCustomScalar(value, inputType ast.ByteSlice, reason string, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(InvalidCustomScalarValueErrMsg, inputType, value, reason)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
 func ErrDuplicatedFieldInputObject(fieldName ast.ByteSlice, first, duplicated position.Position) (err ExternalError) {
 	err.Message = fmt.Sprintf(DuplicatedFieldInputObjectErrMsg, fieldName)
 
@@ -425,6 +463,21 @@ func ErrEnumValueNameMustBeUnique(enumName, enumValueName ast.ByteSlice) (err Ex
 	return err
 }
 
+func ErrRequiredArgumentCannotBeDeprecated(typeName, fieldName, argumentName ast.ByteSlice) (err ExternalError) {
+	err.Message = fmt.Sprintf("Required argument %s.%s(%s:) cannot be deprecated.", typeName, fieldName, argumentName)
+	return err
+}
+
+func ErrRequiredDirectiveArgumentCannotBeDeprecated(directiveName, argumentName ast.ByteSlice) (err ExternalError) {
+	err.Message = fmt.Sprintf("Required argument @%s(%s:) cannot be deprecated.", directiveName, argumentName)
+	return err
+}
+
+func ErrRequiredInputFieldCannotBeDeprecated(typeName, fieldName ast.ByteSlice) (err ExternalError) {
+	err.Message = fmt.Sprintf("Required input field %s.%s cannot be deprecated.", typeName, fieldName)
+	return err
+}
+
 func ErrUnionMembersMustBeUnique(unionName, memberName ast.ByteSlice) (err ExternalError) {
 	err.Message = fmt.Sprintf("union member '%s.%s' can only be defined once", unionName, memberName)
 	return err
@@ -0,0 +1,291 @@
+package scalars
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+)
+
+const (
+	DateTimeSpecifiedByURL = "https://scalars.graphql.org/andimarek/date-time"
+	DateSpecifiedByURL     = "https://scalars.graphql.org/andimarek/local-date"
+	UUIDSpecifiedByURL     = "https://tools.ietf.org/html/rfc4122"
+	JSONSpecifiedByURL     = "https://www.ecma-international.org/publications-and-standards/standards/ecma-404/"
+	EmailSpecifiedByURL    = "https://tools.ietf.org/html/rfc5322"
+	URLSpecifiedByURL      = "https://tools.ietf.org/html/rfc3986"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	errNotString   = errors.New("Expected a string value.")
+	errNotDateTime = errors.New("Expected an RFC 3339 date-time string.")
+	errNotDate     = errors.New("Expected a date string of the format YYYY-MM-DD.")
+	errNotUUID     = errors.New("Expected a UUID string.")
+	errNotJSON     = errors.New("Expected a JSON value.")
+	errNotBigInt   = errors.New("Expected an integer or a string representing an integer.")
+	errNotEmail    = errors.New("Expected an email address string.")
+	errNotURL      = errors.New("Expected an absolute URL string.")
+
+	uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// DateTimeCodec accepts RFC 3339 date-time strings and normalizes them to time.RFC3339Nano
+type DateTimeCodec struct{}
+
+func (DateTimeCodec) ValidateInput(value []byte) error {
+	_, err := parseDateTime(value)
+	return err
+}
+
+func (DateTimeCodec) CoerceInput(value []byte) ([]byte, error) {
+	t, err := parseDateTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return quote(t.Format(time.RFC3339Nano)), nil
+}
+
+func (c DateTimeCodec) Serialize(value []byte) ([]byte, error) {
+	return c.CoerceInput(value)
+}
+
+func parseDateTime(value []byte) (time.Time, error) {
+	str, err := stringValue(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return time.Time{}, errNotDateTime
+	}
+	return t, nil
+}
+
+// DateCodec accepts full-date strings, e.g. 2006-01-02.
+// Serialize additionally accepts RFC 3339 date-time strings and renders their date.
+type DateCodec struct{}
+
+func (DateCodec) ValidateInput(value []byte) error {
+	_, err := parseDate(value)
+	return err
+}
+
+func (DateCodec) CoerceInput(value []byte) ([]byte, error) {
+	t, err := parseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return quote(t.Format(dateLayout)), nil
+}
+
+func (DateCodec) Serialize(value []byte) ([]byte, error) {
+	t, err := parseDate(value)
+	if err == nil {
+		return quote(t.Format(dateLayout)), nil
+	}
+	t, err = parseDateTime(value)
+	if err != nil {
+		return nil, errNotDate
+	}
+	return quote(t.Format(dateLayout)), nil
+}
+
+func parseDate(value []byte) (time.Time, error) {
+	str, err := stringValue(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(dateLayout, str)
+	if err != nil {
+		return time.Time{}, errNotDate
+	}
+	return t, nil
+}
+
+// UUIDCodec accepts UUID strings in the 8-4-4-4-12 format and normalizes them to lower case
+type UUIDCodec struct{}
+
+func (UUIDCodec) ValidateInput(value []byte) error {
+	_, err := parseUUID(value)
+	return err
+}
+
+func (UUIDCodec) CoerceInput(value []byte) ([]byte, error) {
+	str, err := parseUUID(value)
+	if err != nil {
+		return nil, err
+	}
+	return quote(strings.ToLower(str)), nil
+}
+
+func (c UUIDCodec) Serialize(value []byte) ([]byte, error) {
+	return c.CoerceInput(value)
+}
+
+func parseUUID(value []byte) (string, error) {
+	str, err := stringValue(value)
+	if err != nil {
+		return "", err
+	}
+	if !uuidRegex.MatchString(str) {
+		return "", errNotUUID
+	}
+	return str, nil
+}
+
+// JSONCodec accepts any JSON value
+type JSONCodec struct{}
+
+func (JSONCodec) ValidateInput(value []byte) error {
+	if !json.Valid(value) {
+		return errNotJSON
+	}
+	return nil
+}
+
+func (c JSONCodec) CoerceInput(value []byte) ([]byte, error) {
+	if err := c.ValidateInput(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c JSONCodec) Serialize(value []byte) ([]byte, error) {
+	return c.CoerceInput(value)
+}
+
+// BigIntCodec accepts integers of arbitrary size, either as JSON numbers or as strings.
+// Values are coerced and serialized as JSON numbers.
+type BigIntCodec struct{}
+
+func (BigIntCodec) ValidateInput(value []byte) error {
+	_, err := parseBigInt(value)
+	return err
+}
+
+func (BigIntCodec) CoerceInput(value []byte) ([]byte, error) {
+	i, err := parseBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(i.String()), nil
+}
+
+func (c BigIntCodec) Serialize(value []byte) ([]byte, error) {
+	return c.CoerceInput(value)
+}
+
+func parseBigInt(value []byte) (*big.Int, error) {
+	data, dataType, _, err := jsonparser.Get(value)
+	if err != nil {
+		return nil, errNotBigInt
+	}
+
+	var str string
+	switch dataType {
+	case jsonparser.Number:
+		str = string(data)
+	case jsonparser.String:
+		str, err = jsonparser.ParseString(data)
+		if err != nil {
+			return nil, errNotBigInt
+		}
+	default:
+		return nil, errNotBigInt
+	}
+
+	i, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return nil, errNotBigInt
+	}
+	return i, nil
+}
+
+// EmailCodec accepts plain RFC 5322 email addresses without a display name
+type EmailCodec struct{}
+
+func (EmailCodec) ValidateInput(value []byte) error {
+	_, err := parseEmail(value)
+	return err
+}
+
+func (EmailCodec) CoerceInput(value []byte) ([]byte, error) {
+	str, err := parseEmail(value)
+	if err != nil {
+		return nil, err
+	}
+	return quote(str), nil
+}
+
+func (c EmailCodec) Serialize(value []byte) ([]byte, error) {
+	return c.CoerceInput(value)
+}
+
+func parseEmail(value []byte) (string, error) {
+	str, err := stringValue(value)
+	if err != nil {
+		return "", err
+	}
+	address, err := mail.ParseAddress(str)
+	if err != nil || address.Name != "" || address.Address != str {
+		return "", errNotEmail
+	}
+	return str, nil
+}
+
+// URLCodec accepts absolute URLs
+type URLCodec struct{}
+
+func (URLCodec) ValidateInput(value []byte) error {
+	_, err := parseURL(value)
+	return err
+}
+
+func (URLCodec) CoerceInput(value []byte) ([]byte, error) {
+	u, err := parseURL(value)
+	if err != nil {
+		return nil, err
+	}
+	return quote(u.String()), nil
+}
+
+func (c URLCodec) Serialize(value []byte) ([]byte, error) {
+	return c.CoerceInput(value)
+}
+
+func parseURL(value []byte) (*url.URL, error) {
+	str, err := stringValue(value)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(str)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return nil, errNotURL
+	}
+	return u, nil
+}
+
+func stringValue(value []byte) (string, error) {
+	data, dataType, _, err := jsonparser.Get(value)
+	if err != nil || dataType != jsonparser.String {
+		return "", errNotString
+	}
+	str, err := jsonparser.ParseString(data)
+	if err != nil {
+		return "", errNotString
+	}
+	return str, nil
+}
+
+func quote(str string) []byte {
+	return strconv.AppendQuote(nil, str)
+}
@@ -0,0 +1,122 @@
+// Package scalars implements a registry of codecs for custom scalars.
+//
+// A Codec validates and coerces input values of a custom scalar, e.g. variables and literals,
+// and serializes the values resolved from data sources into the response.
+// Codecs are looked up by the @specifiedBy URL of a scalar and fall back to the name of the scalar.
+package scalars
+
+import (
+	"bytes"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+const (
+	SpecifiedByDirectiveName = "specifiedBy"
+	SpecifiedByURLArgName    = "url"
+)
+
+// Codec validates, coerces and serializes the values of a custom scalar.
+// All values are JSON encoded.
+type Codec interface {
+	// ValidateInput returns an error if the input value can't be represented by the scalar
+	ValidateInput(value []byte) error
+	// CoerceInput converts a valid input value into its canonical form, which is passed to the data sources
+	CoerceInput(value []byte) ([]byte, error)
+	// Serialize converts a value resolved from a data source into its response form
+	Serialize(value []byte) ([]byte, error)
+}
+
+// Registry holds the codecs of custom scalars by scalar name and by @specifiedBy URL.
+// A nil Registry contains no codecs.
+type Registry struct {
+	byName           map[string]Codec
+	bySpecifiedByURL map[string]Codec
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byName:           map[string]Codec{},
+		bySpecifiedByURL: map[string]Codec{},
+	}
+}
+
+// NewDefaultRegistry returns a Registry with the built-in codecs for
+// DateTime, Date, UUID, JSON, BigInt, Email and URL registered by name and @specifiedBy URL
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	registry.RegisterWithSpecifiedByURL("DateTime", DateTimeSpecifiedByURL, DateTimeCodec{})
+	registry.RegisterWithSpecifiedByURL("Date", DateSpecifiedByURL, DateCodec{})
+	registry.RegisterWithSpecifiedByURL("UUID", UUIDSpecifiedByURL, UUIDCodec{})
+	registry.RegisterWithSpecifiedByURL("JSON", JSONSpecifiedByURL, JSONCodec{})
+	registry.Register("BigInt", BigIntCodec{})
+	registry.RegisterWithSpecifiedByURL("Email", EmailSpecifiedByURL, EmailCodec{})
+	registry.RegisterWithSpecifiedByURL("URL", URLSpecifiedByURL, URLCodec{})
+	return registry
+}
+
+// Register registers the codec for all scalars with the given name
+func (r *Registry) Register(scalarName string, codec Codec) {
+	r.byName[scalarName] = codec
+}
+
+// RegisterSpecifiedByURL registers the codec for all scalars with a @specifiedBy directive pointing to url,
+// regardless of their name
+func (r *Registry) RegisterSpecifiedByURL(url string, codec Codec) {
+	r.bySpecifiedByURL[url] = codec
+}
+
+// RegisterWithSpecifiedByURL registers the codec by scalar name and @specifiedBy URL
+func (r *Registry) RegisterWithSpecifiedByURL(scalarName, url string, codec Codec) {
+	r.Register(scalarName, codec)
+	r.RegisterSpecifiedByURL(url, codec)
+}
+
+// Codec returns the codec of a scalar type definition.
+// The @specifiedBy URL of the scalar takes precedence over its name.
+func (r *Registry) Codec(definition *ast.Document, scalarTypeDefinitionRef int) (codec Codec, ok bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	if url, hasURL := specifiedByURL(definition, scalarTypeDefinitionRef); hasURL {
+		if codec, ok = r.bySpecifiedByURL[url]; ok {
+			return codec, true
+		}
+	}
+
+	codec, ok = r.byName[definition.ScalarTypeDefinitionNameString(scalarTypeDefinitionRef)]
+	return codec, ok
+}
+
+// CodecByName returns the codec of the scalar type definition with the given name
+func (r *Registry) CodecByName(definition *ast.Document, scalarName []byte) (codec Codec, ok bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	node, exists := definition.Index.FirstNodeByNameBytes(scalarName)
+	if !exists || node.Kind != ast.NodeKindScalarTypeDefinition {
+		return nil, false
+	}
+
+	return r.Codec(definition, node.Ref)
+}
+
+func specifiedByURL(definition *ast.Document, scalarTypeDefinitionRef int) (url string, ok bool) {
+	for _, directiveRef := range definition.ScalarTypeDefinitions[scalarTypeDefinitionRef].Directives.Refs {
+		if !bytes.Equal(definition.DirectiveNameBytes(directiveRef), []byte(SpecifiedByDirectiveName)) {
+			continue
+		}
+
+		value, exists := definition.DirectiveArgumentValueByName(directiveRef, []byte(SpecifiedByURLArgName))
+		if !exists || value.Kind != ast.ValueKindString {
+			return "", false
+		}
+
+		return definition.StringValueContentString(value.Ref), true
+	}
+
+	return "", false
+}
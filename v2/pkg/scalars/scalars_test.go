@@ -0,0 +1,149 @@
+package scalars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
+)
+
+func TestRegistry_Codec(t *testing.T) {
+	definition := unsafeparser.ParseGraphqlDocumentString(`
+		scalar DateTime
+		scalar Timestamp @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time")
+		scalar UUID @specifiedBy(url: "https://example.com/unknown")
+		scalar Custom
+		type Query { a: String }
+	`)
+	registry := NewDefaultRegistry()
+
+	t.Run("by name", func(t *testing.T) {
+		codec, ok := registry.CodecByName(&definition, []byte("DateTime"))
+		assert.True(t, ok)
+		assert.Equal(t, DateTimeCodec{}, codec)
+	})
+	t.Run("by specifiedBy url", func(t *testing.T) {
+		codec, ok := registry.CodecByName(&definition, []byte("Timestamp"))
+		assert.True(t, ok)
+		assert.Equal(t, DateTimeCodec{}, codec)
+	})
+	t.Run("unknown specifiedBy url falls back to name", func(t *testing.T) {
+		codec, ok := registry.CodecByName(&definition, []byte("UUID"))
+		assert.True(t, ok)
+		assert.Equal(t, UUIDCodec{}, codec)
+	})
+	t.Run("specifiedBy url takes precedence over name", func(t *testing.T) {
+		custom := NewRegistry()
+		custom.Register("Timestamp", JSONCodec{})
+		custom.RegisterSpecifiedByURL(DateTimeSpecifiedByURL, DateTimeCodec{})
+		codec, ok := custom.CodecByName(&definition, []byte("Timestamp"))
+		assert.True(t, ok)
+		assert.Equal(t, DateTimeCodec{}, codec)
+	})
+	t.Run("scalar without codec", func(t *testing.T) {
+		_, ok := registry.CodecByName(&definition, []byte("Custom"))
+		assert.False(t, ok)
+	})
+	t.Run("not a scalar", func(t *testing.T) {
+		_, ok := registry.CodecByName(&definition, []byte("Query"))
+		assert.False(t, ok)
+	})
+	t.Run("nil registry", func(t *testing.T) {
+		var nilRegistry *Registry
+		_, ok := nilRegistry.CodecByName(&definition, []byte("DateTime"))
+		assert.False(t, ok)
+	})
+}
+
+func TestBuiltinCodecs(t *testing.T) {
+	type testCase struct {
+		input      string
+		coerced    string
+		serialized string
+		err        string
+	}
+
+	run := func(t *testing.T, codec Codec, cases []testCase) {
+		t.Helper()
+		for _, tc := range cases {
+			t.Run(tc.input, func(t *testing.T) {
+				err := codec.ValidateInput([]byte(tc.input))
+				coerced, coerceErr := codec.CoerceInput([]byte(tc.input))
+				if tc.err != "" {
+					require.Error(t, err)
+					assert.Equal(t, tc.err, err.Error())
+					assert.Error(t, coerceErr)
+					return
+				}
+				require.NoError(t, err)
+				require.NoError(t, coerceErr)
+				assert.Equal(t, tc.coerced, string(coerced))
+
+				serialized, err := codec.Serialize([]byte(tc.input))
+				require.NoError(t, err)
+				expected := tc.serialized
+				if expected == "" {
+					expected = tc.coerced
+				}
+				assert.Equal(t, expected, string(serialized))
+			})
+		}
+	}
+
+	t.Run("DateTime", func(t *testing.T) {
+		run(t, DateTimeCodec{}, []testCase{
+			{input: `"2024-01-02T03:04:05Z"`, coerced: `"2024-01-02T03:04:05Z"`},
+			{input: `"2024-01-02T03:04:05.120+01:00"`, coerced: `"2024-01-02T03:04:05.12+01:00"`},
+			{input: `"2024-01-02"`, err: "Expected an RFC 3339 date-time string."},
+			{input: `1704164645`, err: "Expected a string value."},
+		})
+	})
+	t.Run("Date", func(t *testing.T) {
+		run(t, DateCodec{}, []testCase{
+			{input: `"2024-01-02"`, coerced: `"2024-01-02"`},
+			{input: `"2024-02-30"`, err: "Expected a date string of the format YYYY-MM-DD."},
+			{input: `"2024-01-02T03:04:05Z"`, err: "Expected a date string of the format YYYY-MM-DD."},
+		})
+		serialized, err := DateCodec{}.Serialize([]byte(`"2024-01-02T03:04:05Z"`))
+		require.NoError(t, err)
+		assert.Equal(t, `"2024-01-02"`, string(serialized))
+	})
+	t.Run("UUID", func(t *testing.T) {
+		run(t, UUIDCodec{}, []testCase{
+			{input: `"123E4567-E89B-12D3-A456-426614174000"`, coerced: `"123e4567-e89b-12d3-a456-426614174000"`},
+			{input: `"123e4567e89b12d3a456426614174000"`, err: "Expected a UUID string."},
+		})
+	})
+	t.Run("JSON", func(t *testing.T) {
+		run(t, JSONCodec{}, []testCase{
+			{input: `{"a":[1,true,null]}`, coerced: `{"a":[1,true,null]}`},
+			{input: `"a"`, coerced: `"a"`},
+			{input: `{"a":`, err: "Expected a JSON value."},
+		})
+	})
+	t.Run("BigInt", func(t *testing.T) {
+		run(t, BigIntCodec{}, []testCase{
+			{input: `123456789012345678901234567890`, coerced: `123456789012345678901234567890`},
+			{input: `"-123456789012345678901234567890"`, coerced: `-123456789012345678901234567890`},
+			{input: `1.5`, err: "Expected an integer or a string representing an integer."},
+			{input: `"abc"`, err: "Expected an integer or a string representing an integer."},
+			{input: `true`, err: "Expected an integer or a string representing an integer."},
+		})
+	})
+	t.Run("Email", func(t *testing.T) {
+		run(t, EmailCodec{}, []testCase{
+			{input: `"jane@example.com"`, coerced: `"jane@example.com"`},
+			{input: `"Jane <jane@example.com>"`, err: "Expected an email address string."},
+			{input: `"jane"`, err: "Expected an email address string."},
+		})
+	})
+	t.Run("URL", func(t *testing.T) {
+		run(t, URLCodec{}, []testCase{
+			{input: `"https://example.com/a?b=c"`, coerced: `"https://example.com/a?b=c"`},
+			{input: `"mailto:jane@example.com"`, coerced: `"mailto:jane@example.com"`},
+			{input: `"/relative/path"`, err: "Expected an absolute URL string."},
+		})
+	})
+}
@@ -0,0 +1,591 @@
+package schemadiff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+var (
+	deprecatedDirectiveName = []byte("deprecated")
+	oneOfDirectiveName      = []byte("oneOf")
+)
+
+type differ struct {
+	old, new *ast.Document
+	changes  Changes
+}
+
+func (d *differ) report(changeType ChangeType, criticality Criticality, coordinate string, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Type:        changeType,
+		Criticality: criticality,
+		Coordinate:  coordinate,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) diff() {
+	d.diffRootOperationTypes()
+	d.diffTypes()
+	d.diffDirectiveDefinitions()
+}
+
+func (d *differ) diffRootOperationTypes() {
+	d.diffRootOperationType("query", d.old.Index.QueryTypeName, d.new.Index.QueryTypeName)
+	d.diffRootOperationType("mutation", d.old.Index.MutationTypeName, d.new.Index.MutationTypeName)
+	d.diffRootOperationType("subscription", d.old.Index.SubscriptionTypeName, d.new.Index.SubscriptionTypeName)
+}
+
+func (d *differ) diffRootOperationType(operation string, oldTypeName, newTypeName ast.ByteSlice) {
+	switch {
+	case bytes.Equal(oldTypeName, newTypeName):
+	case len(oldTypeName) == 0:
+		d.report(RootOperationTypeChanged, CriticalitySafe, "schema", "Root %s type %s was added.", operation, newTypeName)
+	case len(newTypeName) == 0:
+		d.report(RootOperationTypeChanged, CriticalityBreaking, "schema", "Root %s type %s was removed.", operation, oldTypeName)
+	default:
+		d.report(RootOperationTypeChanged, CriticalityBreaking, "schema", "Root %s type changed from %s to %s.", operation, oldTypeName, newTypeName)
+	}
+}
+
+func (d *differ) diffTypes() {
+	for _, oldNode := range d.old.RootNodes {
+		if !isTypeDefinition(oldNode.Kind) {
+			continue
+		}
+		typeName := d.old.NodeNameString(oldNode)
+		newNode, exists := d.newTypeDefinition(oldNode.Kind, d.old.NodeNameBytes(oldNode))
+		if !exists {
+			d.report(TypeRemoved, CriticalityBreaking, typeName, "%s was removed.", typeName)
+			continue
+		}
+		if newNode.Kind != oldNode.Kind {
+			d.report(TypeKindChanged, CriticalityBreaking, typeName, "%s changed from %s to %s.", typeName, typeKindDescription(oldNode.Kind), typeKindDescription(newNode.Kind))
+			continue
+		}
+
+		d.diffAppliedDirectives(typeName, d.old.NodeDirectives(oldNode), d.new.NodeDirectives(newNode))
+
+		switch oldNode.Kind {
+		case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition:
+			d.diffImplementedInterfaces(typeName, oldNode, newNode)
+			d.diffFields(typeName, oldNode, newNode)
+		case ast.NodeKindInputObjectTypeDefinition:
+			d.diffInputFields(typeName, oldNode, newNode)
+		case ast.NodeKindEnumTypeDefinition:
+			d.diffEnumValues(typeName, oldNode.Ref, newNode.Ref)
+		case ast.NodeKindUnionTypeDefinition:
+			d.diffUnionMembers(typeName, oldNode, newNode)
+		}
+	}
+
+	for _, newNode := range d.new.RootNodes {
+		if !isTypeDefinition(newNode.Kind) {
+			continue
+		}
+		if _, exists := d.oldTypeDefinition(d.new.NodeNameBytes(newNode)); exists {
+			continue
+		}
+		typeName := d.new.NodeNameString(newNode)
+		d.report(TypeAdded, CriticalitySafe, typeName, "%s was added.", typeName)
+	}
+}
+
+// newTypeDefinition returns the type definition with the given name in the new schema.
+// A node of the same kind is preferred over other nodes with the same name.
+func (d *differ) newTypeDefinition(kind ast.NodeKind, name ast.ByteSlice) (ast.Node, bool) {
+	nodes, ok := d.new.Index.NodesByNameBytes(name)
+	if !ok {
+		return ast.Node{}, false
+	}
+	for i := range nodes {
+		if nodes[i].Kind == kind {
+			return nodes[i], true
+		}
+	}
+	for i := range nodes {
+		if isTypeDefinition(nodes[i].Kind) {
+			return nodes[i], true
+		}
+	}
+	return ast.Node{}, false
+}
+
+func (d *differ) oldTypeDefinition(name ast.ByteSlice) (ast.Node, bool) {
+	nodes, ok := d.old.Index.NodesByNameBytes(name)
+	if !ok {
+		return ast.Node{}, false
+	}
+	for i := range nodes {
+		if isTypeDefinition(nodes[i].Kind) {
+			return nodes[i], true
+		}
+	}
+	return ast.Node{}, false
+}
+
+func (d *differ) diffImplementedInterfaces(typeName string, oldNode, newNode ast.Node) {
+	oldInterfaces := implementedInterfaceRefs(d.old, oldNode)
+	newInterfaces := implementedInterfaceRefs(d.new, newNode)
+
+	for _, oldRef := range oldInterfaces {
+		interfaceName := d.old.TypeNameBytes(oldRef)
+		if !containsTypeName(d.new, newInterfaces, interfaceName) {
+			d.report(ImplementedInterfaceRemoved, CriticalityBreaking, typeName, "%s no longer implements interface %s.", typeName, interfaceName)
+		}
+	}
+	for _, newRef := range newInterfaces {
+		interfaceName := d.new.TypeNameBytes(newRef)
+		if !containsTypeName(d.old, oldInterfaces, interfaceName) {
+			d.report(ImplementedInterfaceAdded, CriticalityDangerous, typeName, "%s now implements interface %s.", typeName, interfaceName)
+		}
+	}
+}
+
+func (d *differ) diffUnionMembers(typeName string, oldNode, newNode ast.Node) {
+	oldMembers := d.old.NodeUnionMemberRefs(oldNode)
+	newMembers := d.new.NodeUnionMemberRefs(newNode)
+
+	for _, oldRef := range oldMembers {
+		memberName := d.old.TypeNameBytes(oldRef)
+		if !containsTypeName(d.new, newMembers, memberName) {
+			d.report(UnionMemberRemoved, CriticalityBreaking, typeName, "%s was removed from union type %s.", memberName, typeName)
+		}
+	}
+	for _, newRef := range newMembers {
+		memberName := d.new.TypeNameBytes(newRef)
+		if !containsTypeName(d.old, oldMembers, memberName) {
+			d.report(UnionMemberAdded, CriticalityDangerous, typeName, "%s was added to union type %s.", memberName, typeName)
+		}
+	}
+}
+
+func (d *differ) diffEnumValues(typeName string, oldRef, newRef int) {
+	oldValues := d.old.EnumTypeDefinitions[oldRef].EnumValuesDefinition.Refs
+	newValues := d.new.EnumTypeDefinitions[newRef].EnumValuesDefinition.Refs
+
+	for _, oldValue := range oldValues {
+		valueName := d.old.EnumValueDefinitionNameBytes(oldValue)
+		coordinate := typeName + "." + string(valueName)
+		newValue, exists := enumValueByName(d.new, newValues, valueName)
+		if !exists {
+			d.report(EnumValueRemoved, CriticalityBreaking, coordinate, "%s was removed from enum type %s.", valueName, typeName)
+			continue
+		}
+		d.diffDirectives(coordinate, d.old.EnumValueDefinitions[oldValue].Directives.Refs, d.new.EnumValueDefinitions[newValue].Directives.Refs)
+	}
+	for _, newValue := range newValues {
+		valueName := d.new.EnumValueDefinitionNameBytes(newValue)
+		if _, exists := enumValueByName(d.old, oldValues, valueName); !exists {
+			d.report(EnumValueAdded, CriticalityDangerous, typeName+"."+string(valueName), "%s was added to enum type %s.", valueName, typeName)
+		}
+	}
+}
+
+func (d *differ) diffFields(typeName string, oldNode, newNode ast.Node) {
+	for _, oldField := range d.old.NodeFieldDefinitions(oldNode) {
+		fieldName := d.old.FieldDefinitionNameBytes(oldField)
+		coordinate := typeName + "." + string(fieldName)
+		newField, exists := d.new.NodeFieldDefinitionByName(newNode, fieldName)
+		if !exists {
+			d.report(FieldRemoved, CriticalityBreaking, coordinate, "%s was removed.", coordinate)
+			continue
+		}
+
+		oldType, newType := d.old.FieldDefinitionType(oldField), d.new.FieldDefinitionType(newField)
+		if !typesEqual(d.old, oldType, d.new, newType) {
+			criticality := CriticalityBreaking
+			if isSafeOutputTypeChange(d.old, oldType, d.new, newType) {
+				criticality = CriticalitySafe
+			}
+			d.report(FieldTypeChanged, criticality, coordinate, "%s changed type from %s to %s.", coordinate, printType(d.old, oldType), printType(d.new, newType))
+		}
+
+		d.diffDirectives(coordinate, d.old.FieldDefinitions[oldField].Directives.Refs, d.new.FieldDefinitions[newField].Directives.Refs)
+		d.diffArguments(coordinate, d.old.FieldDefinitionArgumentsDefinitions(oldField), d.new.FieldDefinitionArgumentsDefinitions(newField))
+	}
+
+	for _, newField := range d.new.NodeFieldDefinitions(newNode) {
+		fieldName := d.new.FieldDefinitionNameBytes(newField)
+		if _, exists := d.old.NodeFieldDefinitionByName(oldNode, fieldName); exists {
+			continue
+		}
+		coordinate := typeName + "." + string(fieldName)
+		d.report(FieldAdded, CriticalitySafe, coordinate, "%s was added.", coordinate)
+	}
+}
+
+func (d *differ) diffArguments(fieldCoordinate string, oldArguments, newArguments []int) {
+	for _, oldArgument := range oldArguments {
+		argumentName := d.old.InputValueDefinitionNameBytes(oldArgument)
+		coordinate := fmt.Sprintf("%s(%s:)", fieldCoordinate, argumentName)
+		newArgument, exists := inputValueByName(d.new, newArguments, argumentName)
+		if !exists {
+			d.report(ArgumentRemoved, CriticalityBreaking, coordinate, "Argument %s was removed from %s.", argumentName, fieldCoordinate)
+			continue
+		}
+		d.diffInputValue(coordinate, oldArgument, newArgument, ArgumentTypeChanged, ArgumentDefaultValueChanged)
+	}
+
+	for _, newArgument := range newArguments {
+		argumentName := d.new.InputValueDefinitionNameBytes(newArgument)
+		if _, exists := inputValueByName(d.old, oldArguments, argumentName); exists {
+			continue
+		}
+		coordinate := fmt.Sprintf("%s(%s:)", fieldCoordinate, argumentName)
+		if d.new.InputValueDefinitionArgumentIsOptional(newArgument) {
+			d.report(OptionalArgumentAdded, CriticalityDangerous, coordinate, "Optional argument %s was added to %s.", argumentName, fieldCoordinate)
+		} else {
+			d.report(RequiredArgumentAdded, CriticalityBreaking, coordinate, "Required argument %s was added to %s.", argumentName, fieldCoordinate)
+		}
+	}
+}
+
+func (d *differ) diffInputFields(typeName string, oldNode, newNode ast.Node) {
+	oldFields := d.old.NodeInputFieldDefinitions(oldNode)
+	newFields := d.new.NodeInputFieldDefinitions(newNode)
+
+	for _, oldField := range oldFields {
+		fieldName := d.old.InputValueDefinitionNameBytes(oldField)
+		coordinate := typeName + "." + string(fieldName)
+		newField, exists := inputValueByName(d.new, newFields, fieldName)
+		if !exists {
+			d.report(InputFieldRemoved, CriticalityBreaking, coordinate, "%s was removed.", coordinate)
+			continue
+		}
+		d.diffInputValue(coordinate, oldField, newField, InputFieldTypeChanged, InputFieldDefaultValueChanged)
+	}
+
+	for _, newField := range newFields {
+		fieldName := d.new.InputValueDefinitionNameBytes(newField)
+		if _, exists := inputValueByName(d.old, oldFields, fieldName); exists {
+			continue
+		}
+		coordinate := typeName + "." + string(fieldName)
+		if d.new.InputValueDefinitionArgumentIsOptional(newField) {
+			d.report(OptionalInputFieldAdded, CriticalityDangerous, coordinate, "Optional input field %s was added.", coordinate)
+		} else {
+			d.report(RequiredInputFieldAdded, CriticalityBreaking, coordinate, "Required input field %s was added.", coordinate)
+		}
+	}
+}
+
+// diffInputValue compares the type, default value and applied directives of an argument or input field
+func (d *differ) diffInputValue(coordinate string, oldRef, newRef int, typeChanged, defaultValueChanged ChangeType) {
+	oldType, newType := d.old.InputValueDefinitionType(oldRef), d.new.InputValueDefinitionType(newRef)
+	if !typesEqual(d.old, oldType, d.new, newType) {
+		criticality := CriticalityBreaking
+		if isSafeInputTypeChange(d.old, oldType, d.new, newType) {
+			criticality = CriticalitySafe
+		}
+		d.report(typeChanged, criticality, coordinate, "%s changed type from %s to %s.", coordinate, printType(d.old, oldType), printType(d.new, newType))
+	}
+
+	oldDefault, newDefault := defaultValue(d.old, oldRef), defaultValue(d.new, newRef)
+	switch {
+	case oldDefault == newDefault:
+	case oldDefault == "":
+		d.report(defaultValueChanged, CriticalityDangerous, coordinate, "Default value %s was added to %s.", newDefault, coordinate)
+	case newDefault == "":
+		d.report(defaultValueChanged, CriticalityDangerous, coordinate, "Default value %s was removed from %s.", oldDefault, coordinate)
+	default:
+		d.report(defaultValueChanged, CriticalityDangerous, coordinate, "Default value of %s changed from %s to %s.", coordinate, oldDefault, newDefault)
+	}
+
+	d.diffDirectives(coordinate, d.old.InputValueDefinitions[oldRef].Directives.Refs, d.new.InputValueDefinitions[newRef].Directives.Refs)
+}
+
+// diffDirectives compares the deprecation and the other directives applied to a field, argument, input field or enum value
+func (d *differ) diffDirectives(coordinate string, oldDirectives, newDirectives []int) {
+	d.diffDeprecation(coordinate, oldDirectives, newDirectives)
+	d.diffAppliedDirectives(coordinate, oldDirectives, newDirectives)
+}
+
+// diffAppliedDirectives compares the applied directives by name, @deprecated is compared by diffDeprecation.
+// Repeated directives are compared as a whole, so a changed argument of any of them is reported once.
+func (d *differ) diffAppliedDirectives(coordinate string, oldDirectives, newDirectives []int) {
+	oldNames, oldUsages := appliedDirectives(d.old, oldDirectives)
+	newNames, newUsages := appliedDirectives(d.new, newDirectives)
+
+	for _, name := range oldNames {
+		newUsage, exists := newUsages[name]
+		switch {
+		case !exists:
+			d.report(DirectiveUsageRemoved, CriticalityDangerous, coordinate, "Directive @%s was removed from %s.", name, coordinate)
+		case newUsage != oldUsages[name]:
+			d.report(DirectiveUsageChanged, CriticalityDangerous, coordinate, "Directive @%s on %s changed from %s to %s.", name, coordinate, oldUsages[name], newUsage)
+		}
+	}
+	for _, name := range newNames {
+		if _, exists := oldUsages[name]; exists {
+			continue
+		}
+		// @oneOf rejects input objects with more than one field, which were valid before
+		criticality := CriticalityDangerous
+		if name == string(oneOfDirectiveName) {
+			criticality = CriticalityBreaking
+		}
+		d.report(DirectiveUsageAdded, criticality, coordinate, "Directive @%s was added to %s.", name, coordinate)
+	}
+}
+
+func (d *differ) diffDeprecation(coordinate string, oldDirectives, newDirectives []int) {
+	oldDeprecated, oldReason := deprecation(d.old, oldDirectives)
+	newDeprecated, newReason := deprecation(d.new, newDirectives)
+
+	switch {
+	case !oldDeprecated && newDeprecated && newReason == "":
+		d.report(DeprecationAdded, CriticalitySafe, coordinate, "%s was deprecated.", coordinate)
+	case !oldDeprecated && newDeprecated:
+		d.report(DeprecationAdded, CriticalitySafe, coordinate, "%s was deprecated with reason %q.", coordinate, newReason)
+	case oldDeprecated && !newDeprecated:
+		d.report(DeprecationRemoved, CriticalitySafe, coordinate, "%s is no longer deprecated.", coordinate)
+	case oldDeprecated && oldReason != newReason:
+		d.report(DeprecationReasonChanged, CriticalitySafe, coordinate, "Deprecation reason of %s changed from %q to %q.", coordinate, oldReason, newReason)
+	}
+}
+
+func (d *differ) diffDirectiveDefinitions() {
+	for oldRef := range d.old.DirectiveDefinitions {
+		directiveName := d.old.DirectiveDefinitionNameBytes(oldRef)
+		coordinate := "@" + string(directiveName)
+		newRef, exists := d.new.DirectiveDefinitionByNameBytes(directiveName)
+		if !exists {
+			d.report(DirectiveRemoved, CriticalityBreaking, coordinate, "%s was removed.", coordinate)
+			continue
+		}
+
+		oldDefinition, newDefinition := d.old.DirectiveDefinitions[oldRef], d.new.DirectiveDefinitions[newRef]
+		switch {
+		case oldDefinition.Repeatable.IsRepeatable && !newDefinition.Repeatable.IsRepeatable:
+			d.report(DirectiveRepeatableRemoved, CriticalityBreaking, coordinate, "%s is no longer repeatable.", coordinate)
+		case !oldDefinition.Repeatable.IsRepeatable && newDefinition.Repeatable.IsRepeatable:
+			d.report(DirectiveRepeatableAdded, CriticalitySafe, coordinate, "%s is now repeatable.", coordinate)
+		}
+
+		oldLocations, newLocations := oldDefinition.DirectiveLocations.Iterable(), newDefinition.DirectiveLocations.Iterable()
+		for oldLocations.Next() {
+			if location := oldLocations.Value(); !newDefinition.DirectiveLocations.Get(location) {
+				d.report(DirectiveLocationRemoved, CriticalityBreaking, coordinate, "Location %s was removed from %s.", location.LiteralString(), coordinate)
+			}
+		}
+		for newLocations.Next() {
+			if location := newLocations.Value(); !oldDefinition.DirectiveLocations.Get(location) {
+				d.report(DirectiveLocationAdded, CriticalitySafe, coordinate, "Location %s was added to %s.", location.LiteralString(), coordinate)
+			}
+		}
+
+		d.diffDirectiveArguments(coordinate, oldDefinition.ArgumentsDefinition.Refs, newDefinition.ArgumentsDefinition.Refs)
+	}
+
+	for newRef := range d.new.DirectiveDefinitions {
+		directiveName := d.new.DirectiveDefinitionNameBytes(newRef)
+		if _, exists := d.old.DirectiveDefinitionByNameBytes(directiveName); exists {
+			continue
+		}
+		coordinate := "@" + string(directiveName)
+		d.report(DirectiveAdded, CriticalitySafe, coordinate, "%s was added.", coordinate)
+	}
+}
+
+func (d *differ) diffDirectiveArguments(directiveCoordinate string, oldArguments, newArguments []int) {
+	for _, oldArgument := range oldArguments {
+		argumentName := d.old.InputValueDefinitionNameBytes(oldArgument)
+		coordinate := fmt.Sprintf("%s(%s:)", directiveCoordinate, argumentName)
+		newArgument, exists := inputValueByName(d.new, newArguments, argumentName)
+		if !exists {
+			d.report(DirectiveArgumentRemoved, CriticalityBreaking, coordinate, "Argument %s was removed from %s.", argumentName, directiveCoordinate)
+			continue
+		}
+		d.diffInputValue(coordinate, oldArgument, newArgument, DirectiveArgumentTypeChanged, DirectiveArgumentDefaultChanged)
+	}
+
+	for _, newArgument := range newArguments {
+		argumentName := d.new.InputValueDefinitionNameBytes(newArgument)
+		if _, exists := inputValueByName(d.old, oldArguments, argumentName); exists {
+			continue
+		}
+		coordinate := fmt.Sprintf("%s(%s:)", directiveCoordinate, argumentName)
+		if d.new.InputValueDefinitionArgumentIsOptional(newArgument) {
+			d.report(OptionalDirectiveArgumentAdded, CriticalitySafe, coordinate, "Optional argument %s was added to %s.", argumentName, directiveCoordinate)
+		} else {
+			d.report(RequiredDirectiveArgumentAdded, CriticalityBreaking, coordinate, "Required argument %s was added to %s.", argumentName, directiveCoordinate)
+		}
+	}
+}
+
+// isSafeOutputTypeChange returns true if every value of the new type is a valid value of the old type,
+// e.g. a nullable field becoming non-null
+func isSafeOutputTypeChange(oldDoc *ast.Document, oldRef int, newDoc *ast.Document, newRef int) bool {
+	oldType, newType := oldDoc.Types[oldRef], newDoc.Types[newRef]
+	switch oldType.TypeKind {
+	case ast.TypeKindNamed:
+		if newType.TypeKind == ast.TypeKindNonNull {
+			return isSafeOutputTypeChange(oldDoc, oldRef, newDoc, newType.OfType)
+		}
+		return newType.TypeKind == ast.TypeKindNamed && bytes.Equal(oldDoc.TypeNameBytes(oldRef), newDoc.TypeNameBytes(newRef))
+	case ast.TypeKindList:
+		if newType.TypeKind == ast.TypeKindNonNull {
+			return isSafeOutputTypeChange(oldDoc, oldRef, newDoc, newType.OfType)
+		}
+		return newType.TypeKind == ast.TypeKindList && isSafeOutputTypeChange(oldDoc, oldType.OfType, newDoc, newType.OfType)
+	case ast.TypeKindNonNull:
+		return newType.TypeKind == ast.TypeKindNonNull && isSafeOutputTypeChange(oldDoc, oldType.OfType, newDoc, newType.OfType)
+	}
+	return false
+}
+
+// isSafeInputTypeChange returns true if every value accepted by the old type is accepted by the new type,
+// e.g. a non-null argument becoming nullable
+func isSafeInputTypeChange(oldDoc *ast.Document, oldRef int, newDoc *ast.Document, newRef int) bool {
+	oldType, newType := oldDoc.Types[oldRef], newDoc.Types[newRef]
+	switch oldType.TypeKind {
+	case ast.TypeKindNamed:
+		return newType.TypeKind == ast.TypeKindNamed && bytes.Equal(oldDoc.TypeNameBytes(oldRef), newDoc.TypeNameBytes(newRef))
+	case ast.TypeKindList:
+		return newType.TypeKind == ast.TypeKindList && isSafeInputTypeChange(oldDoc, oldType.OfType, newDoc, newType.OfType)
+	case ast.TypeKindNonNull:
+		if newType.TypeKind == ast.TypeKindNonNull {
+			return isSafeInputTypeChange(oldDoc, oldType.OfType, newDoc, newType.OfType)
+		}
+		return isSafeInputTypeChange(oldDoc, oldType.OfType, newDoc, newRef)
+	}
+	return false
+}
+
+func typesEqual(oldDoc *ast.Document, oldRef int, newDoc *ast.Document, newRef int) bool {
+	oldType, newType := oldDoc.Types[oldRef], newDoc.Types[newRef]
+	if oldType.TypeKind != newType.TypeKind {
+		return false
+	}
+	if oldType.TypeKind == ast.TypeKindNamed {
+		return bytes.Equal(oldDoc.TypeNameBytes(oldRef), newDoc.TypeNameBytes(newRef))
+	}
+	return typesEqual(oldDoc, oldType.OfType, newDoc, newType.OfType)
+}
+
+func printType(doc *ast.Document, ref int) string {
+	out, _ := doc.PrintTypeBytes(ref, nil)
+	return string(out)
+}
+
+// defaultValue returns the printed default value of an input value or an empty string if there is none
+func defaultValue(doc *ast.Document, ref int) string {
+	if !doc.InputValueDefinitions[ref].DefaultValue.IsDefined {
+		return ""
+	}
+	out, _ := doc.PrintValueBytes(doc.InputValueDefinitions[ref].DefaultValue.Value, nil)
+	return string(out)
+}
+
+// deprecation returns whether the @deprecated directive is present and its reason
+func deprecation(doc *ast.Document, directiveRefs []int) (deprecated bool, reason string) {
+	for _, directiveRef := range directiveRefs {
+		if !bytes.Equal(doc.DirectiveNameBytes(directiveRef), deprecatedDirectiveName) {
+			continue
+		}
+		value, exists := doc.DirectiveArgumentValueByName(directiveRef, []byte("reason"))
+		if exists && value.Kind == ast.ValueKindString {
+			return true, doc.StringValueContentString(value.Ref)
+		}
+		return true, ""
+	}
+	return false, ""
+}
+
+// appliedDirectives returns the names of the applied directives except @deprecated in order of appearance
+// and their printed usages by name, repeated directives are joined into a single usage
+func appliedDirectives(doc *ast.Document, directiveRefs []int) (names []string, usages map[string]string) {
+	usages = make(map[string]string, len(directiveRefs))
+	buf := &strings.Builder{}
+	for _, directiveRef := range directiveRefs {
+		if bytes.Equal(doc.DirectiveNameBytes(directiveRef), deprecatedDirectiveName) {
+			continue
+		}
+		name := doc.DirectiveNameString(directiveRef)
+		buf.Reset()
+		_ = doc.PrintDirective(directiveRef, buf)
+		usage, exists := usages[name]
+		if !exists {
+			names = append(names, name)
+			usages[name] = buf.String()
+			continue
+		}
+		usages[name] = usage + " " + buf.String()
+	}
+	return names, usages
+}
+
+func implementedInterfaceRefs(doc *ast.Document, node ast.Node) []int {
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return doc.ObjectTypeDefinitions[node.Ref].ImplementsInterfaces.Refs
+	case ast.NodeKindInterfaceTypeDefinition:
+		return doc.InterfaceTypeDefinitions[node.Ref].ImplementsInterfaces.Refs
+	default:
+		return nil
+	}
+}
+
+func containsTypeName(doc *ast.Document, typeRefs []int, name ast.ByteSlice) bool {
+	for _, ref := range typeRefs {
+		if bytes.Equal(doc.TypeNameBytes(ref), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func inputValueByName(doc *ast.Document, refs []int, name ast.ByteSlice) (int, bool) {
+	for _, ref := range refs {
+		if bytes.Equal(doc.InputValueDefinitionNameBytes(ref), name) {
+			return ref, true
+		}
+	}
+	return -1, false
+}
+
+func enumValueByName(doc *ast.Document, refs []int, name ast.ByteSlice) (int, bool) {
+	for _, ref := range refs {
+		if bytes.Equal(doc.EnumValueDefinitionNameBytes(ref), name) {
+			return ref, true
+		}
+	}
+	return -1, false
+}
+
+func isTypeDefinition(kind ast.NodeKind) bool {
+	switch kind {
+	case ast.NodeKindObjectTypeDefinition,
+		ast.NodeKindInterfaceTypeDefinition,
+		ast.NodeKindUnionTypeDefinition,
+		ast.NodeKindEnumTypeDefinition,
+		ast.NodeKindInputObjectTypeDefinition,
+		ast.NodeKindScalarTypeDefinition:
+		return true
+	default:
+		return false
+	}
+}
+
+func typeKindDescription(kind ast.NodeKind) string {
+	switch kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return "an Object type"
+	case ast.NodeKindInterfaceTypeDefinition:
+		return "an Interface type"
+	case ast.NodeKindUnionTypeDefinition:
+		return "a Union type"
+	case ast.NodeKindEnumTypeDefinition:
+		return "an Enum type"
+	case ast.NodeKindInputObjectTypeDefinition:
+		return "an Input type"
+	case ast.NodeKindScalarTypeDefinition:
+		return "a Scalar type"
+	default:
+		return kind.String()
+	}
+}
@@ -0,0 +1,288 @@
+package schemadiff
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/variablesvalidation"
+)
+
+const defaultDeprecationReason = "No longer supported"
+
+// Operation is a client operation checked against a proposed schema
+type Operation struct {
+	// Name identifies the operation in the results, e.g. the hash of a persisted query
+	Name    string
+	Content string
+	// Variables are optional, when set they are validated against the variable definitions of the operation
+	Variables []byte
+}
+
+// OperationResult lists why an operation would stop validating against the proposed schema.
+// Warnings point to fields, arguments, input fields and enum values the operation uses which became deprecated.
+type OperationResult struct {
+	Name     string                          `json:"name"`
+	Errors   []operationreport.ExternalError `json:"errors,omitempty"`
+	Warnings []operationreport.ExternalError `json:"warnings,omitempty"`
+}
+
+func (r OperationResult) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// CheckOperationsSDL prepares both schemas like DiffSDL and checks the operations against them
+func CheckOperationsSDL(oldSchema, newSchema string, operations []Operation) ([]OperationResult, error) {
+	oldDefinition, err := parseSchema(oldSchema)
+	if err != nil {
+		return nil, err
+	}
+	newDefinition, err := parseSchema(newSchema)
+	if err != nil {
+		return nil, err
+	}
+	return CheckOperations(oldDefinition, newDefinition, operations)
+}
+
+// CheckOperations validates every operation against the new schema and returns one result per operation in the same order.
+// The old schema is used to only warn about deprecations introduced by the new schema.
+// An error is returned if validation fails internally, invalid operations are reported in the results.
+func CheckOperations(oldSchema, newSchema *ast.Document, operations []Operation) ([]OperationResult, error) {
+	checker := newOperationChecker(oldSchema, newSchema)
+	results := make([]OperationResult, 0, len(operations))
+	for i := range operations {
+		result, err := checker.check(operations[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to check operation %s: %w", operations[i].Name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+type operationChecker struct {
+	newSchema           *ast.Document
+	validator           *astvalidation.OperationValidator
+	variablesValidator  *variablesvalidation.VariablesValidator
+	deprecationsWalker  *astvisitor.Walker
+	deprecationsVisitor *deprecatedUsageVisitor
+}
+
+func newOperationChecker(oldSchema, newSchema *ast.Document) *operationChecker {
+	walker := astvisitor.NewWalker(48)
+	visitor := &deprecatedUsageVisitor{
+		Walker:    &walker,
+		oldSchema: oldSchema,
+	}
+	walker.RegisterEnterDocumentVisitor(visitor)
+	walker.RegisterEnterFieldVisitor(visitor)
+	walker.RegisterEnterVariableDefinitionVisitor(visitor)
+
+	return &operationChecker{
+		newSchema:           newSchema,
+		validator:           astvalidation.DefaultOperationValidator(),
+		variablesValidator:  variablesvalidation.NewVariablesValidator(),
+		deprecationsWalker:  &walker,
+		deprecationsVisitor: visitor,
+	}
+}
+
+func (c *operationChecker) check(operation Operation) (OperationResult, error) {
+	result := OperationResult{
+		Name: operation.Name,
+	}
+
+	document, report := astparser.ParseGraphqlDocumentString(operation.Content)
+	if report.HasErrors() {
+		result.Errors = detachPaths(report.ExternalErrors)
+		return result, nil
+	}
+
+	c.validator.Validate(&document, c.newSchema, &report)
+	if len(report.InternalErrors) > 0 {
+		return result, report
+	}
+	if report.HasErrors() {
+		result.Errors = detachPaths(report.ExternalErrors)
+		return result, nil
+	}
+
+	if len(operation.Variables) > 0 {
+		if err := c.variablesValidator.Validate(&document, c.newSchema, operation.Variables); err != nil {
+			result.Errors = append(result.Errors, operationreport.ExternalError{
+				Message: err.Error(),
+			})
+		}
+	}
+
+	c.deprecationsWalker.Walk(&document, c.newSchema, &report)
+	if report.HasErrors() {
+		return result, report
+	}
+	result.Warnings = c.deprecationsVisitor.warnings
+
+	return result, nil
+}
+
+// detachPaths copies the paths of the errors because they share their backing array with the path of the reused validator
+func detachPaths(errs []operationreport.ExternalError) []operationreport.ExternalError {
+	for i := range errs {
+		errs[i].Path = append(ast.Path(nil), errs[i].Path...)
+	}
+	return errs
+}
+
+// deprecatedUsageVisitor collects the fields, field arguments, input fields and enum values of an operation
+// which are deprecated in the new schema but weren't in the old schema
+type deprecatedUsageVisitor struct {
+	*astvisitor.Walker
+	operation, definition *ast.Document
+	oldSchema             *ast.Document
+	warnings              []operationreport.ExternalError
+}
+
+func (v *deprecatedUsageVisitor) EnterDocument(operation, definition *ast.Document) {
+	v.operation = operation
+	v.definition = definition
+	v.warnings = nil
+}
+
+func (v *deprecatedUsageVisitor) EnterField(ref int) {
+	fieldDefinition, exists := v.FieldDefinition(ref)
+	if !exists {
+		return
+	}
+
+	typeName := v.definition.NodeNameBytes(v.EnclosingTypeDefinition)
+	fieldName := v.operation.FieldNameBytes(ref)
+	oldFieldDefinition, oldFieldExists := v.oldFieldDefinition(typeName, fieldName)
+
+	if deprecated, reason := deprecation(v.definition, v.definition.FieldDefinitions[fieldDefinition].Directives.Refs); deprecated {
+		if !oldFieldExists || !isDeprecated(v.oldSchema, v.oldSchema.FieldDefinitions[oldFieldDefinition].Directives.Refs) {
+			v.warnings = append(v.warnings, operationreport.ExternalError{
+				Message:   fmt.Sprintf("The field %s.%s is deprecated. %s", typeName, fieldName, deprecationReasonOrDefault(reason)),
+				Path:      append(ast.Path(nil), v.Path...),
+				Locations: operationreport.LocationsFromPosition(v.operation.Fields[ref].Position),
+			})
+		}
+	}
+
+	for _, argument := range v.operation.FieldArguments(ref) {
+		argumentName := v.operation.ArgumentNameBytes(argument)
+		argumentDefinition, exists := inputValueByName(v.definition, v.definition.FieldDefinitionArgumentsDefinitions(fieldDefinition), argumentName)
+		if !exists {
+			continue
+		}
+		if deprecated, reason := deprecation(v.definition, v.definition.InputValueDefinitions[argumentDefinition].Directives.Refs); deprecated {
+			if !oldFieldExists || !v.oldArgumentDeprecated(oldFieldDefinition, argumentName) {
+				v.warnings = append(v.warnings, operationreport.ExternalError{
+					Message:   fmt.Sprintf(`Field "%s.%s" argument "%s" is deprecated. %s`, typeName, fieldName, argumentName, deprecationReasonOrDefault(reason)),
+					Path:      append(ast.Path(nil), v.Path...),
+					Locations: operationreport.LocationsFromPosition(v.operation.Arguments[argument].Position),
+				})
+			}
+		}
+		v.checkValue(v.operation.ArgumentValue(argument), v.definition.ResolveTypeNameBytes(v.definition.InputValueDefinitionType(argumentDefinition)))
+	}
+}
+
+func (v *deprecatedUsageVisitor) EnterVariableDefinition(ref int) {
+	if !v.operation.VariableDefinitionHasDefaultValue(ref) {
+		return
+	}
+	v.checkValue(v.operation.VariableDefinitionDefaultValue(ref), v.operation.ResolveTypeNameBytes(v.operation.VariableDefinitions[ref].Type))
+}
+
+// checkValue warns about the input fields and enum values in a value of the named input type
+// which are deprecated in the new schema but weren't in the old one
+func (v *deprecatedUsageVisitor) checkValue(value ast.Value, typeName ast.ByteSlice) {
+	switch value.Kind {
+	case ast.ValueKindList:
+		for _, item := range v.operation.ListValues[value.Ref].Refs {
+			v.checkValue(v.operation.Value(item), typeName)
+		}
+	case ast.ValueKindObject:
+		node, exists := v.definition.Index.FirstNodeByNameBytes(typeName)
+		if !exists || node.Kind != ast.NodeKindInputObjectTypeDefinition {
+			return
+		}
+		for _, objectField := range v.operation.ObjectValues[value.Ref].Refs {
+			fieldName := v.operation.ObjectFieldNameBytes(objectField)
+			inputField, exists := inputValueByName(v.definition, v.definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs, fieldName)
+			if !exists {
+				continue
+			}
+			if deprecated, reason := deprecation(v.definition, v.definition.InputValueDefinitions[inputField].Directives.Refs); deprecated && !v.oldValueDeprecated(typeName, fieldName) {
+				v.warnings = append(v.warnings, operationreport.ExternalError{
+					Message:   fmt.Sprintf("The input field %s.%s is deprecated. %s", typeName, fieldName, deprecationReasonOrDefault(reason)),
+					Path:      append(ast.Path(nil), v.Path...),
+					Locations: operationreport.LocationsFromPosition(v.operation.ObjectFields[objectField].Position),
+				})
+			}
+			v.checkValue(v.operation.ObjectFieldValue(objectField), v.definition.ResolveTypeNameBytes(v.definition.InputValueDefinitionType(inputField)))
+		}
+	case ast.ValueKindEnum:
+		node, exists := v.definition.Index.FirstNodeByNameBytes(typeName)
+		if !exists || node.Kind != ast.NodeKindEnumTypeDefinition {
+			return
+		}
+		valueName := v.operation.EnumValueNameBytes(value.Ref)
+		enumValue, exists := enumValueByName(v.definition, v.definition.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs, valueName)
+		if !exists {
+			return
+		}
+		if deprecated, reason := deprecation(v.definition, v.definition.EnumValueDefinitions[enumValue].Directives.Refs); deprecated && !v.oldValueDeprecated(typeName, valueName) {
+			v.warnings = append(v.warnings, operationreport.ExternalError{
+				Message:   fmt.Sprintf(`The enum value "%s.%s" is deprecated. %s`, typeName, valueName, deprecationReasonOrDefault(reason)),
+				Path:      append(ast.Path(nil), v.Path...),
+				Locations: operationreport.LocationsFromPosition(value.Position),
+			})
+		}
+	}
+}
+
+func (v *deprecatedUsageVisitor) oldArgumentDeprecated(oldFieldDefinition int, argumentName ast.ByteSlice) bool {
+	argument, exists := inputValueByName(v.oldSchema, v.oldSchema.FieldDefinitionArgumentsDefinitions(oldFieldDefinition), argumentName)
+	return exists && isDeprecated(v.oldSchema, v.oldSchema.InputValueDefinitions[argument].Directives.Refs)
+}
+
+// oldValueDeprecated returns true if the input field or enum value was already deprecated in the old schema
+func (v *deprecatedUsageVisitor) oldValueDeprecated(typeName, name ast.ByteSlice) bool {
+	node, exists := v.oldSchema.Index.FirstNodeByNameBytes(typeName)
+	if !exists {
+		return false
+	}
+	switch node.Kind {
+	case ast.NodeKindInputObjectTypeDefinition:
+		inputField, exists := inputValueByName(v.oldSchema, v.oldSchema.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs, name)
+		return exists && isDeprecated(v.oldSchema, v.oldSchema.InputValueDefinitions[inputField].Directives.Refs)
+	case ast.NodeKindEnumTypeDefinition:
+		enumValue, exists := enumValueByName(v.oldSchema, v.oldSchema.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs, name)
+		return exists && isDeprecated(v.oldSchema, v.oldSchema.EnumValueDefinitions[enumValue].Directives.Refs)
+	default:
+		return false
+	}
+}
+
+func (v *deprecatedUsageVisitor) oldFieldDefinition(typeName, fieldName ast.ByteSlice) (int, bool) {
+	node, exists := v.oldSchema.Index.FirstNodeByNameBytes(typeName)
+	if !exists {
+		return ast.InvalidRef, false
+	}
+	return v.oldSchema.NodeFieldDefinitionByName(node, fieldName)
+}
+
+func isDeprecated(doc *ast.Document, directiveRefs []int) bool {
+	deprecated, _ := deprecation(doc, directiveRefs)
+	return deprecated
+}
+
+func deprecationReasonOrDefault(reason string) string {
+	if reason == "" {
+		return defaultDeprecationReason
+	}
+	return reason
+}
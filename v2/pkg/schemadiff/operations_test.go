@@ -0,0 +1,218 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const operationsOldSchema = `
+	type Query {
+		user(id: ID!): User
+		users(first: Int, after: String @deprecated): [User]
+	}
+	type User {
+		id: ID!
+		name: String
+		email: String
+		nickname: String @deprecated(reason: "Use name")
+	}
+`
+
+const operationsNewSchema = `
+	type Query {
+		user(id: Int!): User
+		users(first: Int @deprecated(reason: "Use limit"), after: String @deprecated, limit: Int): [User]
+	}
+	type User {
+		id: ID!
+		name: String @deprecated
+		nickname: String @deprecated(reason: "Use name")
+	}
+`
+
+func TestCheckOperationsSDL(t *testing.T) {
+	results, err := CheckOperationsSDL(operationsOldSchema, operationsNewSchema, []Operation{
+		{
+			Name:    "unchanged",
+			Content: `query Unchanged { users(after: "a") { id nickname } }`,
+		},
+		{
+			Name:    "removedField",
+			Content: `query RemovedField { user(id: 1) { id email } }`,
+		},
+		{
+			Name:      "changedVariableType",
+			Content:   `query ChangedVariableType($id: ID!) { user(id: $id) { id } }`,
+			Variables: []byte(`{"id":"1"}`),
+		},
+		{
+			Name: "newlyDeprecated",
+			Content: `query NewlyDeprecated {
+  users(first: 10) {
+    name
+  }
+}`,
+		},
+		{
+			Name:    "syntaxError",
+			Content: `query {`,
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.Equal(t, OperationResult{Name: "unchanged"}, results[0])
+
+	assert.Equal(t, "removedField", results[1].Name)
+	require.Len(t, results[1].Errors, 1)
+	assert.Equal(t, `field: email not defined on type: User`, results[1].Errors[0].Message)
+	assert.Equal(t, `query.user`, results[1].Errors[0].Path.DotDelimitedString())
+	assert.True(t, results[1].HasErrors())
+
+	assert.Equal(t, "changedVariableType", results[2].Name)
+	require.Len(t, results[2].Errors, 1)
+	assert.Equal(t, `Variable "$id" of type "ID!" used in position expecting type "Int!".`, results[2].Errors[0].Message)
+	assert.Equal(t, []operationreport.Location{{Line: 1, Column: 27}, {Line: 1, Column: 48}}, results[2].Errors[0].Locations)
+
+	assert.Equal(t, OperationResult{
+		Name: "newlyDeprecated",
+		Warnings: []operationreport.ExternalError{
+			{
+				Message:   `Field "Query.users" argument "first" is deprecated. Use limit`,
+				Path:      ast.Path{{Kind: ast.FieldName, FieldName: []byte("query")}},
+				Locations: []operationreport.Location{{Line: 2, Column: 9}},
+			},
+			{
+				Message: "The field User.name is deprecated. No longer supported",
+				Path: ast.Path{
+					{Kind: ast.FieldName, FieldName: []byte("query")},
+					{Kind: ast.FieldName, FieldName: []byte("users")},
+				},
+				Locations: []operationreport.Location{{Line: 3, Column: 5}},
+			},
+		},
+	}, results[3])
+	assert.False(t, results[3].HasErrors())
+
+	assert.Equal(t, "syntaxError", results[4].Name)
+	assert.True(t, results[4].HasErrors())
+}
+
+func TestCheckOperationsSDL_Variables(t *testing.T) {
+	results, err := CheckOperationsSDL(operationsOldSchema, operationsNewSchema, []Operation{
+		{
+			Name:      "invalidVariables",
+			Content:   `query Users($first: Int) { users(limit: $first) { id } }`,
+			Variables: []byte(`{"first":"ten"}`),
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Errors, 1)
+	assert.Contains(t, results[0].Errors[0].Message, `Variable "$first" got invalid value "ten"`)
+}
+
+func TestCheckOperationsSDL_DeprecatedValues(t *testing.T) {
+	oldSchema := `
+		type Query {
+			users(filter: UserFilter, role: Role): [User]
+		}
+		input UserFilter {
+			name: String
+			age: Int
+			role: Role
+			roles: [Role]
+			legacy: String @deprecated
+		}
+		enum Role { ADMIN USER GUEST @deprecated }
+		type User { id: ID! }
+	`
+	newSchema := `
+		type Query {
+			users(filter: UserFilter, role: Role): [User]
+		}
+		input UserFilter {
+			name: String @deprecated(reason: "Use search")
+			age: Int
+			role: Role
+			roles: [Role]
+			legacy: String @deprecated
+		}
+		enum Role { ADMIN USER @deprecated(reason: "Use MEMBER") GUEST @deprecated MEMBER }
+		type User { id: ID! }
+	`
+
+	path := ast.Path{{Kind: ast.FieldName, FieldName: []byte("query")}}
+
+	t.Run("literals in arguments", func(t *testing.T) {
+		results, err := CheckOperationsSDL(oldSchema, newSchema, []Operation{
+			{
+				Name: "literals",
+				Content: `query Users {
+  users(filter: {name: "a", legacy: "b", role: GUEST, roles: [ADMIN, USER]}, role: USER) {
+    id
+  }
+}`,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, OperationResult{
+			Name: "literals",
+			Warnings: []operationreport.ExternalError{
+				{
+					Message:   "The input field UserFilter.name is deprecated. Use search",
+					Path:      path,
+					Locations: []operationreport.Location{{Line: 2, Column: 18}},
+				},
+				{
+					Message:   `The enum value "Role.USER" is deprecated. Use MEMBER`,
+					Path:      path,
+					Locations: []operationreport.Location{{Line: 2, Column: 70}},
+				},
+				{
+					Message:   `The enum value "Role.USER" is deprecated. Use MEMBER`,
+					Path:      path,
+					Locations: []operationreport.Location{{Line: 2, Column: 84}},
+				},
+			},
+		}, results[0])
+	})
+
+	t.Run("variable default values", func(t *testing.T) {
+		results, err := CheckOperationsSDL(oldSchema, newSchema, []Operation{
+			{
+				Name:    "defaults",
+				Content: `query Users($filter: UserFilter = {name: "a", roles: [ADMIN, USER]}, $role: Role = USER) { users(filter: $filter, role: $role) { id } }`,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		assert.Equal(t, OperationResult{
+			Name: "defaults",
+			Warnings: []operationreport.ExternalError{
+				{
+					Message:   "The input field UserFilter.name is deprecated. Use search",
+					Path:      path,
+					Locations: []operationreport.Location{{Line: 1, Column: 36}},
+				},
+				{
+					Message:   `The enum value "Role.USER" is deprecated. Use MEMBER`,
+					Path:      path,
+					Locations: []operationreport.Location{{Line: 1, Column: 62}},
+				},
+				{
+					Message:   `The enum value "Role.USER" is deprecated. Use MEMBER`,
+					Path:      path,
+					Locations: []operationreport.Location{{Line: 1, Column: 84}},
+				},
+			},
+		}, results[0])
+	})
+}
@@ -0,0 +1,144 @@
+// Package schemadiff compares two GraphQL schemas and classifies every change as breaking, dangerous or safe.
+//
+// Breaking changes make valid operations invalid, e.g. removing a field or adding a required argument.
+// Dangerous changes keep operations valid but might change the behaviour of clients, e.g. adding an enum value.
+// Safe changes can't affect existing clients, e.g. adding a field.
+// Renames can't be told apart from a removal and an addition and are reported as such.
+//
+// CheckOperations complements the diff by validating a corpus of client operations, e.g. persisted queries, against the new schema.
+package schemadiff
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+)
+
+type Criticality string
+
+const (
+	CriticalityBreaking  Criticality = "BREAKING"
+	CriticalityDangerous Criticality = "DANGEROUS"
+	CriticalitySafe      Criticality = "SAFE"
+)
+
+type ChangeType string
+
+const (
+	TypeAdded                       ChangeType = "TYPE_ADDED"
+	TypeRemoved                     ChangeType = "TYPE_REMOVED"
+	TypeKindChanged                 ChangeType = "TYPE_KIND_CHANGED"
+	RootOperationTypeChanged        ChangeType = "ROOT_OPERATION_TYPE_CHANGED"
+	FieldAdded                      ChangeType = "FIELD_ADDED"
+	FieldRemoved                    ChangeType = "FIELD_REMOVED"
+	FieldTypeChanged                ChangeType = "FIELD_TYPE_CHANGED"
+	RequiredArgumentAdded           ChangeType = "REQUIRED_ARGUMENT_ADDED"
+	OptionalArgumentAdded           ChangeType = "OPTIONAL_ARGUMENT_ADDED"
+	ArgumentRemoved                 ChangeType = "ARGUMENT_REMOVED"
+	ArgumentTypeChanged             ChangeType = "ARGUMENT_TYPE_CHANGED"
+	ArgumentDefaultValueChanged     ChangeType = "ARGUMENT_DEFAULT_VALUE_CHANGED"
+	RequiredInputFieldAdded         ChangeType = "REQUIRED_INPUT_FIELD_ADDED"
+	OptionalInputFieldAdded         ChangeType = "OPTIONAL_INPUT_FIELD_ADDED"
+	InputFieldRemoved               ChangeType = "INPUT_FIELD_REMOVED"
+	InputFieldTypeChanged           ChangeType = "INPUT_FIELD_TYPE_CHANGED"
+	InputFieldDefaultValueChanged   ChangeType = "INPUT_FIELD_DEFAULT_VALUE_CHANGED"
+	EnumValueAdded                  ChangeType = "ENUM_VALUE_ADDED"
+	EnumValueRemoved                ChangeType = "ENUM_VALUE_REMOVED"
+	UnionMemberAdded                ChangeType = "UNION_MEMBER_ADDED"
+	UnionMemberRemoved              ChangeType = "UNION_MEMBER_REMOVED"
+	ImplementedInterfaceAdded       ChangeType = "IMPLEMENTED_INTERFACE_ADDED"
+	ImplementedInterfaceRemoved     ChangeType = "IMPLEMENTED_INTERFACE_REMOVED"
+	DirectiveAdded                  ChangeType = "DIRECTIVE_ADDED"
+	DirectiveRemoved                ChangeType = "DIRECTIVE_REMOVED"
+	DirectiveLocationAdded          ChangeType = "DIRECTIVE_LOCATION_ADDED"
+	DirectiveLocationRemoved        ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	DirectiveRepeatableAdded        ChangeType = "DIRECTIVE_REPEATABLE_ADDED"
+	DirectiveRepeatableRemoved      ChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+	DeprecationAdded                ChangeType = "DEPRECATION_ADDED"
+	DeprecationRemoved              ChangeType = "DEPRECATION_REMOVED"
+	DeprecationReasonChanged        ChangeType = "DEPRECATION_REASON_CHANGED"
+	DirectiveUsageAdded             ChangeType = "DIRECTIVE_USAGE_ADDED"
+	DirectiveUsageRemoved           ChangeType = "DIRECTIVE_USAGE_REMOVED"
+	DirectiveUsageChanged           ChangeType = "DIRECTIVE_USAGE_CHANGED"
+	RequiredDirectiveArgumentAdded  ChangeType = "REQUIRED_DIRECTIVE_ARGUMENT_ADDED"
+	OptionalDirectiveArgumentAdded  ChangeType = "OPTIONAL_DIRECTIVE_ARGUMENT_ADDED"
+	DirectiveArgumentRemoved        ChangeType = "DIRECTIVE_ARGUMENT_REMOVED"
+	DirectiveArgumentTypeChanged    ChangeType = "DIRECTIVE_ARGUMENT_TYPE_CHANGED"
+	DirectiveArgumentDefaultChanged ChangeType = "DIRECTIVE_ARGUMENT_DEFAULT_VALUE_CHANGED"
+)
+
+// Change is a single difference between two schemas.
+// Coordinate is the schema coordinate of the changed element, e.g. Query.user(id:) or @cached(ttl:)
+type Change struct {
+	Type        ChangeType  `json:"type"`
+	Criticality Criticality `json:"criticality"`
+	Coordinate  string      `json:"coordinate"`
+	Message     string      `json:"message"`
+}
+
+type Changes []Change
+
+// HasBreakingChanges returns true if at least one change is breaking
+func (c Changes) HasBreakingChanges() bool {
+	for i := range c {
+		if c[i].Criticality == CriticalityBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// ByCriticality returns all changes with the given criticality
+func (c Changes) ByCriticality(criticality Criticality) Changes {
+	var out Changes
+	for i := range c {
+		if c[i].Criticality == criticality {
+			out = append(out, c[i])
+		}
+	}
+	return out
+}
+
+// DiffSDL parses both schemas, merges the base schema and type extensions into them and compares them
+func DiffSDL(oldSchema, newSchema string) (Changes, error) {
+	oldDefinition, err := parseSchema(oldSchema)
+	if err != nil {
+		return nil, err
+	}
+	newDefinition, err := parseSchema(newSchema)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(oldDefinition, newDefinition), nil
+}
+
+// Diff compares two schemas.
+// Both definitions are expected to be normalized, e.g. type extensions merged into their types.
+// Changes are reported in the order of the old schema, followed by the additions of the new schema.
+func Diff(oldSchema, newSchema *ast.Document) Changes {
+	d := &differ{
+		old: oldSchema,
+		new: newSchema,
+	}
+	d.diff()
+	return d.changes
+}
+
+func parseSchema(schema string) (*ast.Document, error) {
+	definition, report := astparser.ParseGraphqlDocumentString(schema)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	if err := asttransform.MergeDefinitionWithBaseSchema(&definition); err != nil {
+		return nil, err
+	}
+
+	astnormalization.NormalizeDefinition(&definition, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	return &definition, nil
+}
@@ -0,0 +1,273 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSDL(t *testing.T) {
+	run := func(t *testing.T, oldSchema, newSchema string, expected Changes) {
+		t.Helper()
+		changes, err := DiffSDL(oldSchema, newSchema)
+		require.NoError(t, err)
+		assert.Equal(t, expected, changes)
+	}
+
+	t.Run("identical schemas", func(t *testing.T) {
+		schema := `
+			type Query { user(id: ID!): User }
+			type User { id: ID! name: String @deprecated }
+		`
+		run(t, schema, schema, nil)
+	})
+
+	t.Run("types", func(t *testing.T) {
+		run(t, `
+			type Query { a: String }
+			type Removed { a: String }
+			scalar Changed
+		`, `
+			type Query { a: String }
+			enum Changed { A }
+			input Added { a: String }
+		`, Changes{
+			{Type: TypeRemoved, Criticality: CriticalityBreaking, Coordinate: "Removed", Message: "Removed was removed."},
+			{Type: TypeKindChanged, Criticality: CriticalityBreaking, Coordinate: "Changed", Message: "Changed changed from a Scalar type to an Enum type."},
+			{Type: TypeAdded, Criticality: CriticalitySafe, Coordinate: "Added", Message: "Added was added."},
+		})
+	})
+
+	t.Run("root operation types", func(t *testing.T) {
+		run(t, `
+			schema { query: Query mutation: Mutations }
+			type Query { a: String }
+			type Mutations { a: String }
+		`, `
+			schema { query: Query mutation: Actions subscription: Events }
+			type Query { a: String }
+			type Mutations { a: String }
+			type Actions { a: String }
+			type Events { a: String }
+		`, Changes{
+			{Type: RootOperationTypeChanged, Criticality: CriticalityBreaking, Coordinate: "schema", Message: "Root mutation type changed from Mutations to Actions."},
+			{Type: RootOperationTypeChanged, Criticality: CriticalitySafe, Coordinate: "schema", Message: "Root subscription type Events was added."},
+			{Type: TypeAdded, Criticality: CriticalitySafe, Coordinate: "Actions", Message: "Actions was added."},
+			{Type: TypeAdded, Criticality: CriticalitySafe, Coordinate: "Events", Message: "Events was added."},
+		})
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		run(t, `
+			type Query {
+				removed: String
+				toNonNull: String
+				toNullable: String!
+				toList: String
+				listItemToNonNull: [String]
+				deprecated: String
+				undeprecated: String @deprecated
+				reasonChanged: String @deprecated(reason: "old")
+			}
+		`, `
+			type Query {
+				toNonNull: String!
+				toNullable: String
+				toList: [String]
+				listItemToNonNull: [String!]!
+				deprecated: String @deprecated(reason: "Use toNonNull")
+				undeprecated: String
+				reasonChanged: String @deprecated(reason: "new")
+				added: String
+			}
+		`, Changes{
+			{Type: FieldRemoved, Criticality: CriticalityBreaking, Coordinate: "Query.removed", Message: "Query.removed was removed."},
+			{Type: FieldTypeChanged, Criticality: CriticalitySafe, Coordinate: "Query.toNonNull", Message: "Query.toNonNull changed type from String to String!."},
+			{Type: FieldTypeChanged, Criticality: CriticalityBreaking, Coordinate: "Query.toNullable", Message: "Query.toNullable changed type from String! to String."},
+			{Type: FieldTypeChanged, Criticality: CriticalityBreaking, Coordinate: "Query.toList", Message: "Query.toList changed type from String to [String]."},
+			{Type: FieldTypeChanged, Criticality: CriticalitySafe, Coordinate: "Query.listItemToNonNull", Message: "Query.listItemToNonNull changed type from [String] to [String!]!."},
+			{Type: DeprecationAdded, Criticality: CriticalitySafe, Coordinate: "Query.deprecated", Message: `Query.deprecated was deprecated with reason "Use toNonNull".`},
+			{Type: DeprecationRemoved, Criticality: CriticalitySafe, Coordinate: "Query.undeprecated", Message: "Query.undeprecated is no longer deprecated."},
+			{Type: DeprecationReasonChanged, Criticality: CriticalitySafe, Coordinate: "Query.reasonChanged", Message: `Deprecation reason of Query.reasonChanged changed from "old" to "new".`},
+			{Type: FieldAdded, Criticality: CriticalitySafe, Coordinate: "Query.added", Message: "Query.added was added."},
+		})
+	})
+
+	t.Run("arguments", func(t *testing.T) {
+		run(t, `
+			type Query {
+				users(removed: Int, toNullable: Int!, toNonNull: Int, defaultChanged: Int = 10, defaultRemoved: Int = 1): [String]
+			}
+		`, `
+			type Query {
+				users(toNullable: Int, toNonNull: Int!, defaultChanged: Int = 20, defaultRemoved: Int, required: String!, optional: String, requiredWithDefault: String! = "a"): [String]
+			}
+		`, Changes{
+			{Type: ArgumentRemoved, Criticality: CriticalityBreaking, Coordinate: "Query.users(removed:)", Message: "Argument removed was removed from Query.users."},
+			{Type: ArgumentTypeChanged, Criticality: CriticalitySafe, Coordinate: "Query.users(toNullable:)", Message: "Query.users(toNullable:) changed type from Int! to Int."},
+			{Type: ArgumentTypeChanged, Criticality: CriticalityBreaking, Coordinate: "Query.users(toNonNull:)", Message: "Query.users(toNonNull:) changed type from Int to Int!."},
+			{Type: ArgumentDefaultValueChanged, Criticality: CriticalityDangerous, Coordinate: "Query.users(defaultChanged:)", Message: "Default value of Query.users(defaultChanged:) changed from 10 to 20."},
+			{Type: ArgumentDefaultValueChanged, Criticality: CriticalityDangerous, Coordinate: "Query.users(defaultRemoved:)", Message: "Default value 1 was removed from Query.users(defaultRemoved:)."},
+			{Type: RequiredArgumentAdded, Criticality: CriticalityBreaking, Coordinate: "Query.users(required:)", Message: "Required argument required was added to Query.users."},
+			{Type: OptionalArgumentAdded, Criticality: CriticalityDangerous, Coordinate: "Query.users(optional:)", Message: "Optional argument optional was added to Query.users."},
+			{Type: OptionalArgumentAdded, Criticality: CriticalityDangerous, Coordinate: "Query.users(requiredWithDefault:)", Message: "Optional argument requiredWithDefault was added to Query.users."},
+		})
+	})
+
+	t.Run("input fields", func(t *testing.T) {
+		run(t, `
+			type Query { a(filter: Filter): String }
+			input Filter { removed: String name: String! limit: Int = 10 }
+		`, `
+			type Query { a(filter: Filter): String }
+			input Filter { name: [String!] limit: Int = 10 @deprecated required: ID! optional: ID }
+		`, Changes{
+			{Type: InputFieldRemoved, Criticality: CriticalityBreaking, Coordinate: "Filter.removed", Message: "Filter.removed was removed."},
+			{Type: InputFieldTypeChanged, Criticality: CriticalityBreaking, Coordinate: "Filter.name", Message: "Filter.name changed type from String! to [String!]."},
+			{Type: DeprecationAdded, Criticality: CriticalitySafe, Coordinate: "Filter.limit", Message: "Filter.limit was deprecated."},
+			{Type: RequiredInputFieldAdded, Criticality: CriticalityBreaking, Coordinate: "Filter.required", Message: "Required input field Filter.required was added."},
+			{Type: OptionalInputFieldAdded, Criticality: CriticalityDangerous, Coordinate: "Filter.optional", Message: "Optional input field Filter.optional was added."},
+		})
+	})
+
+	t.Run("enum values", func(t *testing.T) {
+		run(t, `
+			type Query { a: Color }
+			enum Color { RED GREEN BLUE }
+		`, `
+			type Query { a: Color }
+			enum Color { RED GREEN @deprecated(reason: "Use BLUE") BLUE PURPLE }
+		`, Changes{
+			{Type: DeprecationAdded, Criticality: CriticalitySafe, Coordinate: "Color.GREEN", Message: `Color.GREEN was deprecated with reason "Use BLUE".`},
+			{Type: EnumValueAdded, Criticality: CriticalityDangerous, Coordinate: "Color.PURPLE", Message: "PURPLE was added to enum type Color."},
+		})
+		run(t, `
+			type Query { a: Color }
+			enum Color { RED GREEN }
+		`, `
+			type Query { a: Color }
+			enum Color { RED }
+		`, Changes{
+			{Type: EnumValueRemoved, Criticality: CriticalityBreaking, Coordinate: "Color.GREEN", Message: "GREEN was removed from enum type Color."},
+		})
+	})
+
+	t.Run("union members", func(t *testing.T) {
+		run(t, `
+			type Query { a: SearchResult }
+			type User { id: ID }
+			type Post { id: ID }
+			type Comment { id: ID }
+			union SearchResult = User | Post
+		`, `
+			type Query { a: SearchResult }
+			type User { id: ID }
+			type Post { id: ID }
+			type Comment { id: ID }
+			union SearchResult = User | Comment
+		`, Changes{
+			{Type: UnionMemberRemoved, Criticality: CriticalityBreaking, Coordinate: "SearchResult", Message: "Post was removed from union type SearchResult."},
+			{Type: UnionMemberAdded, Criticality: CriticalityDangerous, Coordinate: "SearchResult", Message: "Comment was added to union type SearchResult."},
+		})
+	})
+
+	t.Run("implemented interfaces", func(t *testing.T) {
+		run(t, `
+			type Query { a: Node }
+			interface Node { id: ID! }
+			interface Named { name: String }
+			type User implements Node { id: ID! name: String }
+		`, `
+			type Query { a: Node }
+			interface Node { id: ID! }
+			interface Named { name: String }
+			type User implements Named { id: ID! name: String }
+		`, Changes{
+			{Type: ImplementedInterfaceRemoved, Criticality: CriticalityBreaking, Coordinate: "User", Message: "User no longer implements interface Node."},
+			{Type: ImplementedInterfaceAdded, Criticality: CriticalityDangerous, Coordinate: "User", Message: "User now implements interface Named."},
+		})
+	})
+
+	t.Run("type extensions are merged before comparing", func(t *testing.T) {
+		run(t, `
+			type Query { a: String }
+			extend type Query { b: String }
+		`, `
+			type Query { a: String b: String }
+		`, nil)
+	})
+
+	t.Run("directives", func(t *testing.T) {
+		run(t, `
+			type Query { a: String }
+			directive @removed on FIELD
+			directive @cached(ttl: Int, scope: String) repeatable on FIELD_DEFINITION | OBJECT
+		`, `
+			type Query { a: String }
+			directive @cached(ttl: Int!, region: String!, tags: [String]) on FIELD_DEFINITION | INTERFACE
+			directive @added on FIELD
+		`, Changes{
+			{Type: DirectiveRemoved, Criticality: CriticalityBreaking, Coordinate: "@removed", Message: "@removed was removed."},
+			{Type: DirectiveRepeatableRemoved, Criticality: CriticalityBreaking, Coordinate: "@cached", Message: "@cached is no longer repeatable."},
+			{Type: DirectiveLocationRemoved, Criticality: CriticalityBreaking, Coordinate: "@cached", Message: "Location OBJECT was removed from @cached."},
+			{Type: DirectiveLocationAdded, Criticality: CriticalitySafe, Coordinate: "@cached", Message: "Location INTERFACE was added to @cached."},
+			{Type: DirectiveArgumentTypeChanged, Criticality: CriticalityBreaking, Coordinate: "@cached(ttl:)", Message: "@cached(ttl:) changed type from Int to Int!."},
+			{Type: DirectiveArgumentRemoved, Criticality: CriticalityBreaking, Coordinate: "@cached(scope:)", Message: "Argument scope was removed from @cached."},
+			{Type: RequiredDirectiveArgumentAdded, Criticality: CriticalityBreaking, Coordinate: "@cached(region:)", Message: "Required argument region was added to @cached."},
+			{Type: OptionalDirectiveArgumentAdded, Criticality: CriticalitySafe, Coordinate: "@cached(tags:)", Message: "Optional argument tags was added to @cached."},
+			{Type: DirectiveAdded, Criticality: CriticalitySafe, Coordinate: "@added", Message: "@added was added."},
+		})
+	})
+
+	t.Run("applied directives", func(t *testing.T) {
+		run(t, `
+			type Query {
+				a(filter: Filter @tag(name: "filter")): String @cached(ttl: 10) @tag(name: "a") @deprecated
+				b: String @cached(ttl: 10)
+			}
+			input Filter @oneOf { id: ID name: String }
+			input Search { id: ID name: String @tag(name: "name") }
+			enum Color { RED @tag(name: "red") }
+			directive @cached(ttl: Int) on FIELD_DEFINITION
+			directive @tag(name: String!) repeatable on FIELD_DEFINITION | INPUT_FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM_VALUE | OBJECT
+		`, `
+			type Query @tag(name: "query") {
+				a(filter: Filter): String @cached(ttl: 10) @tag(name: "a") @tag(name: "b")
+				b: String @cached(ttl: 20)
+			}
+			input Filter { id: ID name: String }
+			input Search @oneOf { id: ID name: String @tag(name: "name") }
+			enum Color { RED }
+			directive @cached(ttl: Int) on FIELD_DEFINITION
+			directive @tag(name: String!) repeatable on FIELD_DEFINITION | INPUT_FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM_VALUE | OBJECT
+		`, Changes{
+			{Type: DirectiveUsageAdded, Criticality: CriticalityDangerous, Coordinate: "Query", Message: "Directive @tag was added to Query."},
+			{Type: DeprecationRemoved, Criticality: CriticalitySafe, Coordinate: "Query.a", Message: "Query.a is no longer deprecated."},
+			{Type: DirectiveUsageChanged, Criticality: CriticalityDangerous, Coordinate: "Query.a", Message: `Directive @tag on Query.a changed from @tag(name: "a") to @tag(name: "a") @tag(name: "b").`},
+			{Type: DirectiveUsageRemoved, Criticality: CriticalityDangerous, Coordinate: "Query.a(filter:)", Message: "Directive @tag was removed from Query.a(filter:)."},
+			{Type: DirectiveUsageChanged, Criticality: CriticalityDangerous, Coordinate: "Query.b", Message: "Directive @cached on Query.b changed from @cached(ttl: 10) to @cached(ttl: 20)."},
+			{Type: DirectiveUsageRemoved, Criticality: CriticalityDangerous, Coordinate: "Filter", Message: "Directive @oneOf was removed from Filter."},
+			{Type: DirectiveUsageAdded, Criticality: CriticalityBreaking, Coordinate: "Search", Message: "Directive @oneOf was added to Search."},
+			{Type: DirectiveUsageRemoved, Criticality: CriticalityDangerous, Coordinate: "Color.RED", Message: "Directive @tag was removed from Color.RED."},
+		})
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		_, err := DiffSDL(`type Query {`, `type Query { a: String }`)
+		assert.Error(t, err)
+	})
+}
+
+func TestChanges(t *testing.T) {
+	changes := Changes{
+		{Type: FieldAdded, Criticality: CriticalitySafe, Coordinate: "Query.a"},
+		{Type: EnumValueAdded, Criticality: CriticalityDangerous, Coordinate: "Color.RED"},
+	}
+	assert.False(t, changes.HasBreakingChanges())
+	assert.Equal(t, Changes{changes[1]}, changes.ByCriticality(CriticalityDangerous))
+
+	changes = append(changes, Change{Type: FieldRemoved, Criticality: CriticalityBreaking, Coordinate: "Query.b"})
+	assert.True(t, changes.HasBreakingChanges())
+	assert.Equal(t, Changes{changes[2]}, changes.ByCriticality(CriticalityBreaking))
+}
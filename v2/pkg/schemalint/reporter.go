@@ -0,0 +1,315 @@
+package schemalint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+)
+
+var (
+	lintDirectiveName      = []byte("lint")
+	disableArgumentName    = []byte("disable")
+	disableNextLineComment = []byte("lint-disable-next-line")
+)
+
+// Reporter collects the findings of the rules and drops the suppressed ones
+type Reporter struct {
+	definition *ast.Document
+	severities map[string]Severity
+	findings   Findings
+	// suppressedLines maps a line to the rules disabled by a comment above it, nil disables all rules
+	suppressedLines map[uint32][]string
+	// parents maps field definitions, input value definitions and enum value definitions to their parent node
+	parents map[ast.Node]ast.Node
+}
+
+// Report adds a finding of the rule for the schema element.
+// The severity is replaced by the one configured for the rule through WithSeverity.
+func (r *Reporter) Report(rule string, severity Severity, node ast.Node, message string) {
+	if r.isSuppressed(rule, node) {
+		return
+	}
+	if configured, ok := r.severities[rule]; ok {
+		severity = configured
+	}
+
+	pos := r.position(node)
+	r.findings = append(r.findings, Finding{
+		Rule:       rule,
+		Severity:   severity,
+		Coordinate: r.Coordinate(node),
+		Message:    message,
+		Line:       pos.LineStart,
+		Column:     pos.CharStart,
+	})
+}
+
+// Coordinate returns the schema coordinate of a type, field, argument, input field, enum value or directive definition
+func (r *Reporter) Coordinate(node ast.Node) string {
+	name := r.definition.Input.ByteSliceString(r.nameReference(node))
+	parent, hasParent := r.parents[node]
+
+	switch node.Kind {
+	case ast.NodeKindDirectiveDefinition:
+		return "@" + name
+	case ast.NodeKindFieldDefinition, ast.NodeKindEnumValueDefinition:
+		if hasParent {
+			return r.Coordinate(parent) + "." + name
+		}
+	case ast.NodeKindInputValueDefinition:
+		if !hasParent {
+			break
+		}
+		if parent.Kind == ast.NodeKindInputObjectTypeDefinition || parent.Kind == ast.NodeKindInputObjectTypeExtension {
+			return r.Coordinate(parent) + "." + name
+		}
+		return fmt.Sprintf("%s(%s:)", r.Coordinate(parent), name)
+	}
+	return name
+}
+
+// Parent returns the type of a field definition, input field or enum value,
+// or the field or directive definition of an argument
+func (r *Reporter) Parent(node ast.Node) (ast.Node, bool) {
+	parent, ok := r.parents[node]
+	return parent, ok
+}
+
+func (r *Reporter) reset(definition *ast.Document) {
+	r.definition = definition
+	r.findings = nil
+	r.indexSuppressedLines()
+	r.indexParents()
+}
+
+// indexSuppressedLines collects the lint-disable-next-line comments, which are not part of the AST
+func (r *Reporter) indexSuppressedLines() {
+	r.suppressedLines = map[uint32][]string{}
+	var (
+		pending    []string
+		hasPending bool
+	)
+	for i, line := range bytes.Split(r.definition.Input.RawBytes, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '#' {
+			if hasPending {
+				r.suppressedLines[uint32(i+1)] = pending
+				pending, hasPending = nil, false
+			}
+			continue
+		}
+		comment := bytes.TrimSpace(line[1:])
+		if !bytes.HasPrefix(comment, disableNextLineComment) {
+			continue
+		}
+		rules := bytes.FieldsFunc(comment[len(disableNextLineComment):], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(rules) == 0 || (hasPending && pending == nil) {
+			pending, hasPending = nil, true
+			continue
+		}
+		for _, rule := range rules {
+			pending = append(pending, string(rule))
+		}
+		hasPending = true
+	}
+}
+
+func (r *Reporter) indexParents() {
+	r.parents = map[ast.Node]ast.Node{}
+	for _, node := range r.definition.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition, ast.NodeKindObjectTypeExtension,
+			ast.NodeKindInterfaceTypeDefinition, ast.NodeKindInterfaceTypeExtension:
+			for _, fieldRef := range r.definition.NodeFieldDefinitions(node) {
+				field := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: fieldRef}
+				r.parents[field] = node
+				for _, argumentRef := range r.definition.FieldDefinitions[fieldRef].ArgumentsDefinition.Refs {
+					r.parents[ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: argumentRef}] = field
+				}
+			}
+		case ast.NodeKindInputObjectTypeDefinition:
+			for _, inputFieldRef := range r.definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs {
+				r.parents[ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: inputFieldRef}] = node
+			}
+		case ast.NodeKindInputObjectTypeExtension:
+			for _, inputFieldRef := range r.definition.InputObjectTypeExtensions[node.Ref].InputFieldsDefinition.Refs {
+				r.parents[ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: inputFieldRef}] = node
+			}
+		case ast.NodeKindEnumTypeDefinition:
+			for _, valueRef := range r.definition.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs {
+				r.parents[ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: valueRef}] = node
+			}
+		case ast.NodeKindEnumTypeExtension:
+			for _, valueRef := range r.definition.EnumTypeExtensions[node.Ref].EnumValuesDefinition.Refs {
+				r.parents[ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: valueRef}] = node
+			}
+		case ast.NodeKindDirectiveDefinition:
+			for _, argumentRef := range r.definition.DirectiveDefinitions[node.Ref].ArgumentsDefinition.Refs {
+				r.parents[ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: argumentRef}] = node
+			}
+		}
+	}
+}
+
+// position returns the position of the keyword of a type or directive definition
+// or the position of the name of a field, argument, input field or enum value
+func (r *Reporter) position(node ast.Node) position.Position {
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return r.definition.ObjectTypeDefinitions[node.Ref].TypeLiteral
+	case ast.NodeKindObjectTypeExtension:
+		return r.definition.ObjectTypeExtensions[node.Ref].ExtendLiteral
+	case ast.NodeKindInterfaceTypeDefinition:
+		return r.definition.InterfaceTypeDefinitions[node.Ref].InterfaceLiteral
+	case ast.NodeKindInterfaceTypeExtension:
+		return r.definition.InterfaceTypeExtensions[node.Ref].ExtendLiteral
+	case ast.NodeKindUnionTypeDefinition:
+		return r.definition.UnionTypeDefinitions[node.Ref].UnionLiteral
+	case ast.NodeKindUnionTypeExtension:
+		return r.definition.UnionTypeExtensions[node.Ref].ExtendLiteral
+	case ast.NodeKindEnumTypeDefinition:
+		return r.definition.EnumTypeDefinitions[node.Ref].EnumLiteral
+	case ast.NodeKindEnumTypeExtension:
+		return r.definition.EnumTypeExtensions[node.Ref].ExtendLiteral
+	case ast.NodeKindInputObjectTypeDefinition:
+		return r.definition.InputObjectTypeDefinitions[node.Ref].InputLiteral
+	case ast.NodeKindInputObjectTypeExtension:
+		return r.definition.InputObjectTypeExtensions[node.Ref].ExtendLiteral
+	case ast.NodeKindScalarTypeDefinition:
+		return r.definition.ScalarTypeDefinitions[node.Ref].ScalarLiteral
+	case ast.NodeKindScalarTypeExtension:
+		return r.definition.ScalarTypeExtensions[node.Ref].ExtendLiteral
+	case ast.NodeKindFieldDefinition:
+		return r.definition.FieldDefinitions[node.Ref].Position
+	case ast.NodeKindInputValueDefinition:
+		return r.definition.InputValueDefinitions[node.Ref].Position
+	case ast.NodeKindEnumValueDefinition:
+		return r.definition.EnumValueDefinitions[node.Ref].Position
+	case ast.NodeKindDirectiveDefinition:
+		return r.definition.DirectiveDefinitions[node.Ref].DirectiveLiteral
+	default:
+		return position.Position{}
+	}
+}
+
+func (r *Reporter) isSuppressed(rule string, node ast.Node) bool {
+	if r.isSuppressedOnLine(rule, r.position(node).LineStart) {
+		return true
+	}
+	if description := r.description(node); description.IsDefined && r.isSuppressedOnLine(rule, description.Position.LineStart) {
+		return true
+	}
+
+	for _, directiveRef := range r.definition.NodeDirectives(node) {
+		if !bytes.Equal(r.definition.DirectiveNameBytes(directiveRef), lintDirectiveName) {
+			continue
+		}
+		value, exists := r.definition.DirectiveArgumentValueByName(directiveRef, disableArgumentName)
+		if !exists {
+			return true
+		}
+		switch value.Kind {
+		case ast.ValueKindString:
+			if r.definition.StringValueContentString(value.Ref) == rule {
+				return true
+			}
+		case ast.ValueKindList:
+			for _, valueRef := range r.definition.ListValues[value.Ref].Refs {
+				item := r.definition.Values[valueRef]
+				if item.Kind == ast.ValueKindString && r.definition.StringValueContentString(item.Ref) == rule {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (r *Reporter) isSuppressedOnLine(rule string, line uint32) bool {
+	rules, ok := r.suppressedLines[line]
+	if !ok {
+		return false
+	}
+	if rules == nil {
+		return true
+	}
+	for i := range rules {
+		if rules[i] == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reporter) nameReference(node ast.Node) ast.ByteSliceReference {
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return r.definition.ObjectTypeDefinitions[node.Ref].Name
+	case ast.NodeKindObjectTypeExtension:
+		return r.definition.ObjectTypeExtensions[node.Ref].Name
+	case ast.NodeKindInterfaceTypeDefinition:
+		return r.definition.InterfaceTypeDefinitions[node.Ref].Name
+	case ast.NodeKindInterfaceTypeExtension:
+		return r.definition.InterfaceTypeExtensions[node.Ref].Name
+	case ast.NodeKindUnionTypeDefinition:
+		return r.definition.UnionTypeDefinitions[node.Ref].Name
+	case ast.NodeKindUnionTypeExtension:
+		return r.definition.UnionTypeExtensions[node.Ref].Name
+	case ast.NodeKindEnumTypeDefinition:
+		return r.definition.EnumTypeDefinitions[node.Ref].Name
+	case ast.NodeKindEnumTypeExtension:
+		return r.definition.EnumTypeExtensions[node.Ref].Name
+	case ast.NodeKindInputObjectTypeDefinition:
+		return r.definition.InputObjectTypeDefinitions[node.Ref].Name
+	case ast.NodeKindInputObjectTypeExtension:
+		return r.definition.InputObjectTypeExtensions[node.Ref].Name
+	case ast.NodeKindScalarTypeDefinition:
+		return r.definition.ScalarTypeDefinitions[node.Ref].Name
+	case ast.NodeKindScalarTypeExtension:
+		return r.definition.ScalarTypeExtensions[node.Ref].Name
+	case ast.NodeKindFieldDefinition:
+		return r.definition.FieldDefinitions[node.Ref].Name
+	case ast.NodeKindInputValueDefinition:
+		return r.definition.InputValueDefinitions[node.Ref].Name
+	case ast.NodeKindEnumValueDefinition:
+		return r.definition.EnumValueDefinitions[node.Ref].EnumValue
+	case ast.NodeKindDirectiveDefinition:
+		return r.definition.DirectiveDefinitions[node.Ref].Name
+	default:
+		return ast.ByteSliceReference{}
+	}
+}
+
+func (r *Reporter) description(node ast.Node) ast.Description {
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return r.definition.ObjectTypeDefinitions[node.Ref].Description
+	case ast.NodeKindInterfaceTypeDefinition:
+		return r.definition.InterfaceTypeDefinitions[node.Ref].Description
+	case ast.NodeKindUnionTypeDefinition:
+		return r.definition.UnionTypeDefinitions[node.Ref].Description
+	case ast.NodeKindEnumTypeDefinition:
+		return r.definition.EnumTypeDefinitions[node.Ref].Description
+	case ast.NodeKindInputObjectTypeDefinition:
+		return r.definition.InputObjectTypeDefinitions[node.Ref].Description
+	case ast.NodeKindScalarTypeDefinition:
+		return r.definition.ScalarTypeDefinitions[node.Ref].Description
+	case ast.NodeKindFieldDefinition:
+		return r.definition.FieldDefinitions[node.Ref].Description
+	case ast.NodeKindInputValueDefinition:
+		return r.definition.InputValueDefinitions[node.Ref].Description
+	case ast.NodeKindEnumValueDefinition:
+		return r.definition.EnumValueDefinitions[node.Ref].Description
+	case ast.NodeKindDirectiveDefinition:
+		return r.definition.DirectiveDefinitions[node.Ref].Description
+	default:
+		return ast.Description{}
+	}
+}
@@ -0,0 +1,97 @@
+package schemalint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const EntityIDFieldRule = "entity-id-field"
+
+var (
+	idFieldName      = []byte("id")
+	idTypeName       = []byte("ID")
+	keyDirectiveName = []byte("key")
+)
+
+// EntityIDField validates that entity types have a field id of type ID!.
+// Entity types are the object types with a @key directive on their definition or one of their extensions.
+func EntityIDField() Rule {
+	return func(walker *astvisitor.Walker, reporter *Reporter) {
+		visitor := &entityIDFieldVisitor{
+			Walker:   walker,
+			reporter: reporter,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+	}
+}
+
+type entityIDFieldVisitor struct {
+	*astvisitor.Walker
+	reporter   *Reporter
+	definition *ast.Document
+}
+
+func (e *entityIDFieldVisitor) EnterDocument(operation, definition *ast.Document) {
+	e.definition = operation
+}
+
+func (e *entityIDFieldVisitor) EnterObjectTypeDefinition(ref int) {
+	typeName := e.definition.ObjectTypeDefinitionNameBytes(ref)
+	if !isEntityType(e.definition, typeName) {
+		return
+	}
+
+	fieldRef, exists := objectTypeFieldByName(e.definition, typeName, idFieldName)
+	if !exists {
+		e.reporter.Report(EntityIDFieldRule, SeverityWarning, ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref},
+			fmt.Sprintf(`Type %s must have a field "id" of type ID!.`, typeName))
+		return
+	}
+
+	typeRef := e.definition.FieldDefinitionType(fieldRef)
+	if e.definition.TypeIsNonNull(typeRef) {
+		ofType := e.definition.Types[typeRef].OfType
+		if e.definition.Types[ofType].TypeKind == ast.TypeKindNamed && bytes.Equal(e.definition.TypeNameBytes(ofType), idTypeName) {
+			return
+		}
+	}
+
+	node := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: fieldRef}
+	printedType, _ := e.definition.PrintTypeBytes(typeRef, nil)
+	e.reporter.Report(EntityIDFieldRule, SeverityWarning, node,
+		fmt.Sprintf("Field %s must be of type ID! but is %s.", e.reporter.Coordinate(node), printedType))
+}
+
+// isEntityType returns true if the object type or one of its extensions has a @key directive
+func isEntityType(definition *ast.Document, typeName ast.ByteSlice) bool {
+	nodes, _ := definition.Index.NodesByNameBytes(typeName)
+	for _, node := range nodes {
+		if node.Kind != ast.NodeKindObjectTypeDefinition && node.Kind != ast.NodeKindObjectTypeExtension {
+			continue
+		}
+		for _, directiveRef := range definition.NodeDirectives(node) {
+			if bytes.Equal(definition.DirectiveNameBytes(directiveRef), keyDirectiveName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// objectTypeFieldByName looks up a field of an object type in its definition and all of its extensions
+func objectTypeFieldByName(definition *ast.Document, typeName, fieldName ast.ByteSlice) (int, bool) {
+	nodes, _ := definition.Index.NodesByNameBytes(typeName)
+	for _, node := range nodes {
+		if node.Kind != ast.NodeKindObjectTypeDefinition && node.Kind != ast.NodeKindObjectTypeExtension {
+			continue
+		}
+		if fieldRef, exists := definition.NodeFieldDefinitionByName(node, fieldName); exists {
+			return fieldRef, true
+		}
+	}
+	return ast.InvalidRef, false
+}
@@ -0,0 +1,40 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityIDField(t *testing.T) {
+	t.Run("entities with ids and types without @key", func(t *testing.T) {
+		findings := runLint(t, `
+			directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+			type Query { users: UserConnection }
+			type User @key(fields: "id") { id: ID! }
+			type Post @key(fields: "id") { title: String }
+			extend type Post { id: ID! }
+			type Account { name: String }
+			extend type Account @key(fields: "id") { id: ID! }
+			type UserConnection { edges: [UserEdge!]! }
+			type UserEdge { node: User! cursor: String! }
+			type Address { street: String }
+		`, EntityIDField())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("entities without ids", func(t *testing.T) {
+		findings := runLint(t, `
+			type User @key(fields: "name") { name: String }
+			type Post @key(fields: "id") { id: ID }
+			type Comment @key(fields: "id") { id: String! }
+			directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+		`, EntityIDField())
+
+		assert.Equal(t, Findings{
+			{Rule: EntityIDFieldRule, Severity: SeverityWarning, Coordinate: "User", Message: `Type User must have a field "id" of type ID!.`, Line: 2, Column: 4},
+			{Rule: EntityIDFieldRule, Severity: SeverityWarning, Coordinate: "Post.id", Message: "Field Post.id must be of type ID! but is ID.", Line: 3, Column: 35},
+			{Rule: EntityIDFieldRule, Severity: SeverityWarning, Coordinate: "Comment.id", Message: "Field Comment.id must be of type ID! but is String!.", Line: 4, Column: 38},
+		}, findings)
+	})
+}
@@ -0,0 +1,44 @@
+package schemalint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const InputObjectNamingRule = "input-object-naming"
+
+var inputTypeSuffix = []byte("Input")
+
+// InputObjectNaming validates that the names of input object types end with Input
+func InputObjectNaming() Rule {
+	return func(walker *astvisitor.Walker, reporter *Reporter) {
+		visitor := &inputObjectNamingVisitor{
+			Walker:   walker,
+			reporter: reporter,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeDefinitionVisitor(visitor)
+	}
+}
+
+type inputObjectNamingVisitor struct {
+	*astvisitor.Walker
+	reporter   *Reporter
+	definition *ast.Document
+}
+
+func (i *inputObjectNamingVisitor) EnterDocument(operation, definition *ast.Document) {
+	i.definition = operation
+}
+
+func (i *inputObjectNamingVisitor) EnterInputObjectTypeDefinition(ref int) {
+	typeName := i.definition.InputObjectTypeDefinitionNameBytes(ref)
+	if bytes.HasSuffix(typeName, inputTypeSuffix) || bytes.HasPrefix(typeName, reservedNamePrefix) {
+		return
+	}
+	i.reporter.Report(InputObjectNamingRule, SeverityWarning, ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: ref},
+		fmt.Sprintf(`Input type %s should end with "Input".`, typeName))
+}
@@ -0,0 +1,27 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputObjectNaming(t *testing.T) {
+	t.Run("input suffix", func(t *testing.T) {
+		findings := runLint(t, `
+			input CreateUserInput { name: String }
+			extend input CreateUserInput { email: String }
+		`, InputObjectNaming())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("missing input suffix", func(t *testing.T) {
+		findings := runLint(t, `
+			input UserFilter { name: String }
+		`, InputObjectNaming())
+
+		assert.Equal(t, Findings{
+			{Rule: InputObjectNamingRule, Severity: SeverityWarning, Coordinate: "UserFilter", Message: `Input type UserFilter should end with "Input".`, Line: 2, Column: 4},
+		}, findings)
+	})
+}
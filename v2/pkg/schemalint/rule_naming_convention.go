@@ -0,0 +1,163 @@
+package schemalint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const NamingConventionRule = "naming-convention"
+
+var reservedNamePrefix = []byte("__")
+
+// NamingConvention validates that types are PascalCase, fields and arguments camelCase
+// and enum values SCREAMING_SNAKE_CASE
+func NamingConvention() Rule {
+	return func(walker *astvisitor.Walker, reporter *Reporter) {
+		visitor := &namingConventionVisitor{
+			Walker:   walker,
+			reporter: reporter,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInterfaceTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterUnionTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterEnumTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterScalarTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterFieldDefinitionVisitor(visitor)
+		walker.RegisterEnterInputValueDefinitionVisitor(visitor)
+		walker.RegisterEnterEnumValueDefinitionVisitor(visitor)
+	}
+}
+
+type namingConventionVisitor struct {
+	*astvisitor.Walker
+	reporter   *Reporter
+	definition *ast.Document
+}
+
+func (n *namingConventionVisitor) EnterDocument(operation, definition *ast.Document) {
+	n.definition = operation
+}
+
+func (n *namingConventionVisitor) EnterObjectTypeDefinition(ref int) {
+	n.checkTypeName(ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref})
+}
+
+func (n *namingConventionVisitor) EnterInterfaceTypeDefinition(ref int) {
+	n.checkTypeName(ast.Node{Kind: ast.NodeKindInterfaceTypeDefinition, Ref: ref})
+}
+
+func (n *namingConventionVisitor) EnterUnionTypeDefinition(ref int) {
+	n.checkTypeName(ast.Node{Kind: ast.NodeKindUnionTypeDefinition, Ref: ref})
+}
+
+func (n *namingConventionVisitor) EnterEnumTypeDefinition(ref int) {
+	n.checkTypeName(ast.Node{Kind: ast.NodeKindEnumTypeDefinition, Ref: ref})
+}
+
+func (n *namingConventionVisitor) EnterInputObjectTypeDefinition(ref int) {
+	n.checkTypeName(ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: ref})
+}
+
+func (n *namingConventionVisitor) EnterScalarTypeDefinition(ref int) {
+	n.checkTypeName(ast.Node{Kind: ast.NodeKindScalarTypeDefinition, Ref: ref})
+}
+
+func (n *namingConventionVisitor) EnterFieldDefinition(ref int) {
+	name := n.definition.FieldDefinitionNameBytes(ref)
+	if bytes.HasPrefix(name, reservedNamePrefix) || isCamelCase(name) {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}
+	n.reporter.Report(NamingConventionRule, SeverityWarning, node,
+		fmt.Sprintf("Field %s should be camelCase.", n.reporter.Coordinate(node)))
+}
+
+func (n *namingConventionVisitor) EnterInputValueDefinition(ref int) {
+	name := n.definition.InputValueDefinitionNameBytes(ref)
+	if bytes.HasPrefix(name, reservedNamePrefix) || isCamelCase(name) {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}
+	kind := "Argument"
+	if parent, ok := n.reporter.Parent(node); ok && (parent.Kind == ast.NodeKindInputObjectTypeDefinition || parent.Kind == ast.NodeKindInputObjectTypeExtension) {
+		kind = "Input field"
+	}
+	n.reporter.Report(NamingConventionRule, SeverityWarning, node,
+		fmt.Sprintf("%s %s should be camelCase.", kind, n.reporter.Coordinate(node)))
+}
+
+func (n *namingConventionVisitor) EnterEnumValueDefinition(ref int) {
+	if isScreamingSnakeCase(n.definition.EnumValueDefinitionNameBytes(ref)) {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: ref}
+	n.reporter.Report(NamingConventionRule, SeverityWarning, node,
+		fmt.Sprintf("Enum value %s should be SCREAMING_SNAKE_CASE.", n.reporter.Coordinate(node)))
+}
+
+func (n *namingConventionVisitor) checkTypeName(node ast.Node) {
+	name := n.definition.NodeNameBytes(node)
+	if bytes.HasPrefix(name, reservedNamePrefix) || isPascalCase(name) {
+		return
+	}
+	n.reporter.Report(NamingConventionRule, SeverityWarning, node,
+		fmt.Sprintf("Type %s should be PascalCase.", name))
+}
+
+// isPascalCase accepts names like User or HTTPRequest
+func isPascalCase(name []byte) bool {
+	if len(name) == 0 || !isUpper(name[0]) {
+		return false
+	}
+	return isAlphanumeric(name)
+}
+
+// isCamelCase accepts names like id or createdAt
+func isCamelCase(name []byte) bool {
+	if len(name) == 0 || !isLower(name[0]) {
+		return false
+	}
+	return isAlphanumeric(name)
+}
+
+// isScreamingSnakeCase accepts names like RED or LIGHT_BLUE_2
+func isScreamingSnakeCase(name []byte) bool {
+	if len(name) == 0 || !isUpper(name[0]) || name[len(name)-1] == '_' {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case isUpper(c), isDigit(c):
+		case c == '_' && name[i-1] != '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(name []byte) bool {
+	for _, c := range name {
+		if !isUpper(c) && !isLower(c) && !isDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,50 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingConvention(t *testing.T) {
+	t.Run("valid names", func(t *testing.T) {
+		findings := runLint(t, `
+			type Query { user(id: ID!): User __typename: String! }
+			type User { id: ID! createdAt: String }
+			interface HTTPResource { url: String }
+			union SearchResult = User
+			enum Color { RED LIGHT_BLUE DARK_BLUE_2 }
+			input UserInput { firstName: String }
+			scalar DateTime
+			extend type User { lastName: String }
+		`, NamingConvention())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		findings := runLint(t, `
+			type user { Name: String }
+			interface node_interface { id: ID }
+			enum Color { red Light_Blue DARK__BLUE BLUE_ }
+			input userInput { first_name: String }
+			extend type user { last_name: String }
+		`, NamingConvention())
+
+		var messages []string
+		for _, finding := range findings {
+			messages = append(messages, finding.Message)
+		}
+		assert.Equal(t, []string{
+			"Type user should be PascalCase.",
+			"Field user.Name should be camelCase.",
+			"Type node_interface should be PascalCase.",
+			"Enum value Color.red should be SCREAMING_SNAKE_CASE.",
+			"Enum value Color.Light_Blue should be SCREAMING_SNAKE_CASE.",
+			"Enum value Color.DARK__BLUE should be SCREAMING_SNAKE_CASE.",
+			"Enum value Color.BLUE_ should be SCREAMING_SNAKE_CASE.",
+			"Type userInput should be PascalCase.",
+			"Input field userInput.first_name should be camelCase.",
+			"Field user.last_name should be camelCase.",
+		}, messages)
+	})
+}
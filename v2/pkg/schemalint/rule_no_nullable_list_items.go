@@ -0,0 +1,59 @@
+package schemalint
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const NoNullableListItemsRule = "no-nullable-list-items"
+
+// NoNullableListItems validates that the lists returned by fields of object and interface types have non-null items,
+// e.g. [User!] instead of [User]
+func NoNullableListItems() Rule {
+	return func(walker *astvisitor.Walker, reporter *Reporter) {
+		visitor := &noNullableListItemsVisitor{
+			Walker:   walker,
+			reporter: reporter,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterFieldDefinitionVisitor(visitor)
+	}
+}
+
+type noNullableListItemsVisitor struct {
+	*astvisitor.Walker
+	reporter   *Reporter
+	definition *ast.Document
+}
+
+func (n *noNullableListItemsVisitor) EnterDocument(operation, definition *ast.Document) {
+	n.definition = operation
+}
+
+func (n *noNullableListItemsVisitor) EnterFieldDefinition(ref int) {
+	typeRef := n.definition.FieldDefinitionType(ref)
+	if !n.hasNullableListItems(typeRef) {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}
+	printedType, _ := n.definition.PrintTypeBytes(typeRef, nil)
+	n.reporter.Report(NoNullableListItemsRule, SeverityWarning, node,
+		fmt.Sprintf("Field %s returns a list with nullable items: %s.", n.reporter.Coordinate(node), printedType))
+}
+
+func (n *noNullableListItemsVisitor) hasNullableListItems(typeRef int) bool {
+	switch n.definition.Types[typeRef].TypeKind {
+	case ast.TypeKindNonNull:
+		return n.hasNullableListItems(n.definition.Types[typeRef].OfType)
+	case ast.TypeKindList:
+		ofType := n.definition.Types[typeRef].OfType
+		if n.definition.Types[ofType].TypeKind != ast.TypeKindNonNull {
+			return true
+		}
+		return n.hasNullableListItems(ofType)
+	default:
+		return false
+	}
+}
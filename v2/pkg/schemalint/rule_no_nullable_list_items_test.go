@@ -0,0 +1,31 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoNullableListItems(t *testing.T) {
+	t.Run("non-null list items", func(t *testing.T) {
+		findings := runLint(t, `
+			type Query { users(ids: [ID]): [User!] matrix: [[Int!]!]! name: String }
+			type User { id: ID! }
+			input UserInput { tags: [String] }
+		`, NoNullableListItems())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("nullable list items", func(t *testing.T) {
+		findings := runLint(t, `
+			type Query { users: [User]! matrix: [[Int]!] }
+			interface Node { tags: [String] }
+		`, NoNullableListItems())
+
+		assert.Equal(t, Findings{
+			{Rule: NoNullableListItemsRule, Severity: SeverityWarning, Coordinate: "Query.users", Message: "Field Query.users returns a list with nullable items: [User]!.", Line: 2, Column: 17},
+			{Rule: NoNullableListItemsRule, Severity: SeverityWarning, Coordinate: "Query.matrix", Message: "Field Query.matrix returns a list with nullable items: [[Int]!].", Line: 2, Column: 32},
+			{Rule: NoNullableListItemsRule, Severity: SeverityWarning, Coordinate: "Node.tags", Message: "Field Node.tags returns a list with nullable items: [String].", Line: 3, Column: 21},
+		}, findings)
+	})
+}
@@ -0,0 +1,143 @@
+package schemalint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const RelayConnectionSpecRule = "relay-connection-spec"
+
+var (
+	connectionTypeSuffix = []byte("Connection")
+	edgeTypeSuffix       = []byte("Edge")
+	pageInfoTypeName     = []byte("PageInfo")
+	booleanTypeName      = []byte("Boolean")
+)
+
+// RelayConnectionSpec validates that object types with the suffix Connection and Edge and the PageInfo type
+// have the fields required by the Relay cursor connections specification
+// and that fields returning a connection have the arguments to paginate forward or backward
+func RelayConnectionSpec() Rule {
+	return func(walker *astvisitor.Walker, reporter *Reporter) {
+		visitor := &relayConnectionSpecVisitor{
+			Walker:   walker,
+			reporter: reporter,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterFieldDefinitionVisitor(visitor)
+	}
+}
+
+type relayConnectionSpecVisitor struct {
+	*astvisitor.Walker
+	reporter   *Reporter
+	definition *ast.Document
+}
+
+func (r *relayConnectionSpecVisitor) EnterDocument(operation, definition *ast.Document) {
+	r.definition = operation
+}
+
+func (r *relayConnectionSpecVisitor) EnterObjectTypeDefinition(ref int) {
+	typeName := r.definition.ObjectTypeDefinitionNameBytes(ref)
+	node := ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref}
+
+	switch {
+	case bytes.HasSuffix(typeName, connectionTypeSuffix):
+		r.requireField(node, typeName, "edges", "a list", func(typeRef int) bool {
+			return r.definition.TypeIsList(typeRef)
+		})
+		r.requireField(node, typeName, "pageInfo", "PageInfo!", func(typeRef int) bool {
+			return r.isNonNullNamedType(typeRef, pageInfoTypeName)
+		})
+	case bytes.HasSuffix(typeName, edgeTypeSuffix):
+		r.requireField(node, typeName, "node", "a type which is not a list", func(typeRef int) bool {
+			return !r.definition.TypeIsList(typeRef)
+		})
+		r.requireField(node, typeName, "cursor", "a type which is not a list", func(typeRef int) bool {
+			return !r.definition.TypeIsList(typeRef)
+		})
+	case bytes.Equal(typeName, pageInfoTypeName):
+		for _, fieldName := range []string{"hasNextPage", "hasPreviousPage"} {
+			r.requireField(node, typeName, fieldName, "Boolean!", func(typeRef int) bool {
+				return r.isNonNullNamedType(typeRef, booleanTypeName)
+			})
+		}
+		for _, fieldName := range []string{"startCursor", "endCursor"} {
+			r.requireField(node, typeName, fieldName, "a type which is not a list", func(typeRef int) bool {
+				return !r.definition.TypeIsList(typeRef)
+			})
+		}
+	}
+}
+
+func (r *relayConnectionSpecVisitor) EnterFieldDefinition(ref int) {
+	typeRef := r.definition.FieldDefinitionType(ref)
+	if r.definition.TypeIsList(typeRef) || !r.isConnectionType(r.definition.ResolveTypeNameBytes(typeRef)) {
+		return
+	}
+	if r.hasArguments(ref, "first", "after") || r.hasArguments(ref, "last", "before") {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}
+	r.reporter.Report(RelayConnectionSpecRule, SeverityError, node,
+		fmt.Sprintf(`Field %s returns a connection and must have the arguments "first" and "after" or "last" and "before".`, r.reporter.Coordinate(node)))
+}
+
+func (r *relayConnectionSpecVisitor) isConnectionType(typeName ast.ByteSlice) bool {
+	if !bytes.HasSuffix(typeName, connectionTypeSuffix) {
+		return false
+	}
+	nodes, _ := r.definition.Index.NodesByNameBytes(typeName)
+	for _, node := range nodes {
+		if node.Kind == ast.NodeKindObjectTypeDefinition {
+			return true
+		}
+	}
+	return false
+}
+
+// hasArguments returns true if the field definition has all of the arguments
+func (r *relayConnectionSpecVisitor) hasArguments(fieldRef int, argumentNames ...string) bool {
+	for _, argumentName := range argumentNames {
+		found := false
+		for _, argumentRef := range r.definition.FieldDefinitionArgumentsDefinitions(fieldRef) {
+			if r.definition.InputValueDefinitionNameString(argumentRef) == argumentName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// requireField reports a missing field on the type and a field whose type doesn't satisfy isValidType on the field itself
+func (r *relayConnectionSpecVisitor) requireField(typeNode ast.Node, typeName ast.ByteSlice, fieldName, expectedType string, isValidType func(typeRef int) bool) {
+	fieldRef, exists := objectTypeFieldByName(r.definition, typeName, []byte(fieldName))
+	if !exists {
+		r.reporter.Report(RelayConnectionSpecRule, SeverityError, typeNode,
+			fmt.Sprintf(`Type %s must have a field "%s" which returns %s.`, typeName, fieldName, expectedType))
+		return
+	}
+	if isValidType(r.definition.FieldDefinitionType(fieldRef)) {
+		return
+	}
+	fieldNode := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: fieldRef}
+	r.reporter.Report(RelayConnectionSpecRule, SeverityError, fieldNode,
+		fmt.Sprintf("Field %s must return %s.", r.reporter.Coordinate(fieldNode), expectedType))
+}
+
+func (r *relayConnectionSpecVisitor) isNonNullNamedType(typeRef int, typeName ast.ByteSlice) bool {
+	if !r.definition.TypeIsNonNull(typeRef) {
+		return false
+	}
+	ofType := r.definition.Types[typeRef].OfType
+	return r.definition.Types[ofType].TypeKind == ast.TypeKindNamed && bytes.Equal(r.definition.TypeNameBytes(ofType), typeName)
+}
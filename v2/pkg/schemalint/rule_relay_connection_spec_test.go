@@ -0,0 +1,80 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayConnectionSpec(t *testing.T) {
+	t.Run("conforming connection", func(t *testing.T) {
+		findings := runLint(t, `
+			type UserConnection { edges: [UserEdge] pageInfo: PageInfo! }
+			type UserEdge { node: User cursor: String! }
+			type PageInfo { hasNextPage: Boolean! hasPreviousPage: Boolean! startCursor: String endCursor: String }
+			type User { id: ID! }
+			type Query {
+				users(first: Int, after: String): UserConnection!
+				recentUsers(last: Int, before: String): UserConnection
+				allUsers(first: Int, after: String, last: Int, before: String): UserConnection
+			}
+		`, RelayConnectionSpec())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("fields returning a connection without pagination arguments", func(t *testing.T) {
+		findings := runLint(t, `
+			type UserConnection { edges: [UserEdge] pageInfo: PageInfo! }
+			type UserEdge { node: User cursor: String! }
+			type PageInfo { hasNextPage: Boolean! hasPreviousPage: Boolean! startCursor: String endCursor: String }
+			type User { id: ID! friends(first: Int): UserConnection }
+			type Query {
+				users: UserConnection!
+				recentUsers(last: Int, after: String): UserConnection
+				connections: [UserConnection]
+			}
+		`, RelayConnectionSpec())
+
+		var messages []string
+		for _, finding := range findings {
+			messages = append(messages, finding.Message)
+		}
+		assert.Equal(t, []string{
+			`Field User.friends returns a connection and must have the arguments "first" and "after" or "last" and "before".`,
+			`Field Query.users returns a connection and must have the arguments "first" and "after" or "last" and "before".`,
+			`Field Query.recentUsers returns a connection and must have the arguments "first" and "after" or "last" and "before".`,
+		}, messages)
+	})
+
+	t.Run("fields of extensions are taken into account", func(t *testing.T) {
+		findings := runLint(t, `
+			type UserConnection { edges: [UserEdge] }
+			extend type UserConnection { pageInfo: PageInfo! }
+		`, RelayConnectionSpec())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("non conforming connection", func(t *testing.T) {
+		findings := runLint(t, `
+			type UserConnection { edges: UserEdge pageInfo: PageInfo }
+			type UserEdge { node: [User] }
+			type PageInfo { hasNextPage: Boolean startCursor: String endCursor: String }
+		`, RelayConnectionSpec())
+
+		var messages []string
+		for _, finding := range findings {
+			messages = append(messages, finding.Message)
+		}
+		assert.Equal(t, []string{
+			"Field UserConnection.edges must return a list.",
+			"Field UserConnection.pageInfo must return PageInfo!.",
+			"Field UserEdge.node must return a type which is not a list.",
+			`Type UserEdge must have a field "cursor" which returns a type which is not a list.`,
+			"Field PageInfo.hasNextPage must return Boolean!.",
+			`Type PageInfo must have a field "hasPreviousPage" which returns Boolean!.`,
+		}, messages)
+		for _, finding := range findings {
+			assert.Equal(t, SeverityError, finding.Severity)
+		}
+	})
+}
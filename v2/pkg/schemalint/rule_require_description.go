@@ -0,0 +1,95 @@
+package schemalint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+)
+
+const RequireDescriptionRule = "require-description"
+
+// RequireDescription validates that type definitions, the fields of object and interface types
+// and directive definitions have a description
+func RequireDescription() Rule {
+	return func(walker *astvisitor.Walker, reporter *Reporter) {
+		visitor := &requireDescriptionVisitor{
+			Walker:   walker,
+			reporter: reporter,
+		}
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInterfaceTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterUnionTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterEnumTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterScalarTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterFieldDefinitionVisitor(visitor)
+		walker.RegisterEnterDirectiveDefinitionVisitor(visitor)
+	}
+}
+
+type requireDescriptionVisitor struct {
+	*astvisitor.Walker
+	reporter   *Reporter
+	definition *ast.Document
+}
+
+func (r *requireDescriptionVisitor) EnterDocument(operation, definition *ast.Document) {
+	r.definition = operation
+}
+
+func (r *requireDescriptionVisitor) EnterObjectTypeDefinition(ref int) {
+	r.checkType(ast.Node{Kind: ast.NodeKindObjectTypeDefinition, Ref: ref}, r.definition.ObjectTypeDefinitions[ref].Description)
+}
+
+func (r *requireDescriptionVisitor) EnterInterfaceTypeDefinition(ref int) {
+	r.checkType(ast.Node{Kind: ast.NodeKindInterfaceTypeDefinition, Ref: ref}, r.definition.InterfaceTypeDefinitions[ref].Description)
+}
+
+func (r *requireDescriptionVisitor) EnterUnionTypeDefinition(ref int) {
+	r.checkType(ast.Node{Kind: ast.NodeKindUnionTypeDefinition, Ref: ref}, r.definition.UnionTypeDefinitions[ref].Description)
+}
+
+func (r *requireDescriptionVisitor) EnterEnumTypeDefinition(ref int) {
+	r.checkType(ast.Node{Kind: ast.NodeKindEnumTypeDefinition, Ref: ref}, r.definition.EnumTypeDefinitions[ref].Description)
+}
+
+func (r *requireDescriptionVisitor) EnterInputObjectTypeDefinition(ref int) {
+	r.checkType(ast.Node{Kind: ast.NodeKindInputObjectTypeDefinition, Ref: ref}, r.definition.InputObjectTypeDefinitions[ref].Description)
+}
+
+func (r *requireDescriptionVisitor) EnterScalarTypeDefinition(ref int) {
+	r.checkType(ast.Node{Kind: ast.NodeKindScalarTypeDefinition, Ref: ref}, r.definition.ScalarTypeDefinitions[ref].Description)
+}
+
+func (r *requireDescriptionVisitor) EnterFieldDefinition(ref int) {
+	if hasDescription(r.definition, r.definition.FieldDefinitions[ref].Description) || bytes.HasPrefix(r.definition.FieldDefinitionNameBytes(ref), reservedNamePrefix) {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}
+	r.reporter.Report(RequireDescriptionRule, SeverityWarning, node,
+		fmt.Sprintf("Field %s is missing a description.", r.reporter.Coordinate(node)))
+}
+
+func (r *requireDescriptionVisitor) EnterDirectiveDefinition(ref int) {
+	if hasDescription(r.definition, r.definition.DirectiveDefinitions[ref].Description) {
+		return
+	}
+	node := ast.Node{Kind: ast.NodeKindDirectiveDefinition, Ref: ref}
+	r.reporter.Report(RequireDescriptionRule, SeverityWarning, node,
+		fmt.Sprintf("Directive %s is missing a description.", r.reporter.Coordinate(node)))
+}
+
+func (r *requireDescriptionVisitor) checkType(node ast.Node, description ast.Description) {
+	if hasDescription(r.definition, description) || bytes.HasPrefix(r.definition.NodeNameBytes(node), reservedNamePrefix) {
+		return
+	}
+	r.reporter.Report(RequireDescriptionRule, SeverityWarning, node,
+		fmt.Sprintf("Type %s is missing a description.", r.definition.NodeNameBytes(node)))
+}
+
+func hasDescription(definition *ast.Document, description ast.Description) bool {
+	return description.IsDefined && len(bytes.TrimSpace(definition.Input.ByteSlice(description.Content))) > 0
+}
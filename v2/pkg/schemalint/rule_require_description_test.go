@@ -0,0 +1,50 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireDescription(t *testing.T) {
+	t.Run("described schema", func(t *testing.T) {
+		findings := runLint(t, `
+			"The root query type"
+			type Query {
+				"""
+				Looks up a user
+				"""
+				user(id: ID!): User
+			}
+			"A user"
+			type User { "The id" id: ID! }
+			"Caches a field"
+			directive @cached on FIELD_DEFINITION
+			"Colors"
+			enum Color { RED }
+		`, RequireDescription())
+		assert.Empty(t, findings)
+	})
+
+	t.Run("missing descriptions", func(t *testing.T) {
+		findings := runLint(t, `
+			type Query { user: User }
+			"  "
+			interface Node { "The id" id: ID! }
+			"A user"
+			type User { "The id" id: ID! }
+			extend type User { name: String }
+			input UserInput { name: String }
+			directive @cached on FIELD_DEFINITION
+		`, RequireDescription())
+
+		assert.Equal(t, Findings{
+			{Rule: RequireDescriptionRule, Severity: SeverityWarning, Coordinate: "Query", Message: "Type Query is missing a description.", Line: 2, Column: 4},
+			{Rule: RequireDescriptionRule, Severity: SeverityWarning, Coordinate: "Query.user", Message: "Field Query.user is missing a description.", Line: 2, Column: 17},
+			{Rule: RequireDescriptionRule, Severity: SeverityWarning, Coordinate: "Node", Message: "Type Node is missing a description.", Line: 4, Column: 4},
+			{Rule: RequireDescriptionRule, Severity: SeverityWarning, Coordinate: "User.name", Message: "Field User.name is missing a description.", Line: 7, Column: 23},
+			{Rule: RequireDescriptionRule, Severity: SeverityWarning, Coordinate: "UserInput", Message: "Type UserInput is missing a description.", Line: 8, Column: 4},
+			{Rule: RequireDescriptionRule, Severity: SeverityWarning, Coordinate: "@cached", Message: "Directive @cached is missing a description.", Line: 9, Column: 4},
+		}, findings)
+	})
+}
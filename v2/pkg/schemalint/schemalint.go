@@ -0,0 +1,126 @@
+// Package schemalint lints GraphQL schemas against conventions which go beyond the validation rules of the specification.
+//
+// Rules are registered on an astvisitor.Walker like the rules of astvalidation and report findings to a Reporter.
+// A finding is suppressed when the schema element it points to is preceded by a comment like
+//
+//	# lint-disable-next-line naming-convention, require-description
+//
+// or carries a directive like @lint(disable: ["naming-convention"]).
+// Both forms suppress every rule when no rule names are given.
+package schemalint
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "ERROR"
+	SeverityWarning Severity = "WARNING"
+	SeverityInfo    Severity = "INFO"
+)
+
+// Finding is a violation of a rule by a single schema element.
+// Coordinate is the schema coordinate of the element, e.g. User.name or Query.users(first:)
+// Line and Column point to the keyword of a type or directive definition or to the name of any other element.
+type Finding struct {
+	Rule       string   `json:"rule"`
+	Severity   Severity `json:"severity"`
+	Coordinate string   `json:"coordinate"`
+	Message    string   `json:"message"`
+	Line       uint32   `json:"line"`
+	Column     uint32   `json:"column"`
+}
+
+type Findings []Finding
+
+// HasErrors returns true if at least one finding has the severity SeverityError
+func (f Findings) HasErrors() bool {
+	for i := range f {
+		if f[i].Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule is hook to register callback functions on the Walker, violations are reported to the Reporter
+type Rule func(walker *astvisitor.Walker, reporter *Reporter)
+
+type Option func(linter *Linter)
+
+// WithSeverity overrides the severity of all findings of the rule
+func WithSeverity(rule string, severity Severity) Option {
+	return func(linter *Linter) {
+		linter.reporter.severities[rule] = severity
+	}
+}
+
+// DefaultLinter returns a Linter with all built-in rules registered
+func DefaultLinter(options ...Option) *Linter {
+	return NewLinter([]Rule{
+		NamingConvention(),
+		RequireDescription(),
+		EntityIDField(),
+		RelayConnectionSpec(),
+		NoNullableListItems(),
+		InputObjectNaming(),
+	}, options...)
+}
+
+func NewLinter(rules []Rule, options ...Option) *Linter {
+	linter := &Linter{
+		walker: astvisitor.NewWalker(48),
+		reporter: &Reporter{
+			severities: map[string]Severity{},
+		},
+	}
+
+	for _, option := range options {
+		option(linter)
+	}
+
+	for _, rule := range rules {
+		linter.RegisterRule(rule)
+	}
+
+	return linter
+}
+
+// Linter runs the registered rules over schema definitions
+type Linter struct {
+	walker   astvisitor.Walker
+	reporter *Reporter
+}
+
+func (l *Linter) RegisterRule(rule Rule) {
+	rule(&l.walker, l.reporter)
+}
+
+// Lint walks the definition with all registered rules and returns their findings in the order they were reported.
+// The definition should be linted as written, before merging the base schema or normalizing it,
+// otherwise comments can't be matched to the schema elements and the introspection types are linted as well.
+func (l *Linter) Lint(definition *ast.Document) (Findings, error) {
+	l.reporter.reset(definition)
+
+	report := operationreport.Report{}
+	l.walker.Walk(definition, definition, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	return l.reporter.findings, nil
+}
+
+// LintSDL parses the schema and lints it with the default rules
+func LintSDL(schema string, options ...Option) (Findings, error) {
+	definition, report := astparser.ParseGraphqlDocumentString(schema)
+	if report.HasErrors() {
+		return nil, report
+	}
+	return DefaultLinter(options...).Lint(&definition)
+}
@@ -0,0 +1,105 @@
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+)
+
+func runLint(t *testing.T, schema string, rules ...Rule) Findings {
+	t.Helper()
+	definition, report := astparser.ParseGraphqlDocumentString(schema)
+	require.False(t, report.HasErrors(), report.Error())
+
+	findings, err := NewLinter(rules).Lint(&definition)
+	require.NoError(t, err)
+	return findings
+}
+
+func TestLinter(t *testing.T) {
+	t.Run("findings carry rule, severity, coordinate and position", func(t *testing.T) {
+		findings := runLint(t, `
+type Query {
+  user_by_id(user_id: ID!): String
+}`, NamingConvention())
+
+		assert.Equal(t, Findings{
+			{Rule: NamingConventionRule, Severity: SeverityWarning, Coordinate: "Query.user_by_id", Message: "Field Query.user_by_id should be camelCase.", Line: 3, Column: 3},
+			{Rule: NamingConventionRule, Severity: SeverityWarning, Coordinate: "Query.user_by_id(user_id:)", Message: "Argument Query.user_by_id(user_id:) should be camelCase.", Line: 3, Column: 14},
+		}, findings)
+	})
+
+	t.Run("severity override", func(t *testing.T) {
+		definition, report := astparser.ParseGraphqlDocumentString(`input Filter { a: String }`)
+		require.False(t, report.HasErrors())
+
+		findings, err := NewLinter([]Rule{InputObjectNaming()}, WithSeverity(InputObjectNamingRule, SeverityError)).Lint(&definition)
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, SeverityError, findings[0].Severity)
+		assert.True(t, findings.HasErrors())
+	})
+
+	t.Run("suppressed by comment", func(t *testing.T) {
+		findings := runLint(t, `
+# lint-disable-next-line naming-convention
+type query_root {
+  # lint-disable-next-line
+  "described"
+  first_field: String
+  # lint-disable-next-line require-description
+
+  # lint-disable-next-line input-object-naming, naming-convention
+  second_field: String
+  third_field: String
+}`, NamingConvention())
+
+		assert.Equal(t, Findings{
+			{Rule: NamingConventionRule, Severity: SeverityWarning, Coordinate: "query_root.third_field", Message: "Field query_root.third_field should be camelCase.", Line: 11, Column: 3},
+		}, findings)
+	})
+
+	t.Run("suppression by comment only applies to the listed rules", func(t *testing.T) {
+		findings := runLint(t, `
+# lint-disable-next-line require-description
+input filter { a: String }`, NamingConvention(), InputObjectNaming())
+
+		assert.Len(t, findings, 2)
+	})
+
+	t.Run("suppressed by directive", func(t *testing.T) {
+		findings := runLint(t, `
+type Query {
+  first_field: String @lint
+  second_field: String @lint(disable: "naming-convention")
+  third_field: [String] @lint(disable: ["require-description", "no-nullable-list-items"])
+}`, NamingConvention(), NoNullableListItems())
+
+		assert.Equal(t, Findings{
+			{Rule: NamingConventionRule, Severity: SeverityWarning, Coordinate: "Query.third_field", Message: "Field Query.third_field should be camelCase.", Line: 5, Column: 3},
+		}, findings)
+	})
+
+	t.Run("LintSDL runs the default rules", func(t *testing.T) {
+		findings, err := LintSDL(`
+"The root query type"
+type Query {
+  "All users"
+  users: [User!]!
+}
+
+"A user"
+type User {
+  "The id"
+  id: ID!
+}`)
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+
+		_, err = LintSDL(`type Query {`)
+		assert.Error(t, err)
+	})
+}
@@ -3,12 +3,17 @@ package variablesvalidation
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/buger/jsonparser"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafebytes"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 type InvalidVariableError struct {
@@ -24,21 +29,58 @@ type VariablesValidator struct {
 	walker  *astvisitor.Walker
 }
 
-func NewVariablesValidator() *VariablesValidator {
+type Option func(validator *VariablesValidator)
+
+// WithScalarCodecs validates the values of custom scalars with the codecs of the registry,
+// custom scalars without a codec accept any value
+func WithScalarCodecs(registry *scalars.Registry) Option {
+	return func(validator *VariablesValidator) {
+		validator.visitor.scalarCodecs = registry
+	}
+}
+
+func NewVariablesValidator(options ...Option) *VariablesValidator {
 	walker := astvisitor.NewWalker(8)
 	visitor := &variablesVisitor{
 		variables: &astjson.JSON{},
 		walker:    &walker,
 	}
 	walker.RegisterEnterVariableDefinitionVisitor(visitor)
-	return &VariablesValidator{
+	validator := &VariablesValidator{
 		walker:  &walker,
 		visitor: visitor,
 	}
+	for _, option := range options {
+		option(validator)
+	}
+	return validator
 }
 
 func (v *VariablesValidator) Validate(operation, definition *ast.Document, variables []byte) error {
+	v.visitor.coerce = false
+	return v.validate(operation, definition, variables)
+}
+
+// ValidateAndCoerce validates the variables and returns them with the values of custom scalars
+// coerced by their codecs, see WithScalarCodecs
+func (v *VariablesValidator) ValidateAndCoerce(operation, definition *ast.Document, variables []byte) ([]byte, error) {
+	v.visitor.coerce = true
+	if err := v.validate(operation, definition, variables); err != nil {
+		return nil, err
+	}
+	if !v.visitor.coerced {
+		return variables, nil
+	}
+	out := &bytes.Buffer{}
+	if err := v.visitor.variables.PrintRoot(out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (v *VariablesValidator) validate(operation, definition *ast.Document, variables []byte) error {
 	v.visitor.err = nil
+	v.visitor.coerced = false
 	v.visitor.definition = definition
 	v.visitor.operation = operation
 	err := v.visitor.variables.ParseObject(variables)
@@ -58,6 +100,9 @@ type variablesVisitor struct {
 	operation                  *ast.Document
 	definition                 *ast.Document
 	variables                  *astjson.JSON
+	scalarCodecs               *scalars.Registry
+	coerce                     bool
+	coerced                    bool
 	err                        error
 	currentVariableName        []byte
 	currentVariableJsonNodeRef int
@@ -111,6 +156,10 @@ func (v *variablesVisitor) popPath() {
 	v.path = v.path[:len(v.path)-1]
 }
 
+func (v *variablesVisitor) isListItem() bool {
+	return len(v.path) > 0 && v.path[len(v.path)-1].kind == pathItemKindArray
+}
+
 func (v *variablesVisitor) EnterVariableDefinition(ref int) {
 	varTypeRef := v.operation.VariableDefinitions[ref].Type
 	varName := v.operation.VariableValueNameBytes(v.operation.VariableDefinitions[ref].VariableValue.Ref)
@@ -131,7 +180,7 @@ func (v *variablesVisitor) traverseOperationType(jsonFieldRef int, operationType
 			return
 		}
 		if v.variables.Nodes[jsonFieldRef].Kind == astjson.NodeKindNull {
-			v.renderVariableInvalidNullError(v.currentVariableName, operationTypeRef)
+			v.renderVariableInvalidNullError(v.operation, operationTypeRef)
 			return
 		}
 
@@ -147,7 +196,8 @@ func (v *variablesVisitor) traverseOperationType(jsonFieldRef int, operationType
 
 	if v.operation.TypeIsList(operationTypeRef) {
 		if v.variables.Nodes[jsonFieldRef].Kind != astjson.NodeKindArray {
-			v.renderVariableInvalidObjectTypeError(varTypeName, v.variables.Nodes[jsonFieldRef])
+			// input coercion for lists: a single value is treated as a list with one item
+			v.traverseOperationType(jsonFieldRef, v.operation.Types[operationTypeRef].OfType)
 			return
 		}
 		for i, arrayValue := range v.variables.Nodes[jsonFieldRef].ArrayValues {
@@ -292,21 +342,140 @@ func (v *variablesVisitor) renderVariableEnumValueDoesNotExistError(typeName []b
 	}
 }
 
-func (v *variablesVisitor) renderVariableInvalidNullError(variableName []byte, typeRef int) {
+// validateInt ensures that a number is an integer in the 32-bit signed range,
+// numbers with a zero fraction, e.g. 1.0, are accepted like in graphql-js
+func (v *variablesVisitor) validateInt(jsonNodeRef int, kind ast.NodeKind, typeName []byte) {
+	value := unsafebytes.BytesToString(v.variables.Nodes[jsonNodeRef].ValueBytes(v.variables))
+	if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number != math.Trunc(number) {
+		v.renderVariableInvalidNestedTypeError(jsonNodeRef, kind, typeName)
+		return
+	}
+	if number < math.MinInt32 || number > math.MaxInt32 {
+		v.renderVariableInvalidValueError(jsonNodeRef, fmt.Sprintf(`Int cannot represent non 32-bit signed integer value: %s`, value))
+	}
+}
+
+// validateFloat ensures that a number is finite
+func (v *variablesVisitor) validateFloat(jsonNodeRef int) {
+	value := unsafebytes.BytesToString(v.variables.Nodes[jsonNodeRef].ValueBytes(v.variables))
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		v.renderVariableInvalidValueError(jsonNodeRef, fmt.Sprintf(`Float cannot represent non numeric value: %s`, value))
+	}
+}
+
+// traverseCustomScalar validates the value of a custom scalar with its codec,
+// when coercing, the value is replaced with the coerced value
+func (v *variablesVisitor) traverseCustomScalar(codec scalars.Codec, jsonNodeRef int, typeName []byte) {
 	buf := &bytes.Buffer{}
-	err := v.operation.PrintType(typeRef, buf)
+	err := v.variables.PrintNode(v.variables.Nodes[jsonNodeRef], buf)
+	if err != nil {
+		v.err = err
+		return
+	}
+	value := buf.Bytes()
+
+	if err = codec.ValidateInput(value); err != nil {
+		v.renderVariableInvalidCustomScalarError(value, typeName, err)
+		return
+	}
+
+	if !v.coerce {
+		return
+	}
+
+	coerced, err := codec.CoerceInput(value)
+	if err != nil {
+		v.renderVariableInvalidCustomScalarError(value, typeName, err)
+		return
+	}
+	if bytes.Equal(value, coerced) {
+		return
+	}
+	var coercedRef int
+	if data, dataType, _, _ := jsonparser.Get(coerced); dataType == jsonparser.String {
+		coercedRef = v.variables.AppendStringBytes(data)
+	} else {
+		coercedRef, err = v.variables.AppendAnyJSONBytes(coerced)
+		if err != nil {
+			v.err = err
+			return
+		}
+	}
+	v.variables.Nodes[jsonNodeRef] = v.variables.Nodes[coercedRef]
+	v.coerced = true
+}
+
+func (v *variablesVisitor) renderVariableInvalidCustomScalarError(invalidValue, typeName []byte, reason error) {
+	var path string
+	if len(v.path) > 1 {
+		path = fmt.Sprintf(` at "%s"`, v.renderPath())
+	}
+	v.err = &InvalidVariableError{
+		Message: fmt.Sprintf(`Variable "$%s" got invalid value %s%s; Expected type "%s". %s`, string(v.currentVariableName), string(invalidValue), path, string(typeName), reason.Error()),
+	}
+}
+
+// validateOneOfInputObject ensures that exactly one field of a @oneOf input object is provided and that it is not null
+func (v *variablesVisitor) validateOneOfInputObject(jsonNodeRef int, typeName []byte) {
+	objectFields := v.variables.Nodes[jsonNodeRef].ObjectFields
+	if len(objectFields) != 1 {
+		v.renderVariableInvalidValueError(jsonNodeRef, fmt.Sprintf(`Exactly one key must be specified for OneOf type "%s".`, string(typeName)))
+		return
+	}
+
+	fieldName := v.variables.ObjectFieldKey(objectFields[0])
+	fieldValueRef := v.variables.ObjectFieldValue(objectFields[0])
+	if !v.variables.NodeIsDefined(fieldValueRef) {
+		v.pushObjectPath(fieldName)
+		v.renderVariableInvalidValueError(fieldValueRef, fmt.Sprintf(`Field "%s" must be non-null.`, string(fieldName)))
+		v.popPath()
+	}
+}
+
+func (v *variablesVisitor) renderVariableInvalidValueError(jsonNodeRef int, reason string) {
+	buf := &bytes.Buffer{}
+	err := v.variables.PrintNode(v.variables.Nodes[jsonNodeRef], buf)
+	if err != nil {
+		v.err = err
+		return
+	}
+	var path string
+	if len(v.path) > 1 {
+		path = fmt.Sprintf(` at "%s"`, v.renderPath())
+	}
+	v.err = &InvalidVariableError{
+		Message: fmt.Sprintf(`Variable "$%s" got invalid value %s%s; %s`, string(v.currentVariableName), buf.String(), path, reason),
+	}
+}
+
+func (v *variablesVisitor) renderVariableInvalidNullError(typeDocument *ast.Document, typeRef int) {
+	buf := &bytes.Buffer{}
+	err := typeDocument.PrintType(typeRef, buf)
 	if err != nil {
 		v.err = err
 		return
 	}
 	typeName := buf.String()
+	var path string
+	if len(v.path) > 1 {
+		path = fmt.Sprintf(` at "%s"`, v.renderPath())
+	}
 	v.err = &InvalidVariableError{
-		Message: fmt.Sprintf(`Variable "$%s" got invalid value null; Expected non-nullable type "%s" not to be null.`, string(variableName), typeName),
+		Message: fmt.Sprintf(`Variable "$%s" got invalid value null%s; Expected non-nullable type "%s" not to be null.`, string(v.currentVariableName), path, typeName),
 	}
 }
 
 func (v *variablesVisitor) traverseFieldDefitionType(fieldTypeDefinitionNodeKind ast.NodeKind, fieldName ast.ByteSlice, typeRef int, fieldVariablesJsonNodeRef int) {
 	if v.definition.TypeIsNonNull(typeRef) {
+		if v.isListItem() && fieldVariablesJsonNodeRef != -1 && v.variables.Nodes[fieldVariablesJsonNodeRef].Kind == astjson.NodeKindNull {
+			v.renderVariableInvalidNullError(v.definition, typeRef)
+			return
+		}
 		if !v.variables.NodeIsDefined(fieldVariablesJsonNodeRef) {
 			v.renderVariableRequiredNotProvidedError(fieldName, typeRef)
 		}
@@ -320,11 +489,10 @@ func (v *variablesVisitor) traverseFieldDefitionType(fieldTypeDefinitionNodeKind
 		return
 	}
 
-	typeName := v.definition.ResolveTypeNameBytes(typeRef)
-
 	if v.definition.TypeIsList(typeRef) {
 		if v.variables.Nodes[fieldVariablesJsonNodeRef].Kind != astjson.NodeKindArray {
-			v.renderVariableInvalidNestedTypeError(fieldVariablesJsonNodeRef, fieldTypeDefinitionNodeKind, typeName)
+			// input coercion for lists: a single value is treated as a list with one item
+			v.traverseFieldDefitionType(fieldTypeDefinitionNodeKind, fieldName, v.definition.Types[typeRef].OfType, fieldVariablesJsonNodeRef)
 			return
 		}
 		if len(v.variables.Nodes[fieldVariablesJsonNodeRef].ArrayValues) == 0 {
@@ -379,6 +547,9 @@ func (v *variablesVisitor) traverseNamedTypeNode(jsonNodeRef int, typeName []byt
 				return
 			}
 		}
+		if v.definition.InputObjectTypeDefinitionIsOneOf(fieldTypeDefinitionNode.Ref) {
+			v.validateOneOfInputObject(jsonNodeRef, typeName)
+		}
 	case ast.NodeKindScalarTypeDefinition:
 		switch unsafebytes.BytesToString(typeName) {
 		case "String":
@@ -391,11 +562,13 @@ func (v *variablesVisitor) traverseNamedTypeNode(jsonNodeRef int, typeName []byt
 				v.renderVariableInvalidNestedTypeError(jsonNodeRef, fieldTypeDefinitionNode.Kind, typeName)
 				return
 			}
+			v.validateInt(jsonNodeRef, fieldTypeDefinitionNode.Kind, typeName)
 		case "Float":
 			if v.variables.Nodes[jsonNodeRef].Kind != astjson.NodeKindNumber {
 				v.renderVariableInvalidNestedTypeError(jsonNodeRef, fieldTypeDefinitionNode.Kind, typeName)
 				return
 			}
+			v.validateFloat(jsonNodeRef)
 		case "Boolean":
 			if v.variables.Nodes[jsonNodeRef].Kind != astjson.NodeKindBoolean {
 				v.renderVariableInvalidNestedTypeError(jsonNodeRef, fieldTypeDefinitionNode.Kind, typeName)
@@ -406,6 +579,10 @@ func (v *variablesVisitor) traverseNamedTypeNode(jsonNodeRef int, typeName []byt
 				v.renderVariableInvalidNestedTypeError(jsonNodeRef, fieldTypeDefinitionNode.Kind, typeName)
 				return
 			}
+		default:
+			if codec, ok := v.scalarCodecs.Codec(v.definition, fieldTypeDefinitionNode.Ref); ok {
+				v.traverseCustomScalar(codec, jsonNodeRef, typeName)
+			}
 		}
 	case ast.NodeKindEnumTypeDefinition:
 		if v.variables.Nodes[jsonNodeRef].Kind != astjson.NodeKindString {
@@ -10,6 +10,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/internal/unsafeparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalars"
 )
 
 func TestVariablesValidation(t *testing.T) {
@@ -698,13 +699,272 @@ func TestVariablesValidation(t *testing.T) {
 		err := runTest(t, tc)
 		require.NoError(t, err)
 	})
+
+	t.Run("oneOf input object with exactly one field", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input UserBy @oneOf { id: ID email: String } type Query { user(by: UserBy!): String }`,
+			operation: `query Foo($by: UserBy!) { user(by: $by) }`,
+			variables: `{"by":{"email":"a@b.c"}}`,
+		}
+		err := runTest(t, tc)
+		require.NoError(t, err)
+	})
+
+	t.Run("oneOf input object with more than one field", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input UserBy @oneOf { id: ID email: String } type Query { user(by: UserBy!): String }`,
+			operation: `query Foo($by: UserBy!) { user(by: $by) }`,
+			variables: `{"by":{"id":"1","email":"a@b.c"}}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$by" got invalid value {"id":"1","email":"a@b.c"}; Exactly one key must be specified for OneOf type "UserBy".`, err.Error())
+	})
+
+	t.Run("oneOf input object without fields", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input UserBy @oneOf { id: ID email: String } type Query { user(by: UserBy!): String }`,
+			operation: `query Foo($by: UserBy!) { user(by: $by) }`,
+			variables: `{"by":{}}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$by" got invalid value {}; Exactly one key must be specified for OneOf type "UserBy".`, err.Error())
+	})
+
+	t.Run("oneOf input object with null field", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input UserBy @oneOf { id: ID email: String } type Query { user(by: UserBy!): String }`,
+			operation: `query Foo($by: UserBy!) { user(by: $by) }`,
+			variables: `{"by":{"id":null}}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$by" got invalid value null at "by.id"; Field "id" must be non-null.`, err.Error())
+	})
+
+	t.Run("nested oneOf input object with more than one field", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input UserBy @oneOf { id: ID email: String } input Filter { by: UserBy } type Query { users(filter: Filter): String }`,
+			operation: `query Foo($filter: Filter) { users(filter: $filter) }`,
+			variables: `{"filter":{"by":{"id":"1","email":"a@b.c"}}}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$filter" got invalid value {"id":"1","email":"a@b.c"} at "filter.by"; Exactly one key must be specified for OneOf type "UserBy".`, err.Error())
+	})
+
+	t.Run("Int in 32-bit signed range", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(min: Int, max: Int, zeroFraction: Int): String }`,
+			operation: `query Foo($min: Int, $max: Int, $zeroFraction: Int) { hello(min: $min, max: $max, zeroFraction: $zeroFraction) }`,
+			variables: `{"min":-2147483648,"max":2147483647,"zeroFraction":1.0}`,
+		}
+		err := runTest(t, tc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Int above 32-bit signed range", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(arg: Int): String }`,
+			operation: `query Foo($bar: Int) { hello(arg: $bar) }`,
+			variables: `{"bar":2147483648}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$bar" got invalid value 2147483648; Int cannot represent non 32-bit signed integer value: 2147483648`, err.Error())
+	})
+
+	t.Run("Int below 32-bit signed range on input object", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input Foo { bar: Int } type Query { hello(arg: Foo): String }`,
+			operation: `query Foo($input: Foo) { hello(arg: $input) }`,
+			variables: `{"input":{"bar":-829384293849283498239482938}}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$input" got invalid value -829384293849283498239482938 at "input.bar"; Int cannot represent non 32-bit signed integer value: -829384293849283498239482938`, err.Error())
+	})
+
+	t.Run("Int with fraction", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(arg: Int): String }`,
+			operation: `query Foo($bar: Int) { hello(arg: $bar) }`,
+			variables: `{"bar":1.5}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$bar" got invalid value 1.5; Int cannot represent non-integer value: 1.5`, err.Error())
+	})
+
+	t.Run("integer as Float", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(arg: Float): String }`,
+			operation: `query Foo($bar: Float) { hello(arg: $bar) }`,
+			variables: `{"bar":1}`,
+		}
+		err := runTest(t, tc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("non finite Float", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(arg: Float): String }`,
+			operation: `query Foo($bar: Float) { hello(arg: $bar) }`,
+			variables: `{"bar":1e400}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$bar" got invalid value 1e400; Float cannot represent non numeric value: 1e400`, err.Error())
+	})
+
+	t.Run("single value into list without normalization", func(t *testing.T) {
+		tc := testCase{
+			schema:            `input Foo { bars: [[Int]] } type Query { hello(arg: [[String]], foo: Foo): String }`,
+			operation:         `query Foo($bar: [[String]], $foo: Foo) { hello(arg: $bar, foo: $foo) }`,
+			variables:         `{"bar":"a","foo":{"bars":[1,[2],null]}}`,
+			skipNormalization: true,
+		}
+		err := runTest(t, tc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid single value into list without normalization", func(t *testing.T) {
+		tc := testCase{
+			schema:            `type Query { hello(arg: [[String]]): String }`,
+			operation:         `query Foo($bar: [[String]]) { hello(arg: $bar) }`,
+			variables:         `{"bar":1}`,
+			skipNormalization: true,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$bar" got invalid value 1; String cannot represent a non string value: 1`, err.Error())
+	})
+
+	t.Run("null item of list with non-null items", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(arg: [String!]): String }`,
+			operation: `query Foo($bar: [String!]) { hello(arg: $bar) }`,
+			variables: `{"bar":["a",null]}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$bar" got invalid value null at "bar.[1]"; Expected non-nullable type "String!" not to be null.`, err.Error())
+	})
+
+	t.Run("null item of nested non-null list", func(t *testing.T) {
+		tc := testCase{
+			schema:    `type Query { hello(arg: [[Int!]!]): String }`,
+			operation: `query Foo($bar: [[Int!]!]) { hello(arg: $bar) }`,
+			variables: `{"bar":[[1],null]}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$bar" got invalid value null at "bar.[1]"; Expected non-nullable type "[Int!]!" not to be null.`, err.Error())
+	})
+
+	t.Run("null item of list with non-null items on input object", func(t *testing.T) {
+		tc := testCase{
+			schema:    `input Foo { bars: [[String!]] } type Query { hello(arg: Foo): String }`,
+			operation: `query Foo($input: Foo) { hello(arg: $input) }`,
+			variables: `{"input":{"bars":[["a"],["b",null]]}}`,
+		}
+		err := runTest(t, tc)
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$input" got invalid value null at "input.bars.[1].[1]"; Expected non-nullable type "String!" not to be null.`, err.Error())
+	})
 }
 
 type testCase struct {
 	schema, operation, variables string
+	// skipNormalization validates the variables as provided, without the input coercion of the normalization
+	skipNormalization bool
 }
 
 func runTest(t *testing.T, tc testCase) error {
+	t.Helper()
+	def := unsafeparser.ParseGraphqlDocumentString(tc.schema)
+	op := unsafeparser.ParseGraphqlDocumentString(tc.operation)
+	op.Input.Variables = []byte(tc.variables)
+	err := asttransform.MergeDefinitionWithBaseSchema(&def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tc.skipNormalization {
+		report := &operationreport.Report{}
+		norm := astnormalization.NewNormalizer(true, true)
+		norm.NormalizeOperation(&op, &def, report)
+		if report.HasErrors() {
+			t.Fatal(report.Error())
+		}
+	}
+	validator := NewVariablesValidator()
+	return validator.Validate(&op, &def, op.Input.Variables)
+}
+
+func TestVariablesValidation_ScalarCodecs(t *testing.T) {
+	const schema = `scalar DateTime scalar Timestamp @specifiedBy(url: "https://scalars.graphql.org/andimarek/date-time") scalar Custom input Filter { after: DateTime } type Query { events(at: DateTime, filter: Filter, since: Timestamp, custom: Custom): String }`
+
+	t.Run("valid custom scalar value", func(t *testing.T) {
+		_, err := runScalarCodecsTest(t, testCase{
+			schema:    schema,
+			operation: `query Foo($at: DateTime) { events(at: $at) }`,
+			variables: `{"at":"2024-01-02T03:04:05Z"}`,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid custom scalar value", func(t *testing.T) {
+		_, err := runScalarCodecsTest(t, testCase{
+			schema:    schema,
+			operation: `query Foo($at: DateTime) { events(at: $at) }`,
+			variables: `{"at":"yesterday"}`,
+		})
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$at" got invalid value "yesterday"; Expected type "DateTime". Expected an RFC 3339 date-time string.`, err.Error())
+	})
+
+	t.Run("invalid nested custom scalar value", func(t *testing.T) {
+		_, err := runScalarCodecsTest(t, testCase{
+			schema:    schema,
+			operation: `query Foo($filter: Filter) { events(filter: $filter) }`,
+			variables: `{"filter":{"after":123}}`,
+		})
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$filter" got invalid value 123 at "filter.after"; Expected type "DateTime". Expected a string value.`, err.Error())
+	})
+
+	t.Run("codec by specifiedBy url", func(t *testing.T) {
+		_, err := runScalarCodecsTest(t, testCase{
+			schema:    schema,
+			operation: `query Foo($since: Timestamp) { events(since: $since) }`,
+			variables: `{"since":"now"}`,
+		})
+		require.Error(t, err)
+		assert.Equal(t, `Variable "$since" got invalid value "now"; Expected type "Timestamp". Expected an RFC 3339 date-time string.`, err.Error())
+	})
+
+	t.Run("custom scalar without codec accepts any value", func(t *testing.T) {
+		_, err := runScalarCodecsTest(t, testCase{
+			schema:    schema,
+			operation: `query Foo($custom: Custom) { events(custom: $custom) }`,
+			variables: `{"custom":{"a":[1,true]}}`,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("coerce custom scalar values", func(t *testing.T) {
+		variables, err := runScalarCodecsTest(t, testCase{
+			schema:    schema,
+			operation: `query Foo($at: DateTime, $filter: Filter) { events(at: $at, filter: $filter) }`,
+			variables: `{"at":"2024-01-02T03:04:05.000+00:00","filter":{"after":"2024-01-02T03:04:05.100Z"}}`,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `{"at":"2024-01-02T03:04:05Z","filter":{"after":"2024-01-02T03:04:05.1Z"}}`, string(variables))
+	})
+}
+
+func runScalarCodecsTest(t *testing.T, tc testCase) ([]byte, error) {
 	t.Helper()
 	def := unsafeparser.ParseGraphqlDocumentString(tc.schema)
 	op := unsafeparser.ParseGraphqlDocumentString(tc.operation)
@@ -719,6 +979,6 @@ func runTest(t *testing.T, tc testCase) error {
 	if report.HasErrors() {
 		t.Fatal(report.Error())
 	}
-	validator := NewVariablesValidator()
-	return validator.Validate(&op, &def, op.Input.Variables)
+	validator := NewVariablesValidator(WithScalarCodecs(scalars.NewDefaultRegistry()))
+	return validator.ValidateAndCoerce(&op, &def, op.Input.Variables)
 }